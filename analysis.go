@@ -0,0 +1,101 @@
+package moviego
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// frameMetadata is one frame reported by FFmpeg's metadata=print filter,
+// with the metadata analysis filters attached to it (lavfi.cropdetect.w, ...).
+type frameMetadata struct {
+	Frame  int64
+	Time   float64
+	Values map[string]string
+}
+
+// metadataPass decodes v through the filter chain stages, printing the frame
+// metadata after each stage, and returns the frames reported per stage.
+// Empty stages print the frames as they come from the previous stage. No
+// output is written (null muxer).
+func (v *Video) metadataPass(op string, stages []string, env *RenderEnv) ([][]frameMetadata, error) {
+	ffmpegPath, err := getFFmpegPath()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get ffmpeg path: %w", op, err)
+	}
+	dir, err := os.MkdirTemp(env.tempDir(), "moviego-analysis-*")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer os.RemoveAll(dir)
+
+	var chain []string
+	files := make([]string, len(stages))
+	for i, stage := range stages {
+		if stage != "" {
+			chain = append(chain, stage)
+		}
+		files[i] = filepath.Join(dir, fmt.Sprintf("stage%d.txt", i))
+		chain = append(chain, "metadata=mode=print:file="+filterPath(files[i]))
+	}
+	analyzed, err := v.videoFilter(strings.Join(chain, ","))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if err := sourceJob(op, ffmpegPath, analyzed, "-", []string{"-an", "-f", "null"}, env); err != nil {
+		return nil, err
+	}
+
+	result := make([][]frameMetadata, len(stages))
+	for i, file := range files {
+		if result[i], err = readFrameMetadata(file); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+	return result, nil
+}
+
+// readFrameMetadata parses a metadata=print file:
+//
+//	frame:0    pts:0       pts_time:0
+//	lavfi.cropdetect.x1=0
+func readFrameMetadata(path string) ([]frameMetadata, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read frame metadata: %w", err)
+	}
+	defer f.Close()
+
+	var frames []frameMetadata
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "frame:") {
+			frame := frameMetadata{Values: map[string]string{}}
+			for _, field := range strings.Fields(line) {
+				key, value, _ := strings.Cut(field, ":")
+				switch key {
+				case "frame":
+					frame.Frame, _ = strconv.ParseInt(value, 10, 64)
+				case "pts_time":
+					frame.Time, _ = strconv.ParseFloat(value, 64)
+				}
+			}
+			frames = append(frames, frame)
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok && len(frames) > 0 {
+			frames[len(frames)-1].Values[key] = value
+		}
+	}
+	return frames, scanner.Err()
+}
+
+// metadataInt returns the integer value of key, or false when missing.
+func (f frameMetadata) metadataInt(key string) (int, bool) {
+	n, err := strconv.Atoi(f.Values[key])
+	return n, err == nil
+}
@@ -1,6 +1,9 @@
 package moviego
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // AnimatedRotate applies a time-based rotation. Angle is in radians.
 func (v *Video) AnimatedRotate(a Animation) (*Video, error) {
@@ -137,3 +140,89 @@ type ZoomPanParams struct {
 	Duration float64
 	FPS      int
 }
+
+// ZoomTarget describes the zoom applied to a frozen frame by FreezeAndZoom.
+type ZoomTarget struct {
+	Zoom  float64  // final zoom factor (> 1 zooms in)
+	X     *float64 // focus point in source pixels; nil = frame center
+	Y     *float64 // focus point in source pixels; nil = frame center
+	Curve Curve    // easing of the zoom (default Linear)
+}
+
+// FreezeAndZoom freezes the frame at time at for duration seconds while zooming
+// into it (Ken Burns on the still), then resumes playback from the same frame.
+// Audio is silent during the hold, so the rest of the clip stays in sync.
+func (v *Video) FreezeAndZoom(at, duration float64, target ZoomTarget) (*Video, error) {
+	if len(v.overlays) > 0 {
+		flat, err := v.FlattenOverlays()
+		if err != nil {
+			return nil, fmt.Errorf("FreezeAndZoom: %w", err)
+		}
+		return flat.FreezeAndZoom(at, duration, target)
+	}
+	file, label := safeFirstFilename(v.filenames), safeLastVideoLabel(v)
+	if at < 0 || at >= v.duration {
+		return nil, fmt.Errorf("FreezeAndZoom: at must be within [0, %.4f) (got=%.4f, file=%s, label=%s)", v.duration, at, file, label)
+	}
+	if duration <= 0 {
+		return nil, fmt.Errorf("FreezeAndZoom: duration must be positive (got=%.4f, file=%s, label=%s)", duration, file, label)
+	}
+	if target.Zoom < 1 {
+		return nil, fmt.Errorf("FreezeAndZoom: zoom must be >= 1 (got=%.4f, file=%s, label=%s)", target.Zoom, file, label)
+	}
+
+	fps := v.fps
+	if fps == 0 {
+		fps = 30
+	}
+	frames := int(duration * float64(fps))
+	if frames < 1 {
+		frames = 1
+	}
+	zoom := Animation{Start: 1, End: target.Zoom, EndTime: duration, Curve: target.Curve}
+	zoomExpr := zoom.toExpr(fmt.Sprintf("on/%d", fps))
+	focusX, focusY := "iw/2", "ih/2"
+	if target.X != nil {
+		focusX = fmt.Sprintf("%.4f", *target.X)
+	}
+	if target.Y != nil {
+		focusY = fmt.Sprintf("%.4f", *target.Y)
+	}
+	zoompan := fmt.Sprintf("zoompan=z='%s':x='%s-iw/zoom/2':y='%s-ih/zoom/2':d=%d:s=%dx%d:fps=%d",
+		zoomExpr, focusX, focusY, frames, v.width, v.height, fps)
+
+	videoGraph := func(in, p string) string {
+		var b strings.Builder
+		segments := fmt.Sprintf("[%s_frz][%s_post]", p, p)
+		if at > 0 {
+			fmt.Fprintf(&b, "[%s]split=3[%s_1][%s_2][%s_3];", in, p, p, p)
+			fmt.Fprintf(&b, "[%s_1]trim=end=%.4f,setpts=PTS-STARTPTS[%s_pre];", p, at, p)
+			segments = fmt.Sprintf("[%s_pre]", p) + segments
+		} else {
+			fmt.Fprintf(&b, "[%s]split=2[%s_2][%s_3];", in, p, p)
+		}
+		fmt.Fprintf(&b, "[%s_2]trim=start=%.4f,setpts=PTS-STARTPTS,trim=end_frame=1,%s,setsar=1[%s_frz];", p, at, zoompan, p)
+		fmt.Fprintf(&b, "[%s_3]trim=start=%.4f,setpts=PTS-STARTPTS[%s_post];", p, at, p)
+		fmt.Fprintf(&b, "%sconcat=n=%d:v=1:a=0", segments, strings.Count(segments, "["))
+		return b.String()
+	}
+	audioGraph := func(in, p string) string {
+		if at == 0 {
+			return fmt.Sprintf("[%s]adelay=delays=%d:all=1", in, int64(duration*1000))
+		}
+		return fmt.Sprintf("[%s]asplit=2[%s_1][%s_2];"+
+			"[%s_1]atrim=end=%.4f,asetpts=PTS-STARTPTS,apad=pad_dur=%.4f[%s_pre];"+
+			"[%s_2]atrim=start=%.4f,asetpts=PTS-STARTPTS[%s_post];"+
+			"[%s_pre][%s_post]concat=n=2:v=0:a=1",
+			in, p, p, p, at, duration, p, p, at, p, p, p)
+	}
+
+	frozen := v.graphFilter(videoGraph, audioGraph)
+	newDuration := v.duration + duration
+	frozen.duration = newDuration
+	frozen.audio.duration = newDuration
+	frozen.frames = uint64(float64(fps) * newDuration)
+	frozen.startTime = 0
+	frozen.endTime = newDuration
+	return frozen, nil
+}
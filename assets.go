@@ -0,0 +1,188 @@
+package moviego
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// AssetManager downloads remote assets (fonts, logos, music, templates) into
+// a local cache so renders can use them as plain files:
+//
+//	assets, _ := moviego.NewAssetManager("/var/cache/moviego")
+//	font, err := assets.Resolve("https://cdn.example.com/fonts/Inter.ttf")
+//	video.AddText(moviego.TextClip{Text: "Hi", FontFamily: font})
+//
+// Files are stored by the SHA-256 of their content, so the same asset served
+// from several URLs is kept once, and each URL is downloaded once no matter
+// how many goroutines ask for it at the same time. The cache survives
+// restarts and can be shared by processes using the same directory.
+type AssetManager struct {
+	dir    string
+	client *http.Client
+
+	mu       sync.Mutex
+	inflight map[string]*assetFetch
+}
+
+// assetFetch is a download shared by every caller asking for the same URL.
+type assetFetch struct {
+	done chan struct{}
+	path string
+	err  error
+}
+
+// NewAssetManager returns a manager caching assets in dir.
+func NewAssetManager(dir string) (*AssetManager, error) {
+	for _, sub := range []string{"objects", "urls"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			return nil, fmt.Errorf("NewAssetManager: failed to create '%s': %w", dir, err)
+		}
+	}
+	return &AssetManager{dir: dir, client: http.DefaultClient, inflight: make(map[string]*assetFetch)}, nil
+}
+
+// SetClient sets the HTTP client used for downloads.
+func (m *AssetManager) SetClient(client *http.Client) *AssetManager {
+	m.client = client
+	return m
+}
+
+// Resolve returns a local path for ref. http(s) URLs are downloaded into the
+// cache on first use; anything else is treated as a local path and returned
+// unchanged.
+func (m *AssetManager) Resolve(ref string) (string, error) {
+	if !strings.HasPrefix(ref, "http://") && !strings.HasPrefix(ref, "https://") {
+		return ref, nil
+	}
+	if p, ok := m.cached(ref); ok {
+		return p, nil
+	}
+
+	m.mu.Lock()
+	if f, ok := m.inflight[ref]; ok {
+		m.mu.Unlock()
+		<-f.done
+		return f.path, f.err
+	}
+	f := &assetFetch{done: make(chan struct{})}
+	m.inflight[ref] = f
+	m.mu.Unlock()
+
+	f.path, f.err = m.download(ref)
+	close(f.done)
+
+	m.mu.Lock()
+	delete(m.inflight, ref)
+	m.mu.Unlock()
+	return f.path, f.err
+}
+
+// ResolveVerified is Resolve for an asset with a known SHA-256 (hex). A
+// cached copy is used without any request, and a download with a different
+// hash is rejected.
+func (m *AssetManager) ResolveVerified(ref, sum string) (string, error) {
+	sum = strings.ToLower(sum)
+	if b, err := hex.DecodeString(sum); err != nil || len(b) != sha256.Size {
+		return "", fmt.Errorf("AssetManager: invalid SHA-256 %q for '%s'", sum, ref)
+	}
+	if matches, _ := filepath.Glob(filepath.Join(m.dir, "objects", sum+"*")); len(matches) > 0 {
+		return matches[0], nil
+	}
+	p, err := m.Resolve(ref)
+	if err != nil {
+		return "", err
+	}
+	if got := strings.TrimSuffix(filepath.Base(p), filepath.Ext(p)); got != sum {
+		return "", fmt.Errorf("AssetManager: checksum mismatch for '%s' (want=%s, got=%s)", ref, sum, got)
+	}
+	return p, nil
+}
+
+// cached returns the object previously downloaded from ref.
+func (m *AssetManager) cached(ref string) (string, bool) {
+	data, err := os.ReadFile(m.urlIndex(ref))
+	if err != nil {
+		return "", false
+	}
+	p := filepath.Join(m.dir, "objects", strings.TrimSpace(string(data)))
+	if _, err := os.Stat(p); err != nil {
+		return "", false
+	}
+	return p, true
+}
+
+func (m *AssetManager) urlIndex(ref string) string {
+	sum := sha256.Sum256([]byte(ref))
+	return filepath.Join(m.dir, "urls", hex.EncodeToString(sum[:]))
+}
+
+// download stores ref in the cache. Files are written to a temporary name
+// and renamed, so readers never see partial files.
+func (m *AssetManager) download(ref string) (string, error) {
+	resp, err := m.client.Get(ref)
+	if err != nil {
+		return "", fmt.Errorf("AssetManager: failed to download '%s': %w", ref, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return "", fmt.Errorf("AssetManager: failed to download '%s': %s", ref, resp.Status)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Join(m.dir, "objects"), ".download-*")
+	if err != nil {
+		return "", fmt.Errorf("AssetManager: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, hash), resp.Body); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("AssetManager: failed to download '%s': %w", ref, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("AssetManager: %w", err)
+	}
+
+	// The extension is kept so FFmpeg and font loading can detect the type.
+	object := hex.EncodeToString(hash.Sum(nil)) + assetExt(ref)
+	p := filepath.Join(m.dir, "objects", object)
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		return "", fmt.Errorf("AssetManager: %w", err)
+	}
+	if err := writeFileAtomic(m.urlIndex(ref), []byte(object)); err != nil {
+		return "", fmt.Errorf("AssetManager: %w", err)
+	}
+	return p, nil
+}
+
+func assetExt(ref string) string {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(path.Ext(u.Path))
+}
+
+func writeFileAtomic(name string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
@@ -0,0 +1,123 @@
+package moviego
+
+import "fmt"
+
+// channelFilter validates a channel operation and returns its filter and
+// the number of channels it outputs.
+type channelFilter func(channels uint8) (string, uint8, error)
+
+// monoFilter averages all channels into one.
+func monoFilter(uint8) (string, uint8, error) {
+	return "aformat=channel_layouts=mono", 1, nil
+}
+
+// stereoFilter mixes down (or copies a mono channel) to two channels.
+func stereoFilter(uint8) (string, uint8, error) {
+	return "aformat=channel_layouts=stereo", 2, nil
+}
+
+func panFilter(left, right float64) channelFilter {
+	return func(uint8) (string, uint8, error) {
+		if left < 0 || right < 0 {
+			return "", 0, fmt.Errorf("gains must be non-negative (left=%.4f, right=%.4f)", left, right)
+		}
+		return fmt.Sprintf("aformat=channel_layouts=stereo,pan=stereo|c0=%s*c0|c1=%s*c1", formatFloat(left), formatFloat(right)), 2, nil
+	}
+}
+
+func swapFilter(uint8) (string, uint8, error) {
+	return "aformat=channel_layouts=stereo,channelmap=map=FR-FL|FL-FR:channel_layout=stereo", 2, nil
+}
+
+func selectChannelFilter(channel int) channelFilter {
+	return func(channels uint8) (string, uint8, error) {
+		if channel < 0 || channels > 0 && channel >= int(channels) {
+			return "", 0, fmt.Errorf("channel must be within [0, %d) (got=%d)", channels, channel)
+		}
+		return fmt.Sprintf("pan=stereo|c0=c%d|c1=c%d", channel, channel), 2, nil
+	}
+}
+
+// applyChannels applies a channel operation to the audio.
+func (a *Audio) applyChannels(op string, f channelFilter) (*Audio, error) {
+	filter, channels, err := f(a.channels)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w (file=%s, label=%s)", op, err, safeFirstFilename(a.filenames), a.safeLabel())
+	}
+	out, err := a.audioFilter(filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	out.channels = channels
+	return out, nil
+}
+
+// ToMono mixes all channels down to one.
+func (a *Audio) ToMono() (*Audio, error) {
+	return a.applyChannels("ToMono", monoFilter)
+}
+
+// ToStereo mixes the audio down to two channels; mono audio is copied to
+// both.
+func (a *Audio) ToStereo() (*Audio, error) {
+	return a.applyChannels("ToStereo", stereoFilter)
+}
+
+// Pan sets the gain of the left and right channels (0 = muted, 1 = unchanged).
+// The audio is mixed down to stereo first.
+func (a *Audio) Pan(left, right float64) (*Audio, error) {
+	return a.applyChannels("Pan", panFilter(left, right))
+}
+
+// SwapChannels swaps the left and right channels. The audio is mixed down to
+// stereo first.
+func (a *Audio) SwapChannels() (*Audio, error) {
+	return a.applyChannels("SwapChannels", swapFilter)
+}
+
+// SelectChannel plays channel (0 = left, 1 = right, ...) on both stereo
+// channels, e.g. for interviews recorded with the microphone on one channel
+// only.
+func (a *Audio) SelectChannel(channel int) (*Audio, error) {
+	return a.applyChannels("SelectChannel", selectChannelFilter(channel))
+}
+
+// applyChannels applies a channel operation to the audio of the video.
+func (v *Video) applyChannels(op string, f channelFilter) (*Video, error) {
+	filter, channels, err := f(v.audio.channels)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w (file=%s, label=%s)", op, err, safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	out := v.clone()
+	initRawVideo(&out)
+	out.audio = out.audio.chain(filter)
+	out.audio.channels = channels
+	return &out, nil
+}
+
+// ToMono mixes the channels of the audio down to one.
+func (v *Video) ToMono() (*Video, error) {
+	return v.applyChannels("ToMono", monoFilter)
+}
+
+// ToStereo mixes the audio down to two channels; mono audio is copied to
+// both.
+func (v *Video) ToStereo() (*Video, error) {
+	return v.applyChannels("ToStereo", stereoFilter)
+}
+
+// Pan sets the gain of the left and right audio channels, see Audio.Pan.
+func (v *Video) Pan(left, right float64) (*Video, error) {
+	return v.applyChannels("Pan", panFilter(left, right))
+}
+
+// SwapChannels swaps the left and right audio channels.
+func (v *Video) SwapChannels() (*Video, error) {
+	return v.applyChannels("SwapChannels", swapFilter)
+}
+
+// SelectChannel plays one audio channel on both stereo channels, see
+// Audio.SelectChannel.
+func (v *Video) SelectChannel(channel int) (*Video, error) {
+	return v.applyChannels("SelectChannel", selectChannelFilter(channel))
+}
@@ -0,0 +1,148 @@
+package moviego
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// AudioEffect is an audio processing step created with Atempo, PitchShift,
+// Echo or Reverb and applied with Audio.AddEffect or Video.AddAudioEffect.
+// Effects become part of the audio filter chain, so every writer (WriteVideo,
+// WriteAudio, timelines, composites) renders them.
+type AudioEffect struct {
+	name   string
+	err    error
+	speed  float64                        // the effect divides durations by speed
+	filter func(sampleRate uint64) string // FFmpeg filter chain for the input sample rate
+}
+
+// Atempo changes the tempo by rate (0.5 to 100) without changing the pitch.
+// The duration is divided by rate.
+func Atempo(rate float64) AudioEffect {
+	e := AudioEffect{name: "Atempo", speed: rate, filter: func(uint64) string { return atempoChain(rate) }}
+	if rate < 0.5 || rate > 100.0 {
+		e.err = fmt.Errorf("rate must be between 0.5 and 100.0 (got=%f)", rate)
+	}
+	return e
+}
+
+// PitchShift shifts the pitch by semitones (-24 to 24, negative is lower)
+// without changing the tempo.
+func PitchShift(semitones float64) AudioEffect {
+	e := AudioEffect{name: "PitchShift", speed: 1, filter: func(sampleRate uint64) string {
+		// Play faster at a higher rate, then restore the tempo.
+		rate := math.Round(float64(sampleRate) * math.Pow(2, semitones/12))
+		return fmt.Sprintf("asetrate=%d,aresample=%d,%s", int64(rate), sampleRate, atempoChain(float64(sampleRate)/rate))
+	}}
+	if semitones < -24 || semitones > 24 {
+		e.err = fmt.Errorf("semitones must be between -24 and 24 (got=%f)", semitones)
+	}
+	return e
+}
+
+// Echo repeats the audio after delay milliseconds, attenuated by decay (0-1).
+func Echo(delay, decay float64) AudioEffect {
+	e := AudioEffect{name: "Echo", speed: 1, filter: func(uint64) string {
+		return fmt.Sprintf("aecho=0.8:0.9:%.4f:%.4f", delay, decay)
+	}}
+	if delay <= 0 || delay > 90000 || decay <= 0 || decay >= 1 {
+		e.err = fmt.Errorf("delay must be in (0, 90000] ms and decay in (0, 1) (delay=%.4f, decay=%.4f)", delay, decay)
+	}
+	return e
+}
+
+// Reverb simulates a room with a cluster of short reflections. size (0-1)
+// scales the room from a booth to a hall; wet (0-1) is the level of the
+// reflections against the dry signal.
+func Reverb(size, wet float64) AudioEffect {
+	e := AudioEffect{name: "Reverb", speed: 1, filter: func(uint64) string {
+		base := 15 + 85*size // first reflection in ms
+		var delays, decays []string
+		for i, tap := range []float64{1, 1.7, 2.6, 3.7, 5.1} {
+			delays = append(delays, formatFloat(math.Round(base*tap)))
+			decays = append(decays, formatFloat(math.Round(wet*(0.6-0.1*float64(i))*1000)/1000))
+		}
+		return fmt.Sprintf("aecho=1:%s:%s:%s", formatFloat(math.Round(1000/(1+wet))/1000), strings.Join(delays, "|"), strings.Join(decays, "|"))
+	}}
+	if size < 0 || size > 1 || wet <= 0 || wet > 1 {
+		e.err = fmt.Errorf("size must be 0-1 and wet in (0, 1] (size=%.4f, wet=%.4f)", size, wet)
+	}
+	return e
+}
+
+// atempoChain returns atempo filters for tempo; a single atempo only
+// supports 0.5-2.0.
+func atempoChain(tempo float64) string {
+	var filters []string
+	curr := tempo
+	for curr > 2.0 {
+		filters = append(filters, "atempo=2.0")
+		curr /= 2.0
+	}
+	for curr < 0.5 {
+		filters = append(filters, "atempo=0.5")
+		curr /= 0.5
+	}
+	filters = append(filters, fmt.Sprintf("atempo=%.4f", curr))
+	return strings.Join(filters, ",")
+}
+
+// effectsChain validates effects and returns their filter chain and the
+// factor they scale the duration by.
+func effectsChain(effects []AudioEffect, sampleRate uint64) (string, float64, error) {
+	if len(effects) == 0 {
+		return "", 0, fmt.Errorf("no effects provided")
+	}
+	var prefix string
+	if sampleRate == 0 {
+		// Effects that depend on the sample rate need a known one.
+		sampleRate = defaultSampleRate
+		prefix = fmt.Sprintf("aresample=%d,", sampleRate)
+	}
+	filters := make([]string, len(effects))
+	scale := 1.0
+	for i, e := range effects {
+		if e.filter == nil {
+			return "", 0, fmt.Errorf("effect %d is not initialized, use Atempo, PitchShift, Echo or Reverb", i)
+		}
+		if e.err != nil {
+			return "", 0, fmt.Errorf("effect %d (%s): %w", i, e.name, e.err)
+		}
+		filters[i] = e.filter(sampleRate)
+		scale /= e.speed
+	}
+	return prefix + strings.Join(filters, ","), scale, nil
+}
+
+// AddEffect applies effects in order, e.g.
+// audio.AddEffect(Atempo(1.25), PitchShift(-2), Reverb(0.5, 0.3)).
+func (a *Audio) AddEffect(effects ...AudioEffect) (*Audio, error) {
+	filter, scale, err := effectsChain(effects, a.sampleRate)
+	if err != nil {
+		return nil, fmt.Errorf("AddEffect: %w (file=%s, label=%s)", err, safeFirstFilename(a.filenames), a.safeLabel())
+	}
+	out, err := a.audioFilter(filter)
+	if err != nil {
+		return nil, fmt.Errorf("AddEffect: %w", err)
+	}
+	out.duration = a.duration * scale
+	return out, nil
+}
+
+// AddAudioEffect applies effects to the audio of the video. Effects that
+// change the duration (Atempo) are rejected because the audio would drift
+// from the picture; use Speed to retime both.
+func (v *Video) AddAudioEffect(effects ...AudioEffect) (*Video, error) {
+	filter, scale, err := effectsChain(effects, v.audio.sampleRate)
+	if err != nil {
+		return nil, fmt.Errorf("AddAudioEffect: %w (file=%s, label=%s)", err, safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	if scale != 1 {
+		return nil, fmt.Errorf("AddAudioEffect: effects change the audio duration by %.4fx, use Speed instead (file=%s, label=%s)", scale, safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	out := v.clone()
+	initRawVideo(&out)
+	out.audio = out.audio.chain(filter)
+	return &out, nil
+}
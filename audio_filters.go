@@ -2,6 +2,7 @@ package moviego
 
 import (
 	"fmt"
+	"log/slog"
 	"strings"
 )
 
@@ -49,6 +50,21 @@ func (a *Audio) audioFilter(filter string) (*Audio, error) {
 	}, nil
 }
 
+// chain returns a copy of the audio with filter appended to its last branch.
+// Unlike audioFilter it requires an initialized chain and leaves metadata untouched.
+func (a *Audio) chain(filter string) Audio {
+	n := len(a.filterComplex)
+	label := fmt.Sprintf("%s_a", a.nextLabel())
+
+	out := *a
+	out.filterComplex = append(a.filterComplex[:n:n], FilterComplex{
+		Order:         incrementOrderCounter(),
+		FilterElement: fmt.Sprintf("[%s]%s", a.lastAudioLabel(), filter),
+		Label:         label,
+	})
+	return out
+}
+
 // MuteRanges mutes audio only during the specified time ranges.
 // Uses FFmpeg's volume filter with enable='between(t,s1,e1)+between(t,s2,e2)+...'.
 // If ranges is empty, returns the audio unchanged.
@@ -119,21 +135,8 @@ func (a *Audio) Tempo(tempo float64) (*Audio, error) {
 	if tempo < 0.5 || tempo > 100.0 {
 		return nil, fmt.Errorf("Tempo: must be between 0.5 and 100.0 (got=%f, file=%s, label=%s)", tempo, safeFirstFilename(a.filenames), a.safeLabel())
 	}
-	// ffmpeg's atempo only supports 0.5-2.0 per instance.
-	var filters []string
-	curr := tempo
-	for curr > 2.0 {
-		filters = append(filters, "atempo=2.0")
-		curr /= 2.0
-	}
-	for curr < 0.5 {
-		filters = append(filters, "atempo=0.5")
-		curr /= 0.5
-	}
-	filters = append(filters, fmt.Sprintf("atempo=%.4f", curr))
-	
 	newDuration := a.duration / tempo
-	newAudio, err := a.audioFilter(strings.Join(filters, ","))
+	newAudio, err := a.audioFilter(atempoChain(tempo))
 	if err != nil {
 		return nil, fmt.Errorf("Tempo[file=%s, label=%s]: %w", safeFirstFilename(a.filenames), a.safeLabel(), err)
 	}
@@ -141,6 +144,29 @@ func (a *Audio) Tempo(tempo float64) (*Audio, error) {
 	return newAudio, nil
 }
 
+// Subclip keeps the audio between start and end seconds, like Video.Cut,
+// e.g. to trim a music file before attaching it to a video. A start below 0
+// or an end past the audio is clamped.
+func (a *Audio) Subclip(start, end float64) (*Audio, error) {
+	if start < 0 {
+		slog.Warn("Subclip: Start time is less than 0, setting to 0", "start", start)
+		start = 0
+	}
+	if a.duration > 0 && end > a.duration {
+		slog.Warn("Subclip: End time is greater than audio duration, setting to audio duration", "end", end, "duration", a.duration)
+		end = a.duration
+	}
+	if start >= end {
+		return nil, fmt.Errorf("Subclip: start must be less than end (start=%.4f, end=%.4f, duration=%.4f, file=%s, label=%s)", start, end, a.duration, safeFirstFilename(a.filenames), a.safeLabel())
+	}
+	out, err := a.audioFilter(fmt.Sprintf("atrim=start=%.6f:end=%.6f,asetpts=PTS-STARTPTS", start, end))
+	if err != nil {
+		return nil, fmt.Errorf("Subclip[file=%s, label=%s]: %w", safeFirstFilename(a.filenames), a.safeLabel(), err)
+	}
+	out.duration = end - start
+	return out, nil
+}
+
 // BassBoost boosts the bass frequencies.
 func (a *Audio) BassBoost(gain float64) (*Audio, error) {
 	return a.audioFilter(fmt.Sprintf("bass=g=%.4f", gain))
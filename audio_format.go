@@ -0,0 +1,73 @@
+package moviego
+
+import "fmt"
+
+const (
+	defaultSampleRate uint64 = 48000
+	defaultChannels   uint8  = 2
+)
+
+// channelLayouts maps a channel count to the FFmpeg channel layout name.
+var channelLayouts = map[uint8]string{
+	1: "mono",
+	2: "stereo",
+	3: "2.1",
+	4: "quad",
+	5: "5.0",
+	6: "5.1",
+	7: "6.1",
+	8: "7.1",
+}
+
+// audioFormat is the sample rate and channel count that audio branches are
+// converted to before they are concatenated or mixed together.
+type audioFormat struct {
+	sampleRate uint64
+	channels   uint8
+}
+
+// resolveAudioFormat picks the common format for a set of audio branches:
+// the highest sample rate and channel count among them, falling back to
+// 48 kHz stereo when the inputs carry no metadata.
+func resolveAudioFormat(audios ...*Audio) audioFormat {
+	var f audioFormat
+	for _, a := range audios {
+		if a.sampleRate > f.sampleRate {
+			f.sampleRate = a.sampleRate
+		}
+		if a.channels > f.channels {
+			f.channels = a.channels
+		}
+	}
+	if f.sampleRate == 0 {
+		f.sampleRate = defaultSampleRate
+	}
+	if _, ok := channelLayouts[f.channels]; !ok {
+		f.channels = defaultChannels
+	}
+	return f
+}
+
+// resolveVideosAudioFormat returns the common audio format for the audio tracks of videos.
+func resolveVideosAudioFormat(videos []Video) audioFormat {
+	audios := make([]*Audio, len(videos))
+	for i := range videos {
+		audios[i] = &videos[i].audio
+	}
+	return resolveAudioFormat(audios...)
+}
+
+// filter returns the aresample/aformat chain converting a branch to this format.
+func (f audioFormat) filter() string {
+	return fmt.Sprintf("aresample=%d,aformat=sample_fmts=fltp:sample_rates=%d:channel_layouts=%s",
+		f.sampleRate, f.sampleRate, channelLayouts[f.channels])
+}
+
+// normalized returns a copy of the audio whose last branch is converted to f.
+// The audio must already have at least one filter complex entry.
+func (a *Audio) normalized(f audioFormat) Audio {
+	out := a.chain(f.filter())
+	out.sampleRate = f.sampleRate
+	out.channels = f.channels
+	return out
+}
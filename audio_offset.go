@@ -0,0 +1,33 @@
+package moviego
+
+import (
+	"fmt"
+	"math"
+)
+
+// SetAudioOffset shifts the audio against the picture to fix out-of-sync
+// recordings: a positive offset plays the audio later, a negative one
+// earlier. The audio keeps the length of the video; the gap left by the
+// shift is silent.
+func (v *Video) SetAudioOffset(seconds float64) (*Video, error) {
+	if v.audioRemoved || !v.HasAudio() && len(v.audio.filterComplex) == 0 {
+		return nil, fmt.Errorf("SetAudioOffset: video has no audio (file=%s, label=%s)", safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	if math.Abs(seconds) >= v.duration {
+		return nil, fmt.Errorf("SetAudioOffset: offset must be shorter than the video (got=%.4f, duration=%.4f, file=%s, label=%s)", seconds, v.duration, safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	if seconds == 0 {
+		out := v.clone()
+		return &out, nil
+	}
+	var filter string
+	if seconds > 0 {
+		filter = fmt.Sprintf("adelay=delays=%d:all=1", int64(math.Round(seconds*1000)))
+	} else {
+		filter = fmt.Sprintf("atrim=start=%.6f,asetpts=PTS-STARTPTS", -seconds)
+	}
+	out := v.clone()
+	initRawVideo(&out)
+	out.audio = out.audio.chain(fmt.Sprintf("%s,apad,atrim=duration=%.6f", filter, v.duration))
+	return &out, nil
+}
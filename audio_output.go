@@ -0,0 +1,103 @@
+package moviego
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// audioOnlyExtensions are the output extensions WriteVideo writes as
+// audio-only files.
+var audioOnlyExtensions = map[string]bool{
+	".mp3": true, ".m4a": true, ".aac": true, ".wav": true,
+	".flac": true, ".opus": true, ".ogg": true,
+}
+
+// isAudioOnlyOutput reports whether outputPath names an audio-only container.
+func isAudioOnlyOutput(outputPath string) bool {
+	return audioOnlyExtensions[strings.ToLower(filepath.Ext(outputPath))]
+}
+
+// writeAudioOnly writes the audio of v for WriteVideo, to an audio-only
+// output. Video settings are rejected rather than silently ignored.
+func (v *Video) writeAudioOnly(parms VideoParameters) error {
+	file, label := safeFirstFilename(v.filenames), safeLastVideoLabel(v)
+	var videoOptions []string
+	for name, set := range map[string]bool{
+		"Codec":                 parms.Codec != "",
+		"Fps":                   parms.Fps != 0,
+		"Preset":                parms.Preset != "",
+		"Bitrate":               parms.Bitrate != "",
+		"PixelFormat":           parms.PixelFormat != "",
+		"WithMask":              parms.WithMask,
+		"StreamCopy":            parms.StreamCopy,
+		"Tune":                  parms.Tune != "",
+		"Keyframes":             parms.Keyframes.Interval != 0 || parms.Keyframes.MinInterval != 0 || len(parms.Keyframes.At) > 0 || parms.Keyframes.NoSceneCut,
+		"Delivery":              parms.Delivery != nil,
+		"Captions":              parms.Captions.Preserve || parms.Captions.SCC != "",
+		"FrameMetadata":         parms.FrameMetadata,
+		"OnLevels":              parms.OnLevels != nil,
+		"Sidecar":               parms.Sidecar,
+		"BitstreamFilters":      len(parms.BitstreamFilters) > 0,
+		"AudioBitstreamFilters": len(parms.AudioBitstreamFilters) > 0,
+		"EncoderOptions":        parms.EncoderOptions != (EncoderOptions{}),
+		"HLS":                   parms.HLS != (HLSOptions{}),
+		"CENC":                  parms.CENC != nil,
+		"AudioTracks":           len(parms.AudioTracks) > 0,
+		"VerifyDuration":        parms.VerifyDuration != nil,
+	} {
+		if set {
+			videoOptions = append(videoOptions, name)
+		}
+	}
+	if len(videoOptions) > 0 {
+		slices.Sort(videoOptions)
+		return fmt.Errorf("WriteVideo: %s cannot be used with the audio-only output %s (file=%s, label=%s)", strings.Join(videoOptions, ", "), parms.OutputPath, file, label)
+	}
+	bitrate, err := parseKbps(parms.AudioBitrate)
+	if err != nil {
+		return fmt.Errorf("WriteVideo: invalid AudioBitrate: %w (file=%s, label=%s)", err, file, label)
+	}
+	if target := parms.NormalizeLoudness; target != nil && !v.audioRemoved {
+		if v, err = v.normalizeLoudness("WriteVideo", *target, parms.Env); err != nil {
+			return err
+		}
+	}
+	err = v.WriteAudio(AudioParameters{
+		OutputPath:     parms.OutputPath,
+		Threads:        parms.Threads,
+		Codec:          parms.AudioCodec,
+		SampleRate:     parms.AudioSampleRate,
+		Channels:       parms.AudioChannels,
+		Bitrate:        bitrate,
+		SilentProgress: parms.SilentProgress,
+		OnProgress:     parms.OnProgress,
+		Env:            parms.Env,
+		StripMetadata:  parms.StripMetadata,
+	})
+	if err != nil {
+		return err
+	}
+	return checkAudioReport("WriteVideo", parms)
+}
+
+// parseKbps parses a bitrate such as "128k", "1.5M" or "96000" to kbit/s.
+func parseKbps(bitrate string) (uint64, error) {
+	if bitrate == "" {
+		return 0, nil
+	}
+	scale := 0.001
+	switch {
+	case strings.HasSuffix(bitrate, "k"), strings.HasSuffix(bitrate, "K"):
+		scale, bitrate = 1, bitrate[:len(bitrate)-1]
+	case strings.HasSuffix(bitrate, "M"):
+		scale, bitrate = 1000, bitrate[:len(bitrate)-1]
+	}
+	value, err := strconv.ParseFloat(bitrate, 64)
+	if err != nil || value <= 0 {
+		return 0, fmt.Errorf("bitrate must be a positive number with an optional k or M suffix (got=%s)", bitrate)
+	}
+	return uint64(value*scale + 0.5), nil
+}
@@ -0,0 +1,99 @@
+package moviego
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Peaks summarizes audio for waveform displays, like audiowaveform: every
+// pixel covers SamplesPerPixel mono samples and holds their minimum and
+// maximum, in [-1, 1].
+type Peaks struct {
+	SampleRate      uint64
+	SamplesPerPixel int
+	Min             []float64
+	Max             []float64
+}
+
+// PixelsPerSecond returns the horizontal resolution of the peaks.
+func (p *Peaks) PixelsPerSecond() float64 {
+	return float64(p.SampleRate) / float64(p.SamplesPerPixel)
+}
+
+// Peaks computes the min/max peaks of a for scrubber UIs, with filters
+// applied. The channels are averaged. The audio is decoded once and streamed
+// as 16-bit PCM, so memory only grows with the number of pixels.
+func (a *Audio) Peaks(samplesPerPixel int) (*Peaks, error) {
+	if samplesPerPixel <= 0 {
+		return nil, fmt.Errorf("Peaks: samplesPerPixel must be positive (got=%d, file=%s, label=%s)", samplesPerPixel, safeFirstFilename(a.filenames), a.safeLabel())
+	}
+	sampleRate := a.sampleRate
+	if sampleRate == 0 {
+		sampleRate = defaultSampleRate
+	}
+	peaks := &Peaks{SampleRate: sampleRate, SamplesPerPixel: samplesPerPixel}
+	var n int
+	var lo, hi float64
+	err := a.streamSamples("Peaks", sampleRate, func(s float64) {
+		if n == 0 || s < lo {
+			lo = s
+		}
+		if n == 0 || s > hi {
+			hi = s
+		}
+		if n++; n == samplesPerPixel {
+			peaks.Min, peaks.Max = append(peaks.Min, lo), append(peaks.Max, hi)
+			n = 0
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if n > 0 {
+		peaks.Min, peaks.Max = append(peaks.Min, lo), append(peaks.Max, hi)
+	}
+	return peaks, nil
+}
+
+// streamSamples decodes a as mono 16-bit PCM at sampleRate and passes every
+// sample, in [-1, 1], to fn as it arrives.
+func (a *Audio) streamSamples(op string, sampleRate uint64, fn func(float64)) error {
+	ffmpegPath, err := getFFmpegPath()
+	if err != nil {
+		return fmt.Errorf("%s: failed to get ffmpeg path: %w", op, err)
+	}
+	args, err := a.graphArgs()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	args = append(append([]string{"-hide_banner", "-nostats"}, args...),
+		"-vn", "-ac", "1", "-ar", strconv.FormatUint(sampleRate, 10), "-f", "s16le", "pipe:1")
+
+	stdout, stdoutWriter := io.Pipe()
+	var stderr bytes.Buffer
+	done := make(chan error, 1)
+	go func() {
+		err := runCommand(&Command{Path: ffmpegPath, Args: args, Stdout: stdoutWriter, Stderr: &stderr})
+		stdoutWriter.Close()
+		done <- err
+	}()
+
+	reader := bufio.NewReaderSize(stdout, 64*1024)
+	var sample [2]byte
+	for {
+		if _, err := io.ReadFull(reader, sample[:]); err != nil {
+			break
+		}
+		fn(float64(int16(binary.LittleEndian.Uint16(sample[:]))) / 32768)
+	}
+	io.Copy(io.Discard, stdout)
+	if err := <-done; err != nil {
+		return fmt.Errorf("%s: failed to execute ffmpeg: %w\nffmpeg stderr: %s", op, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package moviego
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// AudioReport summarizes the levels of a finished mix, e.g. for automated
+// pipelines to reject clipped or badly leveled renders.
+type AudioReport struct {
+	Peak          float64 // sample peak in dBFS
+	TruePeak      float64 // true (inter-sample) peak in dBFS
+	RMS           float64 // RMS level in dBFS
+	Integrated    float64 // integrated loudness in LUFS
+	LoudnessRange float64 // loudness range in LU
+	// ClippedSamples is the number of samples per channel at full scale
+	// (averaged over the channels).
+	ClippedSamples int64
+}
+
+// Clipped reports whether any sample reached full scale.
+func (r AudioReport) Clipped() bool {
+	return r.ClippedSamples > 0
+}
+
+// reportFilter measures loudness with ebur128 (which needs the float
+// samples for its true peak), then sample levels with astats on 16-bit
+// samples, where clipped samples saturate at full scale.
+const reportFilter = "ebur128=peak=true:framelog=verbose,aformat=sample_fmts=s16,astats"
+
+// Report measures the levels of a, with filters applied, in one pass of
+// FFmpeg's ebur128 and astats.
+func (a *Audio) Report() (AudioReport, error) {
+	measured := *a
+	initRawAudio(&measured)
+	measured = measured.chain(reportFilter)
+	args, err := measured.graphArgs()
+	if err != nil {
+		return AudioReport{}, fmt.Errorf("Audio.Report: %w", err)
+	}
+	return runAudioReport("Audio.Report", args, nil)
+}
+
+// AudioReport measures the levels of the audio of the video, see
+// Audio.Report.
+func (v *Video) AudioReport() (AudioReport, error) {
+	if v.audioRemoved || !v.HasAudio() && len(v.audio.filterComplex) == 0 {
+		return AudioReport{}, fmt.Errorf("AudioReport: video has no audio (file=%s, label=%s)", safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	out := v.clone()
+	initRawVideo(&out)
+	return out.audio.Report()
+}
+
+// reportOutput measures the first audio stream of a rendered file, for
+// VideoParameters.OnAudioReport.
+func reportOutput(op, output string, env *RenderEnv) (AudioReport, error) {
+	return runAudioReport(op, []string{"-i", output, "-map", "0:a:0", "-af", reportFilter}, env)
+}
+
+// checkAudioReport measures the output for parms.OnAudioReport and passes
+// on its verdict.
+func checkAudioReport(op string, parms VideoParameters) error {
+	if parms.OnAudioReport == nil {
+		return nil
+	}
+	report, err := reportOutput(op, parms.OutputPath, parms.Env)
+	if err != nil {
+		return err
+	}
+	if err := parms.OnAudioReport(report); err != nil {
+		return fmt.Errorf("%s: audio report of %s: %w", op, parms.OutputPath, err)
+	}
+	return nil
+}
+
+// runAudioReport runs args, which read the audio through reportFilter, to
+// the null muxer and parses the measurements FFmpeg logs at the end.
+func runAudioReport(op string, args []string, env *RenderEnv) (AudioReport, error) {
+	ffmpegPath, err := getFFmpegPath()
+	if err != nil {
+		return AudioReport{}, fmt.Errorf("%s: failed to get ffmpeg path: %w", op, err)
+	}
+	args = append(append([]string{"-hide_banner", "-nostats"}, args...), "-vn", "-f", "null", "-")
+	cmd, err := env.command(ffmpegPath, args...)
+	if err != nil {
+		return AudioReport{}, fmt.Errorf("%s: invalid render environment: %w", op, err)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := runCommand(cmd); err != nil {
+		return AudioReport{}, fmt.Errorf("%s: failed to execute ffmpeg: %w\nffmpeg stderr: %s", op, err, strings.TrimSpace(stderr.String()))
+	}
+	return parseAudioReport(stderr.String())
+}
+
+// parseAudioReport reads the ebur128 summary and the overall astats
+// section from an FFmpeg log.
+func parseAudioReport(log string) (AudioReport, error) {
+	var r AudioReport
+	var peakCount float64
+	found := map[string]bool{}
+	summary, overall := false, false
+	scanner := bufio.NewScanner(strings.NewReader(log))
+	for scanner.Scan() {
+		line := scanner.Text()
+		// astats lines are prefixed with the filter instance.
+		if _, rest, ok := strings.Cut(line, "] "); ok && strings.Contains(line, "astats") {
+			line = rest
+		}
+		line = strings.TrimSpace(line)
+		switch {
+		case strings.HasSuffix(line, "Summary:"):
+			summary = true
+			continue
+		case line == "Overall":
+			overall = true
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		value, _, _ = strings.Cut(value, " ") // drop the unit
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			continue
+		}
+		switch {
+		case summary && key == "I":
+			r.Integrated = f
+		case summary && key == "LRA":
+			r.LoudnessRange = f
+		case summary && key == "Peak":
+			r.TruePeak = f
+		case overall && key == "Peak level dB":
+			r.Peak = f
+		case overall && key == "RMS level dB":
+			r.RMS = f
+		case overall && key == "Peak count":
+			peakCount = f
+		default:
+			continue
+		}
+		found[key] = true
+	}
+	for _, key := range []string{"I", "Peak", "Peak level dB", "RMS level dB"} {
+		if !found[key] {
+			return AudioReport{}, fmt.Errorf("audio report: %q not found in FFmpeg output", key)
+		}
+	}
+	// 16-bit full scale is -0.0003 dBFS for positive samples.
+	if r.Peak > -0.001 {
+		r.ClippedSamples = int64(math.Round(peakCount))
+	}
+	return r, nil
+}
@@ -0,0 +1,56 @@
+package moviego
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// GetAudioSamples returns the audio between start and end seconds as mono
+// samples in [-1, 1] with the edit applied (cuts, mixes, filters), and
+// their sample rate, e.g. for voice activity detection or ML features. The
+// channels are averaged. The samples are piped out of FFmpeg as 64-bit PCM,
+// so nothing is written to disk.
+func (v *Video) GetAudioSamples(start, end float64) ([]float64, uint64, error) {
+	if v.audioRemoved || (!v.HasAudio() && len(v.audio.filterComplex) == 0) {
+		return nil, 0, fmt.Errorf("GetAudioSamples: video has no audio (file=%s, label=%s)", safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	if start < 0 || end > v.duration || start >= end {
+		return nil, 0, fmt.Errorf("GetAudioSamples: range must be within [0, %.4f] (start=%.4f, end=%.4f, file=%s, label=%s)", v.duration, start, end, safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	ffmpegPath, err := getFFmpegPath()
+	if err != nil {
+		return nil, 0, fmt.Errorf("GetAudioSamples: failed to get ffmpeg path: %w", err)
+	}
+	cut, err := v.Cut(start, end)
+	if err != nil {
+		return nil, 0, fmt.Errorf("GetAudioSamples: %w", err)
+	}
+	out := cut.clone()
+	initRawVideo(&out)
+	sampleRate := out.audio.sampleRate
+	if sampleRate == 0 {
+		sampleRate = defaultSampleRate
+	}
+
+	args, err := out.audio.graphArgs()
+	if err != nil {
+		return nil, 0, fmt.Errorf("GetAudioSamples: %w", err)
+	}
+	args = append(append([]string{"-hide_banner", "-nostats"}, args...),
+		"-vn", "-ac", "1", "-ar", strconv.FormatUint(sampleRate, 10), "-f", "f64le", "pipe:1")
+	var stdout, stderr bytes.Buffer
+	if err := runCommand(&Command{Path: ffmpegPath, Args: args, Stdout: &stdout, Stderr: &stderr}); err != nil {
+		return nil, 0, fmt.Errorf("GetAudioSamples: failed to execute ffmpeg: %w\nffmpeg stderr: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	data := stdout.Bytes()
+	samples := make([]float64, len(data)/8)
+	for i := range samples {
+		samples[i] = math.Float64frombits(binary.LittleEndian.Uint64(data[8*i:]))
+	}
+	return samples, sampleRate, nil
+}
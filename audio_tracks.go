@@ -0,0 +1,66 @@
+package moviego
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// AudioTrack is an additional audio track of an output, such as a dub or a
+// commentary, selectable in players next to the main audio.
+type AudioTrack struct {
+	Audio    *Audio
+	Language string // ISO 639-2 code, e.g. "fra" (optional)
+	Title    string // track name shown by players, e.g. "Commentary" (optional)
+}
+
+// audioTrackArgs adds the main audio metadata and the extra tracks of parms
+// to args. Each extra track is first rendered to a lossless intermediate,
+// then encoded with the same audio settings as the main audio. mainAudio
+// tells whether the output has an audio stream of its own. The returned
+// cleanup removes the intermediates.
+func audioTrackArgs(args []string, parms VideoParameters, mainAudio bool) ([]string, func(), error) {
+	stream := 0
+	if mainAudio {
+		args = append(args, trackMetadataArgs(stream, parms.AudioLanguage, parms.AudioTitle)...)
+		stream++
+	}
+	if len(parms.AudioTracks) == 0 {
+		return args, func() {}, nil
+	}
+	tmp, err := os.MkdirTemp(parms.Env.tempDir(), "moviego-tracks-*")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() { os.RemoveAll(tmp) }
+	for i, track := range parms.AudioTracks {
+		if track.Audio == nil {
+			cleanup()
+			return nil, nil, fmt.Errorf("audio track %d is nil", i)
+		}
+		mix := filepath.Join(tmp, fmt.Sprintf("track%d.wav", i))
+		err := track.Audio.Write(AudioParameters{OutputPath: mix, Codec: AudioCodecPCM, Env: parms.Env, SilentProgress: true})
+		if err != nil {
+			cleanup()
+			return nil, nil, fmt.Errorf("audio track %d: %w", i, err)
+		}
+		var index int
+		args, index = insertInput(args, "-i", mix)
+		args = append(args, "-map", fmt.Sprintf("%d:a:0", index))
+		args = append(args, trackMetadataArgs(stream, track.Language, track.Title)...)
+		stream++
+	}
+	return args, cleanup, nil
+}
+
+// trackMetadataArgs tags the nth audio stream of the output.
+func trackMetadataArgs(n int, language, title string) []string {
+	var args []string
+	if language != "" {
+		args = append(args, fmt.Sprintf("-metadata:s:a:%d", n), "language="+language)
+	}
+	if title != "" {
+		args = append(args, fmt.Sprintf("-metadata:s:a:%d", n), "title="+title)
+	}
+	return args
+}
@@ -1,16 +1,9 @@
 package moviego
 
 import (
-	"bufio"
-	"bytes"
 	"fmt"
-	"math"
-	"os/exec"
 	"path/filepath"
-	"runtime"
-	"strconv"
 	"strings"
-	"time"
 )
 
 // Write processes the audio with applied filters and writes to output file
@@ -19,7 +12,7 @@ func (a *Audio) Write(parms AudioParameters) error {
 		return fmt.Errorf("WriteAudio: output path is empty, cannot write audio")
 	}
 
-	if len(a.filenames) == 0 {
+	if len(a.filenames) == 0 && len(a.filterComplex) == 0 {
 		return fmt.Errorf("WriteAudio: audio filename is empty, cannot process audio (file=<none>)")
 	}
 
@@ -28,8 +21,74 @@ func (a *Audio) Write(parms AudioParameters) error {
 		return fmt.Errorf("WriteAudio: failed to get ffmpeg path: %w", err)
 	}
 
+	ffmpegArgs, err := a.graphArgs()
+	if err != nil {
+		return err
+	}
+
+	// Threads
+	effectiveThreads := parms.Threads
+	if effectiveThreads == 0 {
+		effectiveThreads = defaultThreads()
+	}
+	effectiveThreads = parms.Env.threads(effectiveThreads)
+	ffmpegArgs = append(ffmpegArgs, "-threads", fmt.Sprintf("%d", effectiveThreads))
+
+	// Audio parameters
+	codec := parms.Codec
+	if codec == "" {
+		codec = defaultAudioCodecs[strings.ToLower(filepath.Ext(parms.OutputPath))]
+	}
+	if codec != "" {
+		ffmpegArgs = append(ffmpegArgs, "-c:a", string(codec))
+	}
+
+	if parms.SampleRate > 0 {
+		ffmpegArgs = append(ffmpegArgs, "-ar", fmt.Sprintf("%d", parms.SampleRate))
+	}
+	if parms.Channels > 0 {
+		ffmpegArgs = append(ffmpegArgs, "-ac", fmt.Sprintf("%d", parms.Channels))
+	}
+	if parms.Bitrate > 0 {
+		ffmpegArgs = append(ffmpegArgs, "-b:a", fmt.Sprintf("%dk", parms.Bitrate))
+	}
+
+	outputArgs := []string{"-vn"}
+	if parms.StripMetadata {
+		outputArgs = append(outputArgs, stripMetadataArgs...)
+	}
+	job := renderJob{
+		op:         "WriteAudio",
+		args:       ffmpegArgs,
+		outputArgs: outputArgs,
+		output:     parms.OutputPath,
+		duration:   a.duration,
+		env:        parms.Env,
+		onProgress: parms.OnProgress,
+		silent:     parms.SilentProgress,
+	}
+	return job.run(ffmpegPath)
+}
+
+// defaultAudioCodecs maps output extensions to the codec used when
+// AudioParameters.Codec is empty. Other extensions use FFmpeg's default.
+var defaultAudioCodecs = map[string]AudioCodec{
+	".mp3":  AudioCodecMP3,
+	".aac":  AudioCodecAAC,
+	".m4a":  AudioCodecAAC,
+	".mp4":  AudioCodecAAC,
+	".mov":  AudioCodecAAC,
+	".wav":  AudioCodecPCM,
+	".flac": AudioCodecFLAC,
+	".opus": AudioCodecOpus,
+	".ogg":  AudioCodecVorbis,
+}
+
+// graphArgs returns the inputs, filter graph and output map rendering a.
+func (a *Audio) graphArgs() ([]string, error) {
 	ffmpegArgs := []string{}
 	for _, filename := range a.filenames {
+		ffmpegArgs = append(ffmpegArgs, inputArgs(filename, a.filterComplex)...)
 		ffmpegArgs = append(ffmpegArgs, "-i", filename)
 	}
 
@@ -68,135 +127,7 @@ func (a *Audio) Write(parms AudioParameters) error {
 	} else if audioLabel != "" {
 		ffmpegArgs = append(ffmpegArgs, "-filter_complex", filterComplex, "-map", fmt.Sprintf("[%s]", audioLabel))
 	} else {
-		return fmt.Errorf("WriteAudio: no audio stream to map (file=%s)", safeFirstFilename(a.filenames))
-	}
-
-	// Threads
-	effectiveThreads := parms.Threads
-	if effectiveThreads == 0 {
-		totalCPUs := runtime.GOMAXPROCS(0)
-		if totalCPUs <= 2 {
-			effectiveThreads = uint16(totalCPUs)
-		} else {
-			effectiveThreads = uint16((totalCPUs * 6) / 10)
-			if effectiveThreads < 2 {
-				effectiveThreads = 2
-			}
-		}
-	}
-	ffmpegArgs = append(ffmpegArgs, "-threads", fmt.Sprintf("%d", effectiveThreads))
-
-	// Audio parameters
-	if parms.Codec != "" {
-		ffmpegArgs = append(ffmpegArgs, "-c:a", string(parms.Codec))
-	} else {
-		// Default to aac for common extensions
-		ext := strings.ToLower(filepath.Ext(parms.OutputPath))
-		if ext == ".mp4" || ext == ".m4a" || ext == ".mov" {
-			ffmpegArgs = append(ffmpegArgs, "-c:a", "aac")
-		}
+		return nil, fmt.Errorf("WriteAudio: no audio stream to map (file=%s)", safeFirstFilename(a.filenames))
 	}
-
-	if parms.SampleRate > 0 {
-		ffmpegArgs = append(ffmpegArgs, "-ar", fmt.Sprintf("%d", parms.SampleRate))
-	}
-	if parms.Channels > 0 {
-		ffmpegArgs = append(ffmpegArgs, "-ac", fmt.Sprintf("%d", parms.Channels))
-	}
-	if parms.Bitrate > 0 {
-		ffmpegArgs = append(ffmpegArgs, "-b:a", fmt.Sprintf("%dk", parms.Bitrate))
-	}
-
-	progressEnabled := parms.OnProgress != nil || !parms.SilentProgress
-	if progressEnabled {
-		ffmpegArgs = append(ffmpegArgs, "-progress", "pipe:1", "-nostats")
-	}
-
-	ffmpegArgs = append(ffmpegArgs, "-vn", "-y", parms.OutputPath)
-
-	cmd := exec.Command(ffmpegPath, ffmpegArgs...)
-	var stderrBuf bytes.Buffer
-	cmd.Stderr = &stderrBuf
-
-	displayProgram := filepath.Base(ffmpegPath)
-	displayProgram = strings.TrimSuffix(displayProgram, filepath.Ext(displayProgram))
-	displayCmd := &exec.Cmd{Args: append([]string{displayProgram}, ffmpegArgs...)}
-
-	fmt.Println(formatCmd(displayCmd))
-
-	if progressEnabled {
-		handler := parms.OnProgress
-		if handler == nil {
-			handler = defaultProgressHandler(parms.OutputPath)
-		}
-		return a.runAudioWithProgress(cmd, &stderrBuf, handler)
-	}
-
-	if err := cmd.Run(); err != nil {
-		stderr := strings.TrimSpace(stderrBuf.String())
-		if stderr != "" {
-			return fmt.Errorf("WriteAudio: failed to execute ffmpeg: %w\nffmpeg stderr: %s", err, stderr)
-		}
-		return fmt.Errorf("WriteAudio: failed to execute ffmpeg: %w", err)
-	}
-
-	return nil
-}
-
-func (a *Audio) runAudioWithProgress(cmd *exec.Cmd, stderrBuf *bytes.Buffer, onProgress func(Progress)) error {
-	stdoutPipe, err := cmd.StdoutPipe()
-	if err != nil {
-		return fmt.Errorf("WriteAudio: failed to create stdout pipe: %w", err)
-	}
-
-	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("WriteAudio: failed to start ffmpeg: %w", err)
-	}
-
-	startTime := time.Now()
-	totalDuration := a.duration
-	scanner := bufio.NewScanner(stdoutPipe)
-	cur := Progress{TotalDuration: totalDuration}
-
-	for scanner.Scan() {
-		line := scanner.Text()
-		key, value, ok := strings.Cut(line, "=")
-		if !ok {
-			continue
-		}
-
-		switch key {
-		case "out_time_us":
-			us, _ := strconv.ParseInt(value, 10, 64)
-			cur.OutTime = float64(us) / 1_000_000
-			if totalDuration > 0 {
-				cur.Percentage = math.Min((cur.OutTime/totalDuration)*100, 100)
-			}
-		case "speed":
-			s := strings.TrimSuffix(strings.TrimSpace(value), "x")
-			cur.Speed, _ = strconv.ParseFloat(s, 64)
-		case "progress":
-			cur.Done = value == "end"
-			if cur.Done {
-				cur.Percentage = 100
-			}
-			cur.ElapsedSeconds = time.Since(startTime).Seconds()
-			if cur.Percentage > 0 && cur.Percentage < 100 {
-				cur.ExpectedTotalSeconds = cur.ElapsedSeconds / (cur.Percentage / 100)
-			} else if cur.Done {
-				cur.ExpectedTotalSeconds = cur.ElapsedSeconds
-			}
-			onProgress(cur)
-		}
-	}
-
-	if err := cmd.Wait(); err != nil {
-		stderr := strings.TrimSpace(stderrBuf.String())
-		if stderr != "" {
-			return fmt.Errorf("WriteAudio: failed to execute ffmpeg: %w\nffmpeg stderr: %s", err, stderr)
-		}
-		return fmt.Errorf("WriteAudio: failed to execute ffmpeg: %w", err)
-	}
-
-	return nil
+	return ffmpegArgs, nil
 }
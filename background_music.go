@@ -0,0 +1,53 @@
+package moviego
+
+import "fmt"
+
+// AddBackgroundMusic mixes the music file at path under the video's audio at
+// the given volume (1.0 = unchanged). The music is looped, or cut, to the
+// duration of the video, so it does not need to be prepared to length.
+func (v *Video) AddBackgroundMusic(path string, volume float64) (*Video, error) {
+	if volume < 0 {
+		return nil, fmt.Errorf("AddBackgroundMusic: volume must be >= 0 (got=%s, file=%s, label=%s)", formatFloat(volume), safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	if v.duration <= 0 {
+		return nil, fmt.Errorf("AddBackgroundMusic: video has no duration (got=%s, file=%s, label=%s)", formatFloat(v.duration), safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	music, err := NewAudioFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("AddBackgroundMusic: %w (file=%s, label=%s)", err, safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+
+	// A video without audio gets the music over silence, so the mix still has
+	// a first input lasting the video.
+	silent := v.audioRemoved || !v.HasAudio() && len(v.audio.filterComplex) == 0
+	out := v.clone()
+	initRawVideo(&out)
+	if silent {
+		label := fmt.Sprintf("bgsilence_%d", incrementGlobalCounter())
+		out.audio = silentAudio(incrementOrderCounter(), label, v.duration)
+	}
+	format := resolveAudioFormat(&out.audio, music)
+	dialog := out.audio.normalized(format)
+
+	initRawAudio(music)
+	// The input is read in a loop by FFmpeg, so no samples are buffered.
+	music.filterComplex[0].FileCopy.InputArgs = []string{"-stream_loop", "-1"}
+	m := music.normalized(format)
+	m = m.chain(fmt.Sprintf("atrim=duration=%s,asetpts=PTS-STARTPTS,volume=%.4f", formatFloat(v.duration), volume))
+
+	filenames := mergeFilenames(dialog.filenames, m.filenames)
+
+	label := fmt.Sprintf("bgmusic_%d_a", incrementGlobalCounter())
+	audioFilterComplex := append(append([]FilterComplex{}, dialog.filterComplex...), m.filterComplex...)
+	audioFilterComplex = append(audioFilterComplex, FilterComplex{
+		Order:         incrementOrderCounter(),
+		Label:         label,
+		FilterElement: fmt.Sprintf("[%s][%s]amix=inputs=2:duration=first:normalize=0", dialog.lastAudioLabel(), m.lastAudioLabel()),
+	})
+
+	out.audio = dialog
+	out.audio.filenames = filenames
+	out.audio.filterComplex = audioFilterComplex
+	out.audioRemoved = false
+	return &out, nil
+}
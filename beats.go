@@ -0,0 +1,117 @@
+package moviego
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	beatSampleRate = 11025 // plenty for onsets, and cheap to decode
+	beatHop        = 256   // samples per onset frame (~23 ms)
+	beatWindow     = 4     // hops per energy window (~93 ms)
+	beatMinGap     = 0.2   // seconds between beats (300 BPM)
+	beatAverage    = 0.5   // seconds on each side of the adaptive threshold
+	beatSilence    = -60.0 // dBFS below which no beat is detected
+)
+
+// DetectBeats returns the onset times, in seconds, of the audio file at
+// audioPath, e.g. to cut a slideshow on the beat (see ConcatenateOnBeats).
+func DetectBeats(audioPath string) ([]float64, error) {
+	a, err := NewAudioFile(audioPath)
+	if err != nil {
+		return nil, fmt.Errorf("DetectBeats: %w", err)
+	}
+	return a.DetectBeats()
+}
+
+// DetectBeats returns the onset times of the audio, in seconds, with filters
+// applied. Onsets are sudden rises of the energy, found with an adaptive
+// threshold, so both drums and note attacks count.
+func (a *Audio) DetectBeats() ([]float64, error) {
+	// Mean square of every hop.
+	var energies []float64
+	var sum float64
+	var n int
+	err := a.streamSamples("DetectBeats", beatSampleRate, func(s float64) {
+		sum += s * s
+		if n++; n == beatHop {
+			energies = append(energies, sum/beatHop)
+			sum, n = 0, 0
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pickOnsets(energies), nil
+}
+
+// pickOnsets finds the onsets in the energies of consecutive hops.
+func pickOnsets(energies []float64) []float64 {
+	// Onset strength: rise of the windowed log energy.
+	level := make([]float64, len(energies))
+	flux := make([]float64, len(energies))
+	var window float64
+	for i, e := range energies {
+		window += e
+		if i >= beatWindow {
+			window -= energies[i-beatWindow]
+		}
+		level[i] = 10 * math.Log10(window/beatWindow+1e-12)
+		if i > 0 {
+			flux[i] = math.Max(level[i]-level[i-1], 0)
+		}
+	}
+
+	hopTime := float64(beatHop) / beatSampleRate
+	span := int(beatAverage / hopTime)
+	var beats []float64
+	last := math.Inf(-1)
+	for i := 1; i < len(flux)-1; i++ {
+		if level[i] < beatSilence || flux[i] < flux[i-1] || flux[i] < flux[i+1] {
+			continue
+		}
+		lo, hi := max(i-span, 0), min(i+span+1, len(flux))
+		var mean float64
+		for _, f := range flux[lo:hi] {
+			mean += f
+		}
+		mean /= float64(hi - lo)
+		t := float64(i-beatWindow/2) * hopTime
+		if flux[i] > 1.5*mean+0.5 && t-last >= beatMinGap {
+			beats = append(beats, math.Max(t, 0))
+			last = t
+		}
+	}
+	return beats
+}
+
+// ConcatenateOnBeats joins clips back to back, cutting each one on the next
+// beat: clip 0 plays until beats[0], clip 1 until beats[1], and so on. Every
+// clip must last at least as long as its slot; images and colors can be
+// given a long duration and are cut to fit. Extra beats are ignored.
+func ConcatenateOnBeats(beats []float64, clips ...Clip) (*Video, error) {
+	if len(clips) == 0 || len(beats) < len(clips) {
+		return nil, fmt.Errorf("ConcatenateOnBeats: need a beat per clip (clips=%d, beats=%d)", len(clips), len(beats))
+	}
+	videos, err := clipsToVideos("ConcatenateOnBeats", clips)
+	if err != nil {
+		return nil, err
+	}
+	start := 0.0
+	for i := range videos {
+		slot := beats[i] - start
+		if slot <= 0 {
+			return nil, fmt.Errorf("ConcatenateOnBeats: beats must be increasing and positive (index=%d, beat=%.4f)", i, beats[i])
+		}
+		if videos[i].duration < slot {
+			return nil, fmt.Errorf("ConcatenateOnBeats: clip %d is shorter than its slot (duration=%.4f, slot=%.4f, file=%s)", i, videos[i].duration, slot, safeFirstFilename(videos[i].filenames))
+		}
+		cut, err := videos[i].Cut(0, slot)
+		if err != nil {
+			return nil, fmt.Errorf("ConcatenateOnBeats: clip %d: %w", i, err)
+		}
+		videos[i] = *cut
+		start = beats[i]
+	}
+	return Concatenate(videos)
+}
@@ -0,0 +1,258 @@
+// Package bench measures how fast this machine runs MovieGo's FFmpeg workloads.
+//
+// Every workload renders a synthetic testsrc2 source, so runs are reproducible
+// and need no media files. The resulting Report recommends the FFmpeg thread
+// count for this machine and can be applied with Report.Apply, replacing the
+// default 60% CPU heuristic.
+package bench
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"time"
+
+	moviego "github.com/YounesseAmhend/MovieGo"
+)
+
+// Workload is a single FFmpeg job run against the synthetic source.
+type Workload struct {
+	Name string
+	// Args are the FFmpeg arguments placed between the input and the output.
+	Args []string
+	// Raw writes rawvideo to a pipe and measures its throughput instead of
+	// discarding the output inside FFmpeg.
+	Raw bool
+}
+
+const (
+	WorkloadRawPipe     = "raw-pipe"
+	WorkloadFilterGraph = "filter-graph"
+	WorkloadEncode      = "encode"
+)
+
+// DefaultWorkloads returns the raw-frame pipe, filter-graph and encoder workloads.
+func DefaultWorkloads() []Workload {
+	return []Workload{
+		{
+			Name: WorkloadRawPipe,
+			Args: []string{"-pix_fmt", "rgba", "-f", "rawvideo"},
+			Raw:  true,
+		},
+		{
+			Name: WorkloadFilterGraph,
+			Args: []string{
+				"-filter_complex", "[0:v]split[a][b];[b]scale=iw/2:ih/2,boxblur=4[s];[a][s]overlay=x=10:y=10,eq=saturation=1.2",
+				"-f", "null",
+			},
+		},
+		{
+			Name: WorkloadEncode,
+			Args: []string{"-c:v", "libx264", "-preset", "medium", "-pix_fmt", "yuv420p", "-f", "null"},
+		},
+	}
+}
+
+// Options configures a benchmark run. Zero fields use the defaults listed below.
+type Options struct {
+	Width        uint64     // default: 1920
+	Height       uint64     // default: 1080
+	Fps          uint64     // default: 30
+	Duration     float64    // seconds of synthetic video per run (default: 5)
+	ThreadCounts []uint16   // default: powers of two up to the CPU count, plus the CPU count
+	Workloads    []Workload // default: DefaultWorkloads()
+}
+
+func (o Options) withDefaults() Options {
+	if o.Width == 0 {
+		o.Width = 1920
+	}
+	if o.Height == 0 {
+		o.Height = 1080
+	}
+	if o.Fps == 0 {
+		o.Fps = 30
+	}
+	if o.Duration == 0 {
+		o.Duration = 5
+	}
+	if len(o.ThreadCounts) == 0 {
+		o.ThreadCounts = defaultThreadCounts(runtime.NumCPU())
+	}
+	if len(o.Workloads) == 0 {
+		o.Workloads = DefaultWorkloads()
+	}
+	return o
+}
+
+// defaultThreadCounts returns 1, 2, 4, ... below cpus, followed by cpus.
+func defaultThreadCounts(cpus int) []uint16 {
+	var counts []uint16
+	for n := 1; n < cpus; n *= 2 {
+		counts = append(counts, uint16(n))
+	}
+	return append(counts, uint16(cpus))
+}
+
+// Result is the measurement of one workload at one thread count.
+type Result struct {
+	Workload    string        `json:"workload"`
+	Threads     uint16        `json:"threads"`
+	Frames      uint64        `json:"frames"`
+	Elapsed     time.Duration `json:"elapsed"`
+	FPS         float64       `json:"fps"`
+	BytesPerSec float64       `json:"bytes_per_sec,omitempty"` // raw workloads only
+}
+
+// Report collects the results of a run together with the machine it ran on.
+type Report struct {
+	CPUs      int       `json:"cpus"`
+	OS        string    `json:"os"`
+	Arch      string    `json:"arch"`
+	Width     uint64    `json:"width"`
+	Height    uint64    `json:"height"`
+	Fps       uint64    `json:"fps"`
+	CreatedAt time.Time `json:"created_at"`
+	Results   []Result  `json:"results"`
+}
+
+// Run executes every workload at every thread count and returns the report.
+func Run(opts Options) (*Report, error) {
+	opts = opts.withDefaults()
+	if opts.Duration < 0 {
+		return nil, fmt.Errorf("bench.Run: duration must be positive (got=%.4f)", opts.Duration)
+	}
+	ffmpegPath, err := moviego.FFmpegPath()
+	if err != nil {
+		return nil, fmt.Errorf("bench.Run: %w", err)
+	}
+
+	report := &Report{
+		CPUs:      runtime.NumCPU(),
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		Width:     opts.Width,
+		Height:    opts.Height,
+		Fps:       opts.Fps,
+		CreatedAt: time.Now(),
+	}
+	frames := uint64(float64(opts.Fps) * opts.Duration)
+	source := fmt.Sprintf("testsrc2=size=%dx%d:rate=%d:duration=%.4f", opts.Width, opts.Height, opts.Fps, opts.Duration)
+
+	for _, w := range opts.Workloads {
+		for _, threads := range opts.ThreadCounts {
+			res, err := runWorkload(ffmpegPath, source, w, threads)
+			if err != nil {
+				return nil, fmt.Errorf("bench.Run: workload %s (threads=%d): %w", w.Name, threads, err)
+			}
+			res.Frames = frames
+			res.FPS = float64(frames) / res.Elapsed.Seconds()
+			report.Results = append(report.Results, res)
+		}
+	}
+	return report, nil
+}
+
+func runWorkload(ffmpegPath, source string, w Workload, threads uint16) (Result, error) {
+	args := []string{"-hide_banner", "-v", "error", "-f", "lavfi", "-i", source, "-threads", strconv.Itoa(int(threads))}
+	args = append(args, w.Args...)
+	if w.Raw {
+		args = append(args, "pipe:1")
+	} else {
+		args = append(args, "-")
+	}
+
+	res := Result{Workload: w.Name, Threads: threads}
+	var output bytes.Buffer
+	cmd := &moviego.Command{Path: ffmpegPath, Args: args, Stdout: &output, Stderr: &output}
+	counter := &countingWriter{}
+	if w.Raw {
+		cmd.Stdout = counter
+	}
+	start := time.Now()
+	if err := moviego.RunCommand(cmd); err != nil {
+		return res, fmt.Errorf("ffmpeg failed: %w\n%s", err, output.Bytes())
+	}
+	res.Elapsed = time.Since(start)
+	if w.Raw {
+		res.BytesPerSec = float64(counter.n) / res.Elapsed.Seconds()
+	}
+	return res, nil
+}
+
+// countingWriter discards raw frames, counting their bytes.
+type countingWriter struct {
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	c.n += int64(len(p))
+	return len(p), nil
+}
+
+// BestThreads returns the smallest thread count reaching 95% of the best
+// throughput measured for a workload, or 0 when the workload was not run.
+// Extra threads past that point only take CPU away from other work.
+func (r *Report) BestThreads(workload string) uint16 {
+	var peak float64
+	for _, res := range r.Results {
+		if res.Workload == workload && res.FPS > peak {
+			peak = res.FPS
+		}
+	}
+	var best uint16
+	for _, res := range r.Results {
+		if res.Workload != workload || res.FPS < peak*0.95 {
+			continue
+		}
+		if best == 0 || res.Threads < best {
+			best = res.Threads
+		}
+	}
+	return best
+}
+
+// RecommendedThreads returns the FFmpeg thread count for renders on this
+// machine: the best count for the heaviest workload that was measured.
+func (r *Report) RecommendedThreads() uint16 {
+	for _, name := range []string{WorkloadEncode, WorkloadFilterGraph, WorkloadRawPipe} {
+		if n := r.BestThreads(name); n > 0 {
+			return n
+		}
+	}
+	return 0
+}
+
+// Apply makes the recommended thread count the default for renders that do
+// not set Threads (see moviego.SetDefaultThreads).
+func (r *Report) Apply() {
+	moviego.SetDefaultThreads(r.RecommendedThreads())
+}
+
+// Save writes the report as JSON.
+func (r *Report) Save(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Report.Save: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("Report.Save: failed to write '%s': %w", path, err)
+	}
+	return nil
+}
+
+// LoadReport reads a report written by Save.
+func LoadReport(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("LoadReport: failed to read '%s': %w", path, err)
+	}
+	var r Report
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("LoadReport: failed to parse '%s': %w", path, err)
+	}
+	return &r, nil
+}
@@ -0,0 +1,43 @@
+package moviego
+
+import "strings"
+
+// BitstreamFilter is an FFmpeg bitstream filter. Bitstream filters rewrite
+// the encoded packets without decoding them, so they also work with
+// StreamCopy.
+type BitstreamFilter string
+
+const (
+	// BSFH264AnnexB converts H.264 from the length-prefixed MP4 layout to
+	// Annex B start codes, for raw .h264 files and MPEG-TS muxers.
+	BSFH264AnnexB BitstreamFilter = "h264_mp4toannexb"
+	// BSFHEVCAnnexB is BSFH264AnnexB for HEVC.
+	BSFHEVCAnnexB BitstreamFilter = "hevc_mp4toannexb"
+	// BSFExtractExtradata copies the parameter sets (SPS/PPS) into the
+	// side data of the packets, for decoders joining mid-stream.
+	BSFExtractExtradata BitstreamFilter = "extract_extradata"
+	// BSFAACADTSToASC converts ADTS AAC, as carried in MPEG-TS and HLS, to
+	// the AudioSpecificConfig MP4 expects.
+	BSFAACADTSToASC BitstreamFilter = "aac_adtstoasc"
+)
+
+// bsfArgs returns the -bsf option applying filters to the streams of the
+// given type ("v" or "a").
+func bsfArgs(streamType string, filters []BitstreamFilter) []string {
+	if len(filters) == 0 {
+		return nil
+	}
+	names := make([]string, len(filters))
+	for i, f := range filters {
+		names[i] = string(f)
+	}
+	return []string{"-bsf:" + streamType, strings.Join(names, ",")}
+}
+
+// stripMetadataArgs drop the metadata and chapters of the inputs (GPS
+// location, device make and model, creation time, ...) and the encoder
+// version strings FFmpeg writes.
+var stripMetadataArgs = []string{
+	"-map_metadata", "-1", "-map_chapters", "-1",
+	"-fflags", "+bitexact", "-flags:v", "+bitexact", "-flags:a", "+bitexact",
+}
@@ -0,0 +1,77 @@
+package moviego
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CaptionOptions carries closed captions (CEA-608/708) into the output.
+type CaptionOptions struct {
+	// Preserve keeps the captions embedded in the source video (A/53 caption
+	// data in the H.264/HEVC SEI) through the re-encode. FFmpeg attaches
+	// them to the decoded frames, so they follow cuts and time-preserving
+	// filters; frames synthesized by the graph carry none.
+	Preserve bool
+	// SCC is a Scenarist caption file embedded as a CEA-608 track. Only the
+	// QuickTime container carries 608 tracks, so the output must be a .mov.
+	SCC string
+	// SCCOffset shifts the SCC captions by this many seconds, e.g. to align
+	// captions timed against the source with a cut that starts later.
+	SCCOffset float64
+}
+
+// captionEncoders are the encoders able to write A/53 caption data.
+var captionEncoders = map[string]bool{
+	"libx264": true, "h264_nvenc": true, "hevc_nvenc": true, "h264_qsv": true, "hevc_qsv": true,
+}
+
+// validate checks the options against the encoder and the output path.
+func (c CaptionOptions) validate(encoder, output string) error {
+	if c.Preserve && encoder != "copy" && !captionEncoders[encoder] {
+		return fmt.Errorf("encoder %s cannot carry embedded captions (valid=libx264, h264_nvenc, hevc_nvenc, h264_qsv, hevc_qsv)", encoder)
+	}
+	if c.SCC == "" {
+		return nil
+	}
+	if _, err := os.Stat(c.SCC); err != nil {
+		return fmt.Errorf("caption file: %w", err)
+	}
+	if !strings.EqualFold(filepath.Ext(output), ".mov") {
+		return fmt.Errorf("SCC captions require a .mov output (got=%s)", output)
+	}
+	return nil
+}
+
+// apply adds the caption inputs and options to args.
+func (c CaptionOptions) apply(args []string, encoder string) []string {
+	if c.Preserve && encoder != "copy" {
+		args = append(args, "-a53cc", "1")
+	}
+	if c.SCC == "" {
+		return args
+	}
+	input := []string{"-f", "scc"}
+	if c.SCCOffset != 0 {
+		input = append(input, "-itsoffset", formatFloat(c.SCCOffset))
+	}
+	args, index := insertInput(args, append(input, "-i", c.SCC)...)
+	return append(args, "-map", fmt.Sprintf("%d:s", index), "-c:s", "copy")
+}
+
+// insertInput inserts an input (its options and "-i file") after the last
+// input of args, where it does not shift the indices of the existing inputs,
+// and returns its input index.
+func insertInput(args []string, input ...string) ([]string, int) {
+	last, count := 0, 0
+	for i := 0; i+1 < len(args); i++ {
+		if args[i] == "-i" {
+			last, count = i+2, count+1
+		}
+	}
+	out := make([]string, 0, len(args)+len(input))
+	out = append(out, args[:last]...)
+	out = append(out, input...)
+	return append(out, args[last:]...), count
+}
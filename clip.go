@@ -1,13 +1,116 @@
 package moviego
 
-// Clip is the shared interface for visual clip types (Video and ImageClip).
-// Audio intentionally does not implement this interface since it has no visual dimensions.
+import "fmt"
+
+// defaultClipFps is the frame rate of generated clips (images, colors) unless set.
+const defaultClipFps uint64 = 30
+
+// Clip is the shared interface for the visual clip types: Video, ImageClip,
+// ColorClip, TitleClip (the clip form of a TextClip), SVGClip, HTMLClip,
+// LottieClip, Sticker and WaveformClip. Any Clip can be passed to
+// ConcatenateClips, CompositeClips and Timeline.AddClip. Audio intentionally
+// does not implement this interface since it has no visual dimensions.
+//
+// The interface is closed: toVideo is unexported, so only this package
+// implements it. Media produced outside the library is added through a
+// ClipProvider and OpenClip.
 type Clip interface {
 	GetWidth() uint64
 	GetHeight() uint64
 	GetDuration() float64
+	GetFps() uint64
 	GetPosition() Position
+
+	// toVideo returns the clip's contribution to a render as a lazy Video.
+	toVideo() (*Video, error)
 }
 
 // Compile-time interface satisfaction checks.
-var _ Clip = (*Video)(nil)
+var (
+	_ Clip = (*Video)(nil)
+	_ Clip = (*ImageClip)(nil)
+	_ Clip = (*ColorClip)(nil)
+	_ Clip = (*TitleClip)(nil)
+	_ Clip = (*SVGClip)(nil)
+	_ Clip = (*HTMLClip)(nil)
+	_ Clip = (*LottieClip)(nil)
+	_ Clip = (*Sticker)(nil)
+	_ Clip = (*WaveformClip)(nil)
+)
+
+func (v *Video) toVideo() (*Video, error) {
+	out := *v
+	return &out, nil
+}
+
+// clipsToVideos converts clips for the Video-based APIs.
+func clipsToVideos(op string, clips []Clip) ([]Video, error) {
+	videos := make([]Video, len(clips))
+	for i, clip := range clips {
+		if clip == nil {
+			return nil, fmt.Errorf("%s: clip %d is nil", op, i)
+		}
+		v, err := clip.toVideo()
+		if err != nil {
+			return nil, fmt.Errorf("%s: clip %d: %w", op, i, err)
+		}
+		videos[i] = *v
+	}
+	return videos, nil
+}
+
+// ConcatenateClips joins clips of any type back to back, see Concatenate.
+func ConcatenateClips(clips ...Clip) (*Video, error) {
+	videos, err := clipsToVideos("ConcatenateClips", clips)
+	if err != nil {
+		return nil, err
+	}
+	return Concatenate(videos)
+}
+
+// CompositeClips overlays clips of any type, the first being the background,
+// see CompositeClip.
+func CompositeClips(clips ...Clip) (*Video, error) {
+	videos, err := clipsToVideos("CompositeClips", clips)
+	if err != nil {
+		return nil, err
+	}
+	return CompositeClip(videos)
+}
+
+// silentAudio returns an Audio made of silence, used by generated clips that
+// have no sound of their own so they can be mixed and concatenated like videos.
+func silentAudio(order uint64, label string, duration float64) Audio {
+	return Audio{
+		sampleRate: defaultSampleRate,
+		channels:   defaultChannels,
+		duration:   duration,
+		filterComplex: []FilterComplex{{
+			Order: order,
+			FilterElement: fmt.Sprintf("anullsrc=r=%d:cl=%s,atrim=duration=%.4f",
+				defaultSampleRate, channelLayouts[defaultChannels], duration),
+			Label: label + "_a",
+		}},
+	}
+}
+
+// generatedVideo wraps a video source graph into a Video with silent audio.
+func generatedVideo(filenames []string, element string, fileCopy FileCopy, label string, width, height, fps uint64, duration float64) *Video {
+	order := incrementOrderCounter()
+	return &Video{
+		filenames: filenames,
+		width:     width,
+		height:    height,
+		fps:       fps,
+		duration:  duration,
+		endTime:   duration,
+		frames:    uint64(float64(fps) * duration),
+		filterComplex: []FilterComplex{{
+			Order:         order,
+			FilterElement: element,
+			FileCopy:      fileCopy,
+			Label:         label + "_v",
+		}},
+		audio: silentAudio(order, label, duration),
+	}
+}
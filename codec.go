@@ -67,36 +67,39 @@ const (
 	AudioCodecPCM    AudioCodec = "pcm_s16le"
 )
 
-type preset string
+// Preset is the speed/compression tradeoff of the encoder. The exported
+// presets are those of the x264 family; they are mapped to the closest
+// preset of hardware encoders.
+type Preset string
 
 const (
 	// Software encoder presets (libx264, libx265, etc.)
-	UltraFast preset = "ultrafast"
-	SuperFast preset = "superfast"
-	VeryFast  preset = "veryfast"
-	Fast      preset = "fast"
-	Medium    preset = "medium"
-	Slow      preset = "slow"
-	VerySlow  preset = "veryslow"
-	Placebo   preset = "placebo"
+	UltraFast Preset = "ultrafast"
+	SuperFast Preset = "superfast"
+	VeryFast  Preset = "veryfast"
+	Fast      Preset = "fast"
+	Medium    Preset = "medium"
+	Slow      Preset = "slow"
+	VerySlow  Preset = "veryslow"
+	Placebo   Preset = "placebo"
 
 	// NVIDIA NVENC presets (internal use only)
-	presetNvencFast   preset = "fast"
-	presetNvencMedium preset = "medium"
-	presetNvencSlow   preset = "slow"
-	presetNvencHQ     preset = "hq"
+	presetNvencFast   Preset = "fast"
+	presetNvencMedium Preset = "medium"
+	presetNvencSlow   Preset = "slow"
+	presetNvencHQ     Preset = "hq"
 
 	// AMD AMF presets (internal use only)
-	presetAmfSpeed    preset = "speed"
-	presetAmfBalanced preset = "balanced"
-	presetAmfQuality  preset = "quality"
+	presetAmfSpeed    Preset = "speed"
+	presetAmfBalanced Preset = "balanced"
+	presetAmfQuality  Preset = "quality"
 
 	// Intel QSV presets (internal use only)
-	presetQsvVeryFast preset = "veryfast"
-	presetQsvFast     preset = "fast"
-	presetQsvMedium   preset = "medium"
-	presetQsvSlow     preset = "slow"
-	presetQsvVerySlow preset = "veryslow"
+	presetQsvVeryFast Preset = "veryfast"
+	presetQsvFast     Preset = "fast"
+	presetQsvMedium   Preset = "medium"
+	presetQsvSlow     Preset = "slow"
+	presetQsvVerySlow Preset = "veryslow"
 )
 
 type PixelFormat string
@@ -108,6 +111,10 @@ const (
 	PixelFormatYUVA420P PixelFormat = "yuva420p"
 	PixelFormatYUV422P PixelFormat = "yuv422p"
 	PixelFormatYUV444P PixelFormat = "yuv444p"
+
+	// PixelFormatSource is only valid as VideoParameters.WorkingPixelFormat:
+	// frames stay in the decoder's format and are not converted before filtering.
+	PixelFormatSource PixelFormat = "source"
 )
 
 // Progress holds real-time encoding progress reported by FFmpeg.
@@ -132,6 +139,9 @@ type Progress struct {
 	ElapsedSeconds float64
 	// ExpectedTotalSeconds is estimated total time; ETA remaining = ExpectedTotalSeconds - ElapsedSeconds.
 	ExpectedTotalSeconds float64
+	// Levels is the latest meter reading when VideoParameters.OnLevels is
+	// set, nil otherwise.
+	Levels *Levels
 }
 
 // VideoParameters holds configuration for video processing.
@@ -144,22 +154,108 @@ type VideoParameters struct {
 	Threads     uint16
 	Codec       Codec
 	Fps         uint64
-	Preset      preset
+	Preset      Preset
 	WithMask    bool
 	Bitrate     string
 	PixelFormat PixelFormat
+	// AudioCodec is the audio encoder, e.g. AudioCodecOpus for WebM or
+	// AudioCodecPCM for MOV masters (default: AAC for .mp4, .m4a and .mov,
+	// FFmpeg's default for other containers).
+	AudioCodec AudioCodec
+	// AudioBitrate is the audio bitrate, e.g. "128k" (default: encoder
+	// default). Not valid for lossless codecs.
+	AudioBitrate    string
+	AudioSampleRate uint64 // e.g. 48000 (default: sample rate of the audio)
+	AudioChannels   uint8  // 1 = mono, 2 = stereo, ... (default: channels of the audio)
+	// AudioLanguage and AudioTitle tag the main audio track, e.g. "eng"
+	// and "Original".
+	AudioLanguage string
+	AudioTitle    string
+	// AudioTracks adds audio tracks after the main audio, e.g. dubs and
+	// commentaries. They are encoded with the same audio settings.
+	AudioTracks []AudioTrack
 	// SilentProgress disables the default colored progress bar.
 	// Has no effect when OnProgress is set.
 	SilentProgress bool
 	// OnProgress, when set, replaces the default colored progress bar.
 	// Called periodically with encoding progress.
 	OnProgress func(Progress)
+	// OnLevels, when set, meters the audio of the output and is called with
+	// a loudness and peak reading every 100 ms of audio, e.g. for realtime
+	// dashboards. The latest reading is also attached to Progress.Levels.
+	OnLevels func(Levels)
+	// OnAudioReport, when set, measures the main audio track of the finished
+	// output (see AudioReport). An error it returns, e.g. for a clipped mix,
+	// is returned by WriteVideo; the output is kept for inspection.
+	OnAudioReport func(AudioReport) error
+	// Env, when set, runs FFmpeg with a scoped priority, CPU set and environment.
+	Env *RenderEnv
+	// AdaptiveThreads times the first seconds of the render with and without
+	// the encoder and splits the threads between the filter graph and the
+	// encoder according to which stage is the bottleneck.
+	AdaptiveThreads bool
+	// WorkingPixelFormat is the format inputs are converted to before the
+	// filter graph (default: yuva420p, which keeps alpha for overlays).
+	// PixelFormatYUV420P halves memory per frame for graphs without
+	// transparency; PixelFormatSource skips the conversion entirely.
+	WorkingPixelFormat PixelFormat
+	// StreamCopy copies the video and audio streams instead of re-encoding.
+	// Only valid for videos without filters other than Cut; cut points snap
+	// to the nearest keyframe.
+	StreamCopy bool
+	// Tune adapts the encoder to the content (film, animation, screen
+	// recordings, low latency streaming).
+	Tune Tune
+	// EncoderOptions sets encoder-specific options (x264 tune and params,
+	// NVENC rate control, QSV low power, ...), see EncoderOptions.
+	EncoderOptions EncoderOptions
+	// Keyframes sets the keyframe interval and forces keyframes at given
+	// times, e.g. for HLS segmentation (see KeyframeOptions).
+	Keyframes KeyframeOptions
+	// Delivery renders to a delivery format such as a broadcast file
+	// specification (see DeliveryProfile).
+	Delivery *DeliveryProfile
+	// NormalizeLoudness normalizes the audio to a loudness target with
+	// two-pass loudnorm, e.g. LoudnessEBUR128 or LoudnessYouTube. The first
+	// pass decodes the whole audio mix before the encode starts.
+	NormalizeLoudness *LoudnessTarget
+	// Captions preserves embedded closed captions and embeds SCC captions
+	// (see CaptionOptions).
+	Captions CaptionOptions
+	// Sidecar writes a delivery manifest next to the output, as
+	// <OutputPath>.json (see Sidecar).
+	Sidecar bool
+	// BitstreamFilters and AudioBitstreamFilters rewrite the encoded video
+	// and audio packets, e.g. BSFH264AnnexB for raw H.264 streams.
+	BitstreamFilters      []BitstreamFilter
+	AudioBitstreamFilters []BitstreamFilter
+	// StripMetadata removes the metadata of the inputs (GPS location,
+	// camera make and model, creation time, ...) and chapters from the
+	// output, for privacy-conscious publishing. Metadata set by MovieGo
+	// itself, such as the start timecode, is kept.
+	StripMetadata bool
+	// HLS configures .m3u8 outputs, which are written as an HLS playlist
+	// and its segments (default: 6 s VOD segments, see HLSOptions).
+	HLS HLSOptions
+	// CENC encrypts MP4 outputs with Common Encryption for DRM systems.
+	CENC *CENCEncryption
+	// VerifyDuration probes the output after the render and compares its
+	// duration, frame count and audio length with the planned ones, failing
+	// with a DurationMismatchError (or logging, see DurationCheck) when the
+	// filter graph dropped or extended content.
+	VerifyDuration *DurationCheck
+	// FrameMetadata writes the per-frame metadata of the EmitFrameMetadata
+	// analyzers to <OutputPath>.frames.json, with output timestamps (see
+	// FrameMetadata).
+	FrameMetadata bool
 }
 
 // AudioParameters holds configuration for audio processing.
 type AudioParameters struct {
 	OutputPath string
 	Threads    uint16
+	// Codec defaults by extension: .mp3 MP3, .aac/.m4a/.mp4/.mov AAC,
+	// .wav PCM, .flac FLAC, .opus Opus and .ogg Vorbis.
 	Codec      AudioCodec
 	SampleRate uint64
 	Channels   uint8
@@ -168,5 +264,10 @@ type AudioParameters struct {
 	SilentProgress bool
 	// OnProgress, when set, replaces the default colored progress bar.
 	OnProgress func(Progress)
+	// StripMetadata removes the metadata of the inputs (tags, GPS
+	// location, ...) and chapters from the output.
+	StripMetadata bool
+	// Env, when set, runs FFmpeg with a scoped priority, CPU set and environment.
+	Env *RenderEnv
 }
 
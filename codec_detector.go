@@ -2,10 +2,9 @@ package moviego
 
 import (
 	"fmt"
-	"os/exec"
+	"path/filepath"
 	"runtime"
 	"strings"
-	"sync"
 )
 
 // GPU vendor types
@@ -19,19 +18,14 @@ const (
 	gpuUnknown gpuVendor = "unknown"
 )
 
-// Cached codec detection result
-var (
-	cachedCodec     string
-	cachedCodecOnce sync.Once
-)
-
 // selectBestH264Codec detects the best available H.264 codec for the system
 // Priority: h264_nvenc (NVIDIA) > h264_qsv (Intel) > h264_amf (AMD) > h264_videotoolbox (Apple) > libx264 (software)
 func selectBestH264Codec() string {
-	cachedCodecOnce.Do(func() {
-		cachedCodec = detectBestH264Codec()
+	c := currentProbeCache()
+	c.codecOnce.Do(func() {
+		c.codec = detectBestH264Codec()
 	})
-	return cachedCodec
+	return c.codec
 }
 
 // detectBestH264Codec performs the actual codec detection
@@ -102,8 +96,7 @@ func detectGPUVendor() gpuVendor {
 
 // detectGPUWindows detects GPU vendor on Windows using wmic
 func detectGPUWindows() gpuVendor {
-	cmd := exec.Command("wmic", "path", "win32_VideoController", "get", "name")
-	output, err := cmd.CombinedOutput()
+	output, err := toolOutput("wmic", "path", "win32_VideoController", "get", "name")
 	if err != nil {
 		return gpuUnknown
 	}
@@ -127,19 +120,17 @@ func detectGPUWindows() gpuVendor {
 // detectGPULinux detects GPU vendor on Linux using multiple methods
 func detectGPULinux() gpuVendor {
 	// Method 1: Check NVIDIA driver
-	if _, err := exec.LookPath("nvidia-smi"); err == nil {
+	if _, err := lookPath("nvidia-smi"); err == nil {
 		return gpuNvidia
 	}
 
 	// Method 2: Check /proc/driver/nvidia
-	cmd := exec.Command("cat", "/proc/driver/nvidia/version")
-	if err := cmd.Run(); err == nil {
+	if _, err := toolOutput("cat", "/proc/driver/nvidia/version"); err == nil {
 		return gpuNvidia
 	}
 
 	// Method 3: Use lspci
-	cmd = exec.Command("lspci")
-	output, err := cmd.CombinedOutput()
+	output, err := toolOutput("lspci")
 	if err != nil {
 		return gpuUnknown
 	}
@@ -163,8 +154,7 @@ func detectGPULinux() gpuVendor {
 	}
 
 	// Method 4: Check /sys/class/drm
-	cmd = exec.Command("ls", "/sys/class/drm")
-	output, err = cmd.CombinedOutput()
+	output, err = toolOutput("ls", "/sys/class/drm")
 	if err == nil {
 		outputStr := strings.ToLower(string(output))
 		if strings.Contains(outputStr, "nvidia") {
@@ -179,8 +169,7 @@ func detectGPULinux() gpuVendor {
 func detectGPUMacOS() gpuVendor {
 	// macOS primarily uses Apple's VideoToolbox
 	// But we can still detect discrete GPUs
-	cmd := exec.Command("system_profiler", "SPDisplaysDataType")
-	output, err := cmd.CombinedOutput()
+	output, err := toolOutput("system_profiler", "SPDisplaysDataType")
 	if err != nil {
 		// If command fails, assume Apple silicon or integrated
 		return gpuApple
@@ -209,8 +198,7 @@ func getAvailableEncoders() map[string]bool {
 	if err != nil {
 		return make(map[string]bool)
 	}
-	cmd := exec.Command(ffmpegPath, "-hide_banner", "-encoders")
-	output, err := cmd.CombinedOutput()
+	output, err := commandCombinedOutput(ffmpegPath, "-hide_banner", "-encoders")
 	if err != nil {
 		return make(map[string]bool)
 	}
@@ -320,7 +308,7 @@ func mapPresetForCodec(codec string, presetValue string) string {
 
 // resolvePreset resolves preset with fallback: preferredPreset → fallbackPreset → Medium
 // Returns the preset string value, or empty string if no preset should be used.
-func resolvePreset(preferredPreset, fallbackPreset preset) string {
+func resolvePreset(preferredPreset, fallbackPreset Preset) string {
 	if preferredPreset != "" {
 		return string(preferredPreset)
 	}
@@ -339,6 +327,62 @@ func resolveBitrate(preferredBitrate, fallbackBitrate string) string {
 	return fallbackBitrate
 }
 
+// resolveAudioEncoder resolves the audio encoder with fallback: preferredCodec → default codec of the output extension → empty
+// Returns the encoder name, or empty string to let FFmpeg pick the container default.
+func resolveAudioEncoder(preferredCodec AudioCodec, outputPath string) string {
+	if preferredCodec != "" {
+		return string(preferredCodec)
+	}
+	switch ext := strings.ToLower(filepath.Ext(outputPath)); ext {
+	case ".mp4", ".m4a", ".mov":
+		return string(defaultAudioCodecs[ext])
+	}
+	return ""
+}
+
+// resolveSampleRate resolves the sample rate with fallback: preferredRate → fallbackRate → 0
+// Returns the sample rate, or 0 to keep the sample rate of the audio.
+func resolveSampleRate(preferredRate, fallbackRate uint64) uint64 {
+	if preferredRate != 0 {
+		return preferredRate
+	}
+	return fallbackRate
+}
+
+// audioEncoderArgs returns the audio encoder arguments of a video written to
+// outputPath with parms, and the encoder they select. The codec and sample
+// rate of a delivery profile take precedence over the parameters.
+func audioEncoderArgs(parms VideoParameters, outputPath string) ([]string, string, error) {
+	encoder := resolveAudioEncoder(parms.AudioCodec, outputPath)
+	rate := parms.AudioSampleRate
+	if d := parms.Delivery; d != nil {
+		if d.AudioCodec != "" {
+			encoder = d.AudioCodec
+		}
+		rate = resolveSampleRate(uint64(d.SampleRate), rate)
+	}
+	if parms.AudioBitrate != "" {
+		switch AudioCodec(encoder) {
+		case AudioCodecFLAC, AudioCodecPCM, "pcm_s24le", "pcm_s32le", "alac":
+			return nil, "", fmt.Errorf("AudioBitrate cannot be used with the lossless codec %s (bitrate=%s)", encoder, parms.AudioBitrate)
+		}
+	}
+	var args []string
+	if encoder != "" {
+		args = append(args, "-c:a", encoder)
+	}
+	if parms.AudioBitrate != "" {
+		args = append(args, "-b:a", parms.AudioBitrate)
+	}
+	if rate > 0 {
+		args = append(args, "-ar", fmt.Sprintf("%d", rate))
+	}
+	if parms.AudioChannels > 0 {
+		args = append(args, "-ac", fmt.Sprintf("%d", parms.AudioChannels))
+	}
+	return args, encoder, nil
+}
+
 // resolveFps resolves FPS with fallback: preferredFps → fallbackFps → 0
 // Returns the FPS value, or 0 if no FPS is specified.
 func resolveFps(preferredFps, fallbackFps uint64) uint64 {
@@ -374,3 +418,18 @@ func resolvePixelFormat(preferred, fallback PixelFormat) PixelFormat {
 	}
 	return fallback
 }
+
+// resolveWorkingPixelFormat returns the filter that converts decoded frames to
+// the working format, or "" when frames are passed through unchanged.
+func resolveWorkingPixelFormat(pf PixelFormat) (string, error) {
+	switch pf {
+	case "":
+		return "format=yuva420p,", nil
+	case PixelFormatSource:
+		return "", nil
+	case PixelFormatYUV420P, PixelFormatYUVA420P, PixelFormatYUV422P, PixelFormatYUV444P, PixelFormatRGBA:
+		return fmt.Sprintf("format=%s,", pf), nil
+	default:
+		return "", fmt.Errorf("unsupported working pixel format %q", pf)
+	}
+}
@@ -0,0 +1,109 @@
+package moviego
+
+import (
+	"fmt"
+	"strings"
+)
+
+// knownCodecs lists the video encoders exported by this package, in the
+// order of the Codec constants.
+var knownCodecs = []Codec{
+	CodecH264, CodecLibx264, CodecH264Auto, CodecH264Nvenc, CodecH264Qsv, CodecH264Amf, CodecH264Vt,
+	CodecH265, CodecHevc, CodecLibx265, CodecHevcNvenc, CodecHevcQsv, CodecHevcAmf, CodecHevcVt,
+	CodecVP8, CodecVP9, CodecLibvpx, CodecLibvpxVP9,
+	CodecAV1, CodecLibaomAV1, CodecLibsvtav1, CodecAV1Nvenc, CodecAV1Qsv,
+	CodecMpeg2video, CodecMpeg4, CodecMpeg1video,
+	CodecTheora, CodecWmv1, CodecWmv2, CodecWmv3, CodecVc1, CodecProres, CodecProresKS,
+	CodecDNxHD, CodecDNxHR, CodecHuffYUV, CodecFFV1, CodecUtvideo, CodecMjpeg, CodecLibxvid,
+}
+
+var knownAudioCodecs = []AudioCodec{
+	AudioCodecAAC, AudioCodecMP3, AudioCodecFLAC, AudioCodecOpus, AudioCodecVorbis, AudioCodecPCM,
+}
+
+var knownPresets = []Preset{UltraFast, SuperFast, VeryFast, Fast, Medium, Slow, VerySlow, Placebo}
+
+var knownPixelFormats = []PixelFormat{
+	PixelFormatRGBA, PixelFormatRGB, PixelFormatYUV420P, PixelFormatYUVA420P,
+	PixelFormatYUV422P, PixelFormatYUV444P, PixelFormatSource,
+}
+
+// codecAliases maps common spellings to the exported codec constants.
+var codecAliases = map[string]Codec{
+	"h.264": CodecH264, "avc": CodecH264, "x264": CodecLibx264,
+	"h.265": CodecH265, "x265": CodecLibx265,
+	"vpx": CodecLibvpx, "svtav1": CodecLibsvtav1,
+}
+
+var audioCodecAliases = map[string]AudioCodec{
+	"mp3": AudioCodecMP3, "opus": AudioCodecOpus, "vorbis": AudioCodecVorbis,
+	"pcm": AudioCodecPCM, "wav": AudioCodecPCM,
+}
+
+// parseKnown looks s up, trimmed and case-insensitively, among values and
+// then aliases.
+func parseKnown[T ~string](s string, values []T, aliases map[string]T) (T, bool) {
+	key := strings.ToLower(strings.TrimSpace(s))
+	for _, v := range values {
+		if string(v) == key {
+			return v, true
+		}
+	}
+	v, ok := aliases[key]
+	return v, ok
+}
+
+// ParseCodec returns the Codec constant named by s, accepting the FFmpeg
+// encoder name ("libx264", "hevc_nvenc") and a few common aliases
+// ("h.264", "x265"). Unknown names are an error; use Codec(s) directly to
+// pass a custom encoder through.
+func ParseCodec(s string) (Codec, error) {
+	c, ok := parseKnown(s, knownCodecs, codecAliases)
+	if !ok {
+		return "", fmt.Errorf("ParseCodec: unknown codec (got=%q)", s)
+	}
+	return c, nil
+}
+
+// ParseAudioCodec returns the AudioCodec constant named by s, accepting the
+// FFmpeg encoder name or its short form ("mp3", "opus", "wav").
+func ParseAudioCodec(s string) (AudioCodec, error) {
+	c, ok := parseKnown(s, knownAudioCodecs, audioCodecAliases)
+	if !ok {
+		return "", fmt.Errorf("ParseAudioCodec: unknown audio codec (got=%q)", s)
+	}
+	return c, nil
+}
+
+// ParsePreset returns the Preset named by s, e.g. "veryfast".
+func ParsePreset(s string) (Preset, error) {
+	p, ok := parseKnown[Preset](s, knownPresets, nil)
+	if !ok {
+		return "", fmt.Errorf("ParsePreset: unknown preset (got=%q)", s)
+	}
+	return p, nil
+}
+
+// ParsePixelFormat returns the PixelFormat constant named by s, e.g.
+// "yuv420p".
+func ParsePixelFormat(s string) (PixelFormat, error) {
+	f, ok := parseKnown[PixelFormat](s, knownPixelFormats, nil)
+	if !ok {
+		return "", fmt.Errorf("ParsePixelFormat: unknown pixel format (got=%q)", s)
+	}
+	return f, nil
+}
+
+// Validate reports whether p is one of the exported presets. The empty
+// preset is valid and means the encoder default.
+func (p Preset) Validate() error {
+	if p == "" {
+		return nil
+	}
+	for _, known := range knownPresets {
+		if p == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown preset (got=%q)", string(p))
+}
@@ -0,0 +1,182 @@
+package moviego
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Color is a color as accepted by every color option of the library:
+//
+//   - hex: "#RGB", "#RRGGBB", "#RRGGBBAA", "0xRRGGBB", "0xRRGGBBAA"
+//   - names: "white", "black", "dodgerblue", ... (the FFmpeg/CSS names)
+//   - functions: "rgb(255, 0, 0)", "rgba(255, 0, 0, 0.5)"
+//
+// Any of them may end in "@alpha" (0-1), e.g. "black@0.5", as in FFmpeg.
+// Colors are validated by the Validate methods of the types using them and
+// are converted to one FFmpeg form when rendered, so alpha is kept the same
+// in drawtext, color sources, box colors and padding.
+type Color string
+
+// rgbaColor is a parsed Color. name is set for named colors, which are
+// passed to FFmpeg by name.
+type rgbaColor struct {
+	name    string
+	r, g, b uint8
+	a       float64
+}
+
+// RGBA returns the color with the given components and alpha in [0, 1].
+func RGBA(r, g, b uint8, alpha float64) Color {
+	return Color(fmt.Sprintf("rgba(%d, %d, %d, %s)", r, g, b, formatAlpha(alpha)))
+}
+
+// ParseColor validates s and returns it as a Color.
+func ParseColor(s string) (Color, error) {
+	c := Color(s)
+	if err := c.Validate(); err != nil {
+		return "", err
+	}
+	return c, nil
+}
+
+// Validate reports whether c is a valid color.
+func (c Color) Validate() error {
+	_, err := c.parse()
+	return err
+}
+
+// String returns the color as written.
+func (c Color) String() string {
+	return string(c)
+}
+
+// ffmpeg returns the color in the form FFmpeg parses in every filter:
+// a name or 0xRRGGBB, with "@alpha" when not opaque. Invalid colors are
+// returned unchanged so FFmpeg reports them.
+func (c Color) ffmpeg() string {
+	p, err := c.parse()
+	if err != nil {
+		return string(c)
+	}
+	base := p.name
+	if base == "" {
+		base = fmt.Sprintf("0x%02X%02X%02X", p.r, p.g, p.b)
+	}
+	if p.a >= 1 {
+		return base
+	}
+	return base + "@" + formatAlpha(p.a)
+}
+
+func (c Color) parse() (rgbaColor, error) {
+	s := strings.ToLower(strings.TrimSpace(string(c)))
+	if s == "" {
+		return rgbaColor{}, fmt.Errorf("color: empty color")
+	}
+
+	alpha := 1.0
+	if strings.HasPrefix(s, "rgb") {
+		return parseColorFunc(s, string(c))
+	}
+	if base, suffix, ok := strings.Cut(s, "@"); ok {
+		a, err := strconv.ParseFloat(suffix, 64)
+		if err != nil || a < 0 || a > 1 {
+			return rgbaColor{}, fmt.Errorf("color: invalid alpha in %q (must be 0-1)", string(c))
+		}
+		s, alpha = base, a
+	}
+
+	var hex string
+	switch {
+	case strings.HasPrefix(s, "#"):
+		hex = s[1:]
+		if len(hex) == 3 {
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		}
+	case strings.HasPrefix(s, "0x"):
+		hex = s[2:]
+	default:
+		if !namedColors[s] {
+			return rgbaColor{}, fmt.Errorf("color: unknown color %q", string(c))
+		}
+		return rgbaColor{name: s, a: alpha}, nil
+	}
+	if len(hex) != 6 && len(hex) != 8 {
+		return rgbaColor{}, fmt.Errorf("color: invalid hex color %q", string(c))
+	}
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return rgbaColor{}, fmt.Errorf("color: invalid hex color %q", string(c))
+	}
+	if len(hex) == 8 {
+		// The hex alpha is combined with an "@alpha" suffix, as FFmpeg does.
+		alpha *= float64(value&0xFF) / 255
+		value >>= 8
+	}
+	return rgbaColor{r: uint8(value >> 16), g: uint8(value >> 8), b: uint8(value), a: alpha}, nil
+}
+
+// parseColorFunc parses rgb(r, g, b) and rgba(r, g, b, a).
+func parseColorFunc(s, orig string) (rgbaColor, error) {
+	name, args, ok := strings.Cut(s, "(")
+	if !ok || !strings.HasSuffix(args, ")") || (name != "rgb" && name != "rgba") {
+		return rgbaColor{}, fmt.Errorf("color: invalid color %q", orig)
+	}
+	fields := strings.Split(strings.TrimSuffix(args, ")"), ",")
+	if want := len(name); len(fields) != want {
+		return rgbaColor{}, fmt.Errorf("color: %s() expects %d components in %q", name, want, orig)
+	}
+	var rgb [3]uint8
+	for i := 0; i < 3; i++ {
+		n, err := strconv.ParseUint(strings.TrimSpace(fields[i]), 10, 8)
+		if err != nil {
+			return rgbaColor{}, fmt.Errorf("color: component %d must be 0-255 in %q", i+1, orig)
+		}
+		rgb[i] = uint8(n)
+	}
+	alpha := 1.0
+	if name == "rgba" {
+		a, err := strconv.ParseFloat(strings.TrimSpace(fields[3]), 64)
+		if err != nil || a < 0 || a > 1 {
+			return rgbaColor{}, fmt.Errorf("color: alpha must be 0-1 in %q", orig)
+		}
+		alpha = a
+	}
+	return rgbaColor{r: rgb[0], g: rgb[1], b: rgb[2], a: alpha}, nil
+}
+
+func formatAlpha(a float64) string {
+	return strconv.FormatFloat(a, 'f', -1, 64)
+}
+
+// namedColors are the color names understood by FFmpeg (av_parse_color).
+var namedColors = func() map[string]bool {
+	names := strings.Fields(`
+		aliceblue antiquewhite aqua aquamarine azure beige bisque black
+		blanchedalmond blue blueviolet brown burlywood cadetblue chartreuse
+		chocolate coral cornflowerblue cornsilk crimson cyan darkblue darkcyan
+		darkgoldenrod darkgray darkgreen darkkhaki darkmagenta darkolivegreen
+		darkorange darkorchid darkred darksalmon darkseagreen darkslateblue
+		darkslategray darkturquoise darkviolet deeppink deepskyblue dimgray
+		dodgerblue firebrick floralwhite forestgreen fuchsia gainsboro
+		ghostwhite gold goldenrod gray green greenyellow honeydew hotpink
+		indianred indigo ivory khaki lavender lavenderblush lawngreen
+		lemonchiffon lightblue lightcoral lightcyan lightgoldenrodyellow
+		lightgreen lightgrey lightpink lightsalmon lightseagreen lightskyblue
+		lightslategray lightsteelblue lightyellow lime limegreen linen magenta
+		maroon mediumaquamarine mediumblue mediumorchid mediumpurple
+		mediumseagreen mediumslateblue mediumspringgreen mediumturquoise
+		mediumvioletred midnightblue mintcream mistyrose moccasin navajowhite
+		navy oldlace olive olivedrab orange orangered orchid palegoldenrod
+		palegreen paleturquoise palevioletred papayawhip peachpuff peru pink
+		plum powderblue purple red rosybrown royalblue saddlebrown salmon
+		sandybrown seagreen seashell sienna silver skyblue slateblue slategray
+		snow springgreen steelblue tan teal thistle tomato turquoise violet
+		wheat white whitesmoke yellow yellowgreen`)
+	m := make(map[string]bool, len(names))
+	for _, name := range names {
+		m[name] = true
+	}
+	return m
+}()
@@ -0,0 +1,94 @@
+package moviego
+
+import "fmt"
+
+// ColorClip is a solid color clip, e.g. a background or a title card.
+type ColorClip struct {
+	color    Color
+	width    uint64
+	height   uint64
+	duration float64
+	fps      uint64
+	position Position
+}
+
+// NewColorClip creates a solid color clip, e.g. "black", "white@0.5" or
+// "#202020" (see Color).
+func NewColorClip(color Color, width, height uint64, duration float64) *ColorClip {
+	return &ColorClip{
+		color:    color,
+		width:    width,
+		height:   height,
+		duration: duration,
+	}
+}
+
+// GetWidth returns the clip width.
+func (cc *ColorClip) GetWidth() uint64 {
+	return cc.width
+}
+
+// GetHeight returns the clip height.
+func (cc *ColorClip) GetHeight() uint64 {
+	return cc.height
+}
+
+// GetDuration returns the clip duration.
+func (cc *ColorClip) GetDuration() float64 {
+	return cc.duration
+}
+
+// GetFps returns the clip frame rate (default: 30).
+func (cc *ColorClip) GetFps() uint64 {
+	if cc.fps == 0 {
+		return defaultClipFps
+	}
+	return cc.fps
+}
+
+// GetPosition returns the overlay position.
+// Returns center position if none was explicitly set.
+func (cc *ColorClip) GetPosition() Position {
+	if cc.position.X == "" && cc.position.Y == "" {
+		return CenterPosition()
+	}
+	return cc.position
+}
+
+// GetColor returns the clip color.
+func (cc *ColorClip) GetColor() Color {
+	return cc.color
+}
+
+// Fps sets the clip frame rate.
+func (cc *ColorClip) Fps(fps uint64) *ColorClip {
+	cc.fps = fps
+	return cc
+}
+
+// SetPosition sets the overlay position used by CompositeClips.
+func (cc *ColorClip) SetPosition(position Position) *ColorClip {
+	cc.position = position
+	return cc
+}
+
+func (cc *ColorClip) toVideo() (*Video, error) {
+	if err := cc.Validate(); err != nil {
+		return nil, err
+	}
+	fps := cc.GetFps()
+	label := fmt.Sprintf("color_%d", incrementGlobalCounter())
+	element := fmt.Sprintf("color=c=%s:s=%dx%d:r=%d:d=%.4f", cc.color.ffmpeg(), cc.width, cc.height, fps, cc.duration)
+	v := generatedVideo(nil, element, FileCopy{}, label, cc.width, cc.height, fps, cc.duration)
+	v.position = cc.position
+	return v, nil
+}
+
+// AddText draws text on the color clip, e.g. for a title card.
+func (cc *ColorClip) AddText(clip TextClip) (*Video, error) {
+	v, err := cc.toVideo()
+	if err != nil {
+		return nil, err
+	}
+	return v.AddText(clip)
+}
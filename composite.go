@@ -1,6 +1,9 @@
 package moviego
 
-import "fmt"
+import (
+	"fmt"
+	"math"
+)
 
 // initRawVideo ensures a video has at least one filter complex entry by adding
 // identity filters (null/anull) for raw videos loaded directly from files.
@@ -30,18 +33,192 @@ func initRawVideo(v *Video) {
 	})
 }
 
+// GapFill defines what is shown in parts of a composite that no clip covers.
+type GapFill string
+
+const (
+	// GapFillFreeze holds the last frame of clips that end early (default).
+	GapFillFreeze GapFill = "freeze"
+	// GapFillBlack extends the background with black video and silence once
+	// it ends. Layers disappear when they end, uncovering what lies beneath.
+	GapFillBlack GapFill = "black"
+	// GapFillColor is GapFillBlack with CompositeOptions.FillColor.
+	GapFillColor GapFill = "color"
+	// GapFillClip shows CompositeOptions.FillClip, scaled to the canvas and
+	// looped, wherever no layer covers the composite. Its audio is not mixed.
+	GapFillClip GapFill = "clip"
+)
+
+// CanvasPolicy defines the size of a composite.
+type CanvasPolicy string
+
+const (
+	// CanvasBackground uses the size of the background (default).
+	CanvasBackground CanvasPolicy = "background"
+	// CanvasFitLargestClip grows the canvas to the largest width and height
+	// of the layers. The background is centered on it, over FillColor
+	// (default: black). Positions resolve against the grown canvas, so
+	// percent points such as PointAt(100, 100, AnchorBottomRight) place
+	// layers relative to it.
+	CanvasFitLargestClip CanvasPolicy = "fit_largest_clip"
+)
+
+// FpsPolicy sets the frame rate of a composite, see CompositeOptions.Fps.
+type FpsPolicy struct {
+	mode string
+	fps  uint64
+}
+
+var (
+	// FpsBackground uses the background frame rate; FFmpeg repeats or drops
+	// frames of other layers implicitly (default).
+	FpsBackground = FpsPolicy{}
+	// FpsHighest uses the highest frame rate of the layers, e.g. 60 for
+	// 24, 30 and 60 fps sources.
+	FpsHighest = FpsPolicy{mode: "highest"}
+	// FpsLowest uses the lowest frame rate of the layers.
+	FpsLowest = FpsPolicy{mode: "lowest"}
+)
+
+// FpsFixed uses the given frame rate.
+func FpsFixed(fps uint64) FpsPolicy {
+	return FpsPolicy{mode: "fixed", fps: fps}
+}
+
+// resolve returns the frame rate of a composite of videos.
+func (p FpsPolicy) resolve(videos []Video) uint64 {
+	fps := videos[0].fps
+	for _, v := range videos[1:] {
+		switch p.mode {
+		case "highest":
+			fps = max(fps, v.fps)
+		case "lowest":
+			fps = min(fps, v.fps)
+		}
+	}
+	if p.mode == "fixed" {
+		fps = p.fps
+	}
+	return fps
+}
+
+// CompositeOptions configures how CompositeClipWithOptions fills gaps, i.e. time
+// after the background ends or after a layer's own time range.
+type CompositeOptions struct {
+	Fill      GapFill
+	FillColor Color  // used with GapFillColor, e.g. "white" or "#202020"
+	FillClip  *Video // used with GapFillClip
+	// Pretranscode, when set, renders the layers reported by
+	// CompositeMismatches to a uniform mezzanine format first, in parallel,
+	// so the final graph decodes cheap, matching sources.
+	Pretranscode *PretranscodeOptions
+	// Canvas sets the size of the composite (default: CanvasBackground).
+	Canvas CanvasPolicy
+	// Fps sets the frame rate of the composite (default: FpsBackground).
+	// With any other policy every layer at another rate is converted with
+	// the fps filter on its own branch, before the overlays.
+	Fps FpsPolicy
+}
+
+// padFilter returns the tpad filter extending the background by duration seconds.
+func (o CompositeOptions) padFilter(duration float64) string {
+	switch o.Fill {
+	case GapFillBlack:
+		return fmt.Sprintf("tpad=stop_mode=add:stop_duration=%.4f:color=black", duration)
+	case GapFillColor:
+		return fmt.Sprintf("tpad=stop_mode=add:stop_duration=%.4f:color=%s", duration, o.FillColor.ffmpeg())
+	default:
+		return fmt.Sprintf("tpad=stop_mode=clone:stop_duration=%.4f", duration)
+	}
+}
+
+// eofAction returns the overlay option applied to layers once they end.
+func (o CompositeOptions) eofAction() string {
+	if o.Fill == GapFillBlack || o.Fill == GapFillColor || o.Fill == GapFillClip {
+		return ":eof_action=pass"
+	}
+	return ""
+}
+
+func (o CompositeOptions) validate() error {
+	if o.Fps.mode == "fixed" && o.Fps.fps == 0 {
+		return fmt.Errorf("fixed frame rate must be positive")
+	}
+	switch o.Canvas {
+	case "", CanvasBackground, CanvasFitLargestClip:
+	default:
+		return fmt.Errorf("unknown canvas policy %q", o.Canvas)
+	}
+	switch o.Fill {
+	case "", GapFillFreeze, GapFillBlack:
+		return nil
+	case GapFillColor:
+		if o.FillColor == "" {
+			return fmt.Errorf("FillColor is required with GapFillColor")
+		}
+		return o.FillColor.Validate()
+	case GapFillClip:
+		if o.FillClip == nil {
+			return fmt.Errorf("FillClip is required with GapFillClip")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown gap fill %q", o.Fill)
+	}
+}
+
 // CompositeClip overlays multiple videos on top of each other, similar to
 // MoviePy's CompositeVideoClip. The first video is the background; each
 // subsequent video is overlaid using its Position (defaults to center).
 // Audio from all layers is mixed together with amix.
 func CompositeClip(videos []Video) (*Video, error) {
+	return CompositeClipWithOptions(videos, CompositeOptions{})
+}
+
+// CompositeClipWithOptions is CompositeClip with explicit gap handling.
+// Layers start at their composite start (see SetCompositeStart); the result lasts
+// until the last layer ends and uncovered time is filled according to opts.Fill.
+func CompositeClipWithOptions(videos []Video, opts CompositeOptions) (*Video, error) {
 	if len(videos) == 0 {
 		return nil, fmt.Errorf("CompositeClip: no videos provided")
 	}
+	if err := opts.validate(); err != nil {
+		return nil, fmt.Errorf("CompositeClip: %w", err)
+	}
+	videos, err := flattenVideos("CompositeClip", videos)
+	if err != nil {
+		return nil, err
+	}
+	if videos[0].compositeStart != 0 {
+		return nil, fmt.Errorf("CompositeClip: background must start at 0 (start=%.4f, file=%s)", videos[0].compositeStart, safeFirstFilename(videos[0].filenames))
+	}
+	for i, video := range videos {
+		if video.compositeStart < 0 {
+			return nil, fmt.Errorf("CompositeClip: layer %d has negative start %.4f (file=%s)", i, video.compositeStart, safeFirstFilename(video.filenames))
+		}
+	}
 	if len(videos) == 1 {
 		v := videos[0]
 		return &v, nil
 	}
+	if opts.Pretranscode != nil {
+		if videos, err = pretranscodeLayers(videos, *opts.Pretranscode); err != nil {
+			return nil, fmt.Errorf("CompositeClip: %w", err)
+		}
+	}
+	if opts.Canvas == CanvasFitLargestClip {
+		if videos, err = fitCanvas(videos, opts.FillColor); err != nil {
+			return nil, fmt.Errorf("CompositeClip: %w", err)
+		}
+	}
+
+	bg := videos[0]
+	fps := opts.Fps.resolve(videos)
+	if opts.Fill == GapFillClip {
+		if videos, err = prependFillClip(videos, *opts.FillClip); err != nil {
+			return nil, fmt.Errorf("CompositeClip: %w", err)
+		}
+	}
 
 	for i := range videos {
 		initRawVideo(&videos[i])
@@ -52,8 +229,31 @@ func CompositeClip(videos []Video) (*Video, error) {
 	audioFilterComplex := []FilterComplex{}
 	seen := make(map[string]struct{})
 
+	format := resolveVideosAudioFormat(videos)
+	var audioLabels []string
+
 	var maxDuration float64
-	for _, video := range videos {
+	layers := make([]compositeLayer, len(videos))
+	for i, video := range videos {
+		layers[i] = compositeLayer{
+			filenames:        video.filenames,
+			start:            video.compositeStart,
+			duration:         video.duration,
+			position:         video.GetPosition(),
+			animatedPosition: video.animatedPosition,
+			animatedOpacity:  video.animatedOpacity,
+		}
+		if i == 0 {
+			layers[i].position = TopLeftPosition()
+		}
+		if mixesAudio(videos, video) {
+			audio := video.audio.normalized(format)
+			if start := video.compositeStart; start > 0 {
+				audio = audio.chain(fmt.Sprintf("adelay=delays=%d:all=1", int64(start*1000)))
+			}
+			audioLabels = append(audioLabels, audio.lastAudioLabel())
+			audioFilterComplex = append(audioFilterComplex, audio.filterComplex...)
+		}
 		for _, filename := range video.filenames {
 			if _, exists := seen[filename]; exists {
 				continue
@@ -62,10 +262,9 @@ func CompositeClip(videos []Video) (*Video, error) {
 			filenames = append(filenames, filename)
 		}
 		videoFilterComplex = append(videoFilterComplex, video.filterComplex...)
-		audioFilterComplex = append(audioFilterComplex, video.audio.filterComplex...)
 
-		if video.duration > maxDuration {
-			maxDuration = video.duration
+		if end := video.compositeStart + video.duration; end > maxDuration {
+			maxDuration = end
 		}
 	}
 
@@ -78,12 +277,39 @@ func CompositeClip(videos []Video) (*Video, error) {
 	//   ...last one gets the final label
 	// If animatedOpacity is set, insert format=rgba,colorchannelmixer before overlay.
 	// If animatedPosition is set, use x='expr':y='expr' instead of static position.
-	currentLabel := videos[0].lastVideoLabel()
+	// Layers with a composite start are shifted with setpts, and the background
+	// is padded with the gap filler when it ends before the composite does.
 	filterElement := ""
+	// convertFps appends the fps conversion of a branch, if any, and
+	// returns the label of its output. A fill clip always takes the rate
+	// of the background it stands in for.
+	convertFps := func(label string, v Video, i int) string {
+		if v.fps == fps || opts.Fps == FpsBackground && (i > 0 || opts.Fill != GapFillClip) {
+			return label
+		}
+		converted := fmt.Sprintf("%s_fps_%d", compositeLabel, i)
+		filterElement += fmt.Sprintf("[%s]fps=%d[%s];", label, fps, converted)
+		return converted
+	}
+	currentLabel := convertFps(videos[0].lastVideoLabel(), videos[0], 0)
+
+	if pad := maxDuration - videos[0].duration; pad > 0 {
+		paddedLabel := compositeLabel + "_bg"
+		filterElement += fmt.Sprintf("[%s]%s[%s];", currentLabel, opts.padFilter(pad), paddedLabel)
+		currentLabel = paddedLabel
+	}
 
 	for i := 1; i < len(videos); i++ {
 		fgLabel := videos[i].lastVideoLabel()
 
+		if start := videos[i].compositeStart; start > 0 {
+			shiftedLabel := fmt.Sprintf("%s_shift_%d", compositeLabel, i)
+			filterElement += fmt.Sprintf("[%s]setpts=PTS+%.4f/TB[%s];", fgLabel, start, shiftedLabel)
+			fgLabel = shiftedLabel
+		}
+		// Converted after the shift, so frames land on the output grid.
+		fgLabel = convertFps(fgLabel, videos[i], i)
+
 		// Apply animated opacity if set
 		if videos[i].animatedOpacity != nil {
 			alphaLabel := fmt.Sprintf("%s_alpha_%d", compositeLabel, i)
@@ -101,6 +327,7 @@ func CompositeClip(videos []Video) (*Video, error) {
 			pos := videos[i].GetPosition()
 			overlayExpr = fmt.Sprintf("[%s][%s]overlay=x=%s:y=%s", currentLabel, fgLabel, pos.X, pos.Y)
 		}
+		overlayExpr += opts.eofAction()
 
 		if i < len(videos)-1 {
 			intermediateLabel := fmt.Sprintf("%s_ov%d", compositeLabel, i)
@@ -119,10 +346,10 @@ func CompositeClip(videos []Video) (*Video, error) {
 
 	// Build audio mix: [a0][a1][a2]...amix=inputs=N:duration=longest
 	audioMixElement := ""
-	for _, video := range videos {
-		audioMixElement += fmt.Sprintf("[%s]", video.audio.lastAudioLabel())
+	for _, audioLabel := range audioLabels {
+		audioMixElement += fmt.Sprintf("[%s]", audioLabel)
 	}
-	audioMixElement += fmt.Sprintf("amix=inputs=%d:duration=longest", len(videos))
+	audioMixElement += fmt.Sprintf("amix=inputs=%d:duration=longest", len(audioLabels))
 
 	audioFilterComplex = append(audioFilterComplex, FilterComplex{
 		Order:         order,
@@ -130,10 +357,11 @@ func CompositeClip(videos []Video) (*Video, error) {
 		FilterElement: audioMixElement,
 	})
 
-	bg := videos[0]
 	newAudio := bg.audio
 	newAudio.filterComplex = audioFilterComplex
 	newAudio.duration = maxDuration
+	newAudio.sampleRate = format.sampleRate
+	newAudio.channels = format.channels
 
 	return &Video{
 		filenames:          filenames,
@@ -144,8 +372,8 @@ func CompositeClip(videos []Video) (*Video, error) {
 		codec:              bg.codec,
 		width:              bg.width,
 		height:             bg.height,
-		fps:                bg.fps,
-		frames:             uint64(float64(bg.fps) * maxDuration),
+		fps:                fps,
+		frames:             uint64(float64(fps) * maxDuration),
 		ffmpegArgs:         bg.ffmpegArgs,
 		isTemp:             false,
 		audio:              newAudio,
@@ -153,5 +381,54 @@ func CompositeClip(videos []Video) (*Video, error) {
 		preset:             bg.preset,
 		withMask:           bg.withMask,
 		pixelFormat:        bg.pixelFormat,
+		layers:             layers,
+		audioRemoved:       allAudioRemoved(videos),
 	}, nil
 }
+
+// prependFillClip puts fill, scaled to the background and looped to the
+// end of the last layer, below videos. The background becomes a layer that
+// disappears when it ends.
+func prependFillClip(videos []Video, fill Video) ([]Video, error) {
+	bg := videos[0]
+	var end float64
+	for _, v := range videos {
+		end = math.Max(end, v.compositeStart+v.duration)
+	}
+	filler, err := fill.Resize(int(bg.width), int(bg.height), ResizeFill)
+	if err != nil {
+		return nil, fmt.Errorf("fill clip: %w", err)
+	}
+	if filler, err = filler.LoopToDuration(end); err != nil {
+		return nil, fmt.Errorf("fill clip: %w", err)
+	}
+	if filler, err = filler.RemoveAudio(); err != nil {
+		return nil, fmt.Errorf("fill clip: %w", err)
+	}
+	filler.compositeStart = 0
+	filler.position = TopLeftPosition()
+	filler.animatedPosition, filler.animatedOpacity = nil, nil
+	out := append([]Video{*filler}, videos...)
+	out[1].position = TopLeftPosition()
+	return out, nil
+}
+
+// fitCanvas pads the background of videos to the largest layer size.
+func fitCanvas(videos []Video, color Color) ([]Video, error) {
+	bg := videos[0]
+	width, height := bg.width, bg.height
+	for _, v := range videos[1:] {
+		width, height = max(width, v.width), max(height, v.height)
+	}
+	if width == bg.width && height == bg.height {
+		return videos, nil
+	}
+	dw, dh := int(width-bg.width), int(height-bg.height)
+	padded, err := bg.PadEdges(dh/2, dh-dh/2, dw/2, dw-dw/2, color)
+	if err != nil {
+		return nil, fmt.Errorf("canvas: %w", err)
+	}
+	out := append([]Video(nil), videos...)
+	out[0] = *padded
+	return out, nil
+}
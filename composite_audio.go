@@ -1,6 +1,9 @@
 package moviego
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // initRawAudio ensures an audio object has at least one filter complex entry by adding
 // an anull filter for raw audio loaded directly from files.
@@ -42,8 +45,17 @@ func Composite(audios []Audio) (*Audio, error) {
 	audioFilterComplex := []FilterComplex{}
 	seen := make(map[string]struct{})
 
+	audioPtrs := make([]*Audio, len(audios))
+	for i := range audios {
+		audioPtrs[i] = &audios[i]
+	}
+	format := resolveAudioFormat(audioPtrs...)
+	audioLabels := make([]string, len(audios))
+
 	var maxDuration float64
-	for _, audio := range audios {
+	for i, audio := range audios {
+		audio = audio.normalized(format)
+		audioLabels[i] = audio.lastAudioLabel()
 		for _, filename := range audio.filenames {
 			if _, exists := seen[filename]; exists {
 				continue
@@ -63,8 +75,8 @@ func Composite(audios []Audio) (*Audio, error) {
 
 	// Build audio mix: [a0][a1][a2]...amix=inputs=N:duration=longest
 	audioMixElement := ""
-	for _, audio := range audios {
-		audioMixElement += fmt.Sprintf("[%s]", audio.lastAudioLabel())
+	for _, audioLabel := range audioLabels {
+		audioMixElement += fmt.Sprintf("[%s]", audioLabel)
 	}
 	audioMixElement += fmt.Sprintf("amix=inputs=%d:duration=longest", len(audios))
 
@@ -78,8 +90,8 @@ func Composite(audios []Audio) (*Audio, error) {
 	return &Audio{
 		filenames:       filenames,
 		codec:           first.codec,
-		sampleRate:      first.sampleRate,
-		channels:        first.channels,
+		sampleRate:      format.sampleRate,
+		channels:        format.channels,
 		bps:             first.bps,
 		bitRate:         first.bitRate,
 		duration:        maxDuration,
@@ -87,3 +99,145 @@ func Composite(audios []Audio) (*Audio, error) {
 		filterComplex:   audioFilterComplex,
 	}, nil
 }
+
+// DuckMix mixes music under voice, ducking the music whenever the voice is
+// active (sidechain compression keyed by the voice). Zero DuckParams fields
+// use the defaults of DefaultLaneSettings. The mix lasts as long as the
+// longer input.
+func DuckMix(voice, music Audio, params DuckParams) (*Audio, error) {
+	if len(voice.filenames) == 0 || len(music.filenames) == 0 {
+		return nil, fmt.Errorf("DuckMix: voice and music are required (voice=%s, music=%s)", safeFirstFilename(voice.filenames), safeFirstFilename(music.filenames))
+	}
+	params = params.withDefaults()
+	if err := params.validate(); err != nil {
+		return nil, fmt.Errorf("DuckMix: %w (voice=%s, music=%s)", err, safeFirstFilename(voice.filenames), safeFirstFilename(music.filenames))
+	}
+
+	initRawAudio(&voice)
+	initRawAudio(&music)
+	format := resolveAudioFormat(&voice, &music)
+	voice = voice.normalized(format)
+	music = music.normalized(format)
+
+	filenames := mergeFilenames(voice.filenames, music.filenames)
+
+	// The voice is both mixed and used as the sidechain key.
+	label := fmt.Sprintf("duck_%d", incrementGlobalCounter())
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s]asplit=2[%s_voice][%s_key];", voice.lastAudioLabel(), label, label)
+	fmt.Fprintf(&b, "%s[%s_music];", buildDuckFilter(music.lastAudioLabel(), label+"_key", params), label)
+	fmt.Fprintf(&b, "[%s_voice][%s_music]amix=inputs=2:duration=longest:normalize=0", label, label)
+
+	audioFilterComplex := append(append([]FilterComplex{}, voice.filterComplex...), music.filterComplex...)
+	audioFilterComplex = append(audioFilterComplex, FilterComplex{
+		Order:         incrementOrderCounter(),
+		Label:         label + "_a",
+		FilterElement: b.String(),
+	})
+
+	out := voice
+	out.filenames = filenames
+	out.filterComplex = audioFilterComplex
+	out.duration = max(voice.duration, music.duration)
+	return &out, nil
+}
+
+// mergeFilenames returns the files of a followed by those of b not in a.
+func mergeFilenames(a, b []string) []string {
+	out := append([]string{}, a...)
+	seen := make(map[string]struct{}, len(out))
+	for _, f := range out {
+		seen[f] = struct{}{}
+	}
+	for _, f := range b {
+		if _, exists := seen[f]; !exists {
+			seen[f] = struct{}{}
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// CompositeAudioItem is an independent audio layer placed on a video's
+// timeline, such as a voiceover, a music bed or a sound effect.
+type CompositeAudioItem struct {
+	Audio  *Audio
+	Start  float64  // seconds on the video's timeline where the item starts
+	Volume *float64 // gain of the item (nil = 1.0)
+	// Envelope automates the item's volume, timed from the item's start.
+	Envelope []VolumeKeyframe
+	// Duck, when set, lowers the item whenever the video's own audio is
+	// active (e.g. music under dialogue). Zero fields use the defaults.
+	Duck *DuckParams
+}
+
+// AddAudio mixes item into the video's audio. The video keeps its duration:
+// an item running past the end of the video is cut there.
+func (v *Video) AddAudio(item *CompositeAudioItem) (*Video, error) {
+	file, label := safeFirstFilename(v.filenames), safeLastVideoLabel(v)
+	if item == nil || item.Audio == nil || len(item.Audio.filenames) == 0 {
+		return nil, fmt.Errorf("AddAudio: no audio provided (file=%s, label=%s)", file, label)
+	}
+	if item.Start < 0 || item.Start >= v.duration {
+		return nil, fmt.Errorf("AddAudio: start must be within the video (got=%.4f, duration=%.4f, file=%s, label=%s)", item.Start, v.duration, file, label)
+	}
+	volume := 1.0
+	if item.Volume != nil {
+		volume = *item.Volume
+	}
+	if volume < 0 {
+		return nil, fmt.Errorf("AddAudio: volume must be >= 0 (got=%.4f, file=%s, label=%s)", volume, file, label)
+	}
+	var duck DuckParams
+	if item.Duck != nil {
+		duck = item.Duck.withDefaults()
+		if err := duck.validate(); err != nil {
+			return nil, fmt.Errorf("AddAudio: %w (file=%s, label=%s)", err, file, label)
+		}
+	}
+
+	out := v.clone()
+	initRawVideo(&out)
+	layer := *item.Audio
+	initRawAudio(&layer)
+	format := resolveAudioFormat(&out.audio, &layer)
+	base := out.audio.normalized(format)
+	layer = layer.normalized(format)
+
+	if len(item.Envelope) > 0 {
+		expr, err := volumeEnvelopeExpr(item.Envelope)
+		if err != nil {
+			return nil, fmt.Errorf("AddAudio: envelope: %w (file=%s, label=%s)", err, file, label)
+		}
+		layer = layer.chain(fmt.Sprintf("volume='%s':eval=frame", expr))
+	}
+	if volume != 1 {
+		layer = layer.chain(fmt.Sprintf("volume=%.4f", volume))
+	}
+	if item.Start > 0 {
+		layer = layer.chain(fmt.Sprintf("adelay=delays=%d:all=1", int64(item.Start*1000)))
+	}
+
+	mixLabel := fmt.Sprintf("audio_item_%d", incrementGlobalCounter())
+	var b strings.Builder
+	main, key := base.lastAudioLabel(), layer.lastAudioLabel()
+	if item.Duck != nil {
+		fmt.Fprintf(&b, "[%s]asplit=2[%s_main][%s_key];", main, mixLabel, mixLabel)
+		fmt.Fprintf(&b, "%s[%s_ducked];", buildDuckFilter(key, mixLabel+"_key", duck), mixLabel)
+		main, key = mixLabel+"_main", mixLabel+"_ducked"
+	}
+	fmt.Fprintf(&b, "[%s][%s]amix=inputs=2:duration=first:normalize=0", main, key)
+
+	audioFilterComplex := append(append([]FilterComplex{}, base.filterComplex...), layer.filterComplex...)
+	audioFilterComplex = append(audioFilterComplex, FilterComplex{
+		Order:         incrementOrderCounter(),
+		Label:         mixLabel + "_a",
+		FilterElement: b.String(),
+	})
+
+	out.audio = base
+	out.audio.filenames = mergeFilenames(base.filenames, layer.filenames)
+	out.audio.filterComplex = audioFilterComplex
+	out.audioRemoved = false
+	return &out, nil
+}
@@ -0,0 +1,159 @@
+package moviego
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// CompositeMismatch is a composite layer whose source makes the single-pass
+// filter graph slow, see CompositeMismatches.
+type CompositeMismatch struct {
+	Layer  int
+	Reason string
+}
+
+// PretranscodeOptions configures CompositeOptions.Pretranscode.
+type PretranscodeOptions struct {
+	// Dir receives the mezzanine files. They are read when the composite is
+	// written, so the caller removes Dir afterwards.
+	Dir string
+	// All pretranscodes every video layer, not only the mismatched ones.
+	All bool
+	// Workers is the number of layers transcoded at the same time
+	// (default: 2).
+	Workers int
+	Env     *RenderEnv
+}
+
+// heavyCodecs are slow to decode compared to the H.264 sources composites
+// are usually made of.
+var heavyCodecs = map[Codec]bool{
+	CodecHevc: true, CodecAV1: true, CodecVP9: true, CodecProres: true, CodecDNxHD: true,
+}
+
+// CompositeMismatches reports the layers of a composite whose sources differ
+// from the background enough to slow the render down: a codec that is slow
+// to decode next to other codecs, another frame rate, or a source more than
+// twice the size of the background that is scaled down on every frame.
+// Generated layers (images, colors, text, ...) are never reported.
+func CompositeMismatches(videos []Video) []CompositeMismatch {
+	if len(videos) < 2 {
+		return nil
+	}
+	bg := videos[0]
+	codecs := make(map[Codec]bool)
+	for _, v := range videos {
+		if v.codec != "" {
+			codecs[v.codec] = true
+		}
+	}
+	var mismatches []CompositeMismatch
+	for i, v := range videos[1:] {
+		if v.codec == "" {
+			continue
+		}
+		layer := i + 1
+		if heavyCodecs[v.codec] && len(codecs) > 1 {
+			mismatches = append(mismatches, CompositeMismatch{layer, fmt.Sprintf("codec %s is slow to decode", v.codec)})
+		}
+		if v.fps != bg.fps {
+			mismatches = append(mismatches, CompositeMismatch{layer, fmt.Sprintf("frame rate %d differs from the background's %d", v.fps, bg.fps)})
+		}
+		// The size is that of the source only while the layer reads its file as is.
+		if _, _, direct := v.directSource(); direct && (v.width > 2*bg.width || v.height > 2*bg.height) {
+			mismatches = append(mismatches, CompositeMismatch{layer, fmt.Sprintf("source %dx%d is over twice the background's %dx%d", v.width, v.height, bg.width, bg.height)})
+		}
+	}
+	return mismatches
+}
+
+// pretranscodeLayers renders the mismatched layers (all video layers with
+// opts.All) to lossless FFV1 mezzanine files at the background frame rate,
+// in parallel, and returns the layers reading them instead. Alpha is kept,
+// and the layers keep their position, start and animations.
+func pretranscodeLayers(videos []Video, opts PretranscodeOptions) ([]Video, error) {
+	if opts.Dir == "" {
+		return nil, fmt.Errorf("pretranscode: Dir is empty")
+	}
+	selected := make(map[int]bool)
+	for _, m := range CompositeMismatches(videos) {
+		selected[m.Layer] = true
+		slog.Info("Pretranscoding composite layer", "layer", m.Layer, "reason", m.Reason, "file", safeFirstFilename(videos[m.Layer].filenames))
+	}
+	if opts.All {
+		for i, v := range videos {
+			if i > 0 && v.codec != "" {
+				selected[i] = true
+			}
+		}
+	}
+	if len(selected) == 0 {
+		return videos, nil
+	}
+	if err := os.MkdirAll(opts.Dir, 0755); err != nil {
+		return nil, fmt.Errorf("pretranscode: %w", err)
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = 2
+	}
+
+	out := append([]Video(nil), videos...)
+	errs := make([]error, len(videos))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i := range selected {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			mezzanine, err := pretranscodeLayer(&videos[i], videos[0].fps, filepath.Join(opts.Dir, fmt.Sprintf("layer_%d_%d.mkv", i, incrementGlobalCounter())), opts.Env)
+			if err != nil {
+				errs[i] = fmt.Errorf("pretranscode: layer %d: %w", i, err)
+				return
+			}
+			out[i] = *mezzanine
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// pretranscodeLayer renders v to path and returns the layer reading it.
+func pretranscodeLayer(v *Video, fps uint64, path string, env *RenderEnv) (*Video, error) {
+	err := v.WriteVideo(VideoParameters{
+		OutputPath:     path,
+		Codec:          CodecFFV1,
+		Fps:            fps,
+		PixelFormat:    PixelFormatYUVA420P,
+		AudioCodec:     AudioCodecPCM,
+		Env:            env,
+		SilentProgress: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	mezzanine, err := NewVideoFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if v.audioRemoved {
+		if mezzanine, err = mezzanine.RemoveAudio(); err != nil {
+			return nil, err
+		}
+	}
+	mezzanine.position = v.position
+	mezzanine.compositeStart = v.compositeStart
+	mezzanine.animatedPosition = v.animatedPosition
+	mezzanine.animatedOpacity = v.animatedOpacity
+	return mezzanine, nil
+}
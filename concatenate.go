@@ -1,8 +1,42 @@
 package moviego
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
+// ConcatenateOptions configures ConcatenateWithOptions.
+type ConcatenateOptions struct {
+	// AudioCrossfade fades the audio of adjacent clips out and back in over
+	// this many seconds around each cut, half before and half after it,
+	// removing clicks and abrupt ambience changes. The video still cuts hard
+	// and every clip's audio stays aligned with its picture, so nothing
+	// drifts however many clips are joined. The audio of each clip is padded
+	// with silence or trimmed to the clip's duration.
+	AudioCrossfade float64
+	// AudioCrossfadeCurve shapes the crossfade (default: CrossfadeLinear).
+	AudioCrossfadeCurve CrossfadeCurve
+}
+
+// CrossfadeCurve is the gain curve of the fades of an audio crossfade
+// (afade curve).
+type CrossfadeCurve string
+
+const (
+	CrossfadeLinear      CrossfadeCurve = "tri"  // straight ramps; best for correlated audio such as one music track
+	CrossfadeEqualPower  CrossfadeCurve = "qsin" // quarter sine, louder near the cut; best between unrelated sources (dialog to music)
+	CrossfadeLogarithmic CrossfadeCurve = "log"
+	CrossfadeExponential CrossfadeCurve = "exp"
+)
+
+// Concatenate joins videos back to back with hard cuts.
 func Concatenate(videos []Video) (*Video, error) {
+	return ConcatenateWithOptions(videos, ConcatenateOptions{})
+}
+
+// ConcatenateWithOptions joins videos back to back with hard video cuts and
+// the audio handling configured by opts.
+func ConcatenateWithOptions(videos []Video, opts ConcatenateOptions) (*Video, error) {
 	if len(videos) == 0 {
 		return nil, fmt.Errorf("Concatenate: no videos provided")
 	}
@@ -10,6 +44,25 @@ func Concatenate(videos []Video) (*Video, error) {
 		v := videos[0]
 		return &v, nil
 	}
+	videos, err := flattenVideos("Concatenate", videos)
+	if err != nil {
+		return nil, err
+	}
+	if opts.AudioCrossfade < 0 {
+		return nil, fmt.Errorf("Concatenate: audio crossfade must be non-negative (got=%.4f)", opts.AudioCrossfade)
+	}
+	switch opts.AudioCrossfadeCurve {
+	case "":
+		opts.AudioCrossfadeCurve = CrossfadeLinear
+	case CrossfadeLinear, CrossfadeEqualPower, CrossfadeLogarithmic, CrossfadeExponential:
+	default:
+		return nil, fmt.Errorf("Concatenate: unknown crossfade curve %q (valid=tri, qsin, log, exp)", opts.AudioCrossfadeCurve)
+	}
+	for i, video := range videos {
+		if opts.AudioCrossfade > 0 && opts.AudioCrossfade >= video.duration {
+			return nil, fmt.Errorf("Concatenate: audio crossfade %.4f must be shorter than clip %d (duration=%.4f, file=%s)", opts.AudioCrossfade, i, video.duration, safeFirstFilename(video.filenames))
+		}
+	}
 
 	for i := range videos {
 		initRawVideo(&videos[i])
@@ -20,9 +73,15 @@ func Concatenate(videos []Video) (*Video, error) {
 	audioFilterComplex := []FilterComplex{}
 	seen := make(map[string]struct{})
 
+	format := resolveVideosAudioFormat(videos)
 	filterElement := ""
+	audioLabels := make([]string, len(videos))
+	durations := make([]float64, len(videos))
 	var duration float64
-	for _, video := range videos {
+	for i, video := range videos {
+		audio := video.audio.normalized(format)
+		audioLabels[i] = audio.lastAudioLabel()
+		durations[i] = video.duration
 		for _, filename := range video.filenames {
 			if _, exists := seen[filename]; exists {
 				continue
@@ -31,21 +90,31 @@ func Concatenate(videos []Video) (*Video, error) {
 			filenames = append(filenames, filename)
 		}
 		videoFilterComplex = append(videoFilterComplex, video.filterComplex...)
-		audioFilterComplex = append(audioFilterComplex, video.audio.filterComplex...)
+		audioFilterComplex = append(audioFilterComplex, audio.filterComplex...)
 
-		filterElement += fmt.Sprintf("[%s][%s]", video.lastVideoLabel(), video.audio.lastAudioLabel())
+		if opts.AudioCrossfade > 0 {
+			filterElement += fmt.Sprintf("[%s]", video.lastVideoLabel())
+		} else {
+			filterElement += fmt.Sprintf("[%s][%s]", video.lastVideoLabel(), audio.lastAudioLabel())
+		}
 		duration += video.duration
 	}
 	label := fmt.Sprintf("concat_%d", incrementGlobalCounter())
 
-	filterElement += fmt.Sprintf("concat=n=%d:a=1:v=1[%s_v][%s_a]", len(videos), label, label)
+	audioElement := ""
+	if opts.AudioCrossfade > 0 {
+		filterElement += fmt.Sprintf("concat=n=%d:a=0:v=1[%s_v]", len(videos), label)
+		audioElement = buildAudioCrossfadeChain(audioLabels, durations, opts.AudioCrossfade, opts.AudioCrossfadeCurve, label)
+	} else {
+		filterElement += fmt.Sprintf("concat=n=%d:a=1:v=1[%s_v][%s_a]", len(videos), label, label)
+	}
 
 	order := incrementOrderCounter()
 
 	audioFilterComplex = append(audioFilterComplex, FilterComplex{
 		Order:         order,
 		Label:         label + "_a",
-		FilterElement: "",
+		FilterElement: audioElement,
 	})
 	videoFilterComplex = append(videoFilterComplex, FilterComplex{
 		Order:         order,
@@ -56,6 +125,8 @@ func Concatenate(videos []Video) (*Video, error) {
 	newAudio := videos[0].audio
 	newAudio.filterComplex = audioFilterComplex
 	newAudio.duration = duration
+	newAudio.sampleRate = format.sampleRate
+	newAudio.channels = format.channels
 
 	return &Video{
 		filenames:          filenames,
@@ -76,5 +147,30 @@ func Concatenate(videos []Video) (*Video, error) {
 		withMask:           videos[0].withMask,
 		pixelFormat:        videos[0].pixelFormat,
 		position:           videos[0].position,
+		audioRemoved:       allAudioRemoved(videos),
 	}, nil
 }
+
+// buildAudioCrossfadeChain fades the audio branches out and in over half the
+// crossfade on either side of each cut and concatenates them. Each branch is
+// padded or trimmed to its clip's duration first, so the audio of every clip
+// stays aligned with its picture.
+func buildAudioCrossfadeChain(labels []string, durations []float64, crossfade float64, curve CrossfadeCurve, label string) string {
+	var b strings.Builder
+	half := crossfade / 2
+	joined := ""
+	for i, l := range labels {
+		next := fmt.Sprintf("%s_xf%d", label, i)
+		fmt.Fprintf(&b, "[%s]asetpts=PTS-STARTPTS,apad,atrim=duration=%.4f", l, durations[i])
+		if i > 0 {
+			fmt.Fprintf(&b, ",afade=t=in:d=%.4f:curve=%s", half, curve)
+		}
+		if i < len(labels)-1 {
+			fmt.Fprintf(&b, ",afade=t=out:st=%.4f:d=%.4f:curve=%s", durations[i]-half, half, curve)
+		}
+		fmt.Fprintf(&b, "[%s];", next)
+		joined += "[" + next + "]"
+	}
+	fmt.Fprintf(&b, "%sconcat=n=%d:v=0:a=1", joined, len(labels))
+	return b.String()
+}
@@ -0,0 +1,84 @@
+package moviego
+
+import (
+	"fmt"
+	"math"
+)
+
+// CropDetectOptions configures DetectCrop. Zero fields use the defaults
+// listed below.
+type CropDetectOptions struct {
+	Start    float64 // start of the sampled range in seconds (default: 0)
+	Duration float64 // length of the sampled range (default: up to the end, at most 120s)
+	Rate     float64 // frames analyzed per second (default: 2)
+	Limit    float64 // luma below which a pixel counts as black, 0..1 (default: 0.1)
+	Round    int     // the size is divisible by this (default: 2)
+	Env      *RenderEnv
+}
+
+// DetectCrop finds the black bars (letterbox or pillarbox) around the picture
+// with FFmpeg's cropdetect over a sampled range, and returns the area that
+// contains picture in any sampled frame. Without bars the full frame is
+// returned.
+func (v *Video) DetectCrop(opts CropDetectOptions) (CropParams, error) {
+	file, label := safeFirstFilename(v.filenames), safeLastVideoLabel(v)
+	if opts.Rate == 0 {
+		opts.Rate = 2
+	}
+	if opts.Limit == 0 {
+		opts.Limit = 0.1
+	}
+	if opts.Round == 0 {
+		opts.Round = 2
+	}
+	if opts.Duration == 0 {
+		opts.Duration = math.Min(v.duration-opts.Start, 120)
+	}
+	if opts.Start < 0 || opts.Duration <= 0 || opts.Start+opts.Duration > v.duration+1e-6 ||
+		opts.Rate < 0 || opts.Limit < 0 || opts.Limit >= 1 || opts.Round < 0 {
+		return CropParams{}, fmt.Errorf("DetectCrop: invalid options (start=%.4f, duration=%.4f, rate=%.4f, limit=%.4f, round=%d, video duration=%.4f, file=%s, label=%s)",
+			opts.Start, opts.Duration, opts.Rate, opts.Limit, opts.Round, v.duration, file, label)
+	}
+
+	sampled, err := v.Cut(opts.Start, opts.Start+opts.Duration)
+	if err != nil {
+		return CropParams{}, fmt.Errorf("DetectCrop: %w", err)
+	}
+	// reset=0 keeps the union of all frames seen so far.
+	stages, err := sampled.metadataPass("DetectCrop", []string{
+		fmt.Sprintf("fps=%s,cropdetect=limit=%s:round=%d:reset=0", formatFloat(opts.Rate), formatFloat(opts.Limit), opts.Round),
+	}, opts.Env)
+	if err != nil {
+		return CropParams{}, err
+	}
+
+	crop := CropParams{Width: int(v.width), Height: int(v.height)}
+	frames := stages[0]
+	if len(frames) == 0 {
+		return crop, nil
+	}
+	last := frames[len(frames)-1]
+	w, okW := last.metadataInt("lavfi.cropdetect.w")
+	h, okH := last.metadataInt("lavfi.cropdetect.h")
+	x, okX := last.metadataInt("lavfi.cropdetect.x")
+	y, okY := last.metadataInt("lavfi.cropdetect.y")
+	// cropdetect reports negative sizes for fully black samples.
+	if !okW || !okH || !okX || !okY || w <= 0 || h <= 0 {
+		return crop, nil
+	}
+	return CropParams{X: x, Y: y, Width: w, Height: h}, nil
+}
+
+// AutoCrop removes the black bars found by DetectCrop. A video without bars
+// is returned unchanged.
+func (v *Video) AutoCrop(opts CropDetectOptions) (*Video, error) {
+	crop, err := v.DetectCrop(opts)
+	if err != nil {
+		return nil, err
+	}
+	if crop.X == 0 && crop.Y == 0 && crop.Width == int(v.width) && crop.Height == int(v.height) {
+		c := v.clone()
+		return &c, nil
+	}
+	return v.Crop(crop)
+}
@@ -2,6 +2,7 @@ package moviego
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 )
 
@@ -297,3 +298,152 @@ func applyCurve(progress string, c Curve) string {
 		return p
 	}
 }
+
+// valueAt evaluates the animation at time t in Go, matching toExpr.
+func (a Animation) valueAt(t float64) float64 {
+	if a.Start == a.End {
+		return a.Start
+	}
+	duration := a.EndTime - a.StartTime
+	if duration <= 0 {
+		return a.End
+	}
+	progress := math.Min(math.Max((t-a.StartTime)/duration, 0), 1)
+	return a.Start + (a.End-a.Start)*evalCurve(progress, a.Curve)
+}
+
+// positionAt evaluates the animated position at time t, matching toExprX/Y.
+func (ap AnimatedPosition) positionAt(t float64) (x, y float64) {
+	x = Animation{Start: parsePositionValue(ap.Start.X), End: parsePositionValue(ap.End.X), StartTime: ap.StartTime, EndTime: ap.EndTime, Curve: ap.Curve}.valueAt(t)
+	y = Animation{Start: parsePositionValue(ap.Start.Y), End: parsePositionValue(ap.End.Y), StartTime: ap.StartTime, EndTime: ap.EndTime, Curve: ap.Curve}.valueAt(t)
+	return x, y
+}
+
+// evalCurve is applyCurve evaluated in Go for a progress p in [0, 1].
+func evalCurve(p float64, c Curve) float64 {
+	pow := math.Pow
+	bounceOut := func(p float64) float64 {
+		switch {
+		case p < 0.36363636:
+			return 7.5625 * pow(p, 2)
+		case p < 0.72727272:
+			return 7.5625*pow(p-0.54545454, 2) + 0.75
+		case p < 0.90909090:
+			return 7.5625*pow(p-0.81818181, 2) + 0.9375
+		default:
+			return 7.5625*pow(p-0.95454545, 2) + 0.984375
+		}
+	}
+	inOut := func(in, out func() float64) float64 {
+		if p < 0.5 {
+			return in()
+		}
+		return out()
+	}
+
+	switch c {
+	case Linear:
+		return p
+
+	case EaseIn:
+		return pow(p, 2)
+	case EaseOut:
+		return 1 - pow(1-p, 2)
+	case EaseInOut:
+		return inOut(func() float64 { return 2 * pow(p, 2) }, func() float64 { return (2 - pow(-2*p+2, 2)) / 2 })
+
+	case CubicIn:
+		return pow(p, 3)
+	case CubicOut:
+		return 1 - pow(1-p, 3)
+	case CubicInOut:
+		return inOut(func() float64 { return 4 * pow(p, 3) }, func() float64 { return 1 - pow(-2*p+2, 3)/2 })
+
+	case QuartIn:
+		return pow(p, 4)
+	case QuartOut:
+		return 1 - pow(1-p, 4)
+	case QuartInOut:
+		return inOut(func() float64 { return 8 * pow(p, 4) }, func() float64 { return 1 - pow(-2*p+2, 4)/2 })
+
+	case QuintIn:
+		return pow(p, 5)
+	case QuintOut:
+		return 1 - pow(1-p, 5)
+	case QuintInOut:
+		return inOut(func() float64 { return 16 * pow(p, 5) }, func() float64 { return 1 - pow(-2*p+2, 5)/2 })
+
+	case SineIn:
+		return 1 - math.Cos(math.Pi*p/2)
+	case SineOut:
+		return math.Sin(math.Pi * p / 2)
+	case SineInOut:
+		return (1 - math.Cos(math.Pi*p)) / 2
+
+	case ExpoIn:
+		if p < 0.0001 {
+			return 0
+		}
+		return pow(2, 10*p-10)
+	case ExpoOut:
+		if p > 0.9999 {
+			return 1
+		}
+		return 1 - pow(2, -10*p)
+	case ExpoInOut:
+		switch {
+		case p < 0.0001:
+			return 0
+		case p > 0.9999:
+			return 1
+		}
+		return inOut(func() float64 { return pow(2, 20*p-10) / 2 }, func() float64 { return (2 - pow(2, -20*p+10)) / 2 })
+
+	case CircIn:
+		return 1 - math.Sqrt(1-pow(p, 2))
+	case CircOut:
+		return math.Sqrt(1 - pow(p-1, 2))
+	case CircInOut:
+		return inOut(func() float64 { return (1 - math.Sqrt(1-pow(2*p, 2))) / 2 }, func() float64 { return (math.Sqrt(1-pow(-2*p+2, 2)) + 1) / 2 })
+
+	case BackIn:
+		return 2.70158*pow(p, 3) - 1.70158*pow(p, 2)
+	case BackOut:
+		return 1 + 2.70158*pow(p-1, 3) + 1.70158*pow(p-1, 2)
+	case BackInOut:
+		return inOut(
+			func() float64 { return (pow(2*p, 2) * ((2.59491*p+0)*2*p - 2.59491)) / 2 },
+			func() float64 { return (pow(2*p-2, 2)*((2.59491*(p-1)+1.59491)*2*(p-1)+2.59491) + 2) / 2 })
+
+	case ElasticIn, ElasticOut, ElasticInOut:
+		switch {
+		case p < 0.0001:
+			return 0
+		case p > 0.9999:
+			return 1
+		case c == ElasticIn:
+			return -pow(2, 10*p-10) * math.Sin((p*10-10.75)*(2*math.Pi/3))
+		case c == ElasticOut:
+			return pow(2, -10*p)*math.Sin((p*10-0.75)*(2*math.Pi/3)) + 1
+		}
+		return inOut(
+			func() float64 { return -(pow(2, 20*p-10) * math.Sin((20*p-11.125)*(2*math.Pi/4.5))) / 2 },
+			func() float64 { return (pow(2, -20*p+10)*math.Sin((20*p-11.125)*(2*math.Pi/4.5)))/2 + 1 })
+
+	case BounceIn:
+		return 1 - bounceOut(p)
+	case BounceOut:
+		return bounceOut(p)
+	case BounceInOut:
+		return inOut(func() float64 { return (1 - bounceOut(1-2*p)) / 2 }, func() float64 { return (bounceOut(2*p-1) + 1) / 2 })
+
+	case Spring:
+		return 1 - math.Exp(-p*5)*math.Cos(p*2*math.Pi)
+
+	case Steps:
+		return math.Floor(p*5) / 5
+
+	default:
+		return p
+	}
+}
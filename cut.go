@@ -12,6 +12,13 @@ import (
 //
 // Returns a new Video object with updated metadata (no file is created until WriteVideo is called)
 func (v *Video) Cut(start, end float64) (*Video, error) {
+	if len(v.overlays) > 0 {
+		flat, err := v.FlattenOverlays()
+		if err != nil {
+			return nil, fmt.Errorf("Cut: %w", err)
+		}
+		return flat.Cut(start, end)
+	}
 
 	// Validate inputs
 	if start < 0 {
@@ -73,6 +80,20 @@ func (v *Video) Cut(start, end float64) (*Video, error) {
 		})
 	}
 
+	// Remember the absolute range while the video is still a plain read of
+	// one file, so WriteVideo can seek the input instead of decoding it.
+	var trim *directTrim
+	if len(v.filterComplex) == 0 && len(v.audio.filterComplex) == 0 {
+		trim = &directTrim{start: start, end: end, videoEntries: 1, audioEntries: 1}
+	} else if t := v.directTrim(); t != nil {
+		trim = &directTrim{
+			start:        t.start + start,
+			end:          t.start + end,
+			videoEntries: len(videoFilterComplex),
+			audioEntries: len(audioFilterComplex),
+		}
+	}
+
 	// Create a new video with copied properties
 	newAudio := v.audio
 	newAudio.filterComplex = audioFilterComplex
@@ -96,8 +117,13 @@ func (v *Video) Cut(start, end float64) (*Video, error) {
 		startTime:        0,
 		endTime:          end - start,
 		position:         v.position,
+		compositeStart:   v.compositeStart,
+		invalid:          v.invalid,
+		trim:             trim,
 		animatedPosition: v.animatedPosition,
 		animatedOpacity:  v.animatedOpacity,
+		startTimecode:    offsetTimecode(v.startTimecode, start, v.fps),
+		audioRemoved:     v.audioRemoved,
 	}
 
 	return newVideo, nil
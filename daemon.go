@@ -0,0 +1,307 @@
+package moviego
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DaemonJob builds the video of a render request from its arguments. Asset
+// references in the arguments can be resolved with assets (nil when the
+// daemon has no AssetManager).
+type DaemonJob func(args map[string]string, assets *AssetManager) (*Video, error)
+
+// DaemonConfig configures a Daemon. Zero fields use the defaults listed below.
+type DaemonConfig struct {
+	Socket string // path of the Unix socket accepting requests (required)
+
+	// Jobs are the renders clients can request, by name, e.g. a template
+	// filled with the request's arguments.
+	Jobs map[string]DaemonJob
+	// Parameters are used for every render; OutputPath is set per request.
+	Parameters VideoParameters
+
+	Assets   *AssetManager // resolves the assets of jobs and Preload
+	Preload  []string      // asset references fetched at startup, e.g. template footage and fonts
+	Encoders []string      // encoders whose options are probed at startup (default: the detected H.264 encoder)
+	Workers  int           // renders run in parallel across all clients (default: 1)
+}
+
+func (c DaemonConfig) withDefaults() DaemonConfig {
+	if c.Workers == 0 {
+		c.Workers = 1
+	}
+	return c
+}
+
+// DaemonRequest asks a daemon to render a job to Output. Requests and
+// responses are exchanged as one JSON object per line.
+type DaemonRequest struct {
+	ID     string            `json:"id,omitempty"` // echoed in the response
+	Job    string            `json:"job"`
+	Output string            `json:"output"`
+	Args   map[string]string `json:"args,omitempty"`
+}
+
+// DaemonResponse reports the result of a DaemonRequest.
+type DaemonResponse struct {
+	ID       string    `json:"id,omitempty"`
+	Output   string    `json:"output,omitempty"`
+	Success  bool      `json:"success"`
+	Error    string    `json:"error,omitempty"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+}
+
+// Daemon is a long-running render worker. Startup work that every render of
+// a short-lived process repeats — locating FFmpeg, detecting the hardware
+// encoder, probing encoder options, building the font cache and fetching
+// template assets — is done once in NewDaemon and stays warm for all
+// requests, which clients send over a local Unix socket (see DaemonRender).
+type Daemon struct {
+	cfg      DaemonConfig
+	listener net.Listener
+	slots    chan struct{} // one token per worker
+
+	mu    sync.Mutex
+	conns map[net.Conn]bool
+}
+
+// NewDaemon validates cfg, warms the caches and listens on cfg.Socket.
+func NewDaemon(cfg DaemonConfig) (*Daemon, error) {
+	cfg = cfg.withDefaults()
+	if cfg.Socket == "" {
+		return nil, fmt.Errorf("NewDaemon: Socket is required")
+	}
+	if len(cfg.Jobs) == 0 {
+		return nil, fmt.Errorf("NewDaemon: at least one job is required")
+	}
+	if cfg.Workers < 0 {
+		return nil, fmt.Errorf("NewDaemon: Workers must not be negative (got=%d)", cfg.Workers)
+	}
+	if len(cfg.Preload) > 0 && cfg.Assets == nil {
+		return nil, fmt.Errorf("NewDaemon: Preload requires an AssetManager")
+	}
+	if err := warmDaemon(cfg); err != nil {
+		return nil, fmt.Errorf("NewDaemon: %w", err)
+	}
+
+	// A socket left behind by a crashed daemon blocks Listen.
+	if info, err := os.Stat(cfg.Socket); err == nil && info.Mode()&os.ModeSocket != 0 {
+		if conn, err := net.Dial("unix", cfg.Socket); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("NewDaemon: a daemon is already listening on '%s'", cfg.Socket)
+		}
+		_ = os.Remove(cfg.Socket)
+	}
+	listener, err := listenPrivate(cfg.Socket)
+	if err != nil {
+		return nil, fmt.Errorf("NewDaemon: failed to listen on '%s': %w", cfg.Socket, err)
+	}
+	return &Daemon{
+		cfg:      cfg,
+		listener: listener,
+		slots:    make(chan struct{}, cfg.Workers),
+		conns:    make(map[net.Conn]bool),
+	}, nil
+}
+
+// listenPrivate listens on the Unix socket path with mode 0600. Requests
+// write files as the daemon user, so the socket is created inside a 0700
+// directory, restricted there and only then moved to path: it is never
+// reachable with the umask permissions.
+func listenPrivate(path string) (net.Listener, error) {
+	dir, err := os.MkdirTemp(filepath.Dir(path), ".moviego-socket-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	private := filepath.Join(dir, "s")
+	listener, err := net.Listen("unix", private)
+	if err != nil {
+		return nil, err
+	}
+	// The socket is renamed, so Close removes it from its final path.
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+	if err := os.Chmod(private, 0600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	if err := os.Rename(private, path); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
+
+// warmDaemon fills the process-wide caches used by renders.
+func warmDaemon(cfg DaemonConfig) error {
+	if _, err := getFFmpegPath(); err != nil {
+		return fmt.Errorf("failed to get ffmpeg path: %w", err)
+	}
+	if _, err := getFFprobePath(); err != nil {
+		return fmt.Errorf("failed to get ffprobe path: %w", err)
+	}
+	encoders := cfg.Encoders
+	if len(encoders) == 0 {
+		encoders = []string{selectBestH264Codec()}
+	}
+	for _, encoder := range encoders {
+		if _, err := probeEncoderCapability(encoder); err != nil {
+			return err
+		}
+	}
+	// drawtext resolves font families through fontconfig; an up-to-date
+	// cache saves every FFmpeg process the directory scan.
+	if fcCache, err := lookPath("fc-cache"); err == nil {
+		if output, err := commandCombinedOutput(fcCache); err != nil {
+			slog.Warn("Daemon: failed to update the font cache", "error", err, "output", string(output))
+		}
+	}
+	for _, ref := range cfg.Preload {
+		if _, err := cfg.Assets.Resolve(ref); err != nil {
+			return fmt.Errorf("failed to preload '%s': %w", ref, err)
+		}
+	}
+	return nil
+}
+
+// Serve accepts clients until stop is closed or Close is called.
+func (d *Daemon) Serve(stop <-chan struct{}) error {
+	go func() {
+		<-stop
+		d.Close()
+	}()
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		conn, err := d.listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return fmt.Errorf("Daemon.Serve: %w", err)
+		}
+		d.mu.Lock()
+		d.conns[conn] = true
+		d.mu.Unlock()
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.serveConn(conn)
+		}()
+	}
+}
+
+// Close stops accepting clients, disconnects the connected ones and removes
+// the socket. Renders in progress run to completion.
+func (d *Daemon) Close() error {
+	err := d.listener.Close()
+	if err == nil {
+		_ = os.Remove(d.cfg.Socket)
+	}
+	d.mu.Lock()
+	for conn := range d.conns {
+		conn.Close()
+	}
+	d.mu.Unlock()
+	if errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+	return err
+}
+
+// serveConn answers the requests of one client in order.
+func (d *Daemon) serveConn(conn net.Conn) {
+	defer func() {
+		conn.Close()
+		d.mu.Lock()
+		delete(d.conns, conn)
+		d.mu.Unlock()
+	}()
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	encoder := json.NewEncoder(conn)
+	for scanner.Scan() {
+		var req DaemonRequest
+		var resp DaemonResponse
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			now := time.Now()
+			resp = DaemonResponse{Error: fmt.Sprintf("invalid request: %v", err), Started: now, Finished: now}
+		} else {
+			resp = d.handle(req)
+		}
+		if err := encoder.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+// handle renders one request once a worker is free.
+func (d *Daemon) handle(req DaemonRequest) DaemonResponse {
+	d.slots <- struct{}{}
+	defer func() { <-d.slots }()
+
+	resp := DaemonResponse{ID: req.ID, Started: time.Now()}
+	err := d.render(req)
+	resp.Finished = time.Now()
+	if err != nil {
+		resp.Error = err.Error()
+		slog.Error("Daemon: render failed", "job", req.Job, "id", req.ID, "error", err)
+	} else {
+		resp.Success = true
+		resp.Output = req.Output
+		slog.Info("Daemon: rendered", "job", req.Job, "id", req.ID, "output", req.Output)
+	}
+	return resp
+}
+
+func (d *Daemon) render(req DaemonRequest) (err error) {
+	// A panicking job must not stop the daemon.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	job, ok := d.cfg.Jobs[req.Job]
+	if !ok {
+		return fmt.Errorf("unknown job %q", req.Job)
+	}
+	if req.Output == "" {
+		return fmt.Errorf("output is required (job=%s)", req.Job)
+	}
+	v, err := job(req.Args, d.cfg.Assets)
+	if err != nil {
+		return err
+	}
+	parms := d.cfg.Parameters
+	parms.OutputPath = req.Output
+	parms.SilentProgress = true
+	return v.WriteVideo(parms)
+}
+
+// DaemonRender sends req to the daemon listening on socket and waits for the
+// render to finish. A failed render is reported in the response, not as an
+// error.
+func DaemonRender(socket string, req DaemonRequest) (DaemonResponse, error) {
+	var resp DaemonResponse
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return resp, fmt.Errorf("DaemonRender: failed to connect to '%s': %w", socket, err)
+	}
+	defer conn.Close()
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return resp, fmt.Errorf("DaemonRender: failed to send request: %w", err)
+	}
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return resp, fmt.Errorf("DaemonRender: failed to read response: %w", err)
+	}
+	return resp, nil
+}
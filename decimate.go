@@ -0,0 +1,87 @@
+package moviego
+
+import (
+	"fmt"
+	"math"
+)
+
+// DuplicateRun is a run of consecutive duplicated frames, seen as a stutter.
+type DuplicateRun struct {
+	Start  float64 // time of the first duplicate
+	End    float64 // time of the last duplicate
+	Frames int
+}
+
+// DuplicateReport is the result of AnalyzeDuplicates.
+type DuplicateReport struct {
+	TotalFrames     int
+	DuplicateFrames int
+	Percentage      float64        // share of duplicated frames, 0..100
+	UniqueFps       float64        // rate of distinct frames, e.g. ~30 for 30fps content in a 60fps file
+	Duplicates      []float64      // times of the duplicated frames
+	Runs            []DuplicateRun // consecutive duplicates
+}
+
+// AnalyzeDuplicates finds frames that repeat the previous one with FFmpeg's
+// mpdecimate, e.g. in screen recordings captured at a rate different from
+// the content.
+func (v *Video) AnalyzeDuplicates(env *RenderEnv) (DuplicateReport, error) {
+	stages, err := v.metadataPass("AnalyzeDuplicates", []string{"", "mpdecimate"}, env)
+	if err != nil {
+		return DuplicateReport{}, err
+	}
+	all, kept := stages[0], stages[1]
+	// metadata=print numbers the frames it sees, so kept frames are matched
+	// by time rather than number.
+	keptTimes := make(map[float64]bool, len(kept))
+	for _, f := range kept {
+		keptTimes[f.Time] = true
+	}
+
+	report := DuplicateReport{TotalFrames: len(all)}
+	var run *DuplicateRun
+	for _, f := range all {
+		if keptTimes[f.Time] {
+			run = nil
+			continue
+		}
+		report.Duplicates = append(report.Duplicates, f.Time)
+		if run == nil {
+			report.Runs = append(report.Runs, DuplicateRun{Start: f.Time})
+			run = &report.Runs[len(report.Runs)-1]
+		}
+		run.End = f.Time
+		run.Frames++
+	}
+	report.DuplicateFrames = len(report.Duplicates)
+	if report.TotalFrames > 0 {
+		report.Percentage = float64(report.DuplicateFrames) / float64(report.TotalFrames) * 100
+	}
+	if v.duration > 0 {
+		report.UniqueFps = float64(report.TotalFrames-report.DuplicateFrames) / v.duration
+	}
+	return report, nil
+}
+
+// Decimate removes duplicated frames and retimes the remaining ones to a
+// constant fps, keeping the duration and audio sync. With fps 0 the rate of
+// distinct frames measured by AnalyzeDuplicates is used.
+func (v *Video) Decimate(fps uint64) (*Video, error) {
+	if fps == 0 {
+		report, err := v.AnalyzeDuplicates(nil)
+		if err != nil {
+			return nil, fmt.Errorf("Decimate: %w", err)
+		}
+		fps = uint64(math.Round(report.UniqueFps))
+		if fps == 0 {
+			return nil, fmt.Errorf("Decimate: could not measure the frame rate (unique fps=%.4f, file=%s, label=%s)", report.UniqueFps, safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+		}
+	}
+	decimated, err := v.videoFilter(fmt.Sprintf("mpdecimate,fps=%d", fps))
+	if err != nil {
+		return nil, fmt.Errorf("Decimate[file=%s, label=%s]: %w", safeFirstFilename(v.filenames), safeLastVideoLabel(v), err)
+	}
+	decimated.fps = fps
+	decimated.frames = uint64(float64(fps) * decimated.duration)
+	return decimated, nil
+}
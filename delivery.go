@@ -0,0 +1,146 @@
+package moviego
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// FieldOrder is the field order of interlaced output.
+type FieldOrder string
+
+const (
+	FieldOrderTop    FieldOrder = "tff" // top field first (1080i50, 1080i59.94)
+	FieldOrderBottom FieldOrder = "bff" // bottom field first (DV, some SD formats)
+)
+
+// DeliveryProfile describes a delivery format, e.g. a broadcaster's file
+// specification. Set VideoParameters.Delivery to render to it: WriteVideo
+// fits the picture to the frame size, interlaces it, normalizes the
+// loudness and encodes with the profile's settings, which take precedence
+// over the codec, fps, bitrate and pixel format of the parameters.
+// VideoParameters.Keyframes in turn overrides the profile's GOP.
+type DeliveryProfile struct {
+	Name        string
+	Width       int
+	Height      int
+	Fps         uint64     // frames per second (25 for 1080i50)
+	FieldOrder  FieldOrder // empty for progressive output
+	Codec       Codec
+	Bitrate     string
+	PixelFormat PixelFormat
+	VideoArgs   []string // further encoder arguments
+	Container   string   // required output extension, e.g. ".mxf" (empty = any)
+
+	AudioCodec string // e.g. "pcm_s24le" (empty = FFmpeg default for the container)
+	SampleRate int
+	// Loudness is the integrated loudness target in LUFS (-23 for EBU R128,
+	// -24 for ATSC A/85); 0 leaves the levels untouched.
+	Loudness float64
+	TruePeak float64 // maximum true peak in dBTP (default: -1)
+}
+
+// xdcamHD422Args are the MPEG-2 4:2:2 Profile @ High Level settings of
+// XDCAM HD422 (Sony MPEG HD422, SMPTE RDD 9): 50 Mbit/s CBR, long GOP.
+var xdcamHD422Args = []string{
+	"-profile:v", "0", "-level:v", "2",
+	"-minrate", "50M", "-maxrate", "50M", "-bufsize", "17825792",
+	"-g", "12", "-bf", "2", "-intra_vlc", "1", "-non_linear_quant", "1",
+	"-dc", "10", "-qmin", "1", "-qmax", "12",
+	"-color_primaries", "bt709", "-color_trc", "bt709", "-colorspace", "bt709",
+}
+
+var (
+	// DeliveryXDCAMHD422_1080i50 is XDCAM HD422 1080i50 in MXF with 24-bit
+	// PCM audio at EBU R128 loudness, as required by most European broadcasters.
+	DeliveryXDCAMHD422_1080i50 = DeliveryProfile{
+		Name:  "XDCAM HD422 1080i50",
+		Width: 1920, Height: 1080, Fps: 25, FieldOrder: FieldOrderTop,
+		Codec: CodecMpeg2video, Bitrate: "50M", PixelFormat: PixelFormatYUV422P,
+		VideoArgs:  append([]string{"-flags", "+ilme+ildct", "-top", "1"}, xdcamHD422Args...),
+		Container:  ".mxf",
+		AudioCodec: "pcm_s24le", SampleRate: 48000, Loudness: -23, TruePeak: -1,
+	}
+	// DeliveryXDCAMHD422_720p50 is the progressive 720p50 variant of XDCAM HD422.
+	DeliveryXDCAMHD422_720p50 = DeliveryProfile{
+		Name:  "XDCAM HD422 720p50",
+		Width: 1280, Height: 720, Fps: 50,
+		Codec: CodecMpeg2video, Bitrate: "50M", PixelFormat: PixelFormatYUV422P,
+		VideoArgs:  xdcamHD422Args,
+		Container:  ".mxf",
+		AudioCodec: "pcm_s24le", SampleRate: 48000, Loudness: -23, TruePeak: -1,
+	}
+)
+
+// validate checks the profile and that output fits its container.
+func (d *DeliveryProfile) validate(output string) error {
+	if d.Width <= 0 || d.Height <= 0 || d.Fps == 0 {
+		return fmt.Errorf("delivery profile %q: size and fps are required (got=%dx%d@%d)", d.Name, d.Width, d.Height, d.Fps)
+	}
+	if d.FieldOrder != "" && d.FieldOrder != FieldOrderTop && d.FieldOrder != FieldOrderBottom {
+		return fmt.Errorf("delivery profile %q: unknown field order %q (valid=tff, bff)", d.Name, d.FieldOrder)
+	}
+	if d.FieldOrder != "" && d.Height%2 != 0 {
+		return fmt.Errorf("delivery profile %q: interlaced height must be even (got=%d)", d.Name, d.Height)
+	}
+	if d.Loudness > 0 || d.TruePeak > 0 {
+		return fmt.Errorf("delivery profile %q: loudness and true peak must be <= 0 (got=%s LUFS, %s dBTP)", d.Name, formatFloat(d.Loudness), formatFloat(d.TruePeak))
+	}
+	if d.Container != "" && !strings.EqualFold(filepath.Ext(output), d.Container) {
+		return fmt.Errorf("delivery profile %q requires a %s output (got=%s)", d.Name, d.Container, output)
+	}
+	return nil
+}
+
+// prepare returns v fitted to the profile: letterboxed to its frame size and
+// interlaced. The loudness is normalized by WriteVideo, see loudness.
+func (d *DeliveryProfile) prepare(v *Video) (*Video, error) {
+	fitted, err := v.videoFilter(fmt.Sprintf("scale=w=%d:h=%d:force_original_aspect_ratio=decrease,pad=w=%d:h=%d:x=(ow-iw)/2:y=(oh-ih)/2,setsar=1",
+		d.Width, d.Height, d.Width, d.Height))
+	if err != nil {
+		return nil, err
+	}
+	fitted.width, fitted.height = uint64(d.Width), uint64(d.Height)
+
+	if d.FieldOrder != "" {
+		// Each output frame weaves two fields sampled 1/(2*fps) apart, so
+		// motion stays as smooth as on an interlaced camera.
+		fitted, err = fitted.videoFilter(fmt.Sprintf("fps=%d,interlace=scan=%s:lowpass=complex,setfield=%s", 2*d.Fps, d.FieldOrder, d.FieldOrder))
+		if err != nil {
+			return nil, err
+		}
+	}
+	fitted.fps = d.Fps
+
+	if d.SampleRate > 0 {
+		fitted.audio = fitted.audio.chain(fmt.Sprintf("aresample=%d", d.SampleRate))
+		fitted.audio.sampleRate = uint64(d.SampleRate)
+	}
+	return fitted, nil
+}
+
+// apply returns parms with the profile's encoding settings.
+func (d *DeliveryProfile) apply(parms VideoParameters) VideoParameters {
+	parms.Codec = d.Codec
+	parms.Fps = d.Fps
+	if d.Bitrate != "" {
+		parms.Bitrate = d.Bitrate
+	}
+	if d.PixelFormat != "" {
+		parms.PixelFormat = d.PixelFormat
+	}
+	return parms
+}
+
+// loudness returns the loudness target of the profile, nil if the profile
+// leaves the levels untouched.
+func (d *DeliveryProfile) loudness() *LoudnessTarget {
+	if d.Loudness == 0 {
+		return nil
+	}
+	target := &LoudnessTarget{Integrated: d.Loudness}
+	if d.TruePeak != 0 {
+		target.TruePeak = dBTP(d.TruePeak)
+	}
+	return target
+}
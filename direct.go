@@ -0,0 +1,50 @@
+package moviego
+
+import "fmt"
+
+// directTrim is the absolute source range of a video built only from Cut calls
+// on one file. The entry counts tell WriteVideo whether other filters have
+// been chained since.
+type directTrim struct {
+	start, end   float64
+	videoEntries int
+	audioEntries int
+}
+
+// directTrim returns the trim of v if no filter was chained after the last Cut.
+func (v *Video) directTrim() *directTrim {
+	t := v.trim
+	if t == nil || len(v.filterComplex) != t.videoEntries || len(v.audio.filterComplex) != t.audioEntries {
+		return nil
+	}
+	return t
+}
+
+// directSource reports whether v is a plain read of one file, optionally cut,
+// and returns the source range (end is 0 when the whole file is used).
+func (v *Video) directSource() (start, end float64, ok bool) {
+	if len(v.filenames) != 1 {
+		return 0, 0, false
+	}
+	for _, f := range v.audio.filenames {
+		if f != v.filenames[0] {
+			return 0, 0, false
+		}
+	}
+	if len(v.filterComplex) == 0 && len(v.audio.filterComplex) == 0 {
+		return 0, 0, true
+	}
+	if t := v.directTrim(); t != nil {
+		return t.start, t.end, true
+	}
+	return 0, 0, false
+}
+
+// directArgs returns the input and map arguments reading filename from start to end.
+func directArgs(filename string, start, end float64) []string {
+	var args []string
+	if end > 0 {
+		args = append(args, "-ss", fmt.Sprintf("%.4f", start), "-to", fmt.Sprintf("%.4f", end))
+	}
+	return append(args, "-i", filename, "-map", "0:v:0", "-map", "0:a:0?")
+}
@@ -0,0 +1,71 @@
+package moviego
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+)
+
+// DurationCheck configures VideoParameters.VerifyDuration.
+type DurationCheck struct {
+	// Tolerance is the allowed difference in seconds between the planned
+	// and the rendered durations (default: 0.1). The frame count may differ
+	// by the frames in Tolerance plus one.
+	Tolerance float64
+	// WarnOnly logs mismatches instead of failing the render.
+	WarnOnly bool
+}
+
+// DurationMismatchError is returned by WriteVideo when the output of a
+// render does not last as long as planned, e.g. because the filter graph
+// dropped or extended content.
+type DurationMismatchError struct {
+	Output     string
+	Mismatches []string // e.g. "duration 9.5200s, planned 10.0000s"
+}
+
+func (e *DurationMismatchError) Error() string {
+	return fmt.Sprintf("%s does not match the plan: %s", e.Output, strings.Join(e.Mismatches, "; "))
+}
+
+// verifyDuration probes output and compares its video duration, frame count
+// and audio duration with the planned duration and frame rate.
+func verifyDuration(op, output string, planned float64, fps uint64, withAudio bool, check DurationCheck) error {
+	tolerance := check.Tolerance
+	if tolerance <= 0 {
+		tolerance = 0.1
+	}
+	out, err := NewVideoFile(output)
+	if err != nil {
+		return fmt.Errorf("%s: verify duration: %w", op, err)
+	}
+	var mismatches []string
+	if d := out.GetDuration(); math.Abs(d-planned) > tolerance {
+		mismatches = append(mismatches, fmt.Sprintf("duration %.4fs, planned %.4fs", d, planned))
+	}
+	// Containers without a frame count (MKV, WebM) are only checked on time.
+	if frames := out.GetFrames(); frames > 0 && fps > 0 {
+		want := int64(math.Round(planned * float64(fps)))
+		if slack := int64(math.Ceil(tolerance*float64(fps))) + 1; frames < want-slack || frames > want+slack {
+			mismatches = append(mismatches, fmt.Sprintf("%d frames, planned %d", frames, want))
+		}
+	}
+	if withAudio {
+		switch audio := out.GetAudio(); {
+		case audio.GetCodec() == "":
+			mismatches = append(mismatches, "no audio stream, planned one")
+		case audio.GetDuration() > 0 && math.Abs(audio.GetDuration()-planned) > tolerance:
+			mismatches = append(mismatches, fmt.Sprintf("audio %.4fs, planned %.4fs", audio.GetDuration(), planned))
+		}
+	}
+	if len(mismatches) == 0 {
+		return nil
+	}
+	mismatch := &DurationMismatchError{Output: output, Mismatches: mismatches}
+	if check.WarnOnly {
+		slog.Warn("Duration check failed", "op", op, "output", output, "mismatches", strings.Join(mismatches, "; "))
+		return nil
+	}
+	return fmt.Errorf("%s: %w", op, mismatch)
+}
@@ -0,0 +1,201 @@
+package moviego
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// EncoderOptions holds encoder-specific settings. Only the group matching
+// the encoder WriteVideo uses is applied, so a render with CodecH264Auto can
+// carry settings for every encoder it may pick. Options are checked against
+// the encoder of the installed FFmpeg before encoding.
+type EncoderOptions struct {
+	X264  *X264Options  // libx264 and libx265
+	NVENC *NVENCOptions // h264_nvenc, hevc_nvenc and av1_nvenc
+	QSV   *QSVOptions   // h264_qsv, hevc_qsv and av1_qsv
+}
+
+// X264Options configures libx264 and libx265.
+type X264Options struct {
+	Tune    string            // e.g. "film", "animation", "stillimage", "zerolatency"
+	Profile string            // e.g. "high", "main"
+	CRF     float64           // constant rate factor (0 = encoder default)
+	Params  map[string]string // -x264-params / -x265-params, e.g. {"bframes": "3"}
+}
+
+// NVENCOptions configures NVIDIA encoders.
+type NVENCOptions struct {
+	RateControl string // -rc, e.g. "vbr", "cbr", "constqp"
+	Tune        string // -tune, e.g. "hq", "ll", "ull", "lossless"
+	CQ          int    // constant quality for vbr (0 = encoder default)
+	Lookahead   int    // -rc-lookahead frames (0 = encoder default)
+	SpatialAQ   bool
+	TemporalAQ  bool
+	Multipass   string // e.g. "qres", "fullres"
+}
+
+// QSVOptions configures Intel Quick Sync encoders.
+type QSVOptions struct {
+	LowPower       bool // use the fixed-function (VDENC) path
+	LookAheadDepth int  // frames of look-ahead (0 = disabled)
+	GlobalQuality  int  // ICQ quality (0 = encoder default)
+}
+
+// args returns the FFmpeg arguments for encoder, validated against the
+// options the encoder supports.
+func (o EncoderOptions) args(encoder string) ([]string, error) {
+	var args []string
+	switch encoder {
+	case "libx264", "libx265":
+		if x := o.X264; x != nil {
+			if x.Tune != "" {
+				args = append(args, "-tune", x.Tune)
+			}
+			if x.Profile != "" {
+				args = append(args, "-profile:v", x.Profile)
+			}
+			if x.CRF != 0 {
+				args = append(args, "-crf", formatFloat(x.CRF))
+			}
+			if len(x.Params) > 0 {
+				keys := make([]string, 0, len(x.Params))
+				for k := range x.Params {
+					keys = append(keys, k)
+				}
+				sort.Strings(keys)
+				pairs := make([]string, len(keys))
+				for i, k := range keys {
+					pairs[i] = k + "=" + x.Params[k]
+				}
+				args = append(args, "-"+strings.TrimPrefix(encoder, "lib")+"-params", strings.Join(pairs, ":"))
+			}
+		}
+	case "h264_nvenc", "hevc_nvenc", "av1_nvenc":
+		if n := o.NVENC; n != nil {
+			if n.RateControl != "" {
+				args = append(args, "-rc", n.RateControl)
+			}
+			if n.Tune != "" {
+				args = append(args, "-tune", n.Tune)
+			}
+			if n.CQ != 0 {
+				args = append(args, "-cq", strconv.Itoa(n.CQ))
+			}
+			if n.Lookahead != 0 {
+				args = append(args, "-rc-lookahead", strconv.Itoa(n.Lookahead))
+			}
+			if n.SpatialAQ {
+				args = append(args, "-spatial-aq", "1")
+			}
+			if n.TemporalAQ {
+				args = append(args, "-temporal-aq", "1")
+			}
+			if n.Multipass != "" {
+				args = append(args, "-multipass", n.Multipass)
+			}
+		}
+	case "h264_qsv", "hevc_qsv", "av1_qsv":
+		if q := o.QSV; q != nil {
+			if q.LowPower {
+				args = append(args, "-low_power", "1")
+			}
+			if q.LookAheadDepth != 0 {
+				args = append(args, "-look_ahead", "1", "-look_ahead_depth", strconv.Itoa(q.LookAheadDepth))
+			}
+			if q.GlobalQuality != 0 {
+				args = append(args, "-global_quality", strconv.Itoa(q.GlobalQuality))
+			}
+		}
+	}
+	if len(args) == 0 {
+		return nil, nil
+	}
+	if err := validateEncoderArgs(encoder, args); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+// encoderCapability lists the private options of an encoder and, for
+// options with named constants, the accepted names.
+type encoderCapability map[string]map[string]bool
+
+var (
+	encoderCapabilitiesMu sync.Mutex
+	encoderCapabilities   = map[string]encoderCapability{}
+)
+
+// validateEncoderArgs checks option/value pairs against the encoder's help.
+func validateEncoderArgs(encoder string, args []string) error {
+	capability, err := probeEncoderCapability(encoder)
+	if err != nil {
+		return err
+	}
+	for i := 0; i+1 < len(args); i += 2 {
+		name := strings.TrimPrefix(strings.SplitN(args[i], ":", 2)[0], "-")
+		if name == "profile" {
+			// -profile:v is also a generic option, accepted by every encoder.
+			continue
+		}
+		values, ok := capability[name]
+		if !ok {
+			return fmt.Errorf("encoder %s does not support option -%s", encoder, name)
+		}
+		if len(values) > 0 && !values[args[i+1]] {
+			if _, err := strconv.Atoi(args[i+1]); err != nil {
+				return fmt.Errorf("encoder %s: invalid value %q for -%s (valid=%s)", encoder, args[i+1], name, strings.Join(sortedKeys(values), ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// probeEncoderCapability parses `ffmpeg -h encoder=NAME`, cached per encoder:
+//
+//	-rc                <int>        E..V....... Override the preset rate-control
+//	   constqp         0            E..V....... Constant QP mode
+func probeEncoderCapability(encoder string) (encoderCapability, error) {
+	encoderCapabilitiesMu.Lock()
+	defer encoderCapabilitiesMu.Unlock()
+	if c, ok := encoderCapabilities[encoder]; ok {
+		return c, nil
+	}
+	ffmpegPath, err := getFFmpegPath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ffmpeg path: %w", err)
+	}
+	output, err := commandCombinedOutput(ffmpegPath, "-hide_banner", "-h", "encoder="+encoder)
+	if err != nil || strings.Contains(string(output), "is not recognized") {
+		return nil, fmt.Errorf("encoder %s is not available in this FFmpeg build", encoder)
+	}
+
+	capability := encoderCapability{}
+	var current map[string]bool
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || !strings.HasPrefix(line, "  ") {
+			current = nil
+			continue
+		}
+		if strings.HasPrefix(fields[0], "-") {
+			current = map[string]bool{}
+			capability[strings.TrimPrefix(fields[0], "-")] = current
+		} else if current != nil && strings.HasPrefix(line, "     ") {
+			current[fields[0]] = true
+		}
+	}
+	encoderCapabilities[encoder] = capability
+	return capability, nil
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,136 @@
+package moviego
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// HLSOptions configures .m3u8 outputs of WriteVideo. The segments are
+// written next to the playlist as <name>_00000.ts, <name>_00001.ts, ...
+type HLSOptions struct {
+	SegmentDuration float64 // target seconds per segment (default: 6)
+	// Live writes an event playlist that grows as segments are written;
+	// by default a VOD playlist is written.
+	Live bool
+	// Encryption encrypts the segments (default: none).
+	Encryption *HLSEncryption
+}
+
+// HLSEncryptionMethod is the EXT-X-KEY method of an encrypted playlist.
+type HLSEncryptionMethod string
+
+const (
+	// HLSAES128 encrypts whole segments with AES-128-CBC.
+	HLSAES128 HLSEncryptionMethod = "AES-128"
+	// HLSSampleAES encrypts individual samples. FFmpeg's HLS muxer cannot
+	// write it, so it is rejected; package with a DRM packager instead.
+	HLSSampleAES HLSEncryptionMethod = "SAMPLE-AES"
+)
+
+// HLSEncryption configures the encryption of HLS segments.
+type HLSEncryption struct {
+	Method HLSEncryptionMethod // default: HLSAES128
+	// Key returns the 16-byte content key and the URI players fetch it
+	// from, e.g. from a key server or KMS. It is called once per render.
+	Key func() (key []byte, uri string, err error)
+	// IV returns the 16-byte initialization vector. Without it the IV is
+	// the segment sequence number, as the HLS specification defines.
+	IV func() ([]byte, error)
+}
+
+// CENCEncryption encrypts MP4 outputs with Common Encryption (ISO/IEC
+// 23001-7, cenc scheme, AES-CTR), as used by Widevine and PlayReady. The
+// license server must serve Key for KeyID.
+type CENCEncryption struct {
+	KeyID []byte // 16 bytes
+	Key   []byte // 16 bytes
+}
+
+// hlsOutputArgs returns the muxer options of an .m3u8 output. Key files
+// are written to a temporary directory in env's TempDir, removed by cleanup.
+func hlsOutputArgs(outputPath string, opts HLSOptions, env *RenderEnv) (args []string, cleanup func(), err error) {
+	cleanup = func() {}
+	segment := opts.SegmentDuration
+	if segment == 0 {
+		segment = 6
+	}
+	if segment < 0 {
+		return nil, cleanup, fmt.Errorf("HLS segment duration must be positive (got=%.4f)", segment)
+	}
+	playlistType := "vod"
+	if opts.Live {
+		playlistType = "event"
+	}
+	base := strings.TrimSuffix(outputPath, filepath.Ext(outputPath))
+	args = []string{"-f", "hls", "-hls_time", formatFloat(segment), "-hls_playlist_type", playlistType,
+		"-hls_segment_filename", base + "_%05d.ts"}
+
+	enc := opts.Encryption
+	if enc == nil {
+		return args, cleanup, nil
+	}
+	switch enc.Method {
+	case "", HLSAES128:
+	case HLSSampleAES:
+		return nil, cleanup, fmt.Errorf("HLS encryption method %s is not supported by FFmpeg's HLS muxer", enc.Method)
+	default:
+		return nil, cleanup, fmt.Errorf("unknown HLS encryption method %q (valid=AES-128)", enc.Method)
+	}
+	if enc.Key == nil {
+		return nil, cleanup, fmt.Errorf("HLS encryption requires a Key callback")
+	}
+	key, uri, err := enc.Key()
+	if err != nil {
+		return nil, cleanup, fmt.Errorf("HLS key: %w", err)
+	}
+	if len(key) != 16 || uri == "" {
+		return nil, cleanup, fmt.Errorf("HLS key must be 16 bytes with a URI (got=%d bytes, uri=%s)", len(key), uri)
+	}
+	var ivHex string
+	if enc.IV != nil {
+		iv, err := enc.IV()
+		if err != nil {
+			return nil, cleanup, fmt.Errorf("HLS IV: %w", err)
+		}
+		if len(iv) != 16 {
+			return nil, cleanup, fmt.Errorf("HLS IV must be 16 bytes (got=%d)", len(iv))
+		}
+		ivHex = hex.EncodeToString(iv)
+	}
+
+	// FFmpeg reads the key from a key info file: URI, key path and IV.
+	dir, err := os.MkdirTemp(env.tempDir(), "moviego-hls-key-*")
+	if err != nil {
+		return nil, cleanup, err
+	}
+	cleanup = func() { os.RemoveAll(dir) }
+	keyPath := filepath.Join(dir, "key.bin")
+	infoPath := filepath.Join(dir, "key.info")
+	info := strings.Join([]string{uri, keyPath, ivHex}, "\n") + "\n"
+	if err := os.WriteFile(keyPath, key, 0600); err != nil {
+		cleanup()
+		return nil, func() {}, err
+	}
+	if err := os.WriteFile(infoPath, []byte(info), 0600); err != nil {
+		cleanup()
+		return nil, func() {}, err
+	}
+	return append(args, "-hls_key_info_file", infoPath), cleanup, nil
+}
+
+// cencArgs returns the muxer options encrypting an MP4 output with e.
+func cencArgs(outputPath string, e *CENCEncryption) ([]string, error) {
+	switch strings.ToLower(filepath.Ext(outputPath)) {
+	case ".mp4", ".m4v", ".mov", ".ismv":
+	default:
+		return nil, fmt.Errorf("CENC encryption requires an MP4 output (got=%s)", outputPath)
+	}
+	if len(e.KeyID) != 16 || len(e.Key) != 16 {
+		return nil, fmt.Errorf("CENC key ID and key must be 16 bytes (got=%d, %d)", len(e.KeyID), len(e.Key))
+	}
+	return []string{"-encryption_scheme", "cenc-aes-ctr",
+		"-encryption_key", hex.EncodeToString(e.Key), "-encryption_kid", hex.EncodeToString(e.KeyID)}, nil
+}
@@ -0,0 +1,183 @@
+package moviego
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os/exec"
+	"strconv"
+	"sync"
+)
+
+// Command is a process MovieGo asks its Executor to run: FFmpeg, FFprobe,
+// the RenderEnv wrappers (nice, ionice, taskset) or a helper tool.
+type Command struct {
+	Path   string    // executable, as returned by Executor.LookPath
+	Args   []string  // arguments, without the executable
+	Env    []string  // complete environment; nil inherits the caller's
+	Stdin  io.Reader // nil reads nothing
+	Stdout io.Writer // nil discards the output
+	Stderr io.Writer // nil discards the output
+}
+
+// ExitError is returned by Executor.Run for a process that ran and exited
+// with a non-zero status, as opposed to one that could not be started.
+type ExitError struct {
+	Code int
+	Err  error // the backend's error, e.g. *exec.ExitError
+}
+
+func (e *ExitError) Error() string {
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return "exit status " + strconv.Itoa(e.Code)
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// Executor runs the processes of MovieGo. The default, LocalExecutor,
+// spawns local processes; SetExecutor plugs in another backend, e.g. a
+// remote render API or an ffmpeg.wasm bridge, for sandboxed or serverless
+// environments where spawning processes is not possible.
+//
+// Commands refer to media by the paths used to build the video, so a remote
+// backend has to make those paths available to its FFmpeg.
+type Executor interface {
+	// LookPath resolves a program name such as "ffmpeg" or "nice" to the
+	// Path of a Command.
+	LookPath(name string) (string, error)
+	// Run runs cmd to completion, streaming its output to cmd.Stdout and
+	// cmd.Stderr. A non-zero exit status is reported as an *ExitError.
+	Run(cmd *Command) error
+}
+
+// LocalExecutor runs commands as local processes.
+type LocalExecutor struct{}
+
+// LookPath searches PATH; ffmpeg and ffprobe are also found in common
+// install locations.
+func (LocalExecutor) LookPath(name string) (string, error) {
+	if name == "ffmpeg" || name == "ffprobe" {
+		return findExecutable(name)
+	}
+	return exec.LookPath(name)
+}
+
+// Run runs cmd with os/exec.
+func (LocalExecutor) Run(cmd *Command) error {
+	c := exec.Command(cmd.Path, cmd.Args...)
+	c.Env = cmd.Env
+	c.Stdin = cmd.Stdin
+	c.Stdout = cmd.Stdout
+	c.Stderr = cmd.Stderr
+	err := c.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return &ExitError{Code: exitErr.ExitCode(), Err: err}
+	}
+	return err
+}
+
+var (
+	executorMu sync.RWMutex
+	executor   Executor = LocalExecutor{}
+)
+
+// SetExecutor replaces the executor used by all subsequent operations;
+// nil restores LocalExecutor. Call it before rendering: the ffmpeg and
+// ffprobe paths, the detected H.264 encoder and the encoder capabilities
+// are probed again through the new executor.
+func SetExecutor(e Executor) {
+	if e == nil {
+		e = LocalExecutor{}
+	}
+	executorMu.Lock()
+	executor = e
+	executorMu.Unlock()
+	resetProbeCaches()
+}
+
+// probeCache holds what is probed once per executor: the ffmpeg and ffprobe
+// paths and the detected H.264 encoder.
+type probeCache struct {
+	ffmpegOnce  sync.Once
+	ffmpegPath  string
+	ffmpegErr   error
+	ffprobeOnce sync.Once
+	ffprobePath string
+	ffprobeErr  error
+	codecOnce   sync.Once
+	codec       string
+}
+
+var (
+	probeCacheMu sync.RWMutex
+	probes       = &probeCache{}
+)
+
+func currentProbeCache() *probeCache {
+	probeCacheMu.RLock()
+	defer probeCacheMu.RUnlock()
+	return probes
+}
+
+// resetProbeCaches drops everything probed through the previous executor.
+// Probes already running finish on the cache they started with.
+func resetProbeCaches() {
+	probeCacheMu.Lock()
+	probes = &probeCache{}
+	probeCacheMu.Unlock()
+	encoderCapabilitiesMu.Lock()
+	encoderCapabilities = map[string]encoderCapability{}
+	encoderCapabilitiesMu.Unlock()
+}
+
+func currentExecutor() Executor {
+	executorMu.RLock()
+	defer executorMu.RUnlock()
+	return executor
+}
+
+// lookPath resolves name through the current executor.
+func lookPath(name string) (string, error) {
+	return currentExecutor().LookPath(name)
+}
+
+// RunCommand runs cmd through the executor set with SetExecutor, so
+// packages built on MovieGo (e.g. bench) reach the same backend.
+func RunCommand(cmd *Command) error {
+	return runCommand(cmd)
+}
+
+// runCommand runs cmd through the current executor.
+func runCommand(cmd *Command) error {
+	return currentExecutor().Run(cmd)
+}
+
+// commandOutput runs path with args and returns its standard output.
+func commandOutput(path string, args ...string) ([]byte, error) {
+	var stdout bytes.Buffer
+	err := runCommand(&Command{Path: path, Args: args, Stdout: &stdout})
+	return stdout.Bytes(), err
+}
+
+// commandCombinedOutput runs path with args and returns its standard output
+// and standard error interleaved.
+func commandCombinedOutput(path string, args ...string) ([]byte, error) {
+	var output bytes.Buffer
+	err := runCommand(&Command{Path: path, Args: args, Stdout: &output, Stderr: &output})
+	return output.Bytes(), err
+}
+
+// toolOutput runs the helper tool name, resolved with lookPath, and returns
+// its standard output and standard error interleaved.
+func toolOutput(name string, args ...string) ([]byte, error) {
+	path, err := lookPath(name)
+	if err != nil {
+		return nil, err
+	}
+	return commandCombinedOutput(path, args...)
+}
@@ -0,0 +1,164 @@
+package moviego
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+// ExportFramesOptions configures ExportFrames. Zero fields use the defaults
+// listed below.
+type ExportFramesOptions struct {
+	Format string // image format: "jpg" (default), "png" or "webp"
+	Prefix string // file name prefix (default: "frame")
+
+	// Crop is the region kept from each frame (default: the whole frame).
+	Crop *Rect
+	// Width and Height resize the frames after cropping. With one of them 0
+	// the aspect ratio is kept; with both 0 the frames keep their size.
+	Width  uint64
+	Height uint64
+
+	Env *RenderEnv
+}
+
+// FrameManifest describes frames written by ExportFrames. It is saved as
+// manifest.json in the output directory.
+type FrameManifest struct {
+	Source   FrameSource     `json:"source"`
+	Interval float64         `json:"interval"`
+	Width    uint64          `json:"width"`
+	Height   uint64          `json:"height"`
+	Crop     *FrameCrop      `json:"crop,omitempty"`
+	Frames   []ExportedFrame `json:"frames"`
+}
+
+// FrameSource is the video the frames were exported from.
+type FrameSource struct {
+	File     string  `json:"file"`
+	Width    uint64  `json:"width"`
+	Height   uint64  `json:"height"`
+	Fps      uint64  `json:"fps"`
+	Duration float64 `json:"duration"`
+	Codec    string  `json:"codec,omitempty"`
+}
+
+// FrameCrop is the region of the source frames that was exported, in pixels.
+type FrameCrop struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// ExportedFrame is one exported frame.
+type ExportedFrame struct {
+	File  string  `json:"file"`  // name of the image, relative to the manifest
+	Index int64   `json:"index"` // frame index in the source
+	Time  float64 `json:"time"`  // seconds
+}
+
+// exportManifestName is the name of the manifest written by ExportFrames.
+const exportManifestName = "manifest.json"
+
+// ExportFrames writes a frame every interval seconds to outputDir, with a
+// manifest of their timestamps, frame indices and source metadata, e.g. to
+// build training datasets from video libraries. Frames are optionally
+// cropped and resized. The whole edit is applied, like RenderStill.
+func (v *Video) ExportFrames(outputDir string, interval float64, opts ExportFramesOptions) (*FrameManifest, error) {
+	file, label := safeFirstFilename(v.filenames), safeLastVideoLabel(v)
+	if outputDir == "" {
+		return nil, fmt.Errorf("ExportFrames: output directory cannot be empty (file=%s, label=%s)", file, label)
+	}
+	if interval <= 0 || v.duration <= 0 {
+		return nil, fmt.Errorf("ExportFrames: interval and duration must be positive (got=%.4f, duration=%.4f, file=%s, label=%s)", interval, v.duration, file, label)
+	}
+	if opts.Format == "" {
+		opts.Format = "jpg"
+	}
+	switch opts.Format {
+	case "jpg", "png", "webp":
+	default:
+		return nil, fmt.Errorf("ExportFrames: unsupported format (got=%s, file=%s, label=%s)", opts.Format, file, label)
+	}
+	if opts.Prefix == "" {
+		opts.Prefix = "frame"
+	}
+	ffmpegPath, err := getFFmpegPath()
+	if err != nil {
+		return nil, fmt.Errorf("ExportFrames: failed to get ffmpeg path: %w", err)
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, fmt.Errorf("ExportFrames: failed to create '%s': %w", outputDir, err)
+	}
+
+	// Sampling first keeps the crop and resize to the exported frames.
+	fps := max(v.fps, 1)
+	out, err := v.videoFilter(fmt.Sprintf("fps=fps=1/%.6f:round=near", interval))
+	if err != nil {
+		return nil, fmt.Errorf("ExportFrames: %w", err)
+	}
+	width, height := v.width, v.height
+	var crop *FrameCrop
+	if opts.Crop != nil {
+		x, y, w, h, err := opts.Crop.resolve(v.width, v.height)
+		if err != nil {
+			return nil, fmt.Errorf("ExportFrames: %w (file=%s, label=%s)", err, file, label)
+		}
+		if out, err = out.Crop(CropParams{X: x, Y: y, Width: w, Height: h}); err != nil {
+			return nil, fmt.Errorf("ExportFrames: %w", err)
+		}
+		crop = &FrameCrop{X: x, Y: y, Width: w, Height: h}
+		width, height = uint64(w), uint64(h)
+	}
+	if opts.Width > 0 || opts.Height > 0 {
+		w, h := opts.Width, opts.Height
+		if w == 0 {
+			w = uint64(math.Round(float64(width)*float64(h)/float64(height)/2)) * 2
+		} else if h == 0 {
+			h = uint64(math.Round(float64(height)*float64(w)/float64(width)/2)) * 2
+		}
+		if out, err = out.videoFilter(fmt.Sprintf("scale=%d:%d", w, h)); err != nil {
+			return nil, fmt.Errorf("ExportFrames: %w", err)
+		}
+		width, height = w, h
+	}
+
+	count := int(math.Ceil(v.duration/interval - 1e-9))
+	pattern := filepath.Join(outputDir, opts.Prefix+"_%06d."+opts.Format)
+	outputArgs := []string{"-an", "-frames:v", fmt.Sprint(count), "-start_number", "0"}
+	if opts.Format == "jpg" {
+		outputArgs = append(outputArgs, "-q:v", "2")
+	}
+	if err := sourceJob("ExportFrames", ffmpegPath, out, pattern, outputArgs, opts.Env); err != nil {
+		return nil, err
+	}
+
+	manifest := &FrameManifest{
+		Source: FrameSource{
+			File: file, Width: v.width, Height: v.height, Fps: v.fps, Duration: v.duration, Codec: v.GetCodec(),
+		},
+		Interval: interval,
+		Width:    width,
+		Height:   height,
+		Crop:     crop,
+	}
+	for i := 0; i < count; i++ {
+		name := fmt.Sprintf("%s_%06d.%s", opts.Prefix, i, opts.Format)
+		if _, err := os.Stat(filepath.Join(outputDir, name)); err != nil {
+			break
+		}
+		t := Seconds(float64(i) * interval).Snap(fps)
+		manifest.Frames = append(manifest.Frames, ExportedFrame{File: name, Index: t.Frame(fps), Time: t.Seconds()})
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("ExportFrames: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, exportManifestName), data, 0644); err != nil {
+		return nil, fmt.Errorf("ExportFrames: failed to write manifest: %w", err)
+	}
+	return manifest, nil
+}
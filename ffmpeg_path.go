@@ -8,20 +8,10 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
-	"sync"
 )
 
 const cacheDirName = "MovieGo"
 
-var (
-	ffprobePath   string
-	ffprobeErr    error
-	ffprobeOnce   sync.Once
-	ffmpegPath    string
-	ffmpegErr     error
-	ffmpegOnce    sync.Once
-)
-
 // getCacheDir returns the directory for caching ffprobe/ffmpeg paths (e.g. UserCacheDir/MovieGo).
 func getCacheDir() (string, error) {
 	dir, err := os.UserCacheDir()
@@ -129,16 +119,24 @@ func findExecutable(name string) (string, error) {
 
 // getFFprobePath returns the path to the ffprobe executable. Cached in memory for the process lifetime.
 func getFFprobePath() (string, error) {
-	ffprobeOnce.Do(func() {
-		ffprobePath, ffprobeErr = findExecutable("ffprobe")
+	c := currentProbeCache()
+	c.ffprobeOnce.Do(func() {
+		c.ffprobePath, c.ffprobeErr = lookPath("ffprobe")
 	})
-	return ffprobePath, ffprobeErr
+	return c.ffprobePath, c.ffprobeErr
 }
 
 // getFFmpegPath returns the path to the ffmpeg executable. Cached in memory for the process lifetime.
 func getFFmpegPath() (string, error) {
-	ffmpegOnce.Do(func() {
-		ffmpegPath, ffmpegErr = findExecutable("ffmpeg")
+	c := currentProbeCache()
+	c.ffmpegOnce.Do(func() {
+		c.ffmpegPath, c.ffmpegErr = lookPath("ffmpeg")
 	})
-	return ffmpegPath, ffmpegErr
+	return c.ffmpegPath, c.ffmpegErr
+}
+
+// FFmpegPath returns the path of the ffmpeg executable used by MovieGo, so
+// companion packages (e.g. bench) run the same binary.
+func FFmpegPath() (string, error) {
+	return getFFmpegPath()
 }
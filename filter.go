@@ -1,5 +1,6 @@
 package moviego
 
+import "fmt"
 
 func (v *Video) addFilterVideo(filter string) *Video {
 	lastFilter := v.lastVideoFilter()
@@ -19,6 +20,19 @@ func (v *Video) addFilterAudio(filter string) *Video {
 	return v
 }
 
+// inputArgs returns the input options set for filename by the chains that
+// read it.
+func inputArgs(filename string, chains ...[]FilterComplex) []string {
+	for _, chain := range chains {
+		for _, f := range chain {
+			if f.FileCopy.Filename == filename && len(f.FileCopy.InputArgs) > 0 {
+				return f.FileCopy.InputArgs
+			}
+		}
+	}
+	return nil
+}
+
 func (f *FilterComplex) addFilter(filter string) *FilterComplex {
 	if f.FilterElement != "" {
 		f.FilterElement += ","
@@ -33,4 +47,30 @@ func (v *Video) lastVideoFilter() *FilterComplex {
 
 func (v *Video) lastAudioFilter() *FilterComplex {
 	return &v.audio.filterComplex[len(v.audio.filterComplex)-1]
+}
+
+// graphFilter appends a video graph and an audio graph to the filter chains
+// and returns the result as a new Video; the receiver is not modified. Each
+// builder receives the label of its branch's current output and a unique prefix
+// for intermediate labels. The builders return FFmpeg graphs whose final chain
+// has no output label. Callers update timing metadata on the returned video.
+func (v *Video) graphFilter(videoGraph, audioGraph func(in, prefix string) string) *Video {
+	src := *v
+	initRawVideo(&src)
+	order := incrementOrderCounter()
+	label := src.nextLabel(src.lastFilename())
+
+	n, m := len(src.filterComplex), len(src.audio.filterComplex)
+	out := src
+	out.filterComplex = append(src.filterComplex[:n:n], FilterComplex{
+		Order:         order,
+		FilterElement: videoGraph(src.lastVideoLabel(), label+"_v"),
+		Label:         fmt.Sprintf("%s_v", label),
+	})
+	out.audio.filterComplex = append(src.audio.filterComplex[:m:m], FilterComplex{
+		Order:         order,
+		FilterElement: audioGraph(src.audio.lastAudioLabel(), label+"_a"),
+		Label:         fmt.Sprintf("%s_a", label),
+	})
+	return &out
 }
\ No newline at end of file
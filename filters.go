@@ -2,6 +2,7 @@ package moviego
 
 import (
 	"fmt"
+	"math"
 	"strings"
 )
 
@@ -87,8 +88,16 @@ func resolveScaledDimensions(srcW, srcH uint64, params ScaleParams) (uint64, uin
 }
 
 // videoFilter applies a video-only FFmpeg filter and passes audio through unchanged.
-// All public filter methods delegate to this.
+// All public filter methods delegate to this. Pending overlays are flattened
+// first so the filter applies to them too.
 func (v *Video) videoFilter(filter string) (*Video, error) {
+	if len(v.overlays) > 0 {
+		flat, err := v.FlattenOverlays()
+		if err != nil {
+			return nil, err
+		}
+		return flat.videoFilter(filter)
+	}
 	audioFilterComplex, _ := deepCopySlice(v.audio.filterComplex)
 	videoFilterComplex, _ := deepCopySlice(v.filterComplex)
 	order := incrementOrderCounter()
@@ -160,8 +169,13 @@ func (v *Video) videoFilter(filter string) (*Video, error) {
 		startTime:          v.startTime,
 		endTime:            v.endTime,
 		position:           v.position,
+		compositeStart:     v.compositeStart,
+		invalid:            v.invalid,
+		layers:             v.layers,
 		animatedPosition:   v.animatedPosition,
 		animatedOpacity:    v.animatedOpacity,
+		startTimecode:      v.startTimecode,
+		audioRemoved:       v.audioRemoved,
 	}, nil
 }
 
@@ -217,6 +231,70 @@ func (v *Video) Rotate(angle float64) (*Video, error) {
 	return v.videoFilter(fmt.Sprintf("rotate=%.4f:fillcolor=none", angle))
 }
 
+// Rotate90 rotates the video 90 degrees clockwise, swapping its width and
+// height, e.g. for portrait footage recorded sideways.
+func (v *Video) Rotate90() (*Video, error) {
+	return v.transpose("Rotate90", "transpose=clock", true)
+}
+
+// Rotate180 turns the video upside down.
+func (v *Video) Rotate180() (*Video, error) {
+	return v.transpose("Rotate180", "hflip,vflip", false)
+}
+
+// Rotate270 rotates the video 90 degrees counterclockwise, swapping its
+// width and height.
+func (v *Video) Rotate270() (*Video, error) {
+	return v.transpose("Rotate270", "transpose=cclock", true)
+}
+
+// RotateDegrees rotates the video clockwise by degrees. Multiples of 90
+// are exact pixel transposes; other angles grow the frame to fit the
+// rotated picture, with transparent corners (black once encoded). Unlike
+// Rotate, nothing is cropped.
+func (v *Video) RotateDegrees(degrees float64) (*Video, error) {
+	file, label := safeFirstFilename(v.filenames), safeLastVideoLabel(v)
+	if math.IsNaN(degrees) || math.IsInf(degrees, 0) {
+		return nil, fmt.Errorf("RotateDegrees: angle must be finite (got=%f, file=%s, label=%s)", degrees, file, label)
+	}
+	switch math.Mod(math.Mod(degrees, 360)+360, 360) {
+	case 0:
+		out := v.clone()
+		return &out, nil
+	case 90:
+		return v.Rotate90()
+	case 180:
+		return v.Rotate180()
+	case 270:
+		return v.Rotate270()
+	}
+	rad := degrees * math.Pi / 180
+	sin, cos := math.Abs(math.Sin(rad)), math.Abs(math.Cos(rad))
+	width := int(math.Ceil(float64(v.width)*cos + float64(v.height)*sin))
+	height := int(math.Ceil(float64(v.width)*sin + float64(v.height)*cos))
+	width += width % 2
+	height += height % 2
+	rotated, err := v.videoFilter(fmt.Sprintf("rotate=%s:ow=%d:oh=%d:fillcolor=none", formatFloat(rad), width, height))
+	if err != nil {
+		return nil, fmt.Errorf("RotateDegrees[file=%s, label=%s]: %w", file, label, err)
+	}
+	rotated.width, rotated.height = uint64(width), uint64(height)
+	return rotated, nil
+}
+
+// transpose applies a lossless rotation filter, swapping the dimensions for
+// quarter turns.
+func (v *Video) transpose(op, filter string, swap bool) (*Video, error) {
+	rotated, err := v.videoFilter(filter)
+	if err != nil {
+		return nil, fmt.Errorf("%s[file=%s, label=%s]: %w", op, safeFirstFilename(v.filenames), safeLastVideoLabel(v), err)
+	}
+	if swap {
+		rotated.width, rotated.height = v.height, v.width
+	}
+	return rotated, nil
+}
+
 // HorizontalFlip flips the video horizontally.
 func (v *Video) HorizontalFlip() (*Video, error) {
 	return v.videoFilter("hflip")
@@ -247,6 +325,49 @@ func (v *Video) FadeOut(duration float64) (*Video, error) {
 	return v.videoFilter(fmt.Sprintf("fade=t=out:st=%.4f:d=%.4f", startTime, duration))
 }
 
+// AudioFadeIn fades the video's audio in over the first duration seconds.
+// The fade travels with the clip through Concatenate and CompositeClip.
+func (v *Video) AudioFadeIn(duration float64) (*Video, error) {
+	if duration <= 0 {
+		return nil, fmt.Errorf("AudioFadeIn: duration must be positive (got=%f, file=%s, label=%s)", duration, safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	if duration > v.duration {
+		return nil, fmt.Errorf("AudioFadeIn: duration %f exceeds video duration %f (file=%s, label=%s)", duration, v.duration, safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	if len(v.overlays) > 0 {
+		flat, err := v.FlattenOverlays()
+		if err != nil {
+			return nil, fmt.Errorf("AudioFadeIn: %w", err)
+		}
+		return flat.AudioFadeIn(duration)
+	}
+	out := v.clone()
+	initRawVideo(&out)
+	out.audio = out.audio.chain(fmt.Sprintf("afade=t=in:st=0:d=%.4f", duration))
+	return &out, nil
+}
+
+// AudioFadeOut fades the video's audio out over its last duration seconds.
+func (v *Video) AudioFadeOut(duration float64) (*Video, error) {
+	if duration <= 0 {
+		return nil, fmt.Errorf("AudioFadeOut: duration must be positive (got=%f, file=%s, label=%s)", duration, safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	if duration > v.duration {
+		return nil, fmt.Errorf("AudioFadeOut: duration %f exceeds video duration %f (file=%s, label=%s)", duration, v.duration, safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	if len(v.overlays) > 0 {
+		flat, err := v.FlattenOverlays()
+		if err != nil {
+			return nil, fmt.Errorf("AudioFadeOut: %w", err)
+		}
+		return flat.AudioFadeOut(duration)
+	}
+	out := v.clone()
+	initRawVideo(&out)
+	out.audio = out.audio.chain(fmt.Sprintf("afade=t=out:st=%.4f:d=%.4f", v.duration-duration, duration))
+	return &out, nil
+}
+
 // Eq applies brightness, contrast, saturation, and gamma adjustments.
 func (v *Video) Eq(params EqParams) (*Video, error) {
 	brightness := 0.0
@@ -381,6 +502,34 @@ func (v *Video) Pad(params PadParams) (*Video, error) {
 	return padded, nil
 }
 
+// PadEdges extends the canvas by the given number of pixels on each side,
+// filled with color (default: black), e.g. to add a border or to turn 16:9
+// footage into a square with bars. An odd output size gets one more pixel
+// on the right or bottom, as yuv420p encoders need even dimensions.
+func (v *Video) PadEdges(top, bottom, left, right int, color Color) (*Video, error) {
+	file, label := safeFirstFilename(v.filenames), safeLastVideoLabel(v)
+	if top < 0 || bottom < 0 || left < 0 || right < 0 {
+		return nil, fmt.Errorf("PadEdges: padding must be non-negative (top=%d, bottom=%d, left=%d, right=%d, file=%s, label=%s)", top, bottom, left, right, file, label)
+	}
+	if color == "" {
+		color = "black"
+	}
+	if err := color.Validate(); err != nil {
+		return nil, fmt.Errorf("PadEdges: %w (file=%s, label=%s)", err, file, label)
+	}
+	width := int(v.width) + left + right
+	height := int(v.height) + top + bottom
+	width += width % 2
+	height += height % 2
+	padded, err := v.videoFilter(fmt.Sprintf("pad=width=%d:height=%d:x=%d:y=%d:color=%s",
+		width, height, left, top, color.ffmpeg()))
+	if err != nil {
+		return nil, fmt.Errorf("PadEdges[file=%s, label=%s]: %w", file, label, err)
+	}
+	padded.width, padded.height = uint64(width), uint64(height)
+	return padded, nil
+}
+
 // Blur applies a Gaussian blur. Sigma controls blur strength (higher = more blur).
 func (v *Video) Blur(sigma float64) (*Video, error) {
 	if sigma <= 0 {
@@ -0,0 +1,47 @@
+//go:build !cgo || !moviego_libav
+
+package moviego
+
+import (
+	"fmt"
+	"os"
+)
+
+const frameBackend = "exec"
+
+// decodeRawFrames runs FFmpeg writing the raw frames to a temporary file.
+func decodeRawFrames(req frameRequest) ([]byte, error) {
+	ffmpegPath, err := getFFmpegPath()
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to get ffmpeg path: %w", req.op, err)
+	}
+	raw, err := os.CreateTemp(req.env.tempDir(), "moviego-frames-*.rgb")
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", req.op, err)
+	}
+	raw.Close()
+	defer os.Remove(raw.Name())
+
+	outputArgs := []string{"-an"}
+	if req.maxFrames > 0 {
+		outputArgs = append(outputArgs, "-frames:v", fmt.Sprint(req.maxFrames))
+	}
+	outputArgs = append(outputArgs, "-s", fmt.Sprintf("%dx%d", req.width, req.height), "-pix_fmt", "rgb24", "-f", "rawvideo")
+	job := renderJob{
+		op:         req.op,
+		args:       req.args,
+		outputArgs: outputArgs,
+		output:     raw.Name(),
+		duration:   req.duration,
+		env:        req.env,
+		silent:     true,
+	}
+	if err := job.run(ffmpegPath); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(raw.Name())
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to read frames: %w", req.op, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,412 @@
+//go:build cgo && moviego_libav
+
+package moviego
+
+/*
+#cgo pkg-config: libavformat libavcodec libavfilter libavutil
+#include <stdio.h>
+#include <stdlib.h>
+#include <string.h>
+#include <libavformat/avformat.h>
+#include <libavcodec/avcodec.h>
+#include <libavfilter/avfilter.h>
+#include <libavfilter/buffersink.h>
+#include <libavfilter/buffersrc.h>
+#include <libavutil/opt.h>
+
+typedef struct {
+	AVFormatContext *fmt;
+	AVCodecContext *dec;
+	AVFilterContext *src;
+	AVPacket *pkt;
+	int stream;
+	int64_t start;  // first timestamp kept, in stream time base
+	int64_t end;    // first timestamp dropped, or INT64_MAX
+	int64_t offset; // subtracted from timestamps so the input starts at 0
+	int eof;
+} mg_input;
+
+static void mg_set_error(char *err, int errlen, const char *what, int code) {
+	char msg[AV_ERROR_MAX_STRING_SIZE] = {0};
+	if (code < 0) {
+		av_strerror(code, msg, sizeof(msg));
+	}
+	snprintf(err, errlen, "%s%s%s", what, code < 0 ? ": " : "", msg);
+}
+
+static void mg_close_input(mg_input *in) {
+	av_packet_free(&in->pkt);
+	avcodec_free_context(&in->dec);
+	avformat_close_input(&in->fmt);
+}
+
+static int mg_open_input(mg_input *in, const char *filename, double start, double end, char *err, int errlen) {
+	int ret = avformat_open_input(&in->fmt, filename, NULL, NULL);
+	if (ret < 0) {
+		mg_set_error(err, errlen, "failed to open input", ret);
+		return ret;
+	}
+	if ((ret = avformat_find_stream_info(in->fmt, NULL)) < 0) {
+		mg_set_error(err, errlen, "failed to read stream info", ret);
+		return ret;
+	}
+	const AVCodec *codec = NULL;
+	ret = av_find_best_stream(in->fmt, AVMEDIA_TYPE_VIDEO, -1, -1, &codec, 0);
+	if (ret < 0) {
+		mg_set_error(err, errlen, "no video stream", ret);
+		return ret;
+	}
+	in->stream = ret;
+	AVStream *st = in->fmt->streams[in->stream];
+	in->dec = avcodec_alloc_context3(codec);
+	in->pkt = av_packet_alloc();
+	if (!in->dec || !in->pkt) {
+		mg_set_error(err, errlen, "out of memory", AVERROR(ENOMEM));
+		return AVERROR(ENOMEM);
+	}
+	avcodec_parameters_to_context(in->dec, st->codecpar);
+	in->dec->pkt_timebase = st->time_base;
+	av_opt_set(in->dec, "threads", "auto", 0);
+	if ((ret = avcodec_open2(in->dec, codec, NULL)) < 0) {
+		mg_set_error(err, errlen, "failed to open decoder", ret);
+		return ret;
+	}
+
+	in->offset = st->start_time != AV_NOPTS_VALUE ? st->start_time : 0;
+	in->start = INT64_MIN;
+	in->end = INT64_MAX;
+	if (end > 0) {
+		// Like -ss before -i: seek to the keyframe before start, decode
+		// up to start and restart the timestamps there.
+		in->start = in->offset + av_rescale_q((int64_t)(start * AV_TIME_BASE), AV_TIME_BASE_Q, st->time_base);
+		in->end = in->offset + av_rescale_q((int64_t)(end * AV_TIME_BASE), AV_TIME_BASE_Q, st->time_base);
+		if (start > 0 && (ret = av_seek_frame(in->fmt, in->stream, in->start, AVSEEK_FLAG_BACKWARD)) < 0) {
+			mg_set_error(err, errlen, "failed to seek", ret);
+			return ret;
+		}
+		in->offset = in->start;
+	}
+	return 0;
+}
+
+// mg_feed_input decodes the next frame of in into its buffer source, or
+// closes the source at the end of the input.
+static int mg_feed_input(mg_input *in, AVFrame *frame, char *err, int errlen) {
+	for (;;) {
+		int ret = avcodec_receive_frame(in->dec, frame);
+		if (ret == 0) {
+			int64_t ts = frame->best_effort_timestamp;
+			if (ts != AV_NOPTS_VALUE && ts < in->start) {
+				av_frame_unref(frame);
+				continue;
+			}
+			if (ts != AV_NOPTS_VALUE && ts >= in->end) {
+				av_frame_unref(frame);
+				in->eof = 1;
+				return av_buffersrc_add_frame(in->src, NULL);
+			}
+			frame->pts = ts == AV_NOPTS_VALUE ? AV_NOPTS_VALUE : ts - in->offset;
+			ret = av_buffersrc_add_frame_flags(in->src, frame, AV_BUFFERSRC_FLAG_KEEP_REF);
+			av_frame_unref(frame);
+			if (ret < 0) {
+				mg_set_error(err, errlen, "failed to feed the filter graph", ret);
+			}
+			return ret;
+		}
+		if (ret == AVERROR_EOF) {
+			in->eof = 1;
+			return av_buffersrc_add_frame(in->src, NULL);
+		}
+		if (ret != AVERROR(EAGAIN)) {
+			mg_set_error(err, errlen, "failed to decode", ret);
+			return ret;
+		}
+		ret = av_read_frame(in->fmt, in->pkt);
+		if (ret == AVERROR_EOF) {
+			ret = avcodec_send_packet(in->dec, NULL);
+		} else if (ret >= 0) {
+			if (in->pkt->stream_index == in->stream) {
+				ret = avcodec_send_packet(in->dec, in->pkt);
+			}
+			av_packet_unref(in->pkt);
+		}
+		if (ret < 0 && ret != AVERROR_EOF) {
+			mg_set_error(err, errlen, "failed to read", ret);
+			return ret;
+		}
+	}
+}
+
+// mg_decode_frames runs graph over the inputs and appends the frames of the
+// output pad label, scaled to width x height and converted to packed RGB24,
+// to *out.
+static int mg_decode_frames(int n, const char **filenames, const double *starts, const double *ends,
+		const char *graph_desc, const char *label, int width, int height, int max_frames,
+		uint8_t **out, size_t *out_len, char *err, int errlen) {
+	int ret = 0;
+	mg_input *inputs = calloc(n, sizeof(mg_input));
+	AVFilterGraph *graph = avfilter_graph_alloc();
+	AVFilterInOut *ins = NULL, *outs = NULL;
+	AVFilterContext *sink = NULL, *scale = NULL, *format = NULL;
+	AVFrame *frame = av_frame_alloc();
+	size_t cap = 0;
+	*out = NULL;
+	*out_len = 0;
+	if (!inputs || !graph || !frame) {
+		ret = AVERROR(ENOMEM);
+		mg_set_error(err, errlen, "out of memory", ret);
+		goto end;
+	}
+	for (int i = 0; i < n; i++) {
+		if ((ret = mg_open_input(&inputs[i], filenames[i], starts[i], ends[i], err, errlen)) < 0) {
+			goto end;
+		}
+	}
+	if ((ret = avfilter_graph_parse2(graph, graph_desc, &ins, &outs)) < 0) {
+		mg_set_error(err, errlen, "failed to parse the filter graph", ret);
+		goto end;
+	}
+
+	// Open inputs of the graph are the [N:v] streams.
+	for (AVFilterInOut *io = ins; io; io = io->next) {
+		int index = -1;
+		char kind = 0;
+		if (!io->name || sscanf(io->name, "%d:%c", &index, &kind) != 2 || kind != 'v' || index < 0 || index >= n) {
+			ret = AVERROR(EINVAL);
+			snprintf(err, errlen, "unsupported graph input [%s]", io->name ? io->name : "");
+			goto end;
+		}
+		mg_input *in = &inputs[index];
+		AVStream *st = in->fmt->streams[in->stream];
+		AVRational fr = av_guess_frame_rate(in->fmt, st, NULL);
+		AVRational sar = in->dec->sample_aspect_ratio.num ? in->dec->sample_aspect_ratio : (AVRational){1, 1};
+		char args[256];
+		snprintf(args, sizeof(args), "video_size=%dx%d:pix_fmt=%d:time_base=%d/%d:pixel_aspect=%d/%d:frame_rate=%d/%d",
+			in->dec->width, in->dec->height, in->dec->pix_fmt, st->time_base.num, st->time_base.den,
+			sar.num, sar.den, fr.num ? fr.num : 25, fr.den ? fr.den : 1);
+		char name[64];
+		snprintf(name, sizeof(name), "in%d_%p", index, (void *)io);
+		if (in->src) {
+			ret = AVERROR(EINVAL);
+			snprintf(err, errlen, "graph input [%s] is used twice", io->name);
+			goto end;
+		}
+		if ((ret = avfilter_graph_create_filter(&in->src, avfilter_get_by_name("buffer"), name, args, NULL, graph)) < 0 ||
+				(ret = avfilter_link(in->src, 0, io->filter_ctx, io->pad_idx)) < 0) {
+			mg_set_error(err, errlen, "failed to create a graph input", ret);
+			goto end;
+		}
+	}
+
+	// The mapped output goes through scale and format=rgb24 to the sink.
+	AVFilterInOut *output = NULL;
+	for (AVFilterInOut *io = outs; io; io = io->next) {
+		if (io->name && strcmp(io->name, label) == 0) {
+			output = io;
+		} else {
+			ret = AVERROR(EINVAL);
+			snprintf(err, errlen, "unsupported graph output [%s]", io->name ? io->name : "");
+			goto end;
+		}
+	}
+	if (!output) {
+		ret = AVERROR(EINVAL);
+		snprintf(err, errlen, "graph has no output [%s]", label);
+		goto end;
+	}
+	char size[64];
+	snprintf(size, sizeof(size), "w=%d:h=%d", width, height);
+	if ((ret = avfilter_graph_create_filter(&scale, avfilter_get_by_name("scale"), "scale", size, NULL, graph)) < 0 ||
+			(ret = avfilter_graph_create_filter(&format, avfilter_get_by_name("format"), "format", "pix_fmts=rgb24", NULL, graph)) < 0 ||
+			(ret = avfilter_graph_create_filter(&sink, avfilter_get_by_name("buffersink"), "out", NULL, NULL, graph)) < 0 ||
+			(ret = avfilter_link(output->filter_ctx, output->pad_idx, scale, 0)) < 0 ||
+			(ret = avfilter_link(scale, 0, format, 0)) < 0 ||
+			(ret = avfilter_link(format, 0, sink, 0)) < 0) {
+		mg_set_error(err, errlen, "failed to create the graph output", ret);
+		goto end;
+	}
+	if ((ret = avfilter_graph_config(graph, NULL)) < 0) {
+		mg_set_error(err, errlen, "failed to configure the filter graph", ret);
+		goto end;
+	}
+
+	for (int frames = 0; max_frames <= 0 || frames < max_frames;) {
+		ret = av_buffersink_get_frame(sink, frame);
+		if (ret == AVERROR_EOF) {
+			ret = 0;
+			break;
+		}
+		if (ret == AVERROR(EAGAIN)) {
+			// Feed the input the graph waited on most, like ffmpeg does.
+			int next = -1;
+			unsigned best = 0;
+			for (int i = 0; i < n; i++) {
+				if (inputs[i].eof || !inputs[i].src) {
+					continue;
+				}
+				unsigned failed = av_buffersrc_get_nb_failed_requests(inputs[i].src);
+				if (next < 0 || failed > best) {
+					next = i;
+					best = failed;
+				}
+			}
+			if (next < 0) {
+				ret = 0;
+				break;
+			}
+			if ((ret = mg_feed_input(&inputs[next], frame, err, errlen)) < 0) {
+				goto end;
+			}
+			continue;
+		}
+		if (ret < 0) {
+			mg_set_error(err, errlen, "failed to filter", ret);
+			goto end;
+		}
+		if (frame->width != width || frame->height != height) {
+			ret = AVERROR(EINVAL);
+			snprintf(err, errlen, "graph output is %dx%d, expected %dx%d", frame->width, frame->height, width, height);
+			goto end;
+		}
+		size_t row = (size_t)width * 3;
+		if (*out_len + row * height > cap) {
+			cap = cap ? cap * 2 : row * height * 8;
+			while (cap < *out_len + row * height) {
+				cap *= 2;
+			}
+			uint8_t *grown = realloc(*out, cap);
+			if (!grown) {
+				ret = AVERROR(ENOMEM);
+				mg_set_error(err, errlen, "out of memory", ret);
+				goto end;
+			}
+			*out = grown;
+		}
+		for (int y = 0; y < height; y++) {
+			memcpy(*out + *out_len, frame->data[0] + (size_t)y * frame->linesize[0], row);
+			*out_len += row;
+		}
+		av_frame_unref(frame);
+		frames++;
+	}
+
+end:
+	av_frame_free(&frame);
+	avfilter_inout_free(&ins);
+	avfilter_inout_free(&outs);
+	avfilter_graph_free(&graph);
+	for (int i = 0; inputs && i < n; i++) {
+		mg_close_input(&inputs[i]);
+	}
+	free(inputs);
+	if (ret < 0) {
+		free(*out);
+		*out = NULL;
+		*out_len = 0;
+	}
+	return ret;
+}
+*/
+import "C"
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+const frameBackend = "libav"
+
+// libavInput is one -i of the source arguments with its -ss/-to trim.
+type libavInput struct {
+	filename   string
+	start, end float64
+}
+
+// decodeRawFrames decodes and filters in-process with libav*. It accepts the
+// arguments built by sourceArgs: inputs, optionally trimmed with -ss/-to, a
+// -filter_complex graph and the -map of its video output.
+func decodeRawFrames(req frameRequest) ([]byte, error) {
+	inputs, graph, label, err := parseLibavArgs(req.args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: libav backend: %w", req.op, err)
+	}
+
+	filenames := make([]*C.char, len(inputs))
+	starts := make([]C.double, len(inputs))
+	ends := make([]C.double, len(inputs))
+	for i, in := range inputs {
+		filenames[i] = C.CString(in.filename)
+		defer C.free(unsafe.Pointer(filenames[i]))
+		starts[i], ends[i] = C.double(in.start), C.double(in.end)
+	}
+	cGraph, cLabel := C.CString(graph), C.CString(label)
+	defer C.free(unsafe.Pointer(cGraph))
+	defer C.free(unsafe.Pointer(cLabel))
+
+	var out *C.uint8_t
+	var outLen C.size_t
+	errBuf := make([]byte, 512)
+	ret := C.mg_decode_frames(C.int(len(inputs)), &filenames[0], &starts[0], &ends[0], cGraph, cLabel,
+		C.int(req.width), C.int(req.height), C.int(req.maxFrames), &out, &outLen,
+		(*C.char)(unsafe.Pointer(&errBuf[0])), C.int(len(errBuf)))
+	if ret < 0 {
+		return nil, fmt.Errorf("%s: libav backend: %s (file=%s)", req.op, C.GoString((*C.char)(unsafe.Pointer(&errBuf[0]))), inputs[0].filename)
+	}
+	defer C.free(unsafe.Pointer(out))
+	return C.GoBytes(unsafe.Pointer(out), C.int(outLen)), nil
+}
+
+// parseLibavArgs extracts the inputs, graph and output label from FFmpeg
+// source arguments. Direct sources without a graph get a pass-through one.
+// Any other argument, or an option the backend would drop, is an error
+// rather than decoded differently from the exec backend.
+func parseLibavArgs(args []string) ([]libavInput, string, string, error) {
+	var inputs []libavInput
+	var graph, label string
+	var start, end float64
+	for i := 0; i < len(args); i++ {
+		if i+1 == len(args) {
+			return nil, "", "", fmt.Errorf("missing value for %s", args[i])
+		}
+		value := args[i+1]
+		var err error
+		switch args[i] {
+		case "-ss":
+			start, err = strconv.ParseFloat(value, 64)
+		case "-to":
+			end, err = strconv.ParseFloat(value, 64)
+		case "-i":
+			inputs = append(inputs, libavInput{filename: value, start: start, end: end})
+			start, end = 0, 0
+		case "-filter_complex":
+			if graph != "" {
+				return nil, "", "", fmt.Errorf("only one filter graph is supported")
+			}
+			graph = value
+		case "-map":
+			if label != "" {
+				return nil, "", "", fmt.Errorf("only one output is supported (got=%s)", value)
+			}
+			label = strings.Trim(value, "[]")
+		default:
+			return nil, "", "", fmt.Errorf("unsupported argument %s", args[i])
+		}
+		if err != nil {
+			return nil, "", "", fmt.Errorf("invalid %s: %w", args[i], err)
+		}
+		i++
+	}
+	if len(inputs) == 0 || label == "" {
+		return nil, "", "", fmt.Errorf("no input or output")
+	}
+	if start != 0 || end != 0 {
+		return nil, "", "", fmt.Errorf("-ss and -to are only supported before an input")
+	}
+	if graph == "" {
+		// -map 0:v:0 of a direct source.
+		graph, label = "[0:v]null[mg_out]", "mg_out"
+	}
+	return inputs, graph, label, nil
+}
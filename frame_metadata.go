@@ -0,0 +1,123 @@
+package moviego
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// FrameAnalyzer is an FFmpeg filter that tags every frame with metadata
+// without changing it, for EmitFrameMetadata. Any filter setting lavfi.*
+// frame metadata works; these are the common ones.
+type FrameAnalyzer string
+
+const (
+	// FrameBrightness measures luma and chroma statistics
+	// (lavfi.signalstats.YAVG, YMIN, YMAX, SATAVG, ...).
+	FrameBrightness FrameAnalyzer = "signalstats"
+	// FrameCropDetect finds black borders (lavfi.cropdetect.x, y, w, h).
+	FrameCropDetect FrameAnalyzer = "cropdetect=round=2"
+	// FrameBlackDetect measures the share of black pixels
+	// (lavfi.blackframe.pblack, only on mostly black frames).
+	FrameBlackDetect FrameAnalyzer = "blackframe=amount=0"
+	// FrameSceneScore measures the change from the previous frame
+	// (lavfi.scene_score, from 0 to 1).
+	FrameSceneScore FrameAnalyzer = "select=gte(scene\\,0)"
+)
+
+// FrameMetadata is the metadata of one output frame, as written to
+// <OutputPath>.frames.json by VideoParameters.FrameMetadata.
+type FrameMetadata struct {
+	Frame  int64             `json:"frame"`
+	Time   float64           `json:"time"` // seconds in the output
+	Values map[string]string `json:"values"`
+}
+
+// EmitFrameMetadata runs an analysis filter at this point of the edit. The
+// metadata it sets on every frame is collected by rendering with
+// VideoParameters.FrameMetadata, so the render is also an analysis pass.
+func (v *Video) EmitFrameMetadata(analyzer FrameAnalyzer) (*Video, error) {
+	if analyzer == "" {
+		return nil, fmt.Errorf("EmitFrameMetadata: analyzer cannot be empty (file=%s, label=%s)", safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	out, err := v.videoFilter(string(analyzer))
+	if err != nil {
+		return nil, fmt.Errorf("EmitFrameMetadata: %w", err)
+	}
+	return out, nil
+}
+
+// frameMetadataPath returns where the frame metadata of output is written.
+func frameMetadataPath(output string) string {
+	return output + ".frames.json"
+}
+
+// frameMetadataFilter returns the filter printing the metadata of every
+// frame to logPath.
+func frameMetadataFilter(logPath string) string {
+	return "metadata=mode=print:file=" + filterPath(logPath)
+}
+
+// writeFrameMetadata converts the log of frameMetadataFilter to the JSON
+// file of output. Frames without metadata are left out.
+func writeFrameMetadata(logPath, output string) error {
+	frames, err := parseFrameMetadata(logPath)
+	if err != nil {
+		return fmt.Errorf("WriteVideo: frame metadata: %w", err)
+	}
+	data, err := json.MarshalIndent(frames, "", "  ")
+	if err != nil {
+		return fmt.Errorf("WriteVideo: frame metadata: %w", err)
+	}
+	if err := os.WriteFile(frameMetadataPath(output), data, 0644); err != nil {
+		return fmt.Errorf("WriteVideo: failed to write frame metadata: %w", err)
+	}
+	return nil
+}
+
+// parseFrameMetadata reads the log of the metadata filter:
+//
+//	frame:0    pts:0       pts_time:0
+//	lavfi.signalstats.YAVG=92.4
+func parseFrameMetadata(logPath string) ([]FrameMetadata, error) {
+	f, err := os.Open(logPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	frames := []FrameMetadata{}
+	var cur *FrameMetadata
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "frame:") {
+			frame := FrameMetadata{Values: map[string]string{}}
+			for _, field := range strings.Fields(line) {
+				key, value, _ := strings.Cut(field, ":")
+				switch key {
+				case "frame":
+					frame.Frame, _ = strconv.ParseInt(value, 10, 64)
+				case "pts_time":
+					frame.Time, _ = strconv.ParseFloat(value, 64)
+				}
+			}
+			frames = append(frames, frame)
+			cur = &frames[len(frames)-1]
+		} else if key, value, ok := strings.Cut(line, "="); ok && cur != nil {
+			cur.Values[key] = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	tagged := frames[:0]
+	for _, frame := range frames {
+		if len(frame.Values) > 0 {
+			tagged = append(tagged, frame)
+		}
+	}
+	return tagged, nil
+}
@@ -0,0 +1,61 @@
+package moviego
+
+import "fmt"
+
+// FrameBackend names the backend decoding raw frames for frame analysis
+// (SuggestPosterFrame and the frame readers):
+//
+//   - "exec" (default) runs an FFmpeg process and reads the frames it
+//     writes, like every other operation.
+//   - "libav" decodes and filters in-process with libavformat, libavcodec
+//     and libavfilter, saving the process start and the copy of every raw
+//     frame through a pipe. Build with cgo, the FFmpeg development packages
+//     and the moviego_libav tag: go build -tags moviego_libav.
+//
+// Writers always run FFmpeg through the Executor, whatever the backend.
+const FrameBackend = frameBackend
+
+// frameRequest describes raw frames to decode from the video stream of a
+// graph. Frames are scaled to width x height and returned packed as RGB24.
+type frameRequest struct {
+	op        string
+	args      []string // inputs, video-only filter graph and map (see sourceArgs)
+	width     int
+	height    int
+	maxFrames int     // 0 decodes every frame
+	duration  float64 // expected duration, for progress
+	env       *RenderEnv
+}
+
+// decodeFrames decodes the video of v as packed RGB24 frames of width x
+// height with the backend selected at build time.
+func decodeFrames(op string, v *Video, width, height, maxFrames int, env *RenderEnv) ([]byte, error) {
+	if width <= 0 || height <= 0 || maxFrames < 0 {
+		return nil, fmt.Errorf("%s: invalid frame size or count (width=%d, height=%d, frames=%d, file=%s, label=%s)", op, width, height, maxFrames, safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	// Frames never need the audio; leaving it out keeps the graph video-only.
+	src := v.clone()
+	src.audioRemoved = true
+	args, err := src.sourceArgs()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	if n := len(args); n >= 2 && args[n-2] == "-map" && args[n-1] == "0:a:0?" {
+		args = args[:n-2]
+	}
+	data, err := decodeRawFrames(frameRequest{
+		op:        op,
+		args:      args,
+		width:     width,
+		height:    height,
+		maxFrames: maxFrames,
+		duration:  v.duration,
+		env:       env,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// A truncated last frame is dropped.
+	size := width * height * 3
+	return data[:len(data)/size*size], nil
+}
@@ -0,0 +1,186 @@
+package moviego
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// frameReaderBudget caps the raw frames a FrameReader holds at once.
+const frameReaderBudget = 64 << 20
+
+// Frame is a decoded frame of a FrameReader.
+type Frame struct {
+	Index int     // frame number at the frame rate of the video
+	Time  float64 // start of the frame, in seconds
+	Image *image.RGBA
+}
+
+// FrameReader gives random access to the frames of a video, with the whole
+// edit applied, for scrubbers and analysis tools. Frames are decoded in
+// chunks through FrameBackend; Seek within the decoded chunk is free, and
+// Seek elsewhere only decodes from the nearest keyframe.
+//
+// A FrameReader is not safe for concurrent use.
+type FrameReader struct {
+	video  *Video
+	fps    uint64
+	frames int // frames in the video
+
+	// keyframes are the frame indexes of the source keyframes, ascending.
+	// Chunks end at keyframes so the next chunk starts on one without
+	// decoding frames twice. Empty for edits that are not a plain read of
+	// one file, which are decoded in fixed chunks.
+	keyframes []int
+	maxChunk  int
+
+	pos        int    // index of the frame Next returns
+	chunkStart int    // index of the first frame of chunk
+	chunk      []byte // packed RGB24 frames
+}
+
+// NewFrameReader opens a frame reader on v, positioned on the first frame.
+func NewFrameReader(v *Video) (*FrameReader, error) {
+	if v.duration <= 0 || v.width == 0 || v.height == 0 {
+		return nil, fmt.Errorf("NewFrameReader: video has no frames (duration=%.4f, size=%dx%d, file=%s, label=%s)", v.duration, v.width, v.height, safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	fps := v.fps
+	if fps == 0 {
+		fps = defaultClipFps
+	}
+	r := &FrameReader{
+		video:    v,
+		fps:      fps,
+		frames:   int(math.Ceil(v.duration*float64(fps) - 1e-6)),
+		maxChunk: max(1, frameReaderBudget/(int(v.width)*int(v.height)*3)),
+	}
+	if len(v.overlays) == 0 {
+		if start, _, ok := v.directSource(); ok {
+			keyframes, err := probeKeyframes(v.filenames[0])
+			if err != nil {
+				return nil, fmt.Errorf("NewFrameReader: %w (file=%s)", err, v.filenames[0])
+			}
+			for _, t := range keyframes {
+				if i := int(Seconds(t - start).Frame(fps)); i > 0 && i < r.frames {
+					r.keyframes = append(r.keyframes, i)
+				}
+			}
+		}
+	}
+	return r, nil
+}
+
+// Len returns the number of frames of the video.
+func (r *FrameReader) Len() int {
+	return r.frames
+}
+
+// Seek positions the reader on the frame shown at t, so Next returns it.
+func (r *FrameReader) Seek(t float64) error {
+	if t < 0 || t >= r.video.duration {
+		return fmt.Errorf("FrameReader.Seek: time must be within [0, %.4f) (got=%.4f, file=%s)", r.video.duration, t, safeFirstFilename(r.video.filenames))
+	}
+	return r.SeekFrame(int(Seconds(t).Frame(r.fps)))
+}
+
+// SeekFrame positions the reader on frame index, so Next returns it.
+func (r *FrameReader) SeekFrame(index int) error {
+	if index < 0 || index >= r.frames {
+		return fmt.Errorf("FrameReader.SeekFrame: index must be within [0, %d) (got=%d, file=%s)", r.frames, index, safeFirstFilename(r.video.filenames))
+	}
+	r.pos = index
+	return nil
+}
+
+// Next returns the frame at the current position and advances to the
+// following one. It returns io.EOF after the last frame.
+func (r *FrameReader) Next() (*Frame, error) {
+	if r.pos >= r.frames {
+		return nil, io.EOF
+	}
+	size := int(r.video.width) * int(r.video.height) * 3
+	if r.pos < r.chunkStart || r.pos >= r.chunkStart+len(r.chunk)/size {
+		if err := r.decodeChunk(); err != nil {
+			return nil, err
+		}
+		if len(r.chunk) == 0 {
+			// The source ends before its nominal duration.
+			r.frames = r.pos
+			return nil, io.EOF
+		}
+	}
+
+	raw := r.chunk[(r.pos-r.chunkStart)*size:]
+	img := image.NewRGBA(image.Rect(0, 0, int(r.video.width), int(r.video.height)))
+	for p := 0; p < size/3; p++ {
+		copy(img.Pix[4*p:4*p+3], raw[3*p:])
+		img.Pix[4*p+3] = 0xff
+	}
+	frame := &Frame{Index: r.pos, Time: FrameTime(int64(r.pos), r.fps).Seconds(), Image: img}
+	r.pos++
+	return frame, nil
+}
+
+// decodeChunk decodes the frames from the current position up to the next
+// keyframe, within the memory budget.
+func (r *FrameReader) decodeChunk() error {
+	count := min(r.maxChunk, r.frames-r.pos)
+	if i := sort.SearchInts(r.keyframes, r.pos+1); i < len(r.keyframes) {
+		count = min(count, r.keyframes[i]-r.pos)
+	}
+	start := FrameTime(int64(r.pos), r.fps).Seconds()
+	end := math.Min(FrameTime(int64(r.pos+count), r.fps).Seconds(), r.video.duration)
+	part, err := r.video.Cut(start, end)
+	if err != nil {
+		return fmt.Errorf("FrameReader.Next: %w", err)
+	}
+	data, err := decodeFrames("FrameReader.Next", part, int(r.video.width), int(r.video.height), count, nil)
+	if err != nil {
+		return err
+	}
+	r.chunkStart, r.chunk = r.pos, data
+	return nil
+}
+
+// probeKeyframes returns the keyframe times of the first video stream of
+// filename, relative to the start of the file, in ascending order. Only the
+// container is read, nothing is decoded.
+func probeKeyframes(filename string) ([]float64, error) {
+	ffprobePath, err := getFFprobePath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ffprobe path: %w", err)
+	}
+	output, err := commandOutput(ffprobePath, "-v", "error", "-select_streams", "v:0",
+		"-show_entries", "packet=pts_time,flags:format=start_time", "-of", "json", filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to probe keyframes: %w", err)
+	}
+	var result struct {
+		Packets []struct {
+			PtsTime string `json:"pts_time"`
+			Flags   string `json:"flags"`
+		} `json:"packets"`
+		Format struct {
+			StartTime string `json:"start_time"`
+		} `json:"format"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse keyframes: %w", err)
+	}
+	offset, _ := strconv.ParseFloat(result.Format.StartTime, 64)
+	var keyframes []float64
+	for _, p := range result.Packets {
+		if len(p.Flags) == 0 || p.Flags[0] != 'K' {
+			continue
+		}
+		if t, err := strconv.ParseFloat(p.PtsTime, 64); err == nil {
+			keyframes = append(keyframes, t-offset)
+		}
+	}
+	sort.Float64s(keyframes)
+	return keyframes, nil
+}
@@ -0,0 +1,34 @@
+package moviego
+
+import "fmt"
+
+// NewToneClip creates a sine tone of frequency Hz lasting duration seconds,
+// e.g. for beeps, censor tones or test signals. The tone is stereo at
+// -18 dBFS; use Volume to change its level.
+func NewToneClip(frequency, duration float64) (*Audio, error) {
+	if frequency <= 0 || frequency >= float64(defaultSampleRate)/2 || duration <= 0 {
+		return nil, fmt.Errorf("NewToneClip: frequency must be within (0, %d) Hz and duration positive (frequency=%.4f, duration=%.4f)", defaultSampleRate/2, frequency, duration)
+	}
+	label := fmt.Sprintf("tone_%d", incrementGlobalCounter())
+	return &Audio{
+		sampleRate: defaultSampleRate,
+		channels:   defaultChannels,
+		duration:   duration,
+		filterComplex: []FilterComplex{{
+			Order: incrementOrderCounter(),
+			FilterElement: fmt.Sprintf("sine=frequency=%s:sample_rate=%d:duration=%.4f,aformat=channel_layouts=%s",
+				formatFloat(frequency), defaultSampleRate, duration, channelLayouts[defaultChannels]),
+			Label: label + "_a",
+		}},
+	}, nil
+}
+
+// NewSilenceClip creates silence lasting duration seconds, e.g. to pad a
+// track or leave a gap between clips when concatenating audio.
+func NewSilenceClip(duration float64) (*Audio, error) {
+	if duration <= 0 {
+		return nil, fmt.Errorf("NewSilenceClip: duration must be positive (got=%.4f)", duration)
+	}
+	silence := silentAudio(incrementOrderCounter(), fmt.Sprintf("silence_%d", incrementGlobalCounter()), duration)
+	return &silence, nil
+}
@@ -0,0 +1,180 @@
+package moviego
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Length is one coordinate or size: pixels, a percentage of the reference
+// size (the frame width for X and widths, its height for Y and heights), or a
+// raw FFmpeg expression.
+type Length struct {
+	value   float64
+	percent bool
+	expr    string
+}
+
+// Px returns a length of n pixels.
+func Px(n float64) Length {
+	return Length{value: n}
+}
+
+// Percent returns a length of p percent of the reference size.
+func Percent(p float64) Length {
+	return Length{value: p, percent: true}
+}
+
+// Expr returns a raw FFmpeg expression. It is used as is, so it must use the
+// variables of the context it ends up in (e.g. W and w for overlays).
+func Expr(expr string) Length {
+	return Length{expr: expr}
+}
+
+// IsExpr reports whether the length is a raw expression.
+func (l Length) IsExpr() bool {
+	return l.expr != ""
+}
+
+// toExpr returns the length as an expression relative to ref.
+func (l Length) toExpr(ref string) string {
+	switch {
+	case l.expr != "":
+		return "(" + l.expr + ")"
+	case l.percent && l.value == 0:
+		return "0"
+	case l.percent && l.value == 100:
+		return ref
+	case l.percent:
+		return fmt.Sprintf("%s*%s", ref, formatFloat(l.value/100))
+	default:
+		return formatFloat(l.value)
+	}
+}
+
+// pixels resolves the length against ref pixels. Expressions cannot be
+// resolved without FFmpeg and return false.
+func (l Length) pixels(ref uint64) (float64, bool) {
+	switch {
+	case l.expr != "":
+		return 0, false
+	case l.percent:
+		return float64(ref) * l.value / 100, true
+	default:
+		return l.value, true
+	}
+}
+
+// Anchor is the point of an item that is placed at a Point.
+type Anchor int
+
+const (
+	AnchorTopLeft Anchor = iota
+	AnchorTop
+	AnchorTopRight
+	AnchorLeft
+	AnchorCenter
+	AnchorRight
+	AnchorBottomLeft
+	AnchorBottom
+	AnchorBottomRight
+)
+
+// fractions returns the anchor as fractions of the item width and height.
+func (a Anchor) fractions() (float64, float64) {
+	return float64(int(a)%3) / 2, float64(int(a)/3) / 2
+}
+
+// Point places an item on the frame: the item's Anchor is put at (X, Y).
+//
+//	// Bottom-right corner of a logo 20px from the bottom-right of the frame.
+//	logo.SetPosition(moviego.Point{
+//		X: moviego.Expr("W-20"), Y: moviego.Expr("H-20"),
+//		Anchor: moviego.AnchorBottomRight,
+//	}.Position())
+type Point struct {
+	X      Length
+	Y      Length
+	Anchor Anchor
+}
+
+// PointAt returns a point at the given percentages of the frame, e.g.
+// PointAt(50, 50, AnchorCenter) centers an item.
+func PointAt(xPercent, yPercent float64, anchor Anchor) Point {
+	return Point{X: Percent(xPercent), Y: Percent(yPercent), Anchor: anchor}
+}
+
+// Position returns the point as an overlay position for composites, image
+// and color clips (frame size W×H, item size w×h).
+func (p Point) Position() Position {
+	return p.position("W", "H", "w", "h")
+}
+
+// TextPosition returns the point as a TextClip position (frame size w×h,
+// text size tw×th).
+func (p Point) TextPosition() Position {
+	return p.position("w", "h", "tw", "th")
+}
+
+func (p Point) position(frameW, frameH, itemW, itemH string) Position {
+	fx, fy := p.Anchor.fractions()
+	return Position{
+		X: anchored(p.X.toExpr(frameW), itemW, fx),
+		Y: anchored(p.Y.toExpr(frameH), itemH, fy),
+	}
+}
+
+func anchored(expr, size string, fraction float64) string {
+	switch fraction {
+	case 0:
+		return expr
+	case 1:
+		return fmt.Sprintf("%s-%s", expr, size)
+	default:
+		return fmt.Sprintf("%s-%s*%s", expr, size, formatFloat(fraction))
+	}
+}
+
+// Rect is a rectangle on the frame, e.g. a crop region.
+type Rect struct {
+	X      Length
+	Y      Length
+	Width  Length
+	Height Length
+}
+
+// resolve returns the rectangle in pixels for a frame of width×height.
+func (r Rect) resolve(width, height uint64) (x, y, w, h int, err error) {
+	values := []struct {
+		name string
+		l    Length
+		ref  uint64
+		out  *int
+	}{
+		{"X", r.X, width, &x},
+		{"Y", r.Y, height, &y},
+		{"Width", r.Width, width, &w},
+		{"Height", r.Height, height, &h},
+	}
+	for _, v := range values {
+		px, ok := v.l.pixels(v.ref)
+		if !ok {
+			return 0, 0, 0, 0, fmt.Errorf("%s: expressions are not supported in a Rect (got=%s)", v.name, v.l.expr)
+		}
+		*v.out = int(px + 0.5)
+	}
+	return x, y, w, h, nil
+}
+
+// CropRect crops the video to r, see Crop. Percentages are relative to the
+// current frame size.
+func (v *Video) CropRect(r Rect) (*Video, error) {
+	x, y, w, h, err := r.resolve(v.width, v.height)
+	if err != nil {
+		return nil, fmt.Errorf("CropRect: %w (file=%s, label=%s)", err, safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	return v.Crop(CropParams{X: x, Y: y, Width: w, Height: h})
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,208 @@
+package moviego
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// HTMLRenderer renders an HTML/CSS document to media with a transparent
+// background. A renderer capturing animations (e.g. a browser driven
+// frame by frame) returns a SourceImageSequence or a SourceVideo with alpha;
+// a renderer taking a single screenshot returns a SourceImage.
+type HTMLRenderer interface {
+	RenderHTML(html string, req ClipRequest) (ClipSource, error)
+}
+
+// HTMLRendererFunc adapts a function to HTMLRenderer.
+type HTMLRendererFunc func(html string, req ClipRequest) (ClipSource, error)
+
+// RenderHTML calls f.
+func (f HTMLRendererFunc) RenderHTML(html string, req ClipRequest) (ClipSource, error) {
+	return f(html, req)
+}
+
+// ChromiumRenderer takes a transparent screenshot of the document with
+// headless Chromium. It renders still graphics only; use a custom
+// HTMLRenderer to capture CSS or JavaScript animations.
+type ChromiumRenderer struct {
+	Path string // browser executable (default: chromium, chromium-browser or google-chrome from PATH)
+}
+
+// RenderHTML writes html to req.TempDir and screenshots it at the request size.
+func (c ChromiumRenderer) RenderHTML(html string, req ClipRequest) (ClipSource, error) {
+	browser, err := c.browserPath()
+	if err != nil {
+		return ClipSource{}, err
+	}
+	page := filepath.Join(req.TempDir, "page.html")
+	if err := os.WriteFile(page, []byte(html), 0644); err != nil {
+		return ClipSource{}, err
+	}
+	abs, err := filepath.Abs(page)
+	if err != nil {
+		return ClipSource{}, err
+	}
+	screenshot := filepath.Join(req.TempDir, "page.png")
+	absShot, err := filepath.Abs(screenshot)
+	if err != nil {
+		return ClipSource{}, err
+	}
+	out, err := commandCombinedOutput(browser,
+		"--headless", "--disable-gpu", "--hide-scrollbars",
+		"--default-background-color=00000000",
+		fmt.Sprintf("--window-size=%d,%d", req.Width, req.Height),
+		"--screenshot="+absShot,
+		"file://"+filepath.ToSlash(abs))
+	if err != nil {
+		return ClipSource{}, fmt.Errorf("chromium failed: %w: %s", err, out)
+	}
+	return ClipSource{Kind: SourceImage, Path: screenshot}, nil
+}
+
+func (c ChromiumRenderer) browserPath() (string, error) {
+	if c.Path != "" {
+		return c.Path, nil
+	}
+	for _, name := range []string{"chromium", "chromium-browser", "google-chrome"} {
+		if p, err := lookPath(name); err == nil {
+			return p, nil
+		}
+	}
+	return "", fmt.Errorf("no Chromium executable found in PATH")
+}
+
+// HTMLClip is a graphic defined in HTML/CSS, rendered with a transparent
+// background and composited like an image or video:
+//
+//	banner := moviego.NewHTMLClip(`<h1 style="color:white">Live</h1>`, 640, 120, 5).
+//		SetPosition(moviego.BottomLeftPosition())
+//	video, err = moviego.CompositeClips(video, banner)
+//
+// It is rendered when the composite is built, with ChromiumRenderer unless
+// another renderer is set. The frames are removed once a WriteVideo reading
+// them finishes, so build the composite again to render it twice.
+type HTMLClip struct {
+	html             string
+	width            uint64
+	height           uint64
+	duration         float64
+	fps              uint64
+	position         Position
+	animatedPosition *AnimatedPosition
+	animatedOpacity  *Animation
+	renderer         HTMLRenderer
+	env              *RenderEnv
+}
+
+// NewHTMLClip creates an HTMLClip rendering html at the given size for duration seconds.
+func NewHTMLClip(html string, width, height uint64, duration float64) *HTMLClip {
+	return &HTMLClip{
+		html:     html,
+		width:    width,
+		height:   height,
+		duration: duration,
+	}
+}
+
+// GetWidth returns the clip width.
+func (hc *HTMLClip) GetWidth() uint64 {
+	return hc.width
+}
+
+// GetHeight returns the clip height.
+func (hc *HTMLClip) GetHeight() uint64 {
+	return hc.height
+}
+
+// GetDuration returns the clip duration.
+func (hc *HTMLClip) GetDuration() float64 {
+	return hc.duration
+}
+
+// GetFps returns the frame rate the document is rendered at (default: 30).
+func (hc *HTMLClip) GetFps() uint64 {
+	if hc.fps == 0 {
+		return defaultClipFps
+	}
+	return hc.fps
+}
+
+// GetPosition returns the overlay position.
+// Returns center position if none was explicitly set.
+func (hc *HTMLClip) GetPosition() Position {
+	if hc.position.X == "" && hc.position.Y == "" {
+		return CenterPosition()
+	}
+	return hc.position
+}
+
+// GetHTML returns the HTML document.
+func (hc *HTMLClip) GetHTML() string {
+	return hc.html
+}
+
+// Fps sets the frame rate the document is rendered at.
+func (hc *HTMLClip) Fps(fps uint64) *HTMLClip {
+	hc.fps = fps
+	return hc
+}
+
+// Renderer sets the renderer used instead of ChromiumRenderer.
+func (hc *HTMLClip) Renderer(r HTMLRenderer) *HTMLClip {
+	hc.renderer = r
+	return hc
+}
+
+// Env sets the render environment; its TempDir holds the rendered frames.
+func (hc *HTMLClip) Env(env *RenderEnv) *HTMLClip {
+	hc.env = env
+	return hc
+}
+
+// SetPosition sets the overlay position used by CompositeClip.
+func (hc *HTMLClip) SetPosition(position Position) *HTMLClip {
+	hc.position = position
+	return hc
+}
+
+// SetAnimatedPosition sets the overlay position animation for CompositeClip.
+func (hc *HTMLClip) SetAnimatedPosition(ap AnimatedPosition) *HTMLClip {
+	hc.animatedPosition = &ap
+	return hc
+}
+
+// SetAnimatedOpacity sets the overlay opacity animation for CompositeClip.
+func (hc *HTMLClip) SetAnimatedOpacity(a Animation) *HTMLClip {
+	hc.animatedOpacity = &a
+	return hc
+}
+
+// toVideo renders the document and loads the result as a clip.
+func (hc *HTMLClip) toVideo() (*Video, error) {
+	if err := hc.Validate(); err != nil {
+		return nil, err
+	}
+	renderer := hc.renderer
+	if renderer == nil {
+		renderer = ChromiumRenderer{}
+	}
+	req, err := newClipRequest("html", hc.width, hc.height, hc.duration, hc.GetFps(), hc.env)
+	if err != nil {
+		return nil, fmt.Errorf("HTMLClip: %w", err)
+	}
+	src, err := renderer.RenderHTML(hc.html, req)
+	if err != nil {
+		releaseClipTempDir(req.TempDir)
+		return nil, fmt.Errorf("HTMLClip: render failed: %w", err)
+	}
+	v, err := src.toVideo(req)
+	if err != nil {
+		releaseClipTempDir(req.TempDir)
+		return nil, fmt.Errorf("HTMLClip: %w", err)
+	}
+	v.position = hc.position
+	v.animatedPosition = hc.animatedPosition
+	v.animatedOpacity = hc.animatedOpacity
+	return v, nil
+}
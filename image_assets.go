@@ -0,0 +1,178 @@
+package moviego
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ImageFormat is the file format of a converted image.
+type ImageFormat string
+
+const (
+	ImageWebP ImageFormat = "webp"
+	ImagePNG  ImageFormat = "png"
+	ImageJPEG ImageFormat = "jpg"
+)
+
+// ImageOptions configures ConvertImage and AssetManager.PrepareImage.
+type ImageOptions struct {
+	// Width and Height bound the image, which is scaled down to fit inside
+	// them keeping its aspect ratio. Zero leaves a side unbounded. Images
+	// are never scaled up.
+	Width  uint64
+	Height uint64
+	// Format is the output format (default: from the output extension for
+	// ConvertImage, the source format for PrepareImage).
+	Format ImageFormat
+	// Quality is the WebP or JPEG quality, 1-100 (default: 90).
+	Quality int
+	// Lossless encodes WebP losslessly, e.g. for flat logos and text.
+	Lossless bool
+}
+
+// imageFormats maps extensions to image formats.
+var imageFormats = map[string]ImageFormat{
+	".webp": ImageWebP,
+	".png":  ImagePNG,
+	".jpg":  ImageJPEG,
+	".jpeg": ImageJPEG,
+}
+
+// args returns the FFmpeg output arguments writing a single image.
+func (o ImageOptions) args() ([]string, error) {
+	quality := o.Quality
+	if quality == 0 {
+		quality = 90
+	}
+	if quality < 1 || quality > 100 {
+		return nil, fmt.Errorf("quality must be in [1, 100] (got=%d)", o.Quality)
+	}
+	if o.Lossless && o.Format != ImageWebP {
+		return nil, fmt.Errorf("lossless encoding is only supported for WebP (format=%s)", o.Format)
+	}
+	args := []string{"-frames:v", "1", "-map_metadata", "-1"}
+	if scale := o.scaleFilter(); scale != "" {
+		args = append(args, "-vf", scale)
+	}
+	switch o.Format {
+	case ImageWebP:
+		args = append(args, "-c:v", "libwebp")
+		if o.Lossless {
+			args = append(args, "-lossless", "1")
+		} else {
+			args = append(args, "-quality", fmt.Sprint(quality))
+		}
+	case ImagePNG:
+		args = append(args, "-c:v", "png", "-pred", "mixed")
+	case ImageJPEG:
+		// mjpeg's qscale runs from 2 (best) to 31.
+		args = append(args, "-c:v", "mjpeg", "-q:v", fmt.Sprint(31-(quality-1)*29/99))
+	default:
+		return nil, fmt.Errorf("unsupported image format %q", o.Format)
+	}
+	return append(args, "-update", "1"), nil
+}
+
+// scaleFilter returns the filter fitting the image inside Width x Height
+// without scaling it up.
+func (o ImageOptions) scaleFilter() string {
+	w, h := "-1", "-1"
+	if o.Width > 0 {
+		w = fmt.Sprintf(`min(%d\,iw)`, o.Width)
+	}
+	if o.Height > 0 {
+		h = fmt.Sprintf(`min(%d\,ih)`, o.Height)
+	}
+	switch {
+	case o.Width > 0 && o.Height > 0:
+		return fmt.Sprintf("scale=w=%s:h=%s:force_original_aspect_ratio=decrease:flags=lanczos", w, h)
+	case o.Width > 0 || o.Height > 0:
+		return fmt.Sprintf("scale=w=%s:h=%s:flags=lanczos", w, h)
+	}
+	return ""
+}
+
+// ConvertImage converts a still image (overlay, logo, cover) to the format
+// of dst, optionally scaled down, with its metadata (EXIF, GPS, color
+// profiles) removed. Preparing assets once at the size they are used at
+// spares every render from decoding and scaling the full-size original.
+func ConvertImage(src, dst string, opts ImageOptions) error {
+	if _, err := os.Stat(src); err != nil {
+		return fmt.Errorf("ConvertImage: %w", err)
+	}
+	if opts.Format == "" {
+		opts.Format = imageFormats[strings.ToLower(filepath.Ext(dst))]
+	}
+	args, err := opts.args()
+	if err != nil {
+		return fmt.Errorf("ConvertImage: %w (file=%s)", err, src)
+	}
+	ffmpegPath, err := getFFmpegPath()
+	if err != nil {
+		return fmt.Errorf("ConvertImage: failed to get ffmpeg path: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return fmt.Errorf("ConvertImage: %w", err)
+	}
+	job := renderJob{
+		op:         "ConvertImage",
+		args:       []string{"-i", src},
+		outputArgs: args,
+		output:     dst,
+		silent:     true,
+	}
+	return job.run(ffmpegPath)
+}
+
+// PrepareImage resolves ref (see Resolve) and returns a converted copy of
+// it (see ConvertImage) from the cache. Variants are keyed by the content of
+// the image and the options, so a template's assets are converted on its
+// first render only.
+func (m *AssetManager) PrepareImage(ref string, opts ImageOptions) (string, error) {
+	src, err := m.Resolve(ref)
+	if err != nil {
+		return "", err
+	}
+	if opts.Format == "" {
+		opts.Format = imageFormats[strings.ToLower(filepath.Ext(src))]
+	}
+	if _, err := opts.args(); err != nil {
+		return "", fmt.Errorf("AssetManager: %w (file=%s)", err, ref)
+	}
+
+	f, err := os.Open(src)
+	if err != nil {
+		return "", fmt.Errorf("AssetManager: %w", err)
+	}
+	hash := sha256.New()
+	_, err = io.Copy(hash, f)
+	f.Close()
+	if err != nil {
+		return "", fmt.Errorf("AssetManager: %w", err)
+	}
+	fmt.Fprintf(hash, "\x00%+v", opts)
+	p := filepath.Join(m.dir, "objects", hex.EncodeToString(hash.Sum(nil))+"."+string(opts.Format))
+	if _, err := os.Stat(p); err == nil {
+		return p, nil
+	}
+
+	// Converted to a temporary name and renamed, like downloads.
+	tmp, err := os.CreateTemp(filepath.Join(m.dir, "objects"), ".prepare-*."+string(opts.Format))
+	if err != nil {
+		return "", fmt.Errorf("AssetManager: %w", err)
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+	if err := ConvertImage(src, tmp.Name(), opts); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), p); err != nil {
+		return "", fmt.Errorf("AssetManager: %w", err)
+	}
+	return p, nil
+}
@@ -1,5 +1,7 @@
 package moviego
 
+import "fmt"
+
 // ImageClip represents a still image used as a clip in compositions/timelines.
 // It carries only image-relevant fields — no audio, codec, fps, or video filter chains.
 type ImageClip struct {
@@ -7,14 +9,12 @@ type ImageClip struct {
 	width            uint64
 	height           uint64
 	duration         float64
+	fps              uint64
 	position         Position
 	animatedPosition *AnimatedPosition
 	animatedOpacity  *Animation
 }
 
-// Compile-time interface satisfaction check.
-var _ Clip = (*ImageClip)(nil)
-
 // NewImageClip creates a new ImageClip with the given filename, dimensions, and duration.
 func NewImageClip(filename string, width, height uint64, duration float64) *ImageClip {
 	return &ImageClip{
@@ -44,6 +44,14 @@ func (ic *ImageClip) GetDuration() float64 {
 	return ic.duration
 }
 
+// GetFps returns the frame rate the image is rendered at (default: 30).
+func (ic *ImageClip) GetFps() uint64 {
+	if ic.fps == 0 {
+		return defaultClipFps
+	}
+	return ic.fps
+}
+
 // GetPosition returns the overlay position.
 // Returns center position if none was explicitly set.
 func (ic *ImageClip) GetPosition() Position {
@@ -68,6 +76,12 @@ func (ic *ImageClip) Duration(d float64) *ImageClip {
 	return ic
 }
 
+// Fps sets the frame rate the image is rendered at.
+func (ic *ImageClip) Fps(fps uint64) *ImageClip {
+	ic.fps = fps
+	return ic
+}
+
 // SetPosition sets the overlay position used by CompositeClip.
 func (ic *ImageClip) SetPosition(position Position) *ImageClip {
 	ic.position = position
@@ -85,3 +99,21 @@ func (ic *ImageClip) SetAnimatedOpacity(a Animation) *ImageClip {
 	ic.animatedOpacity = &a
 	return ic
 }
+
+// toVideo loops the single decoded frame for the clip duration and scales it
+// to the clip size. The clip carries silence as its audio.
+func (ic *ImageClip) toVideo() (*Video, error) {
+	if err := ic.Validate(); err != nil {
+		return nil, err
+	}
+	fps := ic.GetFps()
+	label := fmt.Sprintf("image_%d_%s", incrementGlobalCounter(), sanitize(ic.filename))
+	fileCopy := FileCopy{Filename: ic.filename, Label: label + "_in"}
+	element := fmt.Sprintf("[%s]loop=loop=-1:size=1:start=0,setpts=N/(%d*TB),trim=duration=%.4f,scale=%d:%d,setsar=1",
+		fileCopy.Label, fps, ic.duration, ic.width, ic.height)
+	v := generatedVideo([]string{ic.filename}, element, fileCopy, label, ic.width, ic.height, fps, ic.duration)
+	v.position = ic.position
+	v.animatedPosition = ic.animatedPosition
+	v.animatedOpacity = ic.animatedOpacity
+	return v, nil
+}
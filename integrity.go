@@ -0,0 +1,121 @@
+package moviego
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DecodeError is an error FFmpeg reported while decoding an input.
+type DecodeError struct {
+	File    string
+	Time    float64 // approximate position in seconds (decoding progress when reported)
+	Message string
+}
+
+// IntegrityReport is the result of VerifyIntegrity.
+type IntegrityReport struct {
+	Errors  []DecodeError
+	Decoded map[string]float64 // seconds decoded per input file
+}
+
+// OK reports whether the inputs decoded without any error.
+func (r IntegrityReport) OK() bool {
+	return len(r.Errors) == 0
+}
+
+// VerifyIntegrity decodes every stream of the video's input files completely
+// (without writing anything) and reports the decode errors with their
+// position, e.g. to validate uploads before they enter a render pipeline.
+// Corrupt files are reported in the IntegrityReport; the error is only set
+// when FFmpeg cannot be run.
+func (v *Video) VerifyIntegrity(env *RenderEnv) (IntegrityReport, error) {
+	ffmpegPath, err := getFFmpegPath()
+	if err != nil {
+		return IntegrityReport{}, fmt.Errorf("VerifyIntegrity: failed to get ffmpeg path: %w", err)
+	}
+	report := IntegrityReport{Decoded: make(map[string]float64)}
+	seen := make(map[string]bool)
+	for _, file := range v.filenames {
+		if seen[file] {
+			continue
+		}
+		seen[file] = true
+		decoded, errs, err := verifyFile(ffmpegPath, file, env)
+		if err != nil {
+			return IntegrityReport{}, fmt.Errorf("VerifyIntegrity: %w", err)
+		}
+		report.Decoded[file] = decoded
+		report.Errors = append(report.Errors, errs...)
+	}
+	return report, nil
+}
+
+// verifyFile decodes file to the null muxer. Errors are read from stderr
+// while the progress on stdout gives the position they occurred at.
+func verifyFile(ffmpegPath, file string, env *RenderEnv) (float64, []DecodeError, error) {
+	cmd, err := env.command(ffmpegPath,
+		"-v", "error", "-err_detect", "crccheck+bitstream+buffer",
+		"-i", file, "-map", "0", "-f", "null",
+		"-progress", "pipe:1", "-nostats", "-")
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid render environment: %w", err)
+	}
+	stdout, stdoutWriter := io.Pipe()
+	stderr, stderrWriter := io.Pipe()
+	cmd.Stdout, cmd.Stderr = stdoutWriter, stderrWriter
+	done := make(chan error, 1)
+	go func() {
+		err := runCommand(cmd)
+		stdoutWriter.Close()
+		stderrWriter.Close()
+		done <- err
+	}()
+
+	var mu sync.Mutex
+	var position float64
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer io.Copy(io.Discard, stdout)
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			if value, ok := strings.CutPrefix(scanner.Text(), "out_time_us="); ok {
+				if us, err := strconv.ParseInt(value, 10, 64); err == nil {
+					mu.Lock()
+					position = float64(us) / 1_000_000
+					mu.Unlock()
+				}
+			}
+		}
+	}()
+
+	var errs []DecodeError
+	scanner := bufio.NewScanner(stderr)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		mu.Lock()
+		errs = append(errs, DecodeError{File: file, Time: position, Message: line})
+		mu.Unlock()
+	}
+	io.Copy(io.Discard, stderr)
+	wg.Wait()
+
+	if err := <-done; err != nil {
+		var exitErr *ExitError
+		if !errors.As(err, &exitErr) {
+			return 0, nil, err
+		}
+		// A file FFmpeg cannot read at all is a failed check, not a failed run.
+		errs = append(errs, DecodeError{File: file, Time: position, Message: "ffmpeg: " + err.Error()})
+	}
+	return position, errs, nil
+}
@@ -0,0 +1,88 @@
+package moviego
+
+import (
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+)
+
+// KeyframeOptions controls where the encoder places keyframes. Fixed GOPs
+// and forced keyframes at cut points give clean HLS/DASH segment boundaries
+// and let downstream tools trim frame-accurately without re-encoding.
+type KeyframeOptions struct {
+	Interval    float64   // seconds between keyframes, -g (0 = encoder default)
+	MinInterval float64   // minimum seconds between keyframes, -keyint_min (0 = encoder default)
+	At          []float64 // keyframes forced at these times, e.g. chapter or cut points
+	// NoSceneCut stops the encoder from inserting extra keyframes on scene
+	// changes, so keyframes fall only on the interval and the forced times.
+	NoSceneCut bool
+}
+
+// keyframeArgs returns the encoder arguments for k. They are placed after
+// the Tune and delivery profile arguments, so an Interval overrides the GOP
+// they set.
+func keyframeArgs(k KeyframeOptions, encoder string, fps uint64) ([]string, error) {
+	if k.Interval < 0 || k.MinInterval < 0 {
+		return nil, fmt.Errorf("keyframe intervals must be >= 0 (got interval=%s, min=%s)", formatFloat(k.Interval), formatFloat(k.MinInterval))
+	}
+	if k.Interval > 0 && k.MinInterval > k.Interval {
+		return nil, fmt.Errorf("keyframe min interval exceeds interval (got min=%s, interval=%s)", formatFloat(k.MinInterval), formatFloat(k.Interval))
+	}
+	if fps == 0 {
+		fps = defaultClipFps
+	}
+	frames := func(seconds float64) string {
+		return strconv.FormatUint(max(1, uint64(seconds*float64(fps)+0.5)), 10)
+	}
+
+	var args []string
+	if k.Interval > 0 {
+		args = append(args, "-g", frames(k.Interval))
+	}
+	if k.MinInterval > 0 {
+		args = append(args, "-keyint_min", frames(k.MinInterval))
+	}
+	if len(k.At) > 0 {
+		times := make([]string, len(k.At))
+		for i, t := range k.At {
+			if t < 0 {
+				return nil, fmt.Errorf("forced keyframe time must be >= 0 (got=%s)", formatFloat(t))
+			}
+			times[i] = formatFloat(t)
+		}
+		args = append(args, "-force_key_frames", strings.Join(times, ","))
+	}
+	if k.NoSceneCut {
+		switch encoder {
+		case "libx264":
+			args = append(args, "-sc_threshold", "0")
+		case "libx265":
+			// libx265 has no -sc_threshold; scenecut=0 is merged into the
+			// -x265-params of the EncoderOptions, see sceneCutOptions.
+		case "h264_nvenc", "hevc_nvenc", "av1_nvenc":
+			args = append(args, "-no-scenecut", "1")
+		}
+	}
+	return args, nil
+}
+
+// sceneCutOptions returns o with scenecut=0 added to the libx265 params
+// when k disables scene cuts, so one -x265-params carries both. The
+// caller's options are not modified.
+func (k KeyframeOptions) sceneCutOptions(o EncoderOptions, encoder string) EncoderOptions {
+	if !k.NoSceneCut || encoder != "libx265" {
+		return o
+	}
+	x := X264Options{}
+	if o.X264 != nil {
+		x = *o.X264
+	}
+	x.Params = maps.Clone(x.Params)
+	if x.Params == nil {
+		x.Params = map[string]string{}
+	}
+	x.Params["scenecut"] = "0"
+	o.X264 = &x
+	return o
+}
@@ -0,0 +1,166 @@
+package moviego
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Levels is a loudness and peak meter reading (EBU R128), emitted every
+// 100 ms of audio while metering.
+type Levels struct {
+	Time       float64   // position of the reading, in seconds
+	Momentary  float64   // loudness over the last 400 ms, LUFS
+	ShortTerm  float64   // loudness over the last 3 s, LUFS
+	Integrated float64   // loudness since the start, LUFS
+	Range      float64   // loudness range since the start, LU
+	Peak       []float64 // true peak of the last 100 ms per channel, dBTP
+}
+
+// meterFilter measures the audio passing through it and logs a reading
+// every 100 ms, parsed by levelsWriter. It does not change the audio.
+const meterFilter = "ebur128=peak=true:framelog=info"
+
+var levelsPattern = regexp.MustCompile(`t:\s*(\S+)\s+TARGET:\S+ LUFS\s+M:\s*(\S+)\s+S:\s*(\S+)\s+I:\s*(\S+) LUFS\s+LRA:\s*(\S+) LU(?:\s+FTPK:((?:\s+\S+)+?) dBFS)?`)
+
+// parseLevels parses an ebur128 reading logged by FFmpeg.
+func parseLevels(line string) (Levels, bool) {
+	m := levelsPattern.FindStringSubmatch(line)
+	if m == nil {
+		return Levels{}, false
+	}
+	values := make([]float64, 5)
+	for i := range values {
+		var err error
+		if values[i], err = strconv.ParseFloat(m[i+1], 64); err != nil {
+			return Levels{}, false
+		}
+	}
+	l := Levels{Time: values[0], Momentary: values[1], ShortTerm: values[2], Integrated: values[3], Range: values[4]}
+	for _, field := range strings.Fields(m[6]) {
+		if peak, err := strconv.ParseFloat(field, 64); err == nil {
+			l.Peak = append(l.Peak, peak)
+		}
+	}
+	return l, true
+}
+
+// levelsWriter receives the stderr of an FFmpeg process metering with
+// meterFilter. Readings go to onLevels; every other line goes to rest, so
+// errors are reported as usual.
+type levelsWriter struct {
+	onLevels func(Levels)
+	rest     io.Writer
+
+	mu      sync.Mutex
+	partial []byte
+	last    *Levels
+}
+
+func (w *levelsWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexAny(w.partial, "\r\n")
+		if i < 0 {
+			break
+		}
+		line := string(w.partial[:i+1])
+		w.partial = w.partial[i+1:]
+		if l, ok := parseLevels(line); ok {
+			w.last = &l
+			if w.onLevels != nil {
+				w.onLevels(l)
+			}
+		} else if w.rest != nil {
+			w.rest.Write([]byte(line))
+		}
+	}
+	return len(p), nil
+}
+
+// latest returns the last reading, nil before the first one.
+func (w *levelsWriter) latest() *Levels {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.last == nil {
+		return nil
+	}
+	l := *w.last
+	return &l
+}
+
+// MonitorOptions configures MonitorLevels.
+type MonitorOptions struct {
+	// Format is the FFmpeg input device or format of live inputs, e.g.
+	// "pulse", "alsa", "avfoundation" or "dshow". Empty for files and
+	// network streams.
+	Format string
+	// InputArgs are extra input options, e.g. {"-sample_rate", "48000"}.
+	InputArgs []string
+	// OnLevels receives a reading every 100 ms (required).
+	OnLevels func(Levels)
+	Env      *RenderEnv
+}
+
+// MonitorLevels meters the first audio stream of input, e.g. a capture
+// device or a live stream, until stop is closed or the input ends. Nothing
+// is written; the readings go to opts.OnLevels for realtime dashboards.
+// To meter a render, set VideoParameters.OnLevels instead.
+func MonitorLevels(input string, opts MonitorOptions, stop <-chan struct{}) error {
+	if input == "" {
+		return fmt.Errorf("MonitorLevels: input cannot be empty")
+	}
+	if opts.OnLevels == nil {
+		return fmt.Errorf("MonitorLevels: OnLevels is required (input=%s)", input)
+	}
+	ffmpegPath, err := getFFmpegPath()
+	if err != nil {
+		return fmt.Errorf("MonitorLevels: failed to get ffmpeg path: %w", err)
+	}
+	args := []string{"-hide_banner", "-nostats"}
+	if opts.Format != "" {
+		args = append(args, "-f", opts.Format)
+	}
+	args = append(append(args, opts.InputArgs...), "-i", input, "-map", "0:a:0", "-af", meterFilter, "-f", "null", "-")
+	cmd, err := opts.Env.command(ffmpegPath, args...)
+	if err != nil {
+		return fmt.Errorf("MonitorLevels: invalid render environment: %w", err)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &levelsWriter{onLevels: opts.OnLevels, rest: &stderr}
+	// FFmpeg stops cleanly when it reads "q". An *os.File is handed to the
+	// process as is, so a finished FFmpeg does not wait on the pipe.
+	stdin, stdinWriter, err := os.Pipe()
+	if err != nil {
+		return fmt.Errorf("MonitorLevels: %w", err)
+	}
+	defer stdin.Close()
+	defer stdinWriter.Close()
+	cmd.Stdin = stdin
+
+	done := make(chan error, 1)
+	go func() {
+		done <- runCommand(cmd)
+	}()
+	select {
+	case err = <-done:
+	case <-stop:
+		stdinWriter.Write([]byte("q"))
+		err = <-done
+		// Stopping a live input ends FFmpeg with an error status.
+		if _, ok := err.(*ExitError); ok {
+			err = nil
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("MonitorLevels: failed to execute ffmpeg: %w\nffmpeg stderr: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package moviego
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Loop plays the video count times back to back. Like Concatenate, the
+// repeats are built in the filter graph and rendered in one FFmpeg pass; the
+// frames of one pass are buffered while they are replayed, so loop short
+// clips rather than long ones.
+//
+// Returns a new Video object with updated metadata (no file is created until WriteVideo is called)
+func (v *Video) Loop(count int) (*Video, error) {
+	if count < 1 {
+		return nil, fmt.Errorf("Loop: count must be at least 1 (got=%d, file=%s, label=%s)", count, safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	return v.loop("Loop", count, v.duration*float64(count))
+}
+
+// LoopToDuration repeats the video until it lasts seconds, cutting the last
+// repeat short when seconds is not a multiple of the video duration.
+//
+// Returns a new Video object with updated metadata (no file is created until WriteVideo is called)
+func (v *Video) LoopToDuration(seconds float64) (*Video, error) {
+	if seconds <= 0 {
+		return nil, fmt.Errorf("LoopToDuration: duration must be positive (got=%.4f, file=%s, label=%s)", seconds, safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	if v.duration <= 0 {
+		return nil, fmt.Errorf("LoopToDuration: video has no duration (file=%s, label=%s)", safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	// The epsilon keeps an exact multiple from adding an empty repeat.
+	count := int(math.Ceil(seconds/v.duration - 1e-9))
+	return v.loop("LoopToDuration", count, seconds)
+}
+
+// loop splits both branches into count copies, joins them with concat and
+// trims the result to total seconds.
+func (v *Video) loop(op string, count int, total float64) (*Video, error) {
+	if len(v.overlays) > 0 {
+		flat, err := v.FlattenOverlays()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		return flat.loop(op, count, total)
+	}
+	if len(v.filenames) == 0 && len(v.filterComplex) == 0 {
+		return nil, fmt.Errorf("%s: video has no input (file=<none>)", op)
+	}
+	trim := total < v.duration*float64(count)
+	if count == 1 && !trim {
+		out := v.clone()
+		return &out, nil
+	}
+
+	graph := func(in, p, kind, setpts, trimFilter string) string {
+		var b strings.Builder
+		split := "split"
+		if kind == "a" {
+			split = "asplit"
+		}
+		fmt.Fprintf(&b, "[%s]%s,%s=%d", in, setpts, split, count)
+		for i := 0; i < count; i++ {
+			fmt.Fprintf(&b, "[%s_%d]", p, i)
+		}
+		b.WriteString(";")
+		for i := 0; i < count; i++ {
+			fmt.Fprintf(&b, "[%s_%d]", p, i)
+		}
+		if kind == "a" {
+			fmt.Fprintf(&b, "concat=n=%d:v=0:a=1", count)
+		} else {
+			fmt.Fprintf(&b, "concat=n=%d:v=1:a=0", count)
+		}
+		if trim {
+			fmt.Fprintf(&b, ",%s=duration=%s", trimFilter, formatFloat(total))
+		}
+		return b.String()
+	}
+	videoGraph := func(in, p string) string {
+		return graph(in, p, "v", "setpts=PTS-STARTPTS", "trim")
+	}
+	audioGraph := func(in, p string) string {
+		return graph(in, p, "a", "asetpts=PTS-STARTPTS", "atrim")
+	}
+
+	looped := v.graphFilter(videoGraph, audioGraph)
+	looped.duration = total
+	looped.audio.duration = total
+	looped.frames = uint64(float64(v.fps) * total)
+	looped.startTime = 0
+	looped.endTime = total
+	return looped, nil
+}
@@ -0,0 +1,183 @@
+package moviego
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+)
+
+// LottieRenderer renders a Lottie JSON animation to transparent frames,
+// typically a SourceImageSequence written to req.TempDir. Implementations
+// wrap a Lottie player such as rlottie bindings or a headless browser.
+type LottieRenderer interface {
+	RenderLottie(filename string, req ClipRequest) (ClipSource, error)
+}
+
+// LottieRendererFunc adapts a function to LottieRenderer.
+type LottieRendererFunc func(filename string, req ClipRequest) (ClipSource, error)
+
+// RenderLottie calls f.
+func (f LottieRendererFunc) RenderLottie(filename string, req ClipRequest) (ClipSource, error) {
+	return f(filename, req)
+}
+
+// LottieClip is a Lottie (Bodymovin) animation exported from After Effects,
+// composited with its transparency like any other clip:
+//
+//	logo, err := moviego.NewLottieFile("logo.json")
+//	logo.Renderer(myRlottieRenderer).SetPosition(moviego.TopRightPosition())
+//	video, err = moviego.CompositeClips(video, logo)
+//
+// The animation's own size, frame rate and duration are used unless changed.
+// Like HTMLClip, the rendered frames only last for one WriteVideo.
+type LottieClip struct {
+	filename         string
+	width            uint64
+	height           uint64
+	duration         float64
+	fps              uint64
+	position         Position
+	animatedPosition *AnimatedPosition
+	animatedOpacity  *Animation
+	renderer         LottieRenderer
+	env              *RenderEnv
+}
+
+// lottieHeader holds the top-level fields of a Lottie document.
+type lottieHeader struct {
+	Width     float64 `json:"w"`
+	Height    float64 `json:"h"`
+	FrameRate float64 `json:"fr"`
+	InPoint   float64 `json:"ip"`
+	OutPoint  float64 `json:"op"`
+}
+
+// NewLottieFile reads the size, frame rate and duration of a Lottie JSON file.
+func NewLottieFile(filename string) (*LottieClip, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("NewLottieFile: %w", err)
+	}
+	var h lottieHeader
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, fmt.Errorf("NewLottieFile: invalid Lottie JSON (file=%s): %w", filename, err)
+	}
+	if h.Width <= 0 || h.Height <= 0 || h.FrameRate <= 0 || h.OutPoint <= h.InPoint {
+		return nil, fmt.Errorf("NewLottieFile: missing size, frame rate or frame range (w=%v, h=%v, fr=%v, ip=%v, op=%v, file=%s)",
+			h.Width, h.Height, h.FrameRate, h.InPoint, h.OutPoint, filename)
+	}
+	return &LottieClip{
+		filename: filename,
+		width:    uint64(h.Width),
+		height:   uint64(h.Height),
+		duration: (h.OutPoint - h.InPoint) / h.FrameRate,
+		fps:      uint64(math.Round(h.FrameRate)),
+	}, nil
+}
+
+// GetWidth returns the rendered width.
+func (lc *LottieClip) GetWidth() uint64 {
+	return lc.width
+}
+
+// GetHeight returns the rendered height.
+func (lc *LottieClip) GetHeight() uint64 {
+	return lc.height
+}
+
+// GetDuration returns the animation duration.
+func (lc *LottieClip) GetDuration() float64 {
+	return lc.duration
+}
+
+// GetFps returns the frame rate the animation is rendered at.
+func (lc *LottieClip) GetFps() uint64 {
+	if lc.fps == 0 {
+		return defaultClipFps
+	}
+	return lc.fps
+}
+
+// GetPosition returns the overlay position.
+// Returns center position if none was explicitly set.
+func (lc *LottieClip) GetPosition() Position {
+	if lc.position.X == "" && lc.position.Y == "" {
+		return CenterPosition()
+	}
+	return lc.position
+}
+
+// GetFilename returns the Lottie JSON filename.
+func (lc *LottieClip) GetFilename() string {
+	return lc.filename
+}
+
+// Size sets the rendered size. Lottie is vector based, so the animation is
+// rendered at this size rather than scaled.
+func (lc *LottieClip) Size(width, height uint64) *LottieClip {
+	lc.width = width
+	lc.height = height
+	return lc
+}
+
+// Fps sets the frame rate the animation is rendered at.
+func (lc *LottieClip) Fps(fps uint64) *LottieClip {
+	lc.fps = fps
+	return lc
+}
+
+// Renderer sets the renderer producing the frames. It is required.
+func (lc *LottieClip) Renderer(r LottieRenderer) *LottieClip {
+	lc.renderer = r
+	return lc
+}
+
+// Env sets the render environment; its TempDir holds the rendered frames.
+func (lc *LottieClip) Env(env *RenderEnv) *LottieClip {
+	lc.env = env
+	return lc
+}
+
+// SetPosition sets the overlay position used by CompositeClip.
+func (lc *LottieClip) SetPosition(position Position) *LottieClip {
+	lc.position = position
+	return lc
+}
+
+// SetAnimatedPosition sets the overlay position animation for CompositeClip.
+func (lc *LottieClip) SetAnimatedPosition(ap AnimatedPosition) *LottieClip {
+	lc.animatedPosition = &ap
+	return lc
+}
+
+// SetAnimatedOpacity sets the overlay opacity animation for CompositeClip.
+func (lc *LottieClip) SetAnimatedOpacity(a Animation) *LottieClip {
+	lc.animatedOpacity = &a
+	return lc
+}
+
+// toVideo renders the animation and loads the frames as a clip.
+func (lc *LottieClip) toVideo() (*Video, error) {
+	if err := lc.Validate(); err != nil {
+		return nil, err
+	}
+	req, err := newClipRequest("lottie", lc.width, lc.height, lc.duration, lc.GetFps(), lc.env)
+	if err != nil {
+		return nil, fmt.Errorf("LottieClip: %w", err)
+	}
+	src, err := lc.renderer.RenderLottie(lc.filename, req)
+	if err != nil {
+		releaseClipTempDir(req.TempDir)
+		return nil, fmt.Errorf("LottieClip: render failed (file=%s): %w", lc.filename, err)
+	}
+	v, err := src.toVideo(req)
+	if err != nil {
+		releaseClipTempDir(req.TempDir)
+		return nil, fmt.Errorf("LottieClip: %w", err)
+	}
+	v.position = lc.position
+	v.animatedPosition = lc.animatedPosition
+	v.animatedOpacity = lc.animatedOpacity
+	return v, nil
+}
@@ -0,0 +1,129 @@
+package moviego
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// LoudnessTarget is the loudness an export is normalized to, see
+// VideoParameters.NormalizeLoudness.
+type LoudnessTarget struct {
+	Integrated float64  // integrated loudness in LUFS, e.g. -23
+	TruePeak   *float64 // maximum true peak in dBTP; nil = -1
+	Range      float64  // target loudness range in LU (default: 11)
+}
+
+var (
+	LoudnessEBUR128 = LoudnessTarget{Integrated: -23, TruePeak: dBTP(-1)}   // European broadcast
+	LoudnessATSC    = LoudnessTarget{Integrated: -24, TruePeak: dBTP(-2)}   // US broadcast (ATSC A/85)
+	LoudnessYouTube = LoudnessTarget{Integrated: -14, TruePeak: dBTP(-1)}   // YouTube, Spotify, most streaming services
+	LoudnessPodcast = LoudnessTarget{Integrated: -16, TruePeak: dBTP(-1.5)} // Apple Podcasts
+)
+
+// dBTP returns a pointer to a true peak level, for LoudnessTarget.TruePeak.
+func dBTP(level float64) *float64 {
+	return &level
+}
+
+func (l LoudnessTarget) withDefaults() LoudnessTarget {
+	if l.TruePeak == nil {
+		l.TruePeak = dBTP(-1)
+	}
+	if l.Range == 0 {
+		l.Range = 11
+	}
+	return l
+}
+
+func (l LoudnessTarget) validate() error {
+	if l.Integrated < -70 || l.Integrated > -5 {
+		return fmt.Errorf("integrated loudness must be -70 to -5 LUFS (got=%.2f)", l.Integrated)
+	}
+	if *l.TruePeak < -9 || *l.TruePeak > 0 {
+		return fmt.Errorf("true peak must be -9 to 0 dBTP (got=%.2f)", *l.TruePeak)
+	}
+	if l.Range < 1 || l.Range > 50 {
+		return fmt.Errorf("loudness range must be 1 to 50 LU (got=%.2f)", l.Range)
+	}
+	return nil
+}
+
+// loudnessMeasurement is the analysis printed by the first loudnorm pass.
+type loudnessMeasurement struct {
+	InputI       string `json:"input_i"`
+	InputTP      string `json:"input_tp"`
+	InputLRA     string `json:"input_lra"`
+	InputThresh  string `json:"input_thresh"`
+	TargetOffset string `json:"target_offset"`
+}
+
+// normalizeLoudness returns a copy of v whose audio is normalized to target
+// with two-pass loudnorm: the first pass measures the mix, the second
+// (applied when v is rendered) corrects it linearly using the measurement,
+// which keeps the dynamics that a single pass would compress. A video
+// without audio is returned unchanged.
+func (v *Video) normalizeLoudness(op string, target LoudnessTarget, env *RenderEnv) (*Video, error) {
+	target = target.withDefaults()
+	if err := target.validate(); err != nil {
+		return nil, fmt.Errorf("%s: loudness: %w (file=%s)", op, err, safeFirstFilename(v.filenames))
+	}
+	if v.audioRemoved || !v.HasAudio() && len(v.audio.filterComplex) == 0 {
+		return v, nil
+	}
+	out := v.clone()
+	initRawVideo(&out)
+
+	params := fmt.Sprintf("loudnorm=I=%s:TP=%s:LRA=%s", formatFloat(target.Integrated), formatFloat(*target.TruePeak), formatFloat(target.Range))
+	probe := out.audio.chain(params + ":print_format=json")
+	m, err := measureLoudness(&probe, env)
+	if err != nil {
+		return nil, fmt.Errorf("%s: loudness: %w (file=%s)", op, err, safeFirstFilename(v.filenames))
+	}
+
+	sampleRate := out.audio.sampleRate
+	if sampleRate == 0 {
+		sampleRate = defaultSampleRate
+	}
+	// loudnorm works (and outputs) at 192 kHz; resample back afterwards.
+	out.audio = out.audio.chain(fmt.Sprintf("%s:measured_I=%s:measured_TP=%s:measured_LRA=%s:measured_thresh=%s:offset=%s:linear=true,aresample=%d",
+		params, m.InputI, m.InputTP, m.InputLRA, m.InputThresh, m.TargetOffset, sampleRate))
+	return &out, nil
+}
+
+// measureLoudness runs the analysis pass of a, whose chain ends with a
+// loudnorm printing JSON, and parses the measurement FFmpeg prints last.
+func measureLoudness(a *Audio, env *RenderEnv) (loudnessMeasurement, error) {
+	var m loudnessMeasurement
+	ffmpegPath, err := getFFmpegPath()
+	if err != nil {
+		return m, fmt.Errorf("failed to get ffmpeg path: %w", err)
+	}
+	args, err := a.graphArgs()
+	if err != nil {
+		return m, err
+	}
+	args = append([]string{"-hide_banner", "-nostats"}, args...)
+	cmd, err := env.command(ffmpegPath, append(args, "-vn", "-f", "null", "-")...)
+	if err != nil {
+		return m, fmt.Errorf("invalid render environment: %w", err)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := runCommand(cmd); err != nil {
+		return m, fmt.Errorf("analysis pass failed: %w\nffmpeg stderr: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	output := stderr.String()
+	start, end := strings.LastIndex(output, "{"), strings.LastIndex(output, "}")
+	if start < 0 || end < start {
+		return m, fmt.Errorf("no loudnorm measurement in ffmpeg output")
+	}
+	if err := json.Unmarshal([]byte(output[start:end+1]), &m); err != nil {
+		return m, fmt.Errorf("failed to parse loudnorm measurement: %w", err)
+	}
+	if strings.Contains(m.InputI, "inf") {
+		return m, fmt.Errorf("audio is silent, loudness cannot be measured")
+	}
+	return m, nil
+}
@@ -0,0 +1,116 @@
+package moviego
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Overlay is an item stacked on top of a video: either a clip (video, image,
+// color) or a text. Overlays are drawn from the lowest Layer to the highest;
+// overlays on the same layer are drawn in the order they were added.
+type Overlay struct {
+	Layer int
+	Clip  Clip
+	Text  *TextClip
+}
+
+// AddOverlay stacks a clip on top of the video on the given layer. The clip
+// is placed with its position and, for videos, its composite start.
+func (v *Video) AddOverlay(clip Clip, layer int) *Video {
+	if clip == nil {
+		v.reject("AddOverlay", "clip is nil (file=%s)", safeFirstFilename(v.filenames))
+		return v
+	}
+	v.overlays = append(v.overlays[:len(v.overlays):len(v.overlays)], Overlay{Layer: layer, Clip: clip})
+	return v
+}
+
+// AddTextOverlay stacks a text on top of the video on the given layer.
+func (v *Video) AddTextOverlay(text TextClip, layer int) *Video {
+	v.overlays = append(v.overlays[:len(v.overlays):len(v.overlays)], Overlay{Layer: layer, Text: &text})
+	return v
+}
+
+// GetOverlays returns the pending overlays in drawing order.
+func (v *Video) GetOverlays() []Overlay {
+	overlays := append([]Overlay(nil), v.overlays...)
+	sort.SliceStable(overlays, func(i, j int) bool {
+		return overlays[i].Layer < overlays[j].Layer
+	})
+	return overlays
+}
+
+// FlattenOverlays returns a video with the pending overlays drawn in. Runs of
+// clip overlays are combined with CompositeClip and texts with AddText, so the
+// stacking order is the same whatever the overlay types are. WriteVideo and
+// the functions combining videos flatten automatically.
+func (v *Video) FlattenOverlays() (*Video, error) {
+	base := *v
+	base.overlays = nil
+	if len(v.overlays) == 0 {
+		return &base, nil
+	}
+	// The overlays are composited over the video on its own timeline; its
+	// placement in an outer composite is restored afterwards.
+	base.compositeStart = 0
+
+	current := &base
+	var batch []Video
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		result, err := CompositeClip(append([]Video{*current}, batch...))
+		if err != nil {
+			return err
+		}
+		current = result
+		batch = nil
+		return nil
+	}
+
+	for i, overlay := range v.GetOverlays() {
+		if overlay.Text != nil {
+			if err := flush(); err != nil {
+				return nil, fmt.Errorf("FlattenOverlays: %w", err)
+			}
+			result, err := current.AddText(*overlay.Text)
+			if err != nil {
+				return nil, fmt.Errorf("FlattenOverlays: overlay %d (layer=%d): %w", i, overlay.Layer, err)
+			}
+			current = result
+			continue
+		}
+		layer, err := overlay.Clip.toVideo()
+		if err != nil {
+			return nil, fmt.Errorf("FlattenOverlays: overlay %d (layer=%d): %w", i, overlay.Layer, err)
+		}
+		if layer, err = layer.FlattenOverlays(); err != nil {
+			return nil, err
+		}
+		batch = append(batch, *layer)
+	}
+	if err := flush(); err != nil {
+		return nil, fmt.Errorf("FlattenOverlays: %w", err)
+	}
+
+	current.position = v.position
+	current.compositeStart = v.compositeStart
+	current.animatedPosition = v.animatedPosition
+	current.animatedOpacity = v.animatedOpacity
+	current.invalid = v.invalid
+	return current, nil
+}
+
+// flattenVideos flattens the pending overlays of every video.
+func flattenVideos(op string, videos []Video) ([]Video, error) {
+	out := make([]Video, len(videos))
+	for i := range videos {
+		flat, err := videos[i].FlattenOverlays()
+		if err != nil {
+			return nil, fmt.Errorf("%s: input %d: %w", op, i, err)
+		}
+		out[i] = *flat
+	}
+	return out, nil
+}
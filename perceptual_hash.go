@@ -0,0 +1,75 @@
+package moviego
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// VideoHash is a perceptual fingerprint of a video: the 64-bit difference
+// hash (dHash) of frames sampled at equal intervals. Re-encoding, resizing
+// and small color changes barely change it, so near-identical uploads have
+// near-identical hashes.
+type VideoHash []uint64
+
+// perceptualHashFrames is the number of frames sampled by PerceptualHash.
+const perceptualHashFrames = 16
+
+// PerceptualHash samples frames evenly over the video and hashes each one,
+// e.g. to deduplicate uploads before rendering (see SimilarityScore).
+func (v *Video) PerceptualHash() (VideoHash, error) {
+	if v.duration <= 0 {
+		return nil, fmt.Errorf("PerceptualHash: invalid duration (duration=%.4f, file=%s, label=%s)", v.duration, safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	// Each frame is shrunk to 9x8 pixels; its hash holds whether each pixel
+	// is brighter than its right neighbour.
+	interval := v.duration / perceptualHashFrames
+	sampled, err := v.videoFilter(fmt.Sprintf("trim=start=%.6f,setpts=PTS-STARTPTS,fps=fps=1/%.6f,scale=9:8:flags=area,format=rgb24",
+		interval/2, interval))
+	if err != nil {
+		return nil, fmt.Errorf("PerceptualHash: %w", err)
+	}
+	data, err := decodeFrames("PerceptualHash", sampled, 9, 8, perceptualHashFrames, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("PerceptualHash: no frames decoded (file=%s, label=%s)", safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	const size = 9 * 8 * 3
+	hash := make(VideoHash, 0, len(data)/size)
+	for frame := data; len(frame) >= size; frame = frame[size:] {
+		var h uint64
+		for y := 0; y < 8; y++ {
+			for x := 0; x < 8; x++ {
+				h <<= 1
+				if hashLuma(frame, y*9+x) > hashLuma(frame, y*9+x+1) {
+					h |= 1
+				}
+			}
+		}
+		hash = append(hash, h)
+	}
+	return hash, nil
+}
+
+// hashLuma returns the Rec. 601 luma of pixel p of a packed RGB24 frame.
+func hashLuma(frame []byte, p int) float64 {
+	return 0.299*float64(frame[3*p]) + 0.587*float64(frame[3*p+1]) + 0.114*float64(frame[3*p+2])
+}
+
+// SimilarityScore compares two hashes from PerceptualHash: 1 for identical
+// videos, around 0.5 for unrelated ones. Scores above 0.9 usually mean the
+// same content. Frames are matched by their relative position in the video.
+func SimilarityScore(a, b VideoHash) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	var matching int
+	for i, h := range a {
+		matching += 64 - bits.OnesCount64(h^b[i*len(b)/len(a)])
+	}
+	return float64(matching) / float64(64*len(a))
+}
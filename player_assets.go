@@ -0,0 +1,214 @@
+package moviego
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PlayerAssetsOptions configures GeneratePlayerAssets. Zero fields use the
+// defaults listed below.
+type PlayerAssetsOptions struct {
+	PosterTime        float64    // poster frame time in seconds (default: 10% of the duration)
+	ThumbnailInterval float64    // seconds between sprite thumbnails (default: 5)
+	ThumbnailWidth    uint64     // thumbnail width in pixels (default: 160)
+	SpriteColumns     uint64     // thumbnails per sprite row (default: 10)
+	Peaks             int        // number of waveform peaks (default: 1000)
+	Env               *RenderEnv // optional render environment for the FFmpeg jobs
+}
+
+func (o PlayerAssetsOptions) withDefaults(duration float64) PlayerAssetsOptions {
+	if o.PosterTime == 0 {
+		o.PosterTime = duration * 0.1
+	}
+	if o.ThumbnailInterval == 0 {
+		o.ThumbnailInterval = 5
+	}
+	if o.ThumbnailWidth == 0 {
+		o.ThumbnailWidth = 160
+	}
+	if o.SpriteColumns == 0 {
+		o.SpriteColumns = 10
+	}
+	if o.Peaks == 0 {
+		o.Peaks = 1000
+	}
+	return o
+}
+
+// PlayerAssets lists the files written by GeneratePlayerAssets. Paths are
+// relative to the output directory; Waveform is empty for silent videos.
+type PlayerAssets struct {
+	Poster     string  `json:"poster"`
+	Sprite     string  `json:"sprite"`
+	Thumbnails string  `json:"thumbnails"`
+	Waveform   string  `json:"waveform,omitempty"`
+	Duration   float64 `json:"duration"`
+	Width      uint64  `json:"width"`
+	Height     uint64  `json:"height"`
+	Fps        uint64  `json:"fps"`
+}
+
+// Waveform is the JSON document written to waveform.json: Peaks holds the
+// absolute peak amplitude (0-1) of consecutive, equally long slices.
+type Waveform struct {
+	Duration float64   `json:"duration"`
+	Peaks    []float64 `json:"peaks"`
+}
+
+const (
+	posterFile     = "poster.jpg"
+	spriteFile     = "sprite.jpg"
+	thumbnailsFile = "thumbnails.vtt"
+	waveformFile   = "waveform.json"
+	metadataFile   = "metadata.json"
+
+	waveformSampleRate = 8000
+)
+
+// GeneratePlayerAssets writes everything a custom web player needs next to
+// a rendered video into dir: a poster frame, a thumbnail sprite with a WebVTT
+// track for seek previews, waveform peaks and a metadata.json describing them.
+// The video is read as edited, so v does not need to be rendered first.
+func GeneratePlayerAssets(v *Video, dir string, opts PlayerAssetsOptions) (*PlayerAssets, error) {
+	if v == nil {
+		return nil, fmt.Errorf("GeneratePlayerAssets: video is nil")
+	}
+	if err := v.Validate(); err != nil {
+		return nil, fmt.Errorf("GeneratePlayerAssets: invalid video: %w", err)
+	}
+	if v.duration <= 0 || v.width == 0 || v.height == 0 {
+		return nil, fmt.Errorf("GeneratePlayerAssets: video has no duration or size (%dx%d, duration=%.4f, file=%s)", v.width, v.height, v.duration, safeFirstFilename(v.filenames))
+	}
+	v, err := v.FlattenOverlays()
+	if err != nil {
+		return nil, fmt.Errorf("GeneratePlayerAssets: %w", err)
+	}
+	opts = opts.withDefaults(v.duration)
+	if opts.PosterTime < 0 || opts.PosterTime >= v.duration || opts.ThumbnailInterval < 0 || opts.Peaks < 0 {
+		return nil, fmt.Errorf("GeneratePlayerAssets: invalid options (poster=%.4f, interval=%.4f, peaks=%d, duration=%.4f)", opts.PosterTime, opts.ThumbnailInterval, opts.Peaks, v.duration)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("GeneratePlayerAssets: failed to create '%s': %w", dir, err)
+	}
+	ffmpegPath, err := getFFmpegPath()
+	if err != nil {
+		return nil, fmt.Errorf("GeneratePlayerAssets: failed to get ffmpeg path: %w", err)
+	}
+
+	assets := &PlayerAssets{
+		Poster:     posterFile,
+		Sprite:     spriteFile,
+		Thumbnails: thumbnailsFile,
+		Duration:   v.duration,
+		Width:      v.width,
+		Height:     v.height,
+		Fps:        v.fps,
+	}
+	if err := writePoster(v, filepath.Join(dir, posterFile), opts); err != nil {
+		return nil, err
+	}
+	if err := writeSprite(ffmpegPath, v, dir, opts); err != nil {
+		return nil, err
+	}
+	if v.HasAudio() && opts.Peaks > 0 {
+		if err := writeWaveform(ffmpegPath, v, filepath.Join(dir, waveformFile), opts); err != nil {
+			return nil, err
+		}
+		assets.Waveform = waveformFile
+	}
+	if err := writeJSON(filepath.Join(dir, metadataFile), assets); err != nil {
+		return nil, fmt.Errorf("GeneratePlayerAssets: %w", err)
+	}
+	return assets, nil
+}
+
+// assetJob runs an FFmpeg job reading v and writing output with outputArgs.
+func assetJob(ffmpegPath string, v *Video, output string, outputArgs []string, env *RenderEnv) error {
+	return sourceJob("GeneratePlayerAssets", ffmpegPath, v, output, outputArgs, env)
+}
+
+func writePoster(v *Video, output string, opts PlayerAssetsOptions) error {
+	return v.renderStill("GeneratePlayerAssets", opts.PosterTime, output, opts.Env)
+}
+
+// writeSprite renders all thumbnails into one tiled image and the WebVTT
+// track pointing at each tile.
+func writeSprite(ffmpegPath string, v *Video, dir string, opts PlayerAssetsOptions) error {
+	count := uint64(math.Ceil(v.duration / opts.ThumbnailInterval))
+	columns := min(opts.SpriteColumns, count)
+	rows := (count + columns - 1) / columns
+	thumbW := uint64(evenDimension(int(opts.ThumbnailWidth)))
+	thumbH := uint64(evenDimension(int(math.Round(float64(thumbW) * float64(v.height) / float64(v.width)))))
+
+	tiled, err := v.videoFilter(fmt.Sprintf("fps=1/%.4f,scale=%d:%d,tile=%dx%d", opts.ThumbnailInterval, thumbW, thumbH, columns, rows))
+	if err != nil {
+		return fmt.Errorf("GeneratePlayerAssets: sprite: %w", err)
+	}
+	if err := assetJob(ffmpegPath, tiled, filepath.Join(dir, spriteFile), []string{"-an", "-frames:v", "1", "-q:v", "3"}, opts.Env); err != nil {
+		return err
+	}
+
+	var vtt strings.Builder
+	vtt.WriteString("WEBVTT\n")
+	for i := uint64(0); i < count; i++ {
+		start := float64(i) * opts.ThumbnailInterval
+		end := math.Min(start+opts.ThumbnailInterval, v.duration)
+		fmt.Fprintf(&vtt, "\n%s --> %s\n%s#xywh=%d,%d,%d,%d\n",
+			Seconds(start), Seconds(end), spriteFile, i%columns*thumbW, i/columns*thumbH, thumbW, thumbH)
+	}
+	if err := os.WriteFile(filepath.Join(dir, thumbnailsFile), []byte(vtt.String()), 0644); err != nil {
+		return fmt.Errorf("GeneratePlayerAssets: failed to write thumbnails: %w", err)
+	}
+	return nil
+}
+
+// writeWaveform decodes the audio as mono 16-bit PCM and reduces it to peaks.
+func writeWaveform(ffmpegPath string, v *Video, output string, opts PlayerAssetsOptions) error {
+	pcm, err := os.CreateTemp(opts.Env.tempDir(), "moviego-waveform-*.pcm")
+	if err != nil {
+		return fmt.Errorf("GeneratePlayerAssets: %w", err)
+	}
+	pcm.Close()
+	defer os.Remove(pcm.Name())
+
+	outputArgs := []string{"-vn", "-ac", "1", "-ar", fmt.Sprint(waveformSampleRate), "-f", "s16le"}
+	if err := assetJob(ffmpegPath, v, pcm.Name(), outputArgs, opts.Env); err != nil {
+		return err
+	}
+	data, err := os.ReadFile(pcm.Name())
+	if err != nil {
+		return fmt.Errorf("GeneratePlayerAssets: failed to read waveform samples: %w", err)
+	}
+
+	samples := len(data) / 2
+	waveform := Waveform{Duration: v.duration, Peaks: make([]float64, 0, opts.Peaks)}
+	for i := 0; i < opts.Peaks && samples > 0; i++ {
+		from, to := i*samples/opts.Peaks, (i+1)*samples/opts.Peaks
+		var peak float64
+		for s := from; s < to; s++ {
+			sample := math.Abs(float64(int16(binary.LittleEndian.Uint16(data[2*s:]))) / 32768)
+			peak = math.Max(peak, sample)
+		}
+		waveform.Peaks = append(waveform.Peaks, math.Round(peak*10000)/10000)
+	}
+	if err := writeJSON(output, waveform); err != nil {
+		return fmt.Errorf("GeneratePlayerAssets: %w", err)
+	}
+	return nil
+}
+
+func writeJSON(path string, value any) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write '%s': %w", path, err)
+	}
+	return nil
+}
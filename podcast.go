@@ -0,0 +1,179 @@
+package moviego
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PodcastChapter is a chapter of a podcast episode; it lasts until the next
+// chapter or the end of the episode.
+type PodcastChapter struct {
+	Start float64 // seconds
+	Title string
+}
+
+// PodcastOptions configures WritePodcast. Zero fields use the defaults
+// listed below or are left out of the tags.
+type PodcastOptions struct {
+	Title       string // episode title
+	Author      string // artist and album artist
+	Show        string // album
+	Episode     int    // track number
+	Date        string // e.g. "2024" or "2024-05-01"
+	Genre       string // default: "Podcast"
+	Description string // comment and description
+
+	CoverArt string           // JPEG or PNG cover image, embedded in the file
+	Chapters []PodcastChapter // ID3 CHAP frames in MP3, chapter track in M4A
+
+	// Loudness is the normalization target (default: LoudnessPodcast).
+	Loudness *LoudnessTarget
+	Bitrate  uint64 // kbit/s (default: 128)
+
+	SilentProgress bool
+	OnProgress     func(Progress)
+	Env            *RenderEnv
+}
+
+// WritePodcast exports the audio of the video as a podcast episode: an MP3
+// (ID3v2.3) or M4A (iTunes tags) file, loudness-normalized, with tags,
+// chapters and cover art. The format follows the extension of outputPath.
+func (v *Video) WritePodcast(outputPath string, opts PodcastOptions) error {
+	file, label := safeFirstFilename(v.filenames), safeLastVideoLabel(v)
+	ext := strings.ToLower(filepath.Ext(outputPath))
+	if ext != ".mp3" && ext != ".m4a" {
+		return fmt.Errorf("WritePodcast: output must be .mp3 or .m4a (got=%s, file=%s, label=%s)", outputPath, file, label)
+	}
+	if v.audioRemoved || !v.HasAudio() && len(v.audio.filterComplex) == 0 {
+		return fmt.Errorf("WritePodcast: video has no audio (file=%s, label=%s)", file, label)
+	}
+	for i, c := range opts.Chapters {
+		if c.Start < 0 || c.Start >= v.duration || i > 0 && c.Start <= opts.Chapters[i-1].Start {
+			return fmt.Errorf("WritePodcast: chapters must start in order within [0, %.4f) (chapter=%d, start=%.4f, file=%s, label=%s)", v.duration, i, c.Start, file, label)
+		}
+	}
+	if opts.CoverArt != "" {
+		if _, err := os.Stat(opts.CoverArt); err != nil {
+			return fmt.Errorf("WritePodcast: cover art: %w (file=%s, label=%s)", err, file, label)
+		}
+	}
+	target := LoudnessPodcast
+	if opts.Loudness != nil {
+		target = *opts.Loudness
+	}
+	if opts.Genre == "" {
+		opts.Genre = "Podcast"
+	}
+	if opts.Bitrate == 0 {
+		opts.Bitrate = 128
+	}
+	ffmpegPath, err := getFFmpegPath()
+	if err != nil {
+		return fmt.Errorf("WritePodcast: failed to get ffmpeg path: %w", err)
+	}
+
+	normalized, err := v.normalizeLoudness("WritePodcast", target, opts.Env)
+	if err != nil {
+		return err
+	}
+	args, err := normalized.audio.graphArgs()
+	if err != nil {
+		return fmt.Errorf("WritePodcast: %w", err)
+	}
+
+	// Source tags and chapters are replaced by the podcast's own.
+	outputArgs := []string{"-map_metadata", "-1"}
+	chapterInput := -1
+	if opts.CoverArt != "" {
+		var cover int
+		args, cover = insertInput(args, "-i", opts.CoverArt)
+		outputArgs = append(outputArgs, "-map", fmt.Sprintf("%d:v:0", cover), "-disposition:v:0", "attached_pic",
+			"-metadata:s:v", "title=Cover", "-metadata:s:v", "comment=Cover (front)")
+		switch strings.ToLower(filepath.Ext(opts.CoverArt)) {
+		case ".jpg", ".jpeg", ".png":
+			outputArgs = append(outputArgs, "-c:v", "copy")
+		default:
+			outputArgs = append(outputArgs, "-c:v", "mjpeg")
+		}
+	}
+	if len(opts.Chapters) > 0 {
+		chapters, err := writeChapterMetadata(opts.Chapters, v.duration, opts.Env)
+		if err != nil {
+			return fmt.Errorf("WritePodcast: chapters: %w", err)
+		}
+		defer os.Remove(chapters)
+		args, chapterInput = insertInput(args, "-f", "ffmetadata", "-i", chapters)
+	}
+	outputArgs = append(outputArgs, "-map_chapters", fmt.Sprint(chapterInput))
+
+	codec := AudioCodecMP3
+	if ext == ".m4a" {
+		codec = AudioCodecAAC
+	}
+	outputArgs = append(outputArgs, "-c:a", string(codec), "-b:a", fmt.Sprintf("%dk", opts.Bitrate))
+	if ext == ".mp3" {
+		outputArgs = append(outputArgs, "-id3v2_version", "3", "-write_id3v1", "1")
+	}
+	for _, tag := range [][2]string{
+		{"title", opts.Title}, {"artist", opts.Author}, {"album_artist", opts.Author},
+		{"album", opts.Show}, {"date", opts.Date}, {"genre", opts.Genre},
+		{"comment", opts.Description}, {"description", opts.Description},
+	} {
+		if tag[1] != "" {
+			outputArgs = append(outputArgs, "-metadata", tag[0]+"="+tag[1])
+		}
+	}
+	if opts.Episode > 0 {
+		outputArgs = append(outputArgs, "-metadata", fmt.Sprintf("track=%d", opts.Episode))
+	}
+
+	job := renderJob{
+		op:         "WritePodcast",
+		args:       args,
+		outputArgs: outputArgs,
+		output:     outputPath,
+		duration:   v.duration,
+		env:        opts.Env,
+		onProgress: opts.OnProgress,
+		silent:     opts.SilentProgress,
+	}
+	return job.run(ffmpegPath)
+}
+
+// WritePodcast renders the timeline and exports its audio as a podcast
+// episode, see Video.WritePodcast.
+func (t *Timeline) WritePodcast(outputPath string, opts PodcastOptions) error {
+	v, err := t.Render()
+	if err != nil {
+		return fmt.Errorf("WritePodcast: %w", err)
+	}
+	return v.WritePodcast(outputPath, opts)
+}
+
+// writeChapterMetadata writes chapters to a temporary FFmpeg metadata file
+// in the environment's temp directory and returns its path.
+func writeChapterMetadata(chapters []PodcastChapter, duration float64, env *RenderEnv) (string, error) {
+	escape := strings.NewReplacer(`\`, `\\`, "=", `\=`, ";", `\;`, "#", `\#`, "\n", "\\\n")
+	var b strings.Builder
+	b.WriteString(";FFMETADATA1\n")
+	for i, c := range chapters {
+		end := duration
+		if i+1 < len(chapters) {
+			end = chapters[i+1].Start
+		}
+		fmt.Fprintf(&b, "[CHAPTER]\nTIMEBASE=1/1000\nSTART=%d\nEND=%d\ntitle=%s\n",
+			int64(c.Start*1000), int64(end*1000), escape.Replace(c.Title))
+	}
+	f, err := os.CreateTemp(env.tempDir(), "moviego-chapters-*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := f.WriteString(b.String()); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
@@ -0,0 +1,128 @@
+package moviego
+
+import (
+	"fmt"
+	"os"
+)
+
+// PodcastClipStyle configures the layout of NewPodcastClip. Zero fields use
+// the defaults listed below.
+type PodcastClipStyle struct {
+	Width      uint64 // default: 1280
+	Height     uint64 // default: 720
+	Fps        uint64 // default: 30
+	Background Color  // default: "black"
+
+	WaveformColor  Color        // default: "white"
+	WaveformMode   WaveformMode // default: WaveformCenteredLine
+	WaveformHeight uint64       // default: a fifth of Height
+
+	TitleFont     string // font family or file (default: FFmpeg's default font)
+	TitleFontSize int    // default: Height / 16
+	TitleColor    Color  // default: "white"
+
+	// Captions is a subtitle file (SRT, ASS, ...) burned in above the
+	// waveform (optional).
+	Captions string
+}
+
+func (s PodcastClipStyle) withDefaults() PodcastClipStyle {
+	if s.Width == 0 {
+		s.Width = 1280
+	}
+	if s.Height == 0 {
+		s.Height = 720
+	}
+	if s.Fps == 0 {
+		s.Fps = defaultClipFps
+	}
+	if s.Background == "" {
+		s.Background = "black"
+	}
+	if s.WaveformHeight == 0 {
+		s.WaveformHeight = s.Height / 5
+	}
+	if s.TitleFontSize == 0 {
+		s.TitleFontSize = int(s.Height / 16)
+	}
+	if s.TitleColor == "" {
+		s.TitleColor = "white"
+	}
+	return s
+}
+
+// NewPodcastClip lays out an audio episode for video platforms: the cover
+// image centered at the top, the title below it, an animated waveform along
+// the bottom and, optionally, burned-in captions above the waveform. The
+// clip lasts as long as audio and plays it.
+func NewPodcastClip(audio *Audio, coverImage, title string, style PodcastClipStyle) (*Video, error) {
+	if audio == nil || audio.duration <= 0 {
+		return nil, fmt.Errorf("NewPodcastClip: audio is empty (cover=%s)", coverImage)
+	}
+	file, label := safeFirstFilename(audio.filenames), audio.safeLabel()
+	if _, err := os.Stat(coverImage); err != nil {
+		return nil, fmt.Errorf("NewPodcastClip: cover image: %w (file=%s, label=%s)", err, file, label)
+	}
+	if style.Captions != "" {
+		if _, err := os.Stat(style.Captions); err != nil {
+			return nil, fmt.Errorf("NewPodcastClip: captions: %w (file=%s, label=%s)", err, file, label)
+		}
+	}
+	style = style.withDefaults()
+	if style.WaveformHeight >= style.Height/2 {
+		return nil, fmt.Errorf("NewPodcastClip: waveform must be shorter than half the height (got=%d, height=%d, file=%s, label=%s)", style.WaveformHeight, style.Height, file, label)
+	}
+	w, h, d := style.Width, style.Height, audio.duration
+
+	// The cover takes half the height, a twelfth of it below the top edge.
+	coverSize, coverY := h/2, h/12
+	cover := NewImageClip(coverImage, coverSize, coverSize, d).Fps(style.Fps).
+		SetPosition(Position{X: "(W-w)/2", Y: fmt.Sprint(coverY)})
+	wave := NewWaveformClip(audio, w, style.WaveformHeight).Fps(style.Fps).WithAudio().
+		Color(style.WaveformColor).Mode(style.WaveformMode).
+		SetPosition(Position{X: "0", Y: "H-h"})
+
+	// Only the waveform carries the audio, so the mix leaves it untouched.
+	layers := make([]Video, 0, 3)
+	for _, clip := range []Clip{NewColorClip(style.Background, w, h, d).Fps(style.Fps), cover} {
+		v, err := clip.toVideo()
+		if err != nil {
+			return nil, fmt.Errorf("NewPodcastClip: %w", err)
+		}
+		if v, err = v.RemoveAudio(); err != nil {
+			return nil, fmt.Errorf("NewPodcastClip: %w", err)
+		}
+		layers = append(layers, *v)
+	}
+	waveVideo, err := wave.toVideo()
+	if err != nil {
+		return nil, fmt.Errorf("NewPodcastClip: %w", err)
+	}
+	out, err := CompositeClip(append(layers, *waveVideo))
+	if err != nil {
+		return nil, fmt.Errorf("NewPodcastClip: %w", err)
+	}
+
+	if title != "" {
+		out, err = out.AddText(TextClip{
+			Text:       title,
+			FontFamily: style.TitleFont,
+			FontSize:   style.TitleFontSize,
+			FontColor:  style.TitleColor,
+			Position:   Position{X: posCenterX, Y: fmt.Sprint(coverY + coverSize + h/24)},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("NewPodcastClip: %w", err)
+		}
+	}
+	if style.Captions != "" {
+		// libass margins are in script units, 288 lines high for SRT.
+		margin := (style.WaveformHeight + h/48) * 288 / h
+		out, err = out.videoFilter(fmt.Sprintf("subtitles=filename=%s:force_style='Alignment=2,MarginV=%d'",
+			filterPath(style.Captions), margin))
+		if err != nil {
+			return nil, fmt.Errorf("NewPodcastClip: %w", err)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,197 @@
+package moviego
+
+import (
+	"fmt"
+	"image"
+	"math"
+)
+
+// PosterFrameOptions configures SuggestPosterFrame. Zero fields use the
+// defaults listed below.
+type PosterFrameOptions struct {
+	Candidates int // frames analyzed, evenly spread over the video (default: 24)
+
+	// FaceScore is an optional face detection hook returning how prominent
+	// faces are in a candidate (0 = none, 1 = a clear face). Frames with
+	// faces are preferred when it is set.
+	FaceScore func(frame image.Image) float64
+
+	Env *RenderEnv
+}
+
+// PosterCandidate is the analysis of one candidate frame. Scores are in [0, 1].
+type PosterCandidate struct {
+	Time               float64
+	Sharpness          float64 // edge energy, relative to the sharpest candidate
+	Brightness         float64 // 1 for well exposed frames, 0 for black or blown-out ones
+	Representativeness float64 // similarity of the colors to the whole video
+	Face               float64 // FaceScore result, 0 without hook
+	Score              float64
+}
+
+// PosterFrame is the frame chosen by SuggestPosterFrame.
+type PosterFrame struct {
+	PosterCandidate
+	Path       string            // the saved image
+	Candidates []PosterCandidate // every candidate, in time order
+}
+
+const (
+	posterAnalysisWidth  = 160
+	posterAnalysisHeight = 90
+	posterHistogramBins  = 8 // per channel
+)
+
+// SuggestPosterFrame picks a good poster frame for automated thumbnails and
+// saves it to outputPath (see RenderStill). Candidates are scored on
+// sharpness (no motion blur), exposure, how representative their colors are
+// of the whole video and, with a FaceScore hook, the presence of faces.
+func (v *Video) SuggestPosterFrame(outputPath string, opts PosterFrameOptions) (*PosterFrame, error) {
+	if opts.Candidates == 0 {
+		opts.Candidates = 24
+	}
+	if opts.Candidates < 0 || v.duration <= 0 {
+		return nil, fmt.Errorf("SuggestPosterFrame: invalid candidates or duration (candidates=%d, duration=%.4f, file=%s, label=%s)", opts.Candidates, v.duration, safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	frames, times, err := v.posterCandidates(opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(frames) == 0 {
+		return nil, fmt.Errorf("SuggestPosterFrame: no frames decoded (file=%s, label=%s)", safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	candidates := scorePosterCandidates(frames, times, opts.FaceScore)
+
+	best := 0
+	for i, c := range candidates {
+		if c.Score > candidates[best].Score {
+			best = i
+		}
+	}
+	if err := v.renderStill("SuggestPosterFrame", candidates[best].Time, outputPath, opts.Env); err != nil {
+		return nil, err
+	}
+	return &PosterFrame{PosterCandidate: candidates[best], Path: outputPath, Candidates: candidates}, nil
+}
+
+// posterCandidates decodes the candidates as small RGB frames in one pass.
+func (v *Video) posterCandidates(opts PosterFrameOptions) ([]*image.RGBA, []float64, error) {
+	// Candidates sit in the middle of equal slices of the video, so the
+	// first (often black) and last frames are never picked.
+	interval := v.duration / float64(opts.Candidates)
+	sampled, err := v.videoFilter(fmt.Sprintf("trim=start=%.6f,setpts=PTS-STARTPTS,fps=fps=1/%.6f,scale=%d:%d,format=rgb24",
+		interval/2, interval, posterAnalysisWidth, posterAnalysisHeight))
+	if err != nil {
+		return nil, nil, fmt.Errorf("SuggestPosterFrame: %w", err)
+	}
+	data, err := decodeFrames("SuggestPosterFrame", sampled, posterAnalysisWidth, posterAnalysisHeight, opts.Candidates, opts.Env)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	size := posterAnalysisWidth * posterAnalysisHeight * 3
+	var frames []*image.RGBA
+	var times []float64
+	for i := 0; (i+1)*size <= len(data); i++ {
+		img := image.NewRGBA(image.Rect(0, 0, posterAnalysisWidth, posterAnalysisHeight))
+		for p := 0; p < posterAnalysisWidth*posterAnalysisHeight; p++ {
+			copy(img.Pix[4*p:4*p+3], data[i*size+3*p:])
+			img.Pix[4*p+3] = 0xff
+		}
+		frames = append(frames, img)
+		times = append(times, math.Min(interval/2+float64(i)*interval, v.duration))
+	}
+	return frames, times, nil
+}
+
+// scorePosterCandidates computes the candidate scores. Sharpness is the
+// variance of the Laplacian of the luma, normalized by the sharpest frame;
+// representativeness compares each color histogram to the average one.
+func scorePosterCandidates(frames []*image.RGBA, times []float64, faceScore func(image.Image) float64) []PosterCandidate {
+	candidates := make([]PosterCandidate, len(frames))
+	histograms := make([][]float64, len(frames))
+	average := make([]float64, posterHistogramBins*posterHistogramBins*posterHistogramBins)
+	var maxSharpness float64
+	for i, img := range frames {
+		luma, histogram := frameStats(img)
+		histograms[i] = histogram
+		for b, h := range histogram {
+			average[b] += h / float64(len(frames))
+		}
+		candidates[i] = PosterCandidate{
+			Time:       times[i],
+			Sharpness:  laplacianVariance(luma, img.Rect.Dx(), img.Rect.Dy()),
+			Brightness: exposureScore(luma),
+		}
+		maxSharpness = math.Max(maxSharpness, candidates[i].Sharpness)
+		if faceScore != nil {
+			candidates[i].Face = math.Max(0, math.Min(1, faceScore(img)))
+		}
+	}
+
+	for i := range candidates {
+		c := &candidates[i]
+		if maxSharpness > 0 {
+			c.Sharpness /= maxSharpness
+		}
+		// Histograms sum to 1, so their L1 distance is within [0, 2].
+		var distance float64
+		for b := range average {
+			distance += math.Abs(histograms[i][b] - average[b])
+		}
+		c.Representativeness = 1 - distance/2
+		c.Score = 0.35*c.Sharpness + 0.35*c.Brightness + 0.3*c.Representativeness
+		if faceScore != nil {
+			c.Score = 0.7*c.Score + 0.3*c.Face
+		}
+	}
+	return candidates
+}
+
+// frameStats returns the luma plane of img (0..1) and its normalized color histogram.
+func frameStats(img *image.RGBA) ([]float64, []float64) {
+	pixels := img.Rect.Dx() * img.Rect.Dy()
+	luma := make([]float64, pixels)
+	histogram := make([]float64, posterHistogramBins*posterHistogramBins*posterHistogramBins)
+	for p := 0; p < pixels; p++ {
+		r, g, b := img.Pix[4*p], img.Pix[4*p+1], img.Pix[4*p+2]
+		luma[p] = (0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)) / 255
+		bin := (int(r)*posterHistogramBins/256)*posterHistogramBins*posterHistogramBins +
+			(int(g)*posterHistogramBins/256)*posterHistogramBins + int(b)*posterHistogramBins/256
+		histogram[bin] += 1 / float64(pixels)
+	}
+	return luma, histogram
+}
+
+func laplacianVariance(luma []float64, width, height int) float64 {
+	var sum, sumSq float64
+	var n int
+	for y := 1; y < height-1; y++ {
+		for x := 1; x < width-1; x++ {
+			i := y*width + x
+			l := luma[i-1] + luma[i+1] + luma[i-width] + luma[i+width] - 4*luma[i]
+			sum += l
+			sumSq += l * l
+			n++
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	mean := sum / float64(n)
+	return sumSq/float64(n) - mean*mean
+}
+
+// exposureScore prefers a mean luma around 0.45 with some contrast.
+func exposureScore(luma []float64) float64 {
+	var sum, sumSq float64
+	for _, l := range luma {
+		sum += l
+		sumSq += l * l
+	}
+	n := float64(len(luma))
+	mean := sum / n
+	contrast := math.Sqrt(math.Max(0, sumSq/n-mean*mean))
+	score := 1 - math.Abs(mean-0.45)/0.45
+	return math.Max(0, score) * math.Min(1, contrast/0.15)
+}
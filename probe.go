@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
-	"os/exec"
 	"strconv"
 	"strings"
 )
@@ -18,8 +17,7 @@ func NewVideoFile(filename string) (*Video, error) {
 	if err != nil {
 		return nil, fmt.Errorf("NewVideoFile: ffprobe not found for '%s': %w", filename, err)
 	}
-	cmd := exec.Command(ffprobePath, "-v", "error", "-show_format", "-show_streams", filename, "-of", "json")
-	output, err := cmd.Output()
+	output, err := commandOutput(ffprobePath, "-v", "error", "-show_format", "-show_streams", filename, "-of", "json")
 	if err != nil {
 		return nil, fmt.Errorf("NewVideoFile: failed to probe video file '%s': %w", filename, err)
 	}
@@ -113,6 +111,8 @@ func NewVideoFile(filename string) (*Video, error) {
 		}
 	}
 
+	video.startTimecode = probeTimecode(result)
+
 	// Validate essential video properties
 	if video.GetWidth() <= 0 || video.GetHeight() <= 0 {
 		return nil, fmt.Errorf("NewVideoFile: video file '%s' has invalid dimensions (%dx%d)", filename, video.GetWidth(), video.GetHeight())
@@ -125,26 +125,27 @@ func NewVideoFile(filename string) (*Video, error) {
 	return video, nil
 }
 
-// AudioFile probes an audio file using ffprobe and returns an Audio with
-// its metadata populated (codec, sample rate, channels, bit rate, duration).
-func AudioFile(filename string) (*Audio, error) {
+// NewAudioFile probes an audio file (mp3, wav, aac, flac, ...) using ffprobe
+// and returns an Audio with its metadata populated (codec, sample rate,
+// channels, bit rate, duration), ready to be mixed into composites, set as a
+// video's audio or written on its own.
+func NewAudioFile(filename string) (*Audio, error) {
 	if filename == "" {
-		return nil, fmt.Errorf("AudioFile: filename cannot be empty")
+		return nil, fmt.Errorf("NewAudioFile: filename cannot be empty")
 	}
 
 	ffprobePath, err := getFFprobePath()
 	if err != nil {
-		return nil, fmt.Errorf("AudioFile: ffprobe not found for '%s': %w", filename, err)
+		return nil, fmt.Errorf("NewAudioFile: ffprobe not found for '%s': %w", filename, err)
 	}
-	cmd := exec.Command(ffprobePath, "-v", "error", "-show_format", "-show_streams", filename, "-of", "json")
-	output, err := cmd.Output()
+	output, err := commandOutput(ffprobePath, "-v", "error", "-show_format", "-show_streams", filename, "-of", "json")
 	if err != nil {
-		return nil, fmt.Errorf("AudioFile: failed to probe audio file '%s': %w", filename, err)
+		return nil, fmt.Errorf("NewAudioFile: failed to probe audio file '%s': %w", filename, err)
 	}
 
 	var result map[string]interface{}
 	if err := json.Unmarshal(output, &result); err != nil {
-		return nil, fmt.Errorf("AudioFile: failed to parse metadata for '%s': %w", filename, err)
+		return nil, fmt.Errorf("NewAudioFile: failed to parse metadata for '%s': %w", filename, err)
 	}
 
 	audio := &Audio{
@@ -187,8 +188,29 @@ func AudioFile(filename string) (*Audio, error) {
 	}
 
 	if audio.GetCodec() == "" {
-		return nil, fmt.Errorf("AudioFile: no audio stream found in '%s'", filename)
+		return nil, fmt.Errorf("NewAudioFile: no audio stream found in '%s'", filename)
+	}
+	// Raw and some containerized streams (wav, flac) only report the
+	// duration of the whole file.
+	if audio.GetDuration() <= 0 {
+		if format, ok := result["format"].(map[string]interface{}); ok {
+			if duration, ok := format["duration"].(string); ok {
+				if dur, err := strconv.ParseFloat(duration, 64); err == nil {
+					audio.Duration(dur)
+				}
+			}
+		}
+	}
+	if audio.GetDuration() <= 0 {
+		return nil, fmt.Errorf("NewAudioFile: audio file '%s' has invalid duration (%.2f)", filename, audio.GetDuration())
 	}
 
 	return audio, nil
 }
+
+// AudioFile probes an audio file, see NewAudioFile.
+//
+// Deprecated: use NewAudioFile.
+func AudioFile(filename string) (*Audio, error) {
+	return NewAudioFile(filename)
+}
@@ -0,0 +1,251 @@
+package moviego
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// SourceKind is the kind of media a ClipProvider produced.
+type SourceKind string
+
+const (
+	SourceVideo         SourceKind = "video"          // a video file
+	SourceImage         SourceKind = "image"          // a still image
+	SourceImageSequence SourceKind = "image-sequence" // numbered frames, e.g. "frame_%05d.png"
+)
+
+// ClipRequest is what a clip is needed for. Providers should produce media
+// of this size, duration and frame rate; zero values leave the choice to
+// the provider.
+type ClipRequest struct {
+	Width    uint64
+	Height   uint64
+	Duration float64
+	Fps      uint64
+	Params   map[string]string // provider-specific options
+	TempDir  string            // directory for generated files (created for the request)
+	Env      *RenderEnv        // render environment; a created TempDir is placed in Env.TempDir
+}
+
+// ClipSource is the media a ClipProvider produced for a request.
+type ClipSource struct {
+	Kind SourceKind
+	Path string // file path, or a printf pattern for SourceImageSequence
+	Fps  uint64 // frame rate of an image sequence (default: the request Fps)
+}
+
+// ClipProvider is a source of clips implemented outside the library, e.g. a
+// stock footage API, a Lottie renderer or a browser capture of HTML. The
+// provider only produces media files; OpenClip turns them into a Clip that
+// is composited, concatenated and placed on timelines like native clips.
+type ClipProvider interface {
+	// Name is the scheme the provider is registered under, e.g. "stock" for
+	// sources such as "stock:ocean-waves".
+	Name() string
+	// Provide fetches or renders source for req.
+	Provide(source string, req ClipRequest) (ClipSource, error)
+}
+
+var (
+	providersMu sync.RWMutex
+	providers   = map[string]ClipProvider{}
+)
+
+// RegisterClipProvider makes p available to OpenClip under p.Name(). It is
+// meant to be called from the init function of the provider's package.
+func RegisterClipProvider(p ClipProvider) error {
+	name := p.Name()
+	if name == "" || strings.ContainsAny(name, ":/") {
+		return fmt.Errorf("RegisterClipProvider: invalid provider name %q", name)
+	}
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	if _, exists := providers[name]; exists {
+		return fmt.Errorf("RegisterClipProvider: provider %q is already registered", name)
+	}
+	providers[name] = p
+	return nil
+}
+
+// ClipProviders returns the names of the registered providers.
+func ClipProviders() []string {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// OpenClip returns the clip for a provider source such as "stock:ocean-waves".
+// Videos are returned as *Video, images as *ImageClip and image sequences are
+// packed into a video keeping their alpha channel.
+//
+// Without req.TempDir, the media is produced in a temporary directory that is
+// removed once a WriteVideo reading it finishes; open the clip again to
+// render it a second time.
+func OpenClip(source string, req ClipRequest) (Clip, error) {
+	name, ref, ok := strings.Cut(source, ":")
+	if !ok {
+		return nil, fmt.Errorf("OpenClip: source must be <provider>:<reference> (got=%q)", source)
+	}
+	providersMu.RLock()
+	p, exists := providers[name]
+	providersMu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("OpenClip: unknown provider %q (registered=%v)", name, ClipProviders())
+	}
+
+	release := func() {}
+	if req.TempDir == "" {
+		dir, err := newClipTempDir(name, req.Env)
+		if err != nil {
+			return nil, fmt.Errorf("OpenClip: %w", err)
+		}
+		req.TempDir = dir
+		release = func() { releaseClipTempDir(dir) }
+	} else if err := os.MkdirAll(req.TempDir, 0755); err != nil {
+		return nil, fmt.Errorf("OpenClip: failed to create '%s': %w", req.TempDir, err)
+	}
+
+	src, err := p.Provide(ref, req)
+	if err != nil {
+		release()
+		return nil, fmt.Errorf("OpenClip: provider %q failed for %q: %w", name, ref, err)
+	}
+	clip, err := src.toClip(req)
+	if err != nil {
+		release()
+		return nil, fmt.Errorf("OpenClip: provider %q: %w", name, err)
+	}
+	return clip, nil
+}
+
+// toClip loads the produced media as a clip.
+func (src ClipSource) toClip(req ClipRequest) (Clip, error) {
+	if src.Path == "" {
+		return nil, fmt.Errorf("clip source has no path")
+	}
+	switch src.Kind {
+	case SourceVideo:
+		return NewVideoFile(src.Path)
+	case SourceImage:
+		if req.Width == 0 || req.Height == 0 || req.Duration <= 0 {
+			return nil, fmt.Errorf("an image source needs the request size and duration (%dx%d, duration=%.4f)", req.Width, req.Height, req.Duration)
+		}
+		return NewImageClip(src.Path, req.Width, req.Height, req.Duration).Fps(req.Fps), nil
+	case SourceImageSequence:
+		fps := src.Fps
+		if fps == 0 {
+			fps = req.Fps
+		}
+		if fps == 0 {
+			fps = defaultClipFps
+		}
+		path, err := packImageSequence(src.Path, fps, req.TempDir, req.Env)
+		if err != nil {
+			return nil, err
+		}
+		return NewVideoFile(path)
+	default:
+		return nil, fmt.Errorf("unknown clip source kind %q", src.Kind)
+	}
+}
+
+// toVideo loads the produced media as a lazy Video, for clip types
+// rendering their content through a provider-style renderer.
+func (src ClipSource) toVideo(req ClipRequest) (*Video, error) {
+	clip, err := src.toClip(req)
+	if err != nil {
+		return nil, err
+	}
+	return clip.toVideo()
+}
+
+// newClipRequest returns a request with a fresh temporary directory, see
+// newClipTempDir.
+func newClipRequest(kind string, width, height uint64, duration float64, fps uint64, env *RenderEnv) (ClipRequest, error) {
+	dir, err := newClipTempDir(kind, env)
+	if err != nil {
+		return ClipRequest{}, err
+	}
+	return ClipRequest{Width: width, Height: height, Duration: duration, Fps: fps, TempDir: dir, Env: env}, nil
+}
+
+// clipTempDirs holds the temporary directories of rendered clips until a
+// render reading from them finishes.
+var (
+	clipTempDirsMu sync.Mutex
+	clipTempDirs   = map[string]struct{}{}
+)
+
+// newClipTempDir creates a temporary directory for the media of a rendered
+// clip in env's TempDir. It is removed by releaseClipTempDirs once the
+// render using the clip finishes.
+func newClipTempDir(kind string, env *RenderEnv) (string, error) {
+	dir, err := os.MkdirTemp(env.tempDir(), "moviego-"+kind+"-*")
+	if err != nil {
+		return "", err
+	}
+	clipTempDirsMu.Lock()
+	clipTempDirs[dir] = struct{}{}
+	clipTempDirsMu.Unlock()
+	return dir, nil
+}
+
+// releaseClipTempDir removes dir, e.g. when the clip failed to render.
+func releaseClipTempDir(dir string) {
+	clipTempDirsMu.Lock()
+	delete(clipTempDirs, dir)
+	clipTempDirsMu.Unlock()
+	os.RemoveAll(dir)
+}
+
+// releaseClipTempDirs removes the clip directories read by the FFmpeg
+// arguments args.
+func releaseClipTempDirs(args []string) {
+	clipTempDirsMu.Lock()
+	defer clipTempDirsMu.Unlock()
+	for dir := range clipTempDirs {
+		prefix := dir + string(filepath.Separator)
+		for _, arg := range args {
+			if strings.Contains(arg, prefix) || strings.Contains(arg, filepath.ToSlash(prefix)) {
+				delete(clipTempDirs, dir)
+				os.RemoveAll(dir)
+				break
+			}
+		}
+	}
+}
+
+// packImageSequence encodes numbered frames into a lossless video with
+// alpha and a silent audio track, so it can be used like any video file.
+func packImageSequence(pattern string, fps uint64, dir string, env *RenderEnv) (string, error) {
+	ffmpegPath, err := getFFmpegPath()
+	if err != nil {
+		return "", fmt.Errorf("failed to get ffmpeg path: %w", err)
+	}
+	output := filepath.Join(dir, "sequence.mov")
+	job := renderJob{
+		op: "OpenClip",
+		args: []string{
+			"-framerate", fmt.Sprint(fps), "-i", pattern,
+			"-f", "lavfi", "-i", "anullsrc=r=48000:cl=stereo",
+			"-map", "0:v", "-map", "1:a", "-shortest",
+			"-c:v", "png", "-pix_fmt", "rgba", "-c:a", "pcm_s16le",
+		},
+		output: output,
+		env:    env,
+		silent: true,
+	}
+	if err := job.run(ffmpegPath); err != nil {
+		return "", err
+	}
+	return output, nil
+}
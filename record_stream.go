@@ -0,0 +1,208 @@
+package moviego
+
+import (
+	"bufio"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// RecordOptions configures RecordStream. Zero fields use the defaults listed
+// below.
+type RecordOptions struct {
+	OutputPath string  // file receiving the recording, e.g. "cam1.mp4" (required)
+	Duration   float64 // seconds to record; 0 records until stop is closed or the stream ends
+
+	// SegmentDuration rotates the recording into files of about this many
+	// seconds, named <name>_000.<ext>, <name>_001.<ext>, ... Segments are
+	// cut at keyframes. 0 writes a single file.
+	SegmentDuration float64
+
+	MaxReconnects  int           // reconnections after the stream drops (default: 5, -1 disables)
+	ReconnectDelay time.Duration // wait before reconnecting (default: 2s)
+	Timeout        time.Duration // a stream sending nothing for this long has dropped (default: 10s)
+
+	// OnSegment is called with the path of each finished file.
+	OnSegment  func(path string)
+	OnProgress func(Progress)
+	Env        *RenderEnv
+}
+
+func (o RecordOptions) withDefaults() RecordOptions {
+	if o.MaxReconnects == 0 {
+		o.MaxReconnects = 5
+	}
+	if o.ReconnectDelay == 0 {
+		o.ReconnectDelay = 2 * time.Second
+	}
+	if o.Timeout == 0 {
+		o.Timeout = 10 * time.Second
+	}
+	return o
+}
+
+// Recording is the result of RecordStream.
+type Recording struct {
+	Files      []string // recorded files in order; open them with NewVideoFile
+	Duration   float64  // seconds recorded
+	Reconnects int
+}
+
+// RecordStream records a network stream (RTSP, RTMP, SRT, HLS or any URL
+// FFmpeg reads) without re-encoding, until opts.Duration is reached, stop is
+// closed or the stream ends. A dropped stream is reconnected up to
+// opts.MaxReconnects times; the recording then continues in a new file
+// (the next segment, or <name>_001.<ext> without segments), so the files are
+// always playable and can be joined with ConcatenateClips.
+func RecordStream(url string, opts RecordOptions, stop <-chan struct{}) (*Recording, error) {
+	opts = opts.withDefaults()
+	if url == "" || opts.OutputPath == "" {
+		return nil, fmt.Errorf("RecordStream: url and OutputPath are required (url=%s, output=%s)", url, opts.OutputPath)
+	}
+	if opts.Duration < 0 || opts.SegmentDuration < 0 || opts.MaxReconnects < -1 {
+		return nil, fmt.Errorf("RecordStream: invalid options (duration=%.4f, segment=%.4f, reconnects=%d, url=%s)", opts.Duration, opts.SegmentDuration, opts.MaxReconnects, url)
+	}
+	ffmpegPath, err := getFFmpegPath()
+	if err != nil {
+		return nil, fmt.Errorf("RecordStream: failed to get ffmpeg path: %w", err)
+	}
+	if dir := filepath.Dir(opts.OutputPath); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("RecordStream: failed to create '%s': %w", dir, err)
+		}
+	}
+
+	// "q" on stdin stops FFmpeg cleanly, finalizing the file.
+	stdin, stdinWriter, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("RecordStream: %w", err)
+	}
+	defer stdin.Close()
+	defer stdinWriter.Close()
+	stopped, done := make(chan struct{}), make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-stop:
+			stdinWriter.Write([]byte("q"))
+			close(stopped)
+		case <-done:
+		}
+	}()
+
+	rec := &Recording{}
+	for attempt := 0; ; attempt++ {
+		remaining := 0.0
+		if opts.Duration > 0 {
+			if remaining = opts.Duration - rec.Duration; remaining <= 0.5 {
+				return rec, nil
+			}
+		}
+		files, recorded, err := recordAttempt(ffmpegPath, url, opts, stdin, len(rec.Files), remaining)
+		rec.Files = append(rec.Files, files...)
+		rec.Duration += recorded
+		for _, f := range files {
+			if opts.OnSegment != nil {
+				opts.OnSegment(f)
+			}
+		}
+		select {
+		case <-stopped:
+			return rec, nil
+		default:
+		}
+		if err == nil {
+			return rec, nil
+		}
+		if opts.MaxReconnects < 0 || rec.Reconnects >= opts.MaxReconnects {
+			return rec, fmt.Errorf("RecordStream: stream dropped after %d reconnects: %w", rec.Reconnects, err)
+		}
+		rec.Reconnects++
+		slog.Warn("RecordStream: stream dropped, reconnecting", "url", url, "attempt", rec.Reconnects, "error", err)
+		select {
+		case <-time.After(opts.ReconnectDelay):
+		case <-stopped:
+			return rec, nil
+		}
+	}
+}
+
+// recordAttempt records from one connection, numbering the files from
+// first, and returns the files written and the seconds recorded.
+func recordAttempt(ffmpegPath, url string, opts RecordOptions, stdin *os.File, first int, duration float64) ([]string, float64, error) {
+	args := append([]string{"-hide_banner"}, streamInputArgs(url, opts.Timeout)...)
+	args = append(args, "-i", url, "-map", "0:v?", "-map", "0:a?", "-c", "copy")
+	if duration > 0 {
+		args = append(args, "-t", formatFloat(duration))
+	}
+
+	ext := filepath.Ext(opts.OutputPath)
+	base := strings.TrimSuffix(opts.OutputPath, ext)
+	output := opts.OutputPath
+	var list string
+	var outputArgs []string
+	if opts.SegmentDuration > 0 {
+		list = fmt.Sprintf("%s_list_%d.txt", base, first)
+		defer os.Remove(list)
+		output = base + "_%03d" + ext
+		outputArgs = []string{"-f", "segment", "-segment_time", formatFloat(opts.SegmentDuration),
+			"-segment_start_number", fmt.Sprint(first), "-reset_timestamps", "1",
+			"-segment_list", list, "-segment_list_type", "flat"}
+	} else if first > 0 {
+		output = fmt.Sprintf("%s_%03d%s", base, first, ext)
+	}
+
+	var recorded float64
+	job := renderJob{
+		op:         "RecordStream",
+		args:       args,
+		outputArgs: outputArgs,
+		output:     output,
+		duration:   duration,
+		env:        opts.Env,
+		onProgress: func(p Progress) {
+			recorded = p.OutTime
+			if opts.OnProgress != nil {
+				opts.OnProgress(p)
+			}
+		},
+		stdin: stdin,
+	}
+	err := job.run(ffmpegPath)
+
+	var files []string
+	if list == "" {
+		if info, statErr := os.Stat(output); statErr == nil && info.Size() > 0 {
+			files = append(files, output)
+		}
+		return files, recorded, err
+	}
+	if f, listErr := os.Open(list); listErr == nil {
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			if name := strings.TrimSpace(scanner.Text()); name != "" {
+				files = append(files, filepath.Join(filepath.Dir(opts.OutputPath), filepath.Base(name)))
+			}
+		}
+		f.Close()
+	}
+	return files, recorded, err
+}
+
+// streamInputArgs returns the input options reading url over the network,
+// with timeout as the longest wait for data.
+func streamInputArgs(url string, timeout time.Duration) []string {
+	us := fmt.Sprint(timeout.Microseconds())
+	switch {
+	case strings.HasPrefix(url, "rtsp://"), strings.HasPrefix(url, "rtsps://"):
+		return []string{"-rtsp_transport", "tcp", "-timeout", us}
+	case strings.HasPrefix(url, "http://"), strings.HasPrefix(url, "https://"):
+		// Short HTTP drops (HLS playlists and segments) are retried in place.
+		return []string{"-reconnect", "1", "-reconnect_streamed", "1", "-reconnect_delay_max", "5", "-rw_timeout", us}
+	default:
+		return []string{"-rw_timeout", us}
+	}
+}
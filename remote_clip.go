@@ -0,0 +1,76 @@
+package moviego
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// RemoteClipOptions configures ClipRemote. Zero fields use the defaults
+// listed below.
+type RemoteClipOptions struct {
+	// Accurate re-encodes the clip so it starts exactly at start. By default
+	// the streams are copied and the clip starts at the keyframe before
+	// start, usually the start of an HLS/DASH segment.
+	Accurate bool
+	// Timeout is the longest wait for data from the server (default: 10s).
+	Timeout    time.Duration
+	OnProgress func(Progress)
+	Env        *RenderEnv
+}
+
+// ClipRemote downloads the start to end seconds of an HLS (.m3u8) or DASH
+// (.mpd) stream into outputPath and opens it for editing. Only the segments
+// covering the range are fetched, so a minute of a three-hour VOD costs a
+// minute of download. Master playlists use their best video and audio
+// renditions.
+//
+// url must be a manifest URL; for pages such as YouTube, resolve it first,
+// e.g. with "yt-dlp -g".
+func ClipRemote(url string, start, end float64, outputPath string, opts RemoteClipOptions) (*Video, error) {
+	if url == "" || outputPath == "" {
+		return nil, fmt.Errorf("ClipRemote: url and outputPath are required (url=%s, output=%s)", url, outputPath)
+	}
+	if start < 0 || end <= start {
+		return nil, fmt.Errorf("ClipRemote: invalid time range (start=%.4f, end=%.4f, url=%s)", start, end, url)
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	ffmpegPath, err := getFFmpegPath()
+	if err != nil {
+		return nil, fmt.Errorf("ClipRemote: failed to get ffmpeg path: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return nil, fmt.Errorf("ClipRemote: failed to create '%s': %w", filepath.Dir(outputPath), err)
+	}
+
+	// Seeking before -i makes the HLS and DASH demuxers start at the segment
+	// holding start instead of downloading the stream from its beginning.
+	args := append([]string{"-hide_banner"}, streamInputArgs(url, opts.Timeout)...)
+	args = append(args, "-ss", formatFloat(start), "-i", url, "-t", formatFloat(end-start), "-sn", "-dn")
+	if opts.Accurate {
+		args = append(args, "-c:v", resolveVideoEncoder(CodecH264, ""), "-pix_fmt", string(PixelFormatYUV420P))
+		audioArgs, _, _ := audioEncoderArgs(VideoParameters{}, outputPath)
+		args = append(args, audioArgs...)
+	} else {
+		args = append(args, "-c", "copy")
+	}
+	job := renderJob{
+		op:         "ClipRemote",
+		args:       args,
+		output:     outputPath,
+		duration:   end - start,
+		env:        opts.Env,
+		onProgress: opts.OnProgress,
+	}
+	if err := job.run(ffmpegPath); err != nil {
+		return nil, err
+	}
+	clip, err := NewVideoFile(outputPath)
+	if err != nil {
+		return nil, fmt.Errorf("ClipRemote: %w", err)
+	}
+	return clip, nil
+}
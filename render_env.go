@@ -0,0 +1,136 @@
+package moviego
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// IOPriority is an I/O scheduling class for ionice.
+type IOPriority uint8
+
+const (
+	IOPriorityDefault    IOPriority = 0 // inherit the I/O priority of the caller
+	IOPriorityBestEffort IOPriority = 2
+	IOPriorityIdle       IOPriority = 3 // only get disk time when no other process needs it
+)
+
+// RenderEnv scopes the resources of a single FFmpeg invocation so renders
+// can run next to latency-sensitive services without starving them.
+// The zero value runs FFmpeg unchanged.
+//
+// Nice uses nice(1) on Unix-like systems; IOPriority and CPUs use ionice(1)
+// and taskset(1), which are Linux only.
+type RenderEnv struct {
+	Nice       int        // CPU priority adjustment, 1-19 lowers priority (0 = unchanged)
+	IOPriority IOPriority // I/O scheduling class
+	IOLevel    uint8      // level within the best-effort class, 0 (highest) to 7 (lowest)
+	CPUs       []int      // CPU cores FFmpeg may run on (empty = all)
+	MaxThreads uint16     // upper bound for -threads (0 = no limit)
+	TempDir    string     // TMPDIR for FFmpeg, created if missing
+	Env        []string   // extra "KEY=VALUE" entries added to the environment
+}
+
+// validate checks the environment against the current platform.
+func (e *RenderEnv) validate() error {
+	if e.Nice < -20 || e.Nice > 19 {
+		return fmt.Errorf("Nice must be between -20 and 19 (got=%d)", e.Nice)
+	}
+	if e.IOPriority != IOPriorityDefault && e.IOPriority != IOPriorityBestEffort && e.IOPriority != IOPriorityIdle {
+		return fmt.Errorf("unknown IOPriority %d", e.IOPriority)
+	}
+	if e.IOLevel > 7 {
+		return fmt.Errorf("IOLevel must be between 0 and 7 (got=%d)", e.IOLevel)
+	}
+	for _, cpu := range e.CPUs {
+		if cpu < 0 {
+			return fmt.Errorf("CPU index must be >= 0 (got=%d)", cpu)
+		}
+	}
+	if (e.IOPriority != IOPriorityDefault || len(e.CPUs) > 0) && runtime.GOOS != "linux" {
+		return fmt.Errorf("IOPriority and CPUs are only supported on linux (os=%s)", runtime.GOOS)
+	}
+	if e.Nice != 0 && runtime.GOOS == "windows" {
+		return fmt.Errorf("Nice is not supported on windows")
+	}
+	for _, kv := range e.Env {
+		if !strings.Contains(kv, "=") {
+			return fmt.Errorf("Env entry must be KEY=VALUE (got=%q)", kv)
+		}
+	}
+	return nil
+}
+
+// threads caps n to MaxThreads.
+func (e *RenderEnv) threads(n uint16) uint16 {
+	if e != nil && e.MaxThreads > 0 && n > e.MaxThreads {
+		return e.MaxThreads
+	}
+	return n
+}
+
+// tempDir returns TempDir, or "" for the system default. The directory is
+// created if missing.
+func (e *RenderEnv) tempDir() string {
+	if e == nil || e.TempDir == "" {
+		return ""
+	}
+	_ = os.MkdirAll(e.TempDir, 0755)
+	return e.TempDir
+}
+
+// command builds the Command running program inside the environment.
+// A nil environment returns a plain Command.
+func (e *RenderEnv) command(program string, args ...string) (*Command, error) {
+	if e == nil {
+		return &Command{Path: program, Args: args}, nil
+	}
+	if err := e.validate(); err != nil {
+		return nil, err
+	}
+
+	// Wrappers are applied outside-in: taskset, ionice, nice, program.
+	var argv []string
+	if len(e.CPUs) > 0 {
+		cpus := make([]string, len(e.CPUs))
+		for i, cpu := range e.CPUs {
+			cpus[i] = strconv.Itoa(cpu)
+		}
+		argv = append(argv, "taskset", "-c", strings.Join(cpus, ","))
+	}
+	if e.IOPriority != IOPriorityDefault {
+		argv = append(argv, "ionice", "-c", strconv.Itoa(int(e.IOPriority)))
+		if e.IOPriority == IOPriorityBestEffort {
+			argv = append(argv, "-n", strconv.Itoa(int(e.IOLevel)))
+		}
+	}
+	if e.Nice != 0 {
+		argv = append(argv, "nice", "-n", strconv.Itoa(e.Nice))
+	}
+	argv = append(argv, program)
+	argv = append(argv, args...)
+
+	name := argv[0]
+	if name != program {
+		path, err := lookPath(name)
+		if err != nil {
+			return nil, fmt.Errorf("%s not found: %w", name, err)
+		}
+		name = path
+	}
+	cmd := &Command{Path: name, Args: argv[1:]}
+
+	if e.TempDir != "" || len(e.Env) > 0 {
+		cmd.Env = os.Environ()
+		if e.TempDir != "" {
+			if err := os.MkdirAll(e.TempDir, 0755); err != nil {
+				return nil, fmt.Errorf("failed to create temp dir '%s': %w", e.TempDir, err)
+			}
+			cmd.Env = append(cmd.Env, "TMPDIR="+e.TempDir, "TMP="+e.TempDir, "TEMP="+e.TempDir)
+		}
+		cmd.Env = append(cmd.Env, e.Env...)
+	}
+	return cmd, nil
+}
@@ -0,0 +1,204 @@
+package moviego
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// renderJob describes one FFmpeg invocation. Writers only plan the inputs,
+// graph and encoder arguments; running the job (resource isolation, command
+// display, progress reporting and error handling) is shared so every output
+// behaves the same way.
+type renderJob struct {
+	op         string   // operation name used in messages, e.g. "WriteVideo"
+	args       []string // inputs, filter graph, maps and encoder settings
+	outputArgs []string // options placed right before the output path
+	output     string
+	duration   float64 // expected output duration, used for progress percentages
+	env        *RenderEnv
+	onProgress func(Progress)
+	onLevels   func(Levels) // set when the graph meters its audio with meterFilter
+	silent     bool
+	stdin      io.Reader // FFmpeg stops cleanly when it reads "q"
+
+	meter *levelsWriter
+}
+
+// commandArgs returns the complete FFmpeg argument list of the job.
+func (j *renderJob) commandArgs() []string {
+	args := append([]string{}, j.args...)
+	if j.progressEnabled() {
+		args = append(args, "-progress", "pipe:1", "-nostats")
+	}
+	args = append(args, j.outputArgs...)
+	return append(args, "-y", j.output)
+}
+
+func (j *renderJob) progressEnabled() bool {
+	return j.onProgress != nil || !j.silent
+}
+
+// run executes the job with the given ffmpeg executable.
+func (j *renderJob) run(ffmpegPath string) error {
+	args := j.commandArgs()
+	cmd, err := j.env.command(ffmpegPath, args...)
+	if err != nil {
+		return fmt.Errorf("%s: invalid render environment: %w", j.op, err)
+	}
+	cmd.Stdin = j.stdin
+	var stderrBuf bytes.Buffer
+	cmd.Stderr = &stderrBuf
+	if j.onLevels != nil {
+		j.meter = &levelsWriter{onLevels: j.onLevels, rest: &stderrBuf}
+		cmd.Stderr = j.meter
+	}
+
+	displayProgram := filepath.Base(ffmpegPath)
+	displayProgram = strings.TrimSuffix(displayProgram, filepath.Ext(displayProgram))
+	display := formatCmd(append([]string{displayProgram}, args...))
+
+	// Silent jobs are internal passes (analysis, frame reads, staging) and
+	// SilentProgress renders, so they only trace their command at debug level.
+	if j.silent {
+		slog.Debug("Running ffmpeg", "op", j.op, "output", j.output, "command", display)
+	} else {
+		fmt.Printf("Writing to %s\n", j.output)
+		fmt.Println(display)
+	}
+
+	if j.progressEnabled() {
+		handler := j.onProgress
+		if handler == nil {
+			handler = defaultProgressHandler(j.output)
+		}
+		if err := j.runWithProgress(cmd, &stderrBuf, handler); err != nil {
+			return err
+		}
+		slog.Info("Export completed", "path", j.output)
+		return nil
+	}
+
+	if err := runCommand(cmd); err != nil {
+		return j.execError(err, &stderrBuf)
+	}
+
+	slog.Info("Export completed", "path", j.output)
+	return nil
+}
+
+func (j *renderJob) execError(err error, stderrBuf *bytes.Buffer) error {
+	stderr := strings.TrimSpace(stderrBuf.String())
+	if stderr != "" {
+		return fmt.Errorf("%s: failed to execute ffmpeg: %w\nffmpeg stderr: %s", j.op, err, stderr)
+	}
+	return fmt.Errorf("%s: failed to execute ffmpeg: %w", j.op, err)
+}
+
+func (j *renderJob) runWithProgress(cmd *Command, stderrBuf *bytes.Buffer, onProgress func(Progress)) error {
+	stdoutPipe, stdoutWriter := io.Pipe()
+	cmd.Stdout = stdoutWriter
+	done := make(chan error, 1)
+	go func() {
+		err := runCommand(cmd)
+		stdoutWriter.Close()
+		done <- err
+	}()
+
+	startTime := time.Now()
+	totalDuration := j.duration
+	scanner := bufio.NewScanner(stdoutPipe)
+	cur := Progress{TotalDuration: totalDuration}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "frame":
+			cur.Frame, _ = strconv.ParseInt(value, 10, 64)
+		case "fps":
+			cur.FPS, _ = strconv.ParseFloat(value, 64)
+		case "bitrate":
+			cur.Bitrate = value
+		case "out_time_us":
+			us, _ := strconv.ParseInt(value, 10, 64)
+			cur.OutTime = float64(us) / 1_000_000
+			if totalDuration > 0 {
+				cur.Percentage = math.Min((cur.OutTime/totalDuration)*100, 100)
+			}
+		case "speed":
+			s := strings.TrimSuffix(strings.TrimSpace(value), "x")
+			cur.Speed, _ = strconv.ParseFloat(s, 64)
+		case "progress":
+			cur.Done = value == "end"
+			if cur.Done {
+				cur.Percentage = 100
+			}
+			cur.ElapsedSeconds = time.Since(startTime).Seconds()
+			if cur.Percentage > 0 && cur.Percentage < 100 {
+				cur.ExpectedTotalSeconds = cur.ElapsedSeconds / (cur.Percentage / 100)
+			} else if cur.Done {
+				cur.ExpectedTotalSeconds = cur.ElapsedSeconds
+			}
+			if j.meter != nil {
+				cur.Levels = j.meter.latest()
+			}
+			onProgress(cur)
+		}
+	}
+	io.Copy(io.Discard, stdoutPipe)
+
+	if err := <-done; err != nil {
+		return j.execError(err, stderrBuf)
+	}
+
+	return nil
+}
+
+// sourceArgs returns the input, graph and map arguments reading v, for jobs
+// that add their own output options (stills, thumbnails, analysis). v is
+// not modified.
+func (v *Video) sourceArgs() ([]string, error) {
+	if len(v.overlays) > 0 {
+		flat, err := v.FlattenOverlays()
+		if err != nil {
+			return nil, err
+		}
+		return flat.sourceArgs()
+	}
+	if start, end, ok := v.directSource(); ok {
+		return directArgs(v.filenames[0], start, end), nil
+	}
+	c := v.clone()
+	return c.graphArgs(VideoParameters{})
+}
+
+// sourceJob runs a silent FFmpeg job reading v (see sourceArgs) and writing
+// output with outputArgs.
+func sourceJob(op, ffmpegPath string, v *Video, output string, outputArgs []string, env *RenderEnv) error {
+	args, err := v.sourceArgs()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	job := renderJob{
+		op:         op,
+		args:       args,
+		outputArgs: outputArgs,
+		output:     output,
+		duration:   v.duration,
+		env:        env,
+		silent:     true,
+	}
+	return job.run(ffmpegPath)
+}
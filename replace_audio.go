@@ -0,0 +1,64 @@
+package moviego
+
+import "fmt"
+
+// ReplaceAudio returns a copy of the video with audio as its sound track, in
+// place of the original audio stream. The track is fitted to the video: a
+// shorter track is padded with silence, a longer one is truncated.
+func (v *Video) ReplaceAudio(audio *Audio) (*Video, error) {
+	if audio == nil || len(audio.filenames) == 0 {
+		return nil, fmt.Errorf("ReplaceAudio: no audio provided (file=%s, label=%s)", safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	if v.duration <= 0 {
+		return nil, fmt.Errorf("ReplaceAudio: video has no duration (got=%s, file=%s, label=%s)", formatFloat(v.duration), safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+
+	fitted, err := audio.audioFilter(fmt.Sprintf("apad,atrim=duration=%s,asetpts=PTS-STARTPTS", formatFloat(v.duration)))
+	if err != nil {
+		return nil, fmt.Errorf("ReplaceAudio: %w (file=%s, label=%s)", err, safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	fitted.duration = v.duration
+
+	out := v.clone()
+	initRawVideo(&out)
+	out.audio = *fitted
+	out.audioRemoved = false
+	return &out, nil
+}
+
+// RemoveAudio returns a copy of the video without sound. WriteVideo writes
+// no audio stream for it; joined with clips that have audio (Concatenate,
+// transitions, timelines) it plays silence, and CompositeClip and the stacks
+// leave it out of their mix. ReplaceAudio gives it a sound track again.
+func (v *Video) RemoveAudio() (*Video, error) {
+	out := v.clone()
+	initRawVideo(&out)
+	out.audio = silentAudio(incrementOrderCounter(), fmt.Sprintf("mute_%d", incrementGlobalCounter()), out.duration)
+	out.audioRemoved = true
+	return &out, nil
+}
+
+// allAudioRemoved reports whether every video had its audio removed.
+func allAudioRemoved(videos []Video) bool {
+	for _, v := range videos {
+		if !v.audioRemoved {
+			return false
+		}
+	}
+	return true
+}
+
+// mixesAudio reports whether the audio of v is part of a mix of videos:
+// removed audio is left out unless every video's audio was removed.
+func mixesAudio(videos []Video, v Video) bool {
+	return !v.audioRemoved || allAudioRemoved(videos)
+}
+
+// SetAudioFile is ReplaceAudio with the audio loaded from path.
+func (v *Video) SetAudioFile(path string) (*Video, error) {
+	audio, err := NewAudioFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("SetAudioFile: %w (file=%s, label=%s)", err, safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	return v.ReplaceAudio(audio)
+}
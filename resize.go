@@ -0,0 +1,74 @@
+package moviego
+
+import (
+	"fmt"
+	"math"
+)
+
+// ResizeMode is how Resize fits a video into the target size.
+type ResizeMode string
+
+const (
+	// ResizeFit scales the video to fit inside the target, keeping its
+	// aspect ratio. The output can be smaller than the target on one side.
+	ResizeFit ResizeMode = "fit"
+	// ResizeFill scales the video to cover the target, keeping its aspect
+	// ratio, and crops the overflow around the center.
+	ResizeFill ResizeMode = "fill"
+	// ResizeStretch scales the video to the target, distorting it when the
+	// aspect ratios differ.
+	ResizeStretch ResizeMode = "stretch"
+	// ResizeLetterbox fits the video inside the target and centers it on
+	// black bars.
+	ResizeLetterbox ResizeMode = "letterbox"
+)
+
+// Resize scales the video to width x height according to mode. Dimensions
+// are rounded down to even numbers, as yuv420p encoders require, and the
+// output has square pixels.
+func (v *Video) Resize(width, height int, mode ResizeMode) (*Video, error) {
+	file, label := safeFirstFilename(v.filenames), safeLastVideoLabel(v)
+	if width < 2 || height < 2 {
+		return nil, fmt.Errorf("Resize: width and height must be at least 2 (width=%d, height=%d, file=%s, label=%s)", width, height, file, label)
+	}
+	if v.width == 0 || v.height == 0 {
+		return nil, fmt.Errorf("Resize: video dimensions are unknown (file=%s, label=%s)", file, label)
+	}
+	width, height = evenDimension(width), evenDimension(height)
+	scaleW := float64(width) / float64(v.width)
+	scaleH := float64(height) / float64(v.height)
+
+	var filter string
+	outW, outH := width, height
+	switch mode {
+	case ResizeStretch:
+		filter = fmt.Sprintf("scale=w=%d:h=%d", width, height)
+	case ResizeFit, ResizeLetterbox:
+		ratio := math.Min(scaleW, scaleH)
+		fitW := max(evenDimension(int(float64(v.width)*ratio)), 2)
+		fitH := max(evenDimension(int(float64(v.height)*ratio)), 2)
+		filter = fmt.Sprintf("scale=w=%d:h=%d", fitW, fitH)
+		if mode == ResizeFit {
+			outW, outH = fitW, fitH
+		} else {
+			filter += fmt.Sprintf(",pad=width=%d:height=%d:x=%d:y=%d:color=black",
+				width, height, evenDimension((width-fitW)/2), evenDimension((height-fitH)/2))
+		}
+	case ResizeFill:
+		ratio := math.Max(scaleW, scaleH)
+		// Rounded up so the scaled video always covers the target.
+		coverW := max(int(math.Ceil(float64(v.width)*ratio)), width)
+		coverH := max(int(math.Ceil(float64(v.height)*ratio)), height)
+		filter = fmt.Sprintf("scale=w=%d:h=%d,crop=w=%d:h=%d:x=%d:y=%d",
+			coverW, coverH, width, height, (coverW-width)/2, (coverH-height)/2)
+	default:
+		return nil, fmt.Errorf("Resize: unknown mode %q (file=%s, label=%s)", mode, file, label)
+	}
+
+	resized, err := v.videoFilter(filter + ",setsar=1")
+	if err != nil {
+		return nil, fmt.Errorf("Resize[file=%s, label=%s]: %w", file, label, err)
+	}
+	resized.width, resized.height = uint64(outW), uint64(outH)
+	return resized, nil
+}
@@ -0,0 +1,153 @@
+package moviego
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ROI is a region of interest encoded at a higher (or lower) quality than
+// the rest of the frame, e.g. faces or text that must stay crisp at a low
+// bitrate. The region is honored by encoders reading FFmpeg ROI side data:
+// libx264, libx265, libvpx-vp9 and the QSV encoders; others ignore it.
+type ROI struct {
+	Region Rect
+	// QOffset is the quantizer offset in [-1, 1]; negative values raise the
+	// quality of the region (default: -0.3).
+	QOffset float64
+	// Start and End limit the region to a time range, in seconds (End 0 =
+	// until the end).
+	Start float64
+	End   float64
+	// Track moves the region: each key's region holds from its time until
+	// the next key, and Region is ignored. Keys every few frames follow a
+	// moving subject, e.g. the output of a face tracker.
+	Track []ROIKey
+}
+
+// ROIKey is a position of a tracked ROI.
+type ROIKey struct {
+	Time   float64
+	Region Rect
+}
+
+// roiSpan is an ROI in pixels over one time range.
+type roiSpan struct {
+	start, end float64
+	filter     string
+}
+
+// EncodeROI marks regions of interest for the encoder (FFmpeg addroi). The
+// frames are unchanged; only the bitrate is distributed differently.
+func (v *Video) EncodeROI(regions ...ROI) (*Video, error) {
+	if len(v.overlays) > 0 {
+		flat, err := v.FlattenOverlays()
+		if err != nil {
+			return nil, fmt.Errorf("EncodeROI: %w", err)
+		}
+		return flat.EncodeROI(regions...)
+	}
+	file, label := safeFirstFilename(v.filenames), safeLastVideoLabel(v)
+	if len(regions) == 0 {
+		return nil, fmt.Errorf("EncodeROI: no regions provided (file=%s, label=%s)", file, label)
+	}
+	var spans []roiSpan
+	for i, roi := range regions {
+		roiSpans, err := roi.spans(v.width, v.height, v.duration)
+		if err != nil {
+			return nil, fmt.Errorf("EncodeROI: region %d: %w (file=%s, label=%s)", i, err, file, label)
+		}
+		spans = append(spans, roiSpans...)
+	}
+
+	// Regions covering the whole video are a plain chain.
+	whole := true
+	boundaries := []float64{0, v.duration}
+	for _, s := range spans {
+		whole = whole && s.start == 0 && s.end == v.duration
+		boundaries = append(boundaries, s.start, s.end)
+	}
+	if whole {
+		filters := make([]string, len(spans))
+		for i, s := range spans {
+			filters[i] = s.filter
+		}
+		return v.videoFilter(strings.Join(filters, ","))
+	}
+
+	// addroi has no timeline support, so the video is cut into segments
+	// with a constant set of regions and joined back.
+	slices.Sort(boundaries)
+	boundaries = slices.Compact(boundaries)
+	videoGraph := func(in, p string) string {
+		var b strings.Builder
+		segments := len(boundaries) - 1
+		fmt.Fprintf(&b, "[%s]split=%d", in, segments)
+		for i := 0; i < segments; i++ {
+			fmt.Fprintf(&b, "[%s_%d]", p, i)
+		}
+		b.WriteString(";")
+		var joined string
+		for i := 0; i < segments; i++ {
+			start, end := boundaries[i], boundaries[i+1]
+			fmt.Fprintf(&b, "[%s_%d]trim=start=%.6f:end=%.6f,setpts=PTS-STARTPTS", p, i, start, end)
+			mid := (start + end) / 2
+			for _, s := range spans {
+				if s.start <= mid && mid < s.end {
+					b.WriteString("," + s.filter)
+				}
+			}
+			fmt.Fprintf(&b, "[%s_s%d];", p, i)
+			joined += fmt.Sprintf("[%s_s%d]", p, i)
+		}
+		fmt.Fprintf(&b, "%sconcat=n=%d:v=1:a=0", joined, segments)
+		return b.String()
+	}
+	audioGraph := func(in, p string) string {
+		return fmt.Sprintf("[%s]anull", in)
+	}
+	return v.graphFilter(videoGraph, audioGraph), nil
+}
+
+// spans validates the ROI and resolves it for a width x height video
+// lasting duration seconds.
+func (roi ROI) spans(width, height uint64, duration float64) ([]roiSpan, error) {
+	qoffset := roi.QOffset
+	if qoffset == 0 {
+		qoffset = -0.3
+	}
+	if qoffset < -1 || qoffset > 1 {
+		return nil, fmt.Errorf("qoffset must be within [-1, 1] (got=%.4f)", roi.QOffset)
+	}
+	end := roi.End
+	if end == 0 {
+		end = duration
+	}
+	if roi.Start < 0 || end <= roi.Start || end > duration {
+		return nil, fmt.Errorf("time range must be within [0, %.4f] (start=%.4f, end=%.4f)", duration, roi.Start, roi.End)
+	}
+	keys := roi.Track
+	if len(keys) == 0 {
+		keys = []ROIKey{{Time: roi.Start, Region: roi.Region}}
+	}
+	spans := make([]roiSpan, 0, len(keys))
+	for i, key := range keys {
+		keyEnd := end
+		if i+1 < len(keys) {
+			keyEnd = keys[i+1].Time
+		}
+		if key.Time < roi.Start || keyEnd <= key.Time || keyEnd > end {
+			return nil, fmt.Errorf("track keys must be increasing and within the time range (key=%d, time=%.4f)", i, key.Time)
+		}
+		x, y, w, h, err := key.Region.resolve(width, height)
+		if err != nil {
+			return nil, err
+		}
+		spans = append(spans, roiSpan{
+			start:  key.Time,
+			end:    keyEnd,
+			filter: fmt.Sprintf("addroi=x=%d:y=%d:w=%d:h=%d:qoffset=%s", x, y, w, h, formatFloat(qoffset)),
+		})
+	}
+	return spans, nil
+}
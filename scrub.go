@@ -0,0 +1,110 @@
+package moviego
+
+import "fmt"
+
+// compositeLayer records a layer of a composite for inspection with At.
+type compositeLayer struct {
+	filenames        []string
+	start            float64
+	duration         float64
+	position         Position
+	animatedPosition *AnimatedPosition
+	animatedOpacity  *Animation
+}
+
+// LayerInfo is a composite layer or pending overlay active at a given time.
+type LayerInfo struct {
+	Index     int      // composite layer index (0 = background) or overlay drawing order
+	Layer     int      // overlay layer, see AddOverlay (0 for composite layers)
+	Files     []string // source files of the layer, if any
+	Text      string   // text of a text overlay
+	Start     float64  // when the layer appears, in seconds
+	End       float64  // when the layer disappears, in seconds
+	LocalTime float64  // time inside the layer
+	Position  Position // position; animated positions are resolved to pixels
+	Opacity   float64  // opacity in [0, 1]
+}
+
+// FrameInfo describes what is shown at a given time of a video, see At.
+type FrameInfo struct {
+	Time     float64
+	Frame    int64
+	Layers   []LayerInfo // composite layers, bottom to top
+	Overlays []LayerInfo // pending overlays in drawing order
+}
+
+// At reports which composite layers and overlays are visible at t seconds,
+// with their animations evaluated, without rendering anything. Composite
+// layers are known only while the composite has not been cut, sped up or
+// joined with other videos since.
+func (v *Video) At(t float64) FrameInfo {
+	info := FrameInfo{Time: t, Frame: Seconds(t).Frame(v.fps)}
+
+	for i, layer := range v.layers {
+		end := layer.start + layer.duration
+		if t < layer.start || t >= end {
+			continue
+		}
+		info.Layers = append(info.Layers, layerInfo(i, layer.filenames, layer.start, end, t, layer.position, layer.animatedPosition, layer.animatedOpacity))
+	}
+
+	for i, overlay := range v.GetOverlays() {
+		var li LayerInfo
+		if overlay.Text != nil {
+			text := overlay.Text
+			end := text.EndTime
+			if end <= 0 || end > v.duration {
+				end = v.duration
+			}
+			li = layerInfo(i, nil, text.StartTime, end, t, text.Position, text.AnimatePosition, text.AnimateOpacity)
+			li.Text = text.Text
+			if text.AnimateOpacity == nil && text.Opacity > 0 && text.Opacity < 1 {
+				li.Opacity = text.Opacity
+			}
+		} else {
+			var files []string
+			var start float64
+			var animatedPosition *AnimatedPosition
+			var animatedOpacity *Animation
+			switch clip := overlay.Clip.(type) {
+			case *Video:
+				files, start = clip.filenames, clip.compositeStart
+				animatedPosition, animatedOpacity = clip.animatedPosition, clip.animatedOpacity
+			case *ImageClip:
+				files = []string{clip.filename}
+				animatedPosition, animatedOpacity = clip.animatedPosition, clip.animatedOpacity
+			case *Sticker:
+				files, start = []string{clip.filename}, clip.start
+				animatedPosition, animatedOpacity = clip.animatedPosition, clip.animatedOpacity
+			}
+			li = layerInfo(i, files, start, start+overlay.Clip.GetDuration(), t, overlay.Clip.GetPosition(), animatedPosition, animatedOpacity)
+		}
+		if t < li.Start || t >= li.End {
+			continue
+		}
+		li.Layer = overlay.Layer
+		info.Overlays = append(info.Overlays, li)
+	}
+	return info
+}
+
+func layerInfo(index int, files []string, start, end, t float64, position Position, ap *AnimatedPosition, opacity *Animation) LayerInfo {
+	li := LayerInfo{
+		Index:     index,
+		Files:     files,
+		Start:     start,
+		End:       end,
+		LocalTime: t - start,
+		Position:  position,
+		Opacity:   1,
+	}
+	// Animations are evaluated on the output timeline, as FFmpeg does.
+	if ap != nil {
+		x, y := ap.positionAt(t)
+		li.Position = Position{X: fmt.Sprintf("%.4f", x), Y: fmt.Sprintf("%.4f", y)}
+	}
+	if opacity != nil {
+		li.Opacity = opacity.valueAt(t)
+	}
+	return li
+}
@@ -0,0 +1,175 @@
+package moviego
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strconv"
+	"time"
+)
+
+// modulePath is the import path used to look up the library version.
+const modulePath = "github.com/YounesseAmhend/MovieGo"
+
+// Sidecar is the delivery manifest written next to an output when
+// VideoParameters.Sidecar is set, as <output>.json. It records what was
+// delivered and how it was made, for archival and reproducibility audits.
+type Sidecar struct {
+	File           string          `json:"file"`
+	SHA256         string          `json:"sha256"`
+	Size           int64           `json:"size"`
+	Duration       float64         `json:"duration"`
+	Streams        []SidecarStream `json:"streams"`
+	Encoding       SidecarEncoding `json:"encoding"`
+	MovieGoVersion string          `json:"moviego_version"`
+	Command        []string        `json:"command"`
+	Created        time.Time       `json:"created"`
+}
+
+// SidecarStream describes one stream of the output, as probed.
+type SidecarStream struct {
+	Index      int    `json:"index"`
+	Type       string `json:"type"`
+	Codec      string `json:"codec"`
+	Profile    string `json:"profile,omitempty"`
+	Width      int    `json:"width,omitempty"`
+	Height     int    `json:"height,omitempty"`
+	FrameRate  string `json:"frame_rate,omitempty"`
+	PixFmt     string `json:"pix_fmt,omitempty"`
+	SampleRate string `json:"sample_rate,omitempty"`
+	Channels   int    `json:"channels,omitempty"`
+	BitRate    string `json:"bit_rate,omitempty"`
+}
+
+// SidecarEncoding holds the encoder settings WriteVideo used.
+type SidecarEncoding struct {
+	Encoder     string      `json:"encoder"`
+	Fps         uint64      `json:"fps,omitempty"`
+	Bitrate     string      `json:"bitrate,omitempty"`
+	Preset      string      `json:"preset,omitempty"`
+	Tune        Tune        `json:"tune,omitempty"`
+	PixelFormat PixelFormat `json:"pixel_format,omitempty"`
+	FieldOrder  FieldOrder  `json:"field_order,omitempty"`
+	Threads     uint16      `json:"threads"`
+
+	AudioEncoder string `json:"audio_encoder,omitempty"`
+	AudioBitrate string `json:"audio_bitrate,omitempty"`
+}
+
+// sidecarPath returns where the sidecar of output is written.
+func sidecarPath(output string) string {
+	return output + ".json"
+}
+
+// writeSidecar probes the finished output of job and writes its sidecar.
+func writeSidecar(job *renderJob, encoding SidecarEncoding) error {
+	sum, size, err := fileSHA256(job.output)
+	if err != nil {
+		return fmt.Errorf("%s: sidecar: %w", job.op, err)
+	}
+	duration, streams, err := probeStreams(job.output)
+	if err != nil {
+		return fmt.Errorf("%s: sidecar: %w", job.op, err)
+	}
+	sidecar := Sidecar{
+		File:           filepath.Base(job.output),
+		SHA256:         sum,
+		Size:           size,
+		Duration:       duration,
+		Streams:        streams,
+		Encoding:       encoding,
+		MovieGoVersion: moduleVersion(),
+		Command:        append([]string{"ffmpeg"}, job.commandArgs()...),
+		Created:        time.Now().UTC(),
+	}
+	if err := writeJSON(sidecarPath(job.output), sidecar); err != nil {
+		return fmt.Errorf("%s: sidecar: %w", job.op, err)
+	}
+	return nil
+}
+
+func fileSHA256(path string) (string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+	hash := sha256.New()
+	size, err := io.Copy(hash, f)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+// probeStreams returns the duration and streams of a media file.
+func probeStreams(path string) (float64, []SidecarStream, error) {
+	ffprobePath, err := getFFprobePath()
+	if err != nil {
+		return 0, nil, fmt.Errorf("ffprobe not found: %w", err)
+	}
+	output, err := commandOutput(ffprobePath, "-v", "error", "-show_format", "-show_streams", path, "-of", "json")
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to probe '%s': %w", path, err)
+	}
+	var probe struct {
+		Format struct {
+			Duration string `json:"duration"`
+		} `json:"format"`
+		Streams []struct {
+			Index      int    `json:"index"`
+			CodecType  string `json:"codec_type"`
+			CodecName  string `json:"codec_name"`
+			Profile    string `json:"profile"`
+			Width      int    `json:"width"`
+			Height     int    `json:"height"`
+			FrameRate  string `json:"avg_frame_rate"`
+			PixFmt     string `json:"pix_fmt"`
+			SampleRate string `json:"sample_rate"`
+			Channels   int    `json:"channels"`
+			BitRate    string `json:"bit_rate"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(output, &probe); err != nil {
+		return 0, nil, fmt.Errorf("failed to parse metadata for '%s': %w", path, err)
+	}
+	duration, _ := strconv.ParseFloat(probe.Format.Duration, 64)
+	streams := make([]SidecarStream, len(probe.Streams))
+	for i, s := range probe.Streams {
+		streams[i] = SidecarStream{
+			Index: s.Index, Type: s.CodecType, Codec: s.CodecName, Profile: s.Profile,
+			Width: s.Width, Height: s.Height, PixFmt: s.PixFmt,
+			SampleRate: s.SampleRate, Channels: s.Channels, BitRate: s.BitRate,
+		}
+		if s.CodecType == "video" {
+			streams[i].FrameRate = s.FrameRate
+		}
+	}
+	return duration, streams, nil
+}
+
+// moduleVersion returns the version of this library in the running binary,
+// "(devel)" when built from a checkout.
+func moduleVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	if info.Main.Path == modulePath {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == modulePath {
+			if dep.Replace != nil {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return "unknown"
+}
@@ -0,0 +1,76 @@
+package moviego
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DetectSilence returns the ranges where the audio stays below threshold
+// (in dBFS, e.g. -30) for at least minDuration seconds, with FFmpeg's
+// silencedetect, e.g. to trim dead air or split a recording at its pauses.
+// Filters of a are applied before the detection.
+func (a *Audio) DetectSilence(threshold, minDuration float64) ([]TimeRange, error) {
+	if threshold > 0 || minDuration <= 0 {
+		return nil, fmt.Errorf("DetectSilence: threshold must be <= 0 dB and minDuration positive (threshold=%.4f, minDuration=%.4f, file=%s, label=%s)", threshold, minDuration, safeFirstFilename(a.filenames), a.safeLabel())
+	}
+	ffmpegPath, err := getFFmpegPath()
+	if err != nil {
+		return nil, fmt.Errorf("DetectSilence: failed to get ffmpeg path: %w", err)
+	}
+	detect := *a
+	initRawAudio(&detect)
+	detect = detect.chain(fmt.Sprintf("silencedetect=n=%sdB:d=%s", formatFloat(threshold), formatFloat(minDuration)))
+	args, err := detect.graphArgs()
+	if err != nil {
+		return nil, fmt.Errorf("DetectSilence: %w", err)
+	}
+	args = append(append([]string{"-hide_banner", "-nostats"}, args...), "-vn", "-f", "null", "-")
+	var stderr bytes.Buffer
+	if err := runCommand(&Command{Path: ffmpegPath, Args: args, Stderr: &stderr}); err != nil {
+		return nil, fmt.Errorf("DetectSilence: failed to execute ffmpeg: %w\nffmpeg stderr: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return parseSilence(stderr.String(), a.duration), nil
+}
+
+// DetectSilence returns the silent ranges of the audio of the video, see
+// Audio.DetectSilence.
+func (v *Video) DetectSilence(threshold, minDuration float64) ([]TimeRange, error) {
+	if v.audioRemoved || !v.HasAudio() && len(v.audio.filterComplex) == 0 {
+		return nil, fmt.Errorf("DetectSilence: video has no audio (file=%s, label=%s)", safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	out := v.clone()
+	initRawVideo(&out)
+	out.audio.duration = v.duration
+	return out.audio.DetectSilence(threshold, minDuration)
+}
+
+// parseSilence reads the silence_start and silence_end lines silencedetect
+// logs. A silence still running at the end of the audio ends at duration.
+func parseSilence(log string, duration float64) []TimeRange {
+	var ranges []TimeRange
+	start := -1.0
+	scanner := bufio.NewScanner(strings.NewReader(log))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if _, value, ok := strings.Cut(line, "silence_start: "); ok {
+			if t, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+				start = math.Max(t, 0)
+			}
+		} else if _, value, ok := strings.Cut(line, "silence_end: "); ok && start >= 0 {
+			// silence_end: 4.51 | silence_duration: 2.1
+			value, _, _ = strings.Cut(value, " ")
+			if t, err := strconv.ParseFloat(value, 64); err == nil {
+				ranges = append(ranges, TimeRange{Start: start, End: t})
+				start = -1
+			}
+		}
+	}
+	if start >= 0 && duration > start {
+		ranges = append(ranges, TimeRange{Start: start, End: duration})
+	}
+	return ranges
+}
@@ -2,6 +2,7 @@ package moviego
 
 import (
 	"fmt"
+	"math"
 	"strings"
 )
 
@@ -18,7 +19,7 @@ func buildAtempoChain(speed float64) string {
 		speed /= 0.5
 	}
 	if speed > 0 && speed != 1.0 {
-		filters = append(filters, fmt.Sprintf("atempo=%.4f", speed))
+		filters = append(filters, "atempo="+formatFloat(speed))
 	}
 	if len(filters) == 0 {
 		return "atempo=1"
@@ -49,6 +50,13 @@ func buildAudioSpeedFilter(speed, pitch float64, sampleRate uint64) string {
 //
 // Returns a new Video object with updated metadata (no file is created until WriteVideo is called)
 func (v *Video) Speed(speed float64, pitch ...float64) (*Video, error) {
+	if len(v.overlays) > 0 {
+		flat, err := v.FlattenOverlays()
+		if err != nil {
+			return nil, fmt.Errorf("Speed: %w", err)
+		}
+		return flat.Speed(speed, pitch...)
+	}
 	if speed <= 0 {
 		return nil, fmt.Errorf("Speed: speed must be positive (got=%f, file=%s, label=%s)", speed, safeFirstFilename(v.filenames), safeLastVideoLabel(v))
 	}
@@ -66,7 +74,7 @@ func (v *Video) Speed(speed float64, pitch ...float64) (*Video, error) {
 
 	newDuration := v.duration / speed
 	sampleRate := v.GetAudio().GetSampleRate()
-	videoFilter := fmt.Sprintf("setpts=PTS/%.4f", speed)
+	videoFilter := fmt.Sprintf("setpts=PTS/%s", formatFloat(speed))
 	audioFilter := buildAudioSpeedFilter(speed, pitchVal, sampleRate)
 
 	if len(v.filterComplex) == 0 {
@@ -113,6 +121,7 @@ func (v *Video) Speed(speed float64, pitch ...float64) (*Video, error) {
 
 	newAudio := v.audio
 	newAudio.filterComplex = audioFilterComplex
+	newAudio.duration = v.audio.duration / speed
 
 	newVideo := &Video{
 		filenames:          v.filenames,
@@ -133,9 +142,153 @@ func (v *Video) Speed(speed float64, pitch ...float64) (*Video, error) {
 		startTime:          0,
 		endTime:            newDuration,
 		position:           v.position,
+		compositeStart:     v.compositeStart,
+		invalid:            v.invalid,
 		animatedPosition:   v.animatedPosition,
 		animatedOpacity:    v.animatedOpacity,
+		startTimecode:      v.startTimecode,
+		audioRemoved:       v.audioRemoved,
+	}
+
+	return newVideo, nil
+}
+
+// SpeedX plays the video factor times faster (2 = double speed, 0.25 =
+// quarter-speed slow motion), keeping the pitch of the audio. atempo only
+// accepts 0.5-2 per instance, so larger changes chain several of them.
+// It is Speed without the pitch option.
+func (v *Video) SpeedX(factor float64) (*Video, error) {
+	return v.Speed(factor)
+}
+
+// speedRampStep is the longest source span compiled with a single constant
+// speed while interpolating between two speed keyframes.
+const speedRampStep = 0.25
+
+// SpeedKeyframe sets the playback speed at a point of the source clip.
+type SpeedKeyframe struct {
+	Time  float64 // source time in seconds
+	Speed float64 // playback multiplier (1 = normal, 0.25 = quarter speed)
+}
+
+// speedSegment is a source time range played back at a constant speed.
+type speedSegment struct {
+	start float64
+	end   float64
+	speed float64
+}
+
+// buildSpeedSegments splits [0, duration] into constant-speed segments.
+// Speed is held before the first and after the last keyframe and interpolated
+// linearly in between, sampled every speedRampStep seconds at segment midpoints.
+func buildSpeedSegments(keyframes []SpeedKeyframe, duration float64) []speedSegment {
+	var segments []speedSegment
+	add := func(start, end, speed float64) {
+		if end-start <= 0 {
+			return
+		}
+		if n := len(segments); n > 0 && segments[n-1].speed == speed {
+			segments[n-1].end = end
+			return
+		}
+		segments = append(segments, speedSegment{start: start, end: end, speed: speed})
+	}
+
+	first, last := keyframes[0], keyframes[len(keyframes)-1]
+	add(0, first.Time, first.Speed)
+	for i := 0; i+1 < len(keyframes); i++ {
+		k1, k2 := keyframes[i], keyframes[i+1]
+		span := k2.Time - k1.Time
+		steps := int(math.Ceil(span / speedRampStep))
+		if k1.Speed == k2.Speed {
+			steps = 1
+		}
+		for s := 0; s < steps; s++ {
+			start := k1.Time + span*float64(s)/float64(steps)
+			end := k1.Time + span*float64(s+1)/float64(steps)
+			mid := ((start+end)/2 - k1.Time) / span
+			add(start, end, k1.Speed+(k2.Speed-k1.Speed)*mid)
+		}
+	}
+	add(last.Time, duration, last.Speed)
+	return segments
+}
+
+// buildSpeedRampPTS returns a setpts expression mapping source time to output
+// time: the sum over segments of the time spent in each one divided by its speed.
+func buildSpeedRampPTS(segments []speedSegment) string {
+	terms := make([]string, len(segments))
+	for i, seg := range segments {
+		terms[i] = fmt.Sprintf("min(max(T-STARTT-%.4f,0),%.4f)/%.4f", seg.start, seg.end-seg.start, seg.speed)
+	}
+	return fmt.Sprintf("setpts='(%s)/TB'", strings.Join(terms, "+"))
+}
+
+// buildSpeedRampAudio returns a graph that splits the audio at segment
+// boundaries, retimes each segment with atempo and concatenates them again.
+func buildSpeedRampAudio(inputLabel, label string, segments []speedSegment) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s]asplit=%d", inputLabel, len(segments))
+	for i := range segments {
+		fmt.Fprintf(&b, "[%s_seg%d]", label, i)
+	}
+	b.WriteString(";")
+	for i, seg := range segments {
+		fmt.Fprintf(&b, "[%s_seg%d]atrim=start=%.4f:end=%.4f,asetpts=PTS-STARTPTS,%s[%s_ramp%d];",
+			label, i, seg.start, seg.end, buildAtempoChain(seg.speed), label, i)
+	}
+	for i := range segments {
+		fmt.Fprintf(&b, "[%s_ramp%d]", label, i)
+	}
+	fmt.Fprintf(&b, "concat=n=%d:v=0:a=1", len(segments))
+	return b.String()
+}
+
+// SpeedRamp varies playback speed over time (e.g. 100% -> 25% -> 100%).
+// Keyframe times are in source seconds and must be increasing; speed is
+// interpolated linearly between keyframes and held before the first and after
+// the last one. Video is retimed with a single setpts expression; audio is cut
+// into segments that are each retimed with atempo.
+//
+// Returns a new Video object with updated metadata (no file is created until WriteVideo is called)
+func (v *Video) SpeedRamp(keyframes []SpeedKeyframe) (*Video, error) {
+	if len(v.overlays) > 0 {
+		flat, err := v.FlattenOverlays()
+		if err != nil {
+			return nil, fmt.Errorf("SpeedRamp: %w", err)
+		}
+		return flat.SpeedRamp(keyframes)
+	}
+	file, label := safeFirstFilename(v.filenames), safeLastVideoLabel(v)
+	if len(keyframes) == 0 {
+		return nil, fmt.Errorf("SpeedRamp: at least one keyframe is required (file=%s, label=%s)", file, label)
+	}
+	for i, k := range keyframes {
+		if k.Speed <= 0 {
+			return nil, fmt.Errorf("SpeedRamp: keyframe %d speed must be positive (got=%f, file=%s, label=%s)", i, k.Speed, file, label)
+		}
+		if k.Time < 0 || k.Time > v.duration {
+			return nil, fmt.Errorf("SpeedRamp: keyframe %d time %.4f is outside the clip [0, %.4f] (file=%s, label=%s)", i, k.Time, v.duration, file, label)
+		}
+		if i > 0 && k.Time <= keyframes[i-1].Time {
+			return nil, fmt.Errorf("SpeedRamp: keyframe times must be increasing (keyframe %d at %.4f, file=%s, label=%s)", i, k.Time, file, label)
+		}
+	}
+
+	segments := buildSpeedSegments(keyframes, v.duration)
+	var newDuration float64
+	for _, seg := range segments {
+		newDuration += (seg.end - seg.start) / seg.speed
 	}
 
+	newVideo := v.graphFilter(
+		func(in, _ string) string { return fmt.Sprintf("[%s]%s", in, buildSpeedRampPTS(segments)) },
+		func(in, prefix string) string { return buildSpeedRampAudio(in, prefix, segments) },
+	)
+	newVideo.audio.duration = newDuration
+	newVideo.duration = newDuration
+	newVideo.frames = uint64(float64(v.fps) * newDuration)
+	newVideo.startTime = 0
+	newVideo.endTime = newDuration
 	return newVideo, nil
 }
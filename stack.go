@@ -17,7 +17,10 @@ func HStack(videos []Video) (*Video, error) {
 		return &v, nil
 	}
 
-	prepared := append([]Video(nil), videos...)
+	prepared, err := flattenVideos("HStack", videos)
+	if err != nil {
+		return nil, err
+	}
 	for i := range prepared {
 		initRawVideo(&prepared[i])
 	}
@@ -45,7 +48,10 @@ func VStack(videos []Video) (*Video, error) {
 		return &v, nil
 	}
 
-	prepared := append([]Video(nil), videos...)
+	prepared, err := flattenVideos("VStack", videos)
+	if err != nil {
+		return nil, err
+	}
 	for i := range prepared {
 		initRawVideo(&prepared[i])
 	}
@@ -76,7 +82,10 @@ func XStack(videos []Video, layout string) (*Video, error) {
 		return nil, fmt.Errorf("XStack: layout is required")
 	}
 
-	prepared := append([]Video(nil), videos...)
+	prepared, err := flattenVideos("XStack", videos)
+	if err != nil {
+		return nil, err
+	}
 	for i := range prepared {
 		initRawVideo(&prepared[i])
 	}
@@ -96,6 +105,11 @@ func stackVideos(videos []Video, videoFilter string, width, height uint64, label
 		initRawVideo(&prepared[i])
 	}
 
+	format := resolveVideosAudioFormat(prepared)
+	for i := range prepared {
+		prepared[i].audio = prepared[i].audio.normalized(format)
+	}
+
 	filenames := []string{}
 	videoFilterComplex := []FilterComplex{}
 	audioFilterComplex := []FilterComplex{}
@@ -111,7 +125,9 @@ func stackVideos(videos []Video, videoFilter string, width, height uint64, label
 			filenames = append(filenames, filename)
 		}
 		videoFilterComplex = append(videoFilterComplex, video.filterComplex...)
-		audioFilterComplex = append(audioFilterComplex, video.audio.filterComplex...)
+		if mixesAudio(prepared, video) {
+			audioFilterComplex = append(audioFilterComplex, video.audio.filterComplex...)
+		}
 		if video.duration > maxDuration {
 			maxDuration = video.duration
 		}
@@ -154,6 +170,7 @@ func stackVideos(videos []Video, videoFilter string, width, height uint64, label
 		preset:             base.preset,
 		withMask:           base.withMask,
 		pixelFormat:        base.pixelFormat,
+		audioRemoved:       allAudioRemoved(prepared),
 	}, nil
 }
 
@@ -173,12 +190,17 @@ func buildStackInputs(videos []Video) string {
 
 func buildAudioMix(videos []Video) string {
 	var b strings.Builder
+	inputs := 0
 	for _, video := range videos {
+		if !mixesAudio(videos, video) {
+			continue
+		}
 		b.WriteString("[")
 		b.WriteString(video.audio.lastAudioLabel())
 		b.WriteString("]")
+		inputs++
 	}
-	b.WriteString(fmt.Sprintf("amix=inputs=%d:duration=longest", len(videos)))
+	b.WriteString(fmt.Sprintf("amix=inputs=%d:duration=longest", inputs))
 	return b.String()
 }
 
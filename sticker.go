@@ -0,0 +1,180 @@
+package moviego
+
+import (
+	"fmt"
+	"math"
+)
+
+// Sticker is an animated GIF, APNG or WebP placed over a video for a time
+// range, looping while it is shown, as in mobile editors:
+//
+//	heart, err := moviego.NewSticker("heart.gif")
+//	heart.Size(160, 160).Between(2, 5).Drag(moviego.Position{X: "100", Y: "500"}, moviego.Position{X: "900", Y: "120"}, moviego.EaseInOut)
+//	video, err = video.AddSticker(heart)
+//
+// Animated WebP needs an FFmpeg build able to decode it (FFmpeg 8 or later).
+type Sticker struct {
+	filename         string
+	width            uint64
+	height           uint64
+	sourceDuration   float64 // length of one animation loop
+	start            float64
+	end              float64
+	fps              uint64
+	position         Position
+	animatedPosition *AnimatedPosition
+	animatedOpacity  *Animation
+}
+
+// NewSticker probes an animated image. The sticker keeps its own size and is
+// shown for one loop from the start of the video until changed.
+func NewSticker(filename string) (*Sticker, error) {
+	probe, err := NewVideoFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("NewSticker: %w", err)
+	}
+	duration := probe.GetDuration()
+	if duration <= 0 {
+		// Single-frame images have no duration; show them for a second.
+		duration = 1
+	}
+	return &Sticker{
+		filename:       filename,
+		width:          probe.GetWidth(),
+		height:         probe.GetHeight(),
+		sourceDuration: duration,
+		end:            duration,
+	}, nil
+}
+
+// GetWidth returns the sticker width.
+func (s *Sticker) GetWidth() uint64 {
+	return s.width
+}
+
+// GetHeight returns the sticker height.
+func (s *Sticker) GetHeight() uint64 {
+	return s.height
+}
+
+// GetDuration returns how long the sticker is shown.
+func (s *Sticker) GetDuration() float64 {
+	return s.end - s.start
+}
+
+// GetFps returns the frame rate the animation is sampled at (default: 30).
+func (s *Sticker) GetFps() uint64 {
+	if s.fps == 0 {
+		return defaultClipFps
+	}
+	return s.fps
+}
+
+// GetPosition returns the overlay position.
+// Returns center position if none was explicitly set.
+func (s *Sticker) GetPosition() Position {
+	if s.position.X == "" && s.position.Y == "" {
+		return CenterPosition()
+	}
+	return s.position
+}
+
+// GetFilename returns the sticker filename.
+func (s *Sticker) GetFilename() string {
+	return s.filename
+}
+
+// GetTimeRange returns when the sticker is shown on the video timeline.
+func (s *Sticker) GetTimeRange() (start, end float64) {
+	return s.start, s.end
+}
+
+// Size sets the displayed size of the sticker.
+func (s *Sticker) Size(width, height uint64) *Sticker {
+	s.width = width
+	s.height = height
+	return s
+}
+
+// Between shows the sticker from start to end seconds of the video it is
+// added to, looping the animation as needed.
+func (s *Sticker) Between(start, end float64) *Sticker {
+	s.start = start
+	s.end = end
+	return s
+}
+
+// Fps sets the frame rate the animation is sampled at.
+func (s *Sticker) Fps(fps uint64) *Sticker {
+	s.fps = fps
+	return s
+}
+
+// SetPosition places the sticker at a fixed position.
+func (s *Sticker) SetPosition(position Position) *Sticker {
+	s.position = position
+	s.animatedPosition = nil
+	return s
+}
+
+// Drag moves the sticker between two pixel positions over its time range,
+// like a sticker dragged across the screen. Set the time range first.
+func (s *Sticker) Drag(from, to Position, curve Curve) *Sticker {
+	s.animatedPosition = &AnimatedPosition{
+		Start:     from,
+		End:       to,
+		StartTime: s.start,
+		EndTime:   s.end,
+		Curve:     curve,
+	}
+	return s
+}
+
+// SetAnimatedPosition sets a custom position animation. Times are on the
+// video timeline.
+func (s *Sticker) SetAnimatedPosition(ap AnimatedPosition) *Sticker {
+	s.animatedPosition = &ap
+	return s
+}
+
+// SetAnimatedOpacity sets the opacity animation. Times are on the video timeline.
+func (s *Sticker) SetAnimatedOpacity(a Animation) *Sticker {
+	s.animatedOpacity = &a
+	return s
+}
+
+// toVideo resamples the animation to a constant frame rate, loops it for the
+// time range and scales it to the sticker size.
+func (s *Sticker) toVideo() (*Video, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	fps := s.GetFps()
+	loopFrames := max(1, int(math.Ceil(s.sourceDuration*float64(fps))))
+	duration := s.GetDuration()
+	label := fmt.Sprintf("sticker_%d_%s", incrementGlobalCounter(), sanitize(s.filename))
+	fileCopy := FileCopy{Filename: s.filename, Label: label + "_in"}
+	element := fmt.Sprintf("[%s]fps=%d,loop=loop=-1:size=%d:start=0,setpts=N/(%d*TB),trim=duration=%.4f,scale=%d:%d,setsar=1,format=rgba",
+		fileCopy.Label, fps, loopFrames, fps, duration, s.width, s.height)
+	v := generatedVideo([]string{s.filename}, element, fileCopy, label, s.width, s.height, fps, duration)
+	v.compositeStart = s.start
+	v.position = s.position
+	v.animatedPosition = s.animatedPosition
+	v.animatedOpacity = s.animatedOpacity
+	return v, nil
+}
+
+// AddSticker composites the stickers over the video.
+func (v *Video) AddSticker(stickers ...*Sticker) (*Video, error) {
+	clips := []Clip{v}
+	for i, s := range stickers {
+		if s == nil {
+			return nil, fmt.Errorf("AddSticker: sticker %d is nil (file=%s, label=%s)", i, safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+		}
+		if s.end > v.duration+1e-6 {
+			return nil, fmt.Errorf("AddSticker: sticker %d ends after the video (end=%.4f, duration=%.4f, file=%s, label=%s)", i, s.end, v.duration, safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+		}
+		clips = append(clips, s)
+	}
+	return CompositeClips(clips...)
+}
@@ -0,0 +1,49 @@
+package moviego
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RenderStill renders the single frame shown at time t to outputPath, with
+// the whole edit applied (composites, overlays, text, filters), e.g. for
+// thumbnails or UI previews. t is snapped to the frame grid so the still is
+// exactly the frame a render would contain. The image format follows the
+// extension of outputPath (.jpg, .png, .webp, ...).
+func (v *Video) RenderStill(t float64, outputPath string) error {
+	return v.renderStill("RenderStill", t, outputPath, nil)
+}
+
+func (v *Video) renderStill(op string, t float64, outputPath string, env *RenderEnv) error {
+	if outputPath == "" {
+		return fmt.Errorf("%s: output path cannot be empty (file=%s, label=%s)", op, safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	if t < 0 || t >= v.duration {
+		return fmt.Errorf("%s: time must be within [0, %.4f) (got=%.4f, file=%s, label=%s)", op, v.duration, t, safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	ffmpegPath, err := getFFmpegPath()
+	if err != nil {
+		return fmt.Errorf("%s: failed to get ffmpeg path: %w", op, err)
+	}
+	if dir := filepath.Dir(outputPath); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("%s: failed to create '%s': %w", op, dir, err)
+		}
+	}
+
+	fps := max(v.fps, 1)
+	start := Seconds(t).Snap(fps).Seconds()
+	frame, err := v.Cut(start, math.Min(start+1/float64(fps), v.duration))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	outputArgs := []string{"-an", "-frames:v", "1"}
+	switch strings.ToLower(filepath.Ext(outputPath)) {
+	case ".jpg", ".jpeg":
+		outputArgs = append(outputArgs, "-q:v", "2")
+	}
+	return sourceJob(op, ffmpegPath, frame, outputPath, outputArgs, env)
+}
@@ -0,0 +1,172 @@
+package moviego
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Storage is where inputs are read from and outputs are written to when they
+// do not live on the local disk, e.g. an S3, GCS or Azure bucket. Names are
+// storage keys such as "renders/intro.mp4".
+//
+// NewVideoFromStorage and Video.WriteVideoTo use it so callers do not have to
+// stage files locally themselves. See DirStorage and HTTPStorage.
+type Storage interface {
+	// Open returns a reader for the object name.
+	Open(name string) (io.ReadCloser, error)
+	// Create returns a writer replacing the object name. The object is
+	// complete once Close returns without error.
+	Create(name string) (io.WriteCloser, error)
+}
+
+// StreamStorage is implemented by storages FFmpeg can read from directly
+// (e.g. through a presigned URL), so inputs are streamed instead of copied.
+type StreamStorage interface {
+	Storage
+	// StreamURL returns a URL FFmpeg can read the object name from, or an
+	// error when the object must be downloaded instead.
+	StreamURL(name string) (string, error)
+}
+
+// DirStorage stores objects as files below a local directory. Names that
+// would resolve outside the directory (absolute or "../" keys) are rejected.
+type DirStorage string
+
+// Open opens the file name below the directory.
+func (d DirStorage) Open(name string) (io.ReadCloser, error) {
+	path, err := d.path(name)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(path)
+}
+
+// Create creates the file name below the directory, with its parent directories.
+func (d DirStorage) Create(name string) (io.WriteCloser, error) {
+	path, err := d.path(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	return os.Create(path)
+}
+
+// StreamURL returns the local path of name, which FFmpeg reads directly.
+func (d DirStorage) StreamURL(name string) (string, error) {
+	return d.path(name)
+}
+
+// path returns the file of name below the directory.
+func (d DirStorage) path(name string) (string, error) {
+	clean := filepath.Clean(filepath.FromSlash(name))
+	if filepath.IsAbs(clean) || filepath.VolumeName(clean) != "" ||
+		clean == ".." || strings.HasPrefix(clean, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("DirStorage: name '%s' is outside the storage directory", name)
+	}
+	return filepath.Join(string(d), clean), nil
+}
+
+// NewVideoFromStorage loads the video name from s. Objects with a stream URL
+// (see StreamStorage) are read by FFmpeg directly; others are copied to a
+// temporary file first (see Video.GetIsTemp).
+func NewVideoFromStorage(s Storage, name string) (*Video, error) {
+	if ss, ok := s.(StreamStorage); ok {
+		if url, err := ss.StreamURL(name); err == nil {
+			return NewVideoFile(url)
+		}
+	}
+	var env *RenderEnv
+	if h, ok := s.(*HTTPStorage); ok {
+		env = h.Env
+	}
+	path, err := fetchFromStorage(s, name, env)
+	if err != nil {
+		return nil, fmt.Errorf("NewVideoFromStorage: %w", err)
+	}
+	v, err := NewVideoFile(path)
+	if err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+	return v.SetIsTemp(true), nil
+}
+
+// WriteVideoTo renders the video to a temporary file and uploads it to s as
+// name. parms.OutputPath is ignored; the output format follows the
+// extension of name. With parms.Sidecar the manifest is uploaded as
+// name + ".json".
+func (v *Video) WriteVideoTo(s Storage, name string, parms VideoParameters) error {
+	tmp, err := os.MkdirTemp(parms.Env.tempDir(), "moviego-upload-*")
+	if err != nil {
+		return fmt.Errorf("WriteVideoTo: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	parms.OutputPath = filepath.Join(tmp, "output"+filepath.Ext(name))
+	if err := v.WriteVideo(parms); err != nil {
+		return err
+	}
+	if err := copyToStorage(s, name, parms.OutputPath); err != nil {
+		return fmt.Errorf("WriteVideoTo: %w", err)
+	}
+	if parms.Sidecar {
+		if err := copyToStorage(s, sidecarPath(name), sidecarPath(parms.OutputPath)); err != nil {
+			return fmt.Errorf("WriteVideoTo: %w", err)
+		}
+	}
+	return nil
+}
+
+// fetchFromStorage copies name to a temporary file in env's TempDir and
+// returns its path.
+func fetchFromStorage(s Storage, name string, env *RenderEnv) (string, error) {
+	r, err := s.Open(name)
+	if err != nil {
+		return "", fmt.Errorf("failed to open '%s': %w", name, err)
+	}
+	defer r.Close()
+	f, err := os.CreateTemp(env.tempDir(), "moviego-*"+filepath.Ext(name))
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to download '%s': %w", name, err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// copyToStorage uploads the local file path to s as name.
+func copyToStorage(s Storage, name, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w, err := s.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to create '%s': %w", name, err)
+	}
+	if _, err := io.Copy(w, f); err != nil {
+		if a, ok := w.(interface{ abort() }); ok {
+			a.abort()
+		} else {
+			w.Close()
+		}
+		return fmt.Errorf("failed to upload '%s': %w", name, err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to upload '%s': %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,254 @@
+package moviego
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// HTTPStorage is a Storage over plain HTTP GET and PUT requests. It is the
+// base of the S3, GCS and Azure adapters and works with any service that
+// accepts presigned or token-authenticated URLs.
+//
+// Reads are streamed. Writes are buffered in a temporary file and uploaded
+// on Close, since object stores need the content length up front.
+type HTTPStorage struct {
+	// URL returns the URL of name for method ("GET" or "PUT").
+	URL func(method, name string) (string, error)
+	// Token returns a bearer token sent with every request (optional).
+	Token func() (string, error)
+	// PutHeader holds extra headers for uploads, e.g. the Azure blob type.
+	PutHeader http.Header
+	// Client is the HTTP client (default: http.DefaultClient).
+	Client *http.Client
+	// Env is the optional render environment; its TempDir holds buffered
+	// uploads and objects downloaded by NewVideoFromStorage.
+	Env *RenderEnv
+}
+
+// Open streams the object name.
+func (h *HTTPStorage) Open(name string) (io.ReadCloser, error) {
+	resp, err := h.do(http.MethodGet, name, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Create returns a writer uploading name when closed.
+func (h *HTTPStorage) Create(name string) (io.WriteCloser, error) {
+	f, err := os.CreateTemp(h.Env.tempDir(), "moviego-upload-*")
+	if err != nil {
+		return nil, err
+	}
+	return &httpUpload{File: f, storage: h, name: name}, nil
+}
+
+// StreamURL returns the GET URL of name. Storages authenticated with a
+// Token cannot be streamed by FFmpeg, so they return an error and
+// NewVideoFromStorage downloads them instead.
+func (h *HTTPStorage) StreamURL(name string) (string, error) {
+	if h.Token != nil {
+		return "", fmt.Errorf("HTTPStorage: token-authenticated objects cannot be streamed")
+	}
+	return h.URL(http.MethodGet, name)
+}
+
+func (h *HTTPStorage) do(method, name string, body io.Reader, length int64) (*http.Response, error) {
+	u, err := h.URL(method, name)
+	if err != nil {
+		return nil, fmt.Errorf("HTTPStorage: failed to resolve '%s': %w", name, err)
+	}
+	req, err := http.NewRequest(method, u, body)
+	if err != nil {
+		return nil, fmt.Errorf("HTTPStorage: %w", err)
+	}
+	if method == http.MethodPut {
+		req.ContentLength = length
+		for k, values := range h.PutHeader {
+			req.Header[k] = values
+		}
+	}
+	if h.Token != nil {
+		token, err := h.Token()
+		if err != nil {
+			return nil, fmt.Errorf("HTTPStorage: failed to get token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTPStorage: %s '%s': %w", method, name, err)
+	}
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("HTTPStorage: %s '%s': %s: %s", method, name, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return resp, nil
+}
+
+// httpUpload buffers an upload in a temporary file.
+type httpUpload struct {
+	*os.File
+	storage *HTTPStorage
+	name    string
+}
+
+// abort discards the upload without sending it.
+func (u *httpUpload) abort() {
+	u.File.Close()
+	os.Remove(u.File.Name())
+}
+
+func (u *httpUpload) Close() error {
+	defer u.abort()
+	size, err := u.File.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := u.File.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	resp, err := u.storage.do(http.MethodPut, u.name, u.File, size)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// S3Config configures NewS3Storage.
+type S3Config struct {
+	Bucket          string
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string        // optional, for temporary credentials
+	Endpoint        string        // optional, e.g. "https://minio.local:9000" (default: AWS)
+	Expires         time.Duration // presigned URL lifetime (default: 1 hour)
+}
+
+// NewS3Storage returns a storage for an S3 bucket (or an S3-compatible
+// service) using SigV4 presigned URLs, so FFmpeg streams inputs directly.
+func NewS3Storage(cfg S3Config) *HTTPStorage {
+	return &HTTPStorage{URL: func(method, name string) (string, error) {
+		return cfg.presign(method, name, time.Now().UTC())
+	}}
+}
+
+// presign returns a SigV4 query-signed URL for a path-style object URL.
+func (cfg S3Config) presign(method, name string, now time.Time) (string, error) {
+	if cfg.Bucket == "" || cfg.Region == "" || cfg.AccessKeyID == "" || cfg.SecretAccessKey == "" {
+		return "", fmt.Errorf("S3Config: Bucket, Region, AccessKeyID and SecretAccessKey are required")
+	}
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", cfg.Region)
+	}
+	base, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("S3Config: invalid endpoint: %w", err)
+	}
+	expires := cfg.Expires
+	if expires == 0 {
+		expires = time.Hour
+	}
+
+	path := "/" + awsEscape(cfg.Bucket, false) + "/" + awsEscape(strings.TrimPrefix(name, "/"), true)
+	date := now.Format("20060102")
+	stamp := now.Format("20060102T150405Z")
+	scope := date + "/" + cfg.Region + "/s3/aws4_request"
+
+	query := map[string]string{
+		"X-Amz-Algorithm":     "AWS4-HMAC-SHA256",
+		"X-Amz-Credential":    cfg.AccessKeyID + "/" + scope,
+		"X-Amz-Date":          stamp,
+		"X-Amz-Expires":       fmt.Sprint(int(expires.Seconds())),
+		"X-Amz-SignedHeaders": "host",
+	}
+	if cfg.SessionToken != "" {
+		query["X-Amz-Security-Token"] = cfg.SessionToken
+	}
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, k := range keys {
+		pairs[i] = awsEscape(k, false) + "=" + awsEscape(query[k], false)
+	}
+	canonicalQuery := strings.Join(pairs, "&")
+
+	canonical := strings.Join([]string{method, path, canonicalQuery, "host:" + base.Host, "", "host", "UNSIGNED-PAYLOAD"}, "\n")
+	hash := sha256.Sum256([]byte(canonical))
+	toSign := strings.Join([]string{"AWS4-HMAC-SHA256", stamp, scope, hex.EncodeToString(hash[:])}, "\n")
+
+	key := []byte("AWS4" + cfg.SecretAccessKey)
+	for _, part := range []string{date, cfg.Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, toSign))
+	return fmt.Sprintf("%s://%s%s?%s&X-Amz-Signature=%s", base.Scheme, base.Host, path, canonicalQuery, signature), nil
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// awsEscape percent-encodes s as SigV4 requires, keeping '/' when slash is set.
+func awsEscape(s string, slash bool) string {
+	var b strings.Builder
+	for _, c := range []byte(s) {
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', slash && c == '/':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// NewGCSStorage returns a storage for a Google Cloud Storage bucket using
+// OAuth2 access tokens (e.g. from a service account). Inputs are downloaded
+// before use since FFmpeg cannot send the token.
+func NewGCSStorage(bucket string, token func() (string, error)) *HTTPStorage {
+	return &HTTPStorage{
+		URL: func(method, name string) (string, error) {
+			return "https://storage.googleapis.com/" + awsEscape(bucket, false) + "/" + awsEscape(strings.TrimPrefix(name, "/"), true), nil
+		},
+		Token: token,
+	}
+}
+
+// NewAzureBlobStorage returns a storage for an Azure Blob Storage container,
+// e.g. "https://account.blob.core.windows.net/videos", authenticated with a
+// SAS token ("sv=...&sig=...").
+func NewAzureBlobStorage(containerURL, sasToken string) *HTTPStorage {
+	return &HTTPStorage{
+		URL: func(method, name string) (string, error) {
+			u := strings.TrimSuffix(containerURL, "/") + "/" + awsEscape(strings.TrimPrefix(name, "/"), true)
+			if sasToken != "" {
+				u += "?" + strings.TrimPrefix(sasToken, "?")
+			}
+			return u, nil
+		},
+		PutHeader: http.Header{"X-Ms-Blob-Type": {"BlockBlob"}},
+	}
+}
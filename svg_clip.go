@@ -0,0 +1,182 @@
+package moviego
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+)
+
+// SVGClip is a vector graphic (typically a logo) rasterized by FFmpeg's
+// librsvg decoder at the exact size it is shown at, so it stays crisp at any
+// output resolution without pre-rendered PNGs.
+//
+// When the clip is scaled over time (SetAnimatedScale), it is rasterized at
+// the largest scale reached and only ever scaled down. Like HTMLClip, the
+// rasterized image only lasts for one WriteVideo.
+type SVGClip struct {
+	filename         string
+	width            uint64
+	height           uint64
+	duration         float64
+	fps              uint64
+	position         Position
+	animatedPosition *AnimatedPosition
+	animatedOpacity  *Animation
+	animatedScale    *Animation
+	env              *RenderEnv
+}
+
+// NewSVGClip creates an SVGClip shown at width x height for duration seconds.
+func NewSVGClip(filename string, width, height uint64, duration float64) *SVGClip {
+	return &SVGClip{
+		filename: filename,
+		width:    width,
+		height:   height,
+		duration: duration,
+	}
+}
+
+// GetWidth returns the width the graphic is shown at (before animated scaling).
+func (sc *SVGClip) GetWidth() uint64 {
+	return sc.width
+}
+
+// GetHeight returns the height the graphic is shown at (before animated scaling).
+func (sc *SVGClip) GetHeight() uint64 {
+	return sc.height
+}
+
+// GetDuration returns how long the graphic is displayed.
+func (sc *SVGClip) GetDuration() float64 {
+	return sc.duration
+}
+
+// GetFps returns the frame rate the graphic is rendered at (default: 30).
+func (sc *SVGClip) GetFps() uint64 {
+	if sc.fps == 0 {
+		return defaultClipFps
+	}
+	return sc.fps
+}
+
+// GetPosition returns the overlay position.
+// Returns center position if none was explicitly set.
+func (sc *SVGClip) GetPosition() Position {
+	if sc.position.X == "" && sc.position.Y == "" {
+		return CenterPosition()
+	}
+	return sc.position
+}
+
+// GetFilename returns the SVG filename.
+func (sc *SVGClip) GetFilename() string {
+	return sc.filename
+}
+
+// Fps sets the frame rate the graphic is rendered at.
+func (sc *SVGClip) Fps(fps uint64) *SVGClip {
+	sc.fps = fps
+	return sc
+}
+
+// Env sets the render environment used to rasterize the graphic; its
+// TempDir holds the rasterized image.
+func (sc *SVGClip) Env(env *RenderEnv) *SVGClip {
+	sc.env = env
+	return sc
+}
+
+// SetPosition sets the overlay position used by CompositeClip.
+func (sc *SVGClip) SetPosition(position Position) *SVGClip {
+	sc.position = position
+	return sc
+}
+
+// SetAnimatedPosition sets the overlay position animation for CompositeClip.
+func (sc *SVGClip) SetAnimatedPosition(ap AnimatedPosition) *SVGClip {
+	sc.animatedPosition = &ap
+	return sc
+}
+
+// SetAnimatedOpacity sets the overlay opacity animation for CompositeClip.
+func (sc *SVGClip) SetAnimatedOpacity(a Animation) *SVGClip {
+	sc.animatedOpacity = &a
+	return sc
+}
+
+// SetAnimatedScale scales the graphic over time. Start/End are scale ratios
+// of the clip size (1.0 = 100%).
+func (sc *SVGClip) SetAnimatedScale(a Animation) *SVGClip {
+	sc.animatedScale = &a
+	return sc
+}
+
+// maxScale is the largest scale the graphic is shown at.
+func (sc *SVGClip) maxScale() float64 {
+	if sc.animatedScale == nil {
+		return 1
+	}
+	return math.Max(sc.animatedScale.Start, sc.animatedScale.End)
+}
+
+// toVideo rasterizes the graphic at its largest displayed size and shows it
+// like an ImageClip, scaling it down over time when animated.
+func (sc *SVGClip) toVideo() (*Video, error) {
+	if err := sc.Validate(); err != nil {
+		return nil, err
+	}
+	scale := sc.maxScale()
+	width := max(2, evenDimension(int(math.Round(float64(sc.width)*scale))))
+	height := max(2, evenDimension(int(math.Round(float64(sc.height)*scale))))
+	req, err := newClipRequest("svg", uint64(width), uint64(height), sc.duration, sc.GetFps(), sc.env)
+	if err != nil {
+		return nil, fmt.Errorf("SVGClip: %w", err)
+	}
+	png, err := rasterizeSVG(sc.filename, req)
+	if err != nil {
+		releaseClipTempDir(req.TempDir)
+		return nil, err
+	}
+	v, err := NewImageClip(png, req.Width, req.Height, sc.duration).Fps(req.Fps).toVideo()
+	if err != nil {
+		releaseClipTempDir(req.TempDir)
+		return nil, err
+	}
+	if sc.animatedScale != nil {
+		a := *sc.animatedScale
+		a.Start /= scale
+		a.End /= scale
+		if v, err = v.AnimatedScale(a); err != nil {
+			return nil, err
+		}
+	}
+	v.position = sc.position
+	v.animatedPosition = sc.animatedPosition
+	v.animatedOpacity = sc.animatedOpacity
+	return v, nil
+}
+
+// rasterizeSVG renders filename to a transparent PNG of the request size.
+func rasterizeSVG(filename string, req ClipRequest) (string, error) {
+	ffmpegPath, err := getFFmpegPath()
+	if err != nil {
+		return "", fmt.Errorf("SVGClip: failed to get ffmpeg path: %w", err)
+	}
+	output := filepath.Join(req.TempDir, "svg.png")
+	job := renderJob{
+		op: "SVGClip",
+		args: []string{
+			// Options of the librsvg decoder: render at the target size.
+			"-width", fmt.Sprint(req.Width), "-height", fmt.Sprint(req.Height), "-keep_ar", "0",
+			"-i", filename,
+			"-frames:v", "1", "-pix_fmt", "rgba",
+		},
+		output: output,
+		env:    req.Env,
+		silent: true,
+	}
+	if err := job.run(ffmpegPath); err != nil {
+		return "", fmt.Errorf("SVGClip: failed to rasterize '%s' (FFmpeg needs librsvg support): %w", filename, err)
+	}
+	return output, nil
+}
@@ -296,6 +296,32 @@ func TestAnimatedScaleWithFilters(t *testing.T) {
 	}
 }
 
+func TestFreezeAndZoom(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	cut, err := video.Cut(0, 3)
+	if err != nil {
+		t.Fatalf("Failed to cut: %v", err)
+	}
+	frozen, err := cut.FreezeAndZoom(1, 2, moviego.ZoomTarget{Zoom: 1.5, Curve: moviego.EaseInOut})
+	if err != nil {
+		t.Fatalf("Failed to freeze and zoom: %v", err)
+	}
+	outputPath := filepath.Join("output", "freeze_and_zoom.mp4")
+	if err := frozen.WriteVideo(moviego.VideoParameters{OutputPath: outputPath}); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+	out, err := moviego.NewVideoFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to load output: %v", err)
+	}
+	if math.Abs(out.GetDuration()-5) > 0.2 {
+		t.Errorf("expected duration ~5, got %f", out.GetDuration())
+	}
+}
+
 func TestMain(m *testing.M) {
 	_ = os.MkdirAll("output", 0755)
 	os.Exit(m.Run())
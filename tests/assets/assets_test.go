@@ -0,0 +1,111 @@
+package assets_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	moviego "github.com/YounesseAmhend/MovieGo"
+)
+
+func TestAssetManagerDeduplicates(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte("logo"))
+	}))
+	defer server.Close()
+
+	manager, err := moviego.NewAssetManager(filepath.Join(t.TempDir(), "cache"))
+	if err != nil {
+		t.Fatalf("Failed to create asset manager: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	paths := make([]string, 8)
+	for i := range paths {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			p, err := manager.Resolve(server.URL + "/logo.png")
+			if err != nil {
+				t.Errorf("Resolve: %v", err)
+			}
+			paths[i] = p
+		}(i)
+	}
+	wg.Wait()
+	if n := requests.Load(); n != 1 {
+		t.Errorf("expected 1 download, got %d", n)
+	}
+	for _, p := range paths[1:] {
+		if p != paths[0] {
+			t.Errorf("expected the same path, got %s and %s", paths[0], p)
+		}
+	}
+	if filepath.Ext(paths[0]) != ".png" {
+		t.Errorf("expected the .png extension to be kept, got %s", paths[0])
+	}
+
+	// Another URL with the same content shares the object.
+	other, err := manager.Resolve(server.URL + "/copy/logo.png")
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if other != paths[0] {
+		t.Errorf("expected content-addressed path %s, got %s", paths[0], other)
+	}
+
+	sum := sha256.Sum256([]byte("logo"))
+	if _, err := manager.ResolveVerified(server.URL+"/logo.png", hex.EncodeToString(sum[:])); err != nil {
+		t.Errorf("ResolveVerified: %v", err)
+	}
+	if _, err := manager.ResolveVerified(server.URL+"/other.png", "00"); err == nil {
+		t.Error("expected error for an invalid checksum")
+	}
+	if p, _ := manager.Resolve("local.ttf"); p != "local.ttf" {
+		t.Errorf("expected local paths unchanged, got %s", p)
+	}
+}
+
+func TestPrepareImage(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "large.png")
+	if err := exec.Command("ffmpeg", "-f", "lavfi", "-i", "color=c=red:s=1600x800", "-frames:v", "1", "-y", src).Run(); err != nil {
+		t.Fatalf("Failed to create image: %v", err)
+	}
+	manager, err := moviego.NewAssetManager(filepath.Join(t.TempDir(), "cache"))
+	if err != nil {
+		t.Fatalf("Failed to create asset manager: %v", err)
+	}
+
+	opts := moviego.ImageOptions{Width: 400, Height: 400, Format: moviego.ImageWebP}
+	p, err := manager.PrepareImage(src, opts)
+	if err != nil {
+		t.Fatalf("PrepareImage: %v", err)
+	}
+	if filepath.Ext(p) != ".webp" {
+		t.Errorf("expected a .webp variant, got %s", p)
+	}
+	out, err := exec.Command("ffprobe", "-v", "error", "-show_entries", "stream=width,height", "-of", "csv=p=0", p).Output()
+	if err != nil {
+		t.Fatalf("Failed to probe %s: %v", p, err)
+	}
+	if size := strings.TrimSpace(string(out)); size != "400,200" {
+		t.Errorf("expected 400x200 keeping the aspect ratio, got %s", size)
+	}
+	again, err := manager.PrepareImage(src, opts)
+	if err != nil || again != p {
+		t.Errorf("expected the cached variant %s, got %s (%v)", p, again, err)
+	}
+
+	if _, err := manager.PrepareImage(src, moviego.ImageOptions{Format: moviego.ImagePNG, Lossless: true}); err == nil {
+		t.Error("expected an error for lossless PNG")
+	}
+}
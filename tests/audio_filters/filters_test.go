@@ -31,6 +31,17 @@ func TestFilters(t *testing.T) {
 		{"treble_boost", func(a *moviego.Audio) (*moviego.Audio, error) { return a.TrebleBoost(5) }},
 		{"normalize", func(a *moviego.Audio) (*moviego.Audio, error) { return a.Normalize() }},
 		{"echo", func(a *moviego.Audio) (*moviego.Audio, error) { return a.Echo(500, 0.5) }},
+		{"volume_envelope", func(a *moviego.Audio) (*moviego.Audio, error) {
+			return a.SetVolumeEnvelope([]moviego.VolumeKeyframe{{Time: 0, Level: 1}, {Time: 1, Level: 0.2}, {Time: 2, Level: 1, Curve: moviego.EaseInOut}})
+		}},
+		{"to_mono", func(a *moviego.Audio) (*moviego.Audio, error) { return a.ToMono() }},
+		{"pan", func(a *moviego.Audio) (*moviego.Audio, error) { return a.Pan(1, 0.25) }},
+		{"swap_channels", func(a *moviego.Audio) (*moviego.Audio, error) { return a.SwapChannels() }},
+		{"select_channel", func(a *moviego.Audio) (*moviego.Audio, error) { return a.SelectChannel(0) }},
+		{"subclip", func(a *moviego.Audio) (*moviego.Audio, error) { return a.Subclip(1, 3) }},
+		{"effect_chain", func(a *moviego.Audio) (*moviego.Audio, error) {
+			return a.AddEffect(moviego.Atempo(1.5), moviego.PitchShift(-3), moviego.Echo(200, 0.3), moviego.Reverb(0.5, 0.3))
+		}},
 	}
 
 	for _, tt := range tests {
@@ -58,16 +69,89 @@ func TestFilters(t *testing.T) {
 				t.Fatalf("Failed to probe output %s: %v", outputPath, err)
 			}
 
-			if tt.name == "tempo" {
+			if tt.name == "tempo" || tt.name == "effect_chain" {
 				expectedDuration := audio.GetDuration() / 1.5
 				if math.Abs(out.GetDuration()-expectedDuration) > 0.5 {
 					t.Errorf("Expected duration ~%f, got %f", expectedDuration, out.GetDuration())
 				}
 			}
+			if tt.name == "subclip" && math.Abs(out.GetDuration()-2) > 0.1 {
+				t.Errorf("Expected duration ~2, got %f", out.GetDuration())
+			}
 		})
 	}
 }
 
+func TestVideoAudioFade(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	if _, err := video.AudioFadeOut(video.GetDuration() + 1); err == nil {
+		t.Fatalf("Expected an error for a fade longer than the video")
+	}
+
+	first, err := video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	first, err = first.AudioFadeIn(0.5)
+	if err != nil {
+		t.Fatalf("Failed to fade in: %v", err)
+	}
+	second, err := video.AudioFadeOut(1)
+	if err != nil {
+		t.Fatalf("Failed to fade out: %v", err)
+	}
+
+	joined, err := moviego.Concatenate([]moviego.Video{*first, *second})
+	if err != nil {
+		t.Fatalf("Failed to concatenate: %v", err)
+	}
+	outputPath := "output/test_video_audio_fade.mp4"
+	err = joined.WriteVideo(moviego.VideoParameters{
+		OutputPath:     outputPath,
+		SilentProgress: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to write video: %v", err)
+	}
+
+	out, err := moviego.NewAudioFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to probe output: %v", err)
+	}
+	if math.Abs(out.GetDuration()-joined.GetDuration()) > 0.5 {
+		t.Errorf("Expected duration ~%f, got %f", joined.GetDuration(), out.GetDuration())
+	}
+}
+
+func TestVideoVolumeEnvelope(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	if _, err := video.SetVolumeEnvelope([]moviego.VolumeKeyframe{{Time: 1, Level: 1}, {Time: 1, Level: 0}}); err == nil {
+		t.Fatalf("Expected an error for keyframes out of order")
+	}
+
+	cut, err := video.Cut(0, 3)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	ducked, err := cut.SetVolumeEnvelope([]moviego.VolumeKeyframe{{Time: 0.5, Level: 1}, {Time: 1, Level: 0.1}, {Time: 2, Level: 0.1}, {Time: 2.5, Level: 1}})
+	if err != nil {
+		t.Fatalf("Failed to set volume envelope: %v", err)
+	}
+	err = ducked.WriteVideo(moviego.VideoParameters{
+		OutputPath:     "output/test_video_volume_envelope.mp4",
+		SilentProgress: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to write video: %v", err)
+	}
+}
+
 func TestMain(m *testing.M) {
 	_ = os.MkdirAll("output", 0755)
 	
@@ -78,3 +162,149 @@ func TestMain(m *testing.M) {
 
 	os.Exit(m.Run())
 }
+
+func TestVideoAudioEffect(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	if _, err := video.AddAudioEffect(moviego.Atempo(1.25)); err == nil {
+		t.Error("Expected an error for an effect changing the audio duration")
+	}
+	if _, err := video.AddAudioEffect(moviego.PitchShift(30)); err == nil {
+		t.Error("Expected an error for an out of range pitch shift")
+	}
+
+	cut, err := video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	processed, err := cut.AddAudioEffect(moviego.PitchShift(4), moviego.Reverb(0.8, 0.5))
+	if err != nil {
+		t.Fatalf("Failed to add audio effects: %v", err)
+	}
+	outputPath := "output/test_video_audio_effect.mp4"
+	if err := processed.WriteVideo(moviego.VideoParameters{OutputPath: outputPath, SilentProgress: true}); err != nil {
+		t.Fatalf("Failed to write video: %v", err)
+	}
+	out, err := moviego.NewAudioFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to probe output: %v", err)
+	}
+	if math.Abs(out.GetDuration()-2) > 0.5 {
+		t.Errorf("Expected duration ~2, got %f", out.GetDuration())
+	}
+}
+
+func TestVideoChannels(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	cut, err := video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	mono, err := cut.ToMono()
+	if err != nil {
+		t.Fatalf("Failed to mix down to mono: %v", err)
+	}
+	outputPath := "output/test_video_mono.mp4"
+	if err := mono.WriteVideo(moviego.VideoParameters{OutputPath: outputPath, SilentProgress: true}); err != nil {
+		t.Fatalf("Failed to write video: %v", err)
+	}
+	out, err := moviego.NewAudioFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to probe output: %v", err)
+	}
+	if out.GetChannels() != 1 {
+		t.Errorf("Expected 1 channel, got %d", out.GetChannels())
+	}
+
+	if _, err := cut.Pan(-1, 1); err == nil {
+		t.Error("Expected an error for a negative gain")
+	}
+	if _, err := cut.SelectChannel(8); err == nil {
+		t.Error("Expected an error for a missing channel")
+	}
+}
+
+func TestVideoAudioOffset(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	cut, err := video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	for _, offset := range []float64{0.5, -0.5} {
+		shifted, err := cut.SetAudioOffset(offset)
+		if err != nil {
+			t.Fatalf("Failed to offset audio by %.1f: %v", offset, err)
+		}
+		outputPath := fmt.Sprintf("output/test_audio_offset_%.1f.mp4", offset)
+		if err := shifted.WriteVideo(moviego.VideoParameters{OutputPath: outputPath, SilentProgress: true}); err != nil {
+			t.Fatalf("Failed to write video: %v", err)
+		}
+		out, err := moviego.NewAudioFile(outputPath)
+		if err != nil {
+			t.Fatalf("Failed to probe output: %v", err)
+		}
+		// The audio keeps the length of the video.
+		if math.Abs(out.GetDuration()-2) > 0.15 {
+			t.Errorf("Offset %.1f: expected audio duration ~2, got %f", offset, out.GetDuration())
+		}
+	}
+
+	if _, err := cut.SetAudioOffset(3); err == nil {
+		t.Error("Expected an error for an offset longer than the video")
+	}
+}
+
+func TestDetectBeats(t *testing.T) {
+	// A click every half second.
+	clicks := "output/clicks.wav"
+	cmd := exec.Command("ffmpeg", "-y", "-f", "lavfi", "-i", "aevalsrc='if(lt(mod(t,0.5),0.02),sin(2*PI*1000*t),0)':d=4", clicks)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to create click track: %v\n%s", err, out)
+	}
+	beats, err := moviego.DetectBeats(clicks)
+	if err != nil {
+		t.Fatalf("Failed to detect beats: %v", err)
+	}
+	if len(beats) < 7 || len(beats) > 8 {
+		t.Fatalf("Expected ~8 beats, got %v", beats)
+	}
+	for _, beat := range beats {
+		if off := math.Abs(beat - math.Round(beat*2)/2); off > 0.05 {
+			t.Errorf("Beat %f is %f s off the click grid", beat, off)
+		}
+	}
+
+	colors := []moviego.Clip{
+		moviego.NewColorClip("red", 320, 240, 5),
+		moviego.NewColorClip("green", 320, 240, 5),
+		moviego.NewColorClip("blue", 320, 240, 5),
+	}
+	slideshow, err := moviego.ConcatenateOnBeats(beats[1:], colors...)
+	if err != nil {
+		t.Fatalf("Failed to cut on beats: %v", err)
+	}
+	if math.Abs(slideshow.GetDuration()-beats[3]) > 0.05 {
+		t.Errorf("Expected the slideshow to end on beat %f, got %f", beats[3], slideshow.GetDuration())
+	}
+	if _, err := moviego.ConcatenateOnBeats(beats[:1], colors...); err == nil {
+		t.Error("Expected an error with fewer beats than clips")
+	}
+}
+
+func TestAudioSubclipInvalid(t *testing.T) {
+	audio, err := moviego.NewAudioFile(common.TestAudioPath)
+	if err != nil {
+		t.Fatalf("Failed to load audio: %v", err)
+	}
+	if _, err := audio.Subclip(3, 1); err == nil {
+		t.Error("Expected an error for an inverted range")
+	}
+}
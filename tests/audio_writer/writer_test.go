@@ -2,8 +2,11 @@ package audio_writer_test
 
 import (
 	"fmt"
+	"math"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	moviego "github.com/YounesseAmhend/MovieGo"
@@ -55,6 +58,37 @@ func TestWriteWithProgress(t *testing.T) {
 	}
 }
 
+func TestWriteWithRenderEnv(t *testing.T) {
+	audio, err := moviego.AudioFile(common.TestAudioPath)
+	if err != nil {
+		t.Fatalf("Failed to load audio: %v", err)
+	}
+
+	tempDir := "output/render_env_tmp"
+	outputPath := "output/test_write_render_env.mp3"
+	err = audio.Write(moviego.AudioParameters{
+		OutputPath:     outputPath,
+		Codec:          moviego.AudioCodecMP3,
+		SilentProgress: true,
+		Env:            &moviego.RenderEnv{MaxThreads: 1, TempDir: tempDir},
+	})
+	if err != nil {
+		t.Fatalf("Failed to write audio: %v", err)
+	}
+	if _, err := os.Stat(tempDir); err != nil {
+		t.Errorf("expected temp dir to be created: %v", err)
+	}
+
+	err = audio.Write(moviego.AudioParameters{
+		OutputPath:     outputPath,
+		SilentProgress: true,
+		Env:            &moviego.RenderEnv{IOLevel: 9},
+	})
+	if err == nil {
+		t.Error("expected error for invalid IOLevel")
+	}
+}
+
 func TestMain(m *testing.M) {
 	_ = os.MkdirAll("output", 0755)
 	
@@ -74,3 +108,473 @@ func TestMain(m *testing.M) {
 	
 	os.Exit(code)
 }
+
+func TestNewAudioFile(t *testing.T) {
+	audio, err := moviego.NewAudioFile(common.TestAudioPath)
+	if err != nil {
+		t.Fatalf("Failed to load audio: %v", err)
+	}
+	if audio.GetCodec() != "mp3" || audio.GetSampleRate() == 0 || audio.GetDuration() <= 0 {
+		t.Fatalf("Expected mp3 metadata, got codec=%q, sample rate=%d, duration=%.2f", audio.GetCodec(), audio.GetSampleRate(), audio.GetDuration())
+	}
+	if alias, err := moviego.AudioFile(common.TestAudioPath); err != nil || alias.GetDuration() != audio.GetDuration() {
+		t.Errorf("Expected AudioFile to match NewAudioFile, got %v", err)
+	}
+
+	// WAV, FLAC and raw AAC streams may only report the duration of the
+	// whole file, which NewAudioFile falls back to.
+	dir := t.TempDir()
+	for _, name := range []string{"formats.wav", "formats.flac", "formats.aac"} {
+		path := filepath.Join(dir, name)
+		if err := audio.Write(moviego.AudioParameters{OutputPath: path, SilentProgress: true}); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		loaded, err := moviego.NewAudioFile(path)
+		if err != nil {
+			t.Fatalf("Failed to load %s: %v", name, err)
+		}
+		if math.Abs(loaded.GetDuration()-audio.GetDuration()) > 0.2 || loaded.GetSampleRate() == 0 {
+			t.Errorf("%s: expected ~%.2fs of audio, got %.2fs at %d Hz", name, audio.GetDuration(), loaded.GetDuration(), loaded.GetSampleRate())
+		}
+	}
+
+	if _, err := moviego.NewAudioFile(""); err == nil {
+		t.Error("Expected an error for an empty filename")
+	}
+	if _, err := moviego.NewAudioFile(filepath.Join(dir, "missing.wav")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+}
+
+func TestVideoWriteAudio(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	cut, err := video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+
+	for ext, codec := range map[string]string{".mp3": "mp3", ".m4a": "aac", ".wav": "pcm_s16le", ".flac": "flac"} {
+		outputPath := "output/test_video_write_audio" + ext
+		progressCalled := false
+		err := cut.WriteAudio(moviego.AudioParameters{
+			OutputPath: outputPath,
+			OnProgress: func(moviego.Progress) { progressCalled = true },
+		})
+		if err != nil {
+			t.Fatalf("Failed to write %s: %v", ext, err)
+		}
+		if !progressCalled {
+			t.Errorf("%s: progress callback was not called", ext)
+		}
+		out, err := moviego.NewAudioFile(outputPath)
+		if err != nil {
+			t.Fatalf("Failed to probe %s: %v", outputPath, err)
+		}
+		if out.GetCodec() != codec {
+			t.Errorf("%s: expected codec %s, got %s", ext, codec, out.GetCodec())
+		}
+		if math.Abs(out.GetDuration()-2) > 0.3 {
+			t.Errorf("%s: expected duration ~2, got %f", ext, out.GetDuration())
+		}
+	}
+}
+
+func TestGetAudioSamples(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	samples, sampleRate, err := video.GetAudioSamples(1, 2)
+	if err != nil {
+		t.Fatalf("Failed to get samples: %v", err)
+	}
+	if sampleRate == 0 {
+		t.Fatalf("Expected a sample rate")
+	}
+	if got := float64(len(samples)) / float64(sampleRate); math.Abs(got-1) > 0.05 {
+		t.Errorf("Expected 1s of samples, got %.4fs", got)
+	}
+	for i, s := range samples {
+		if s < -1 || s > 1 {
+			t.Fatalf("Sample %d out of range: %f", i, s)
+		}
+	}
+
+	if _, _, err := video.GetAudioSamples(2, 1); err == nil {
+		t.Errorf("Expected an error for an empty range")
+	}
+}
+
+func TestAudioPeaks(t *testing.T) {
+	audio, err := moviego.NewAudioFile(common.TestAudioPath)
+	if err != nil {
+		t.Fatalf("Failed to load audio: %v", err)
+	}
+	peaks, err := audio.Peaks(512)
+	if err != nil {
+		t.Fatalf("Failed to compute peaks: %v", err)
+	}
+	if len(peaks.Min) == 0 || len(peaks.Min) != len(peaks.Max) {
+		t.Fatalf("Expected matching min/max arrays, got %d and %d", len(peaks.Min), len(peaks.Max))
+	}
+	if got := float64(len(peaks.Max)) / peaks.PixelsPerSecond(); math.Abs(got-audio.GetDuration()) > 0.1 {
+		t.Errorf("Expected peaks covering %.4fs, got %.4fs", audio.GetDuration(), got)
+	}
+	for i := range peaks.Min {
+		if peaks.Min[i] > peaks.Max[i] || peaks.Min[i] < -1 || peaks.Max[i] > 1 {
+			t.Fatalf("Invalid peak %d: min=%f max=%f", i, peaks.Min[i], peaks.Max[i])
+		}
+	}
+
+	if _, err := audio.Peaks(0); err == nil {
+		t.Errorf("Expected an error for samplesPerPixel=0")
+	}
+}
+
+func TestLevels(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	cut, err := video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+
+	var readings []moviego.Levels
+	var progressLevels bool
+	err = cut.WriteVideo(moviego.VideoParameters{
+		OutputPath: "output/test_levels.mp4",
+		OnLevels:   func(l moviego.Levels) { readings = append(readings, l) },
+		OnProgress: func(p moviego.Progress) { progressLevels = progressLevels || p.Levels != nil },
+	})
+	if err != nil {
+		t.Fatalf("Failed to write video: %v", err)
+	}
+	// One reading per 100 ms of audio.
+	if len(readings) < 15 {
+		t.Fatalf("Expected ~20 readings, got %d", len(readings))
+	}
+	if last := readings[len(readings)-1]; last.Time < 1.5 || len(last.Peak) == 0 {
+		t.Errorf("Unexpected last reading: %+v", last)
+	}
+	if !progressLevels {
+		t.Errorf("Expected levels in the progress events")
+	}
+
+	var monitored int
+	stop := make(chan struct{})
+	defer close(stop)
+	err = moviego.MonitorLevels(common.TestAudioPath, moviego.MonitorOptions{
+		OnLevels: func(moviego.Levels) { monitored++ },
+	}, stop)
+	if err != nil {
+		t.Fatalf("Failed to monitor levels: %v", err)
+	}
+	if monitored == 0 {
+		t.Errorf("Expected readings from MonitorLevels")
+	}
+}
+
+func TestDetectSilence(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	cut, err := video.Cut(0, 3)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	// Silence the middle second, then find it again.
+	audio := cut.GetAudio()
+	muted, err := audio.MuteRanges([]moviego.TimeRange{{Start: 1, End: 2}})
+	if err != nil {
+		t.Fatalf("Failed to mute: %v", err)
+	}
+	ranges, err := muted.DetectSilence(-50, 0.5)
+	if err != nil {
+		t.Fatalf("Failed to detect silence: %v", err)
+	}
+	found := false
+	for _, r := range ranges {
+		if math.Abs(r.Start-1) < 0.1 && math.Abs(r.End-2) < 0.1 {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected a silence from 1s to 2s, got %+v", ranges)
+	}
+
+	if _, err := cut.DetectSilence(-50, 0.5); err != nil {
+		t.Errorf("Failed to detect silence of the video: %v", err)
+	}
+	if _, err := cut.DetectSilence(10, 0.5); err == nil {
+		t.Errorf("Expected an error for a positive threshold")
+	}
+}
+
+func TestVideoAudioEncoding(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	cut, err := video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+
+	outputPath := "output/test_audio_encoding.mkv"
+	err = cut.WriteVideo(moviego.VideoParameters{
+		OutputPath:      outputPath,
+		AudioCodec:      moviego.AudioCodecOpus,
+		AudioBitrate:    "96k",
+		AudioSampleRate: 48000,
+		AudioChannels:   1,
+		SilentProgress:  true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to write video: %v", err)
+	}
+	out, err := moviego.NewVideoFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to probe %s: %v", outputPath, err)
+	}
+	audio := out.GetAudio()
+	if audio.GetCodec() != "opus" || audio.GetSampleRate() != 48000 || audio.GetChannels() != 1 {
+		t.Errorf("Expected mono 48 kHz opus, got codec=%s rate=%d channels=%d", audio.GetCodec(), audio.GetSampleRate(), audio.GetChannels())
+	}
+
+	err = cut.WriteVideo(moviego.VideoParameters{
+		OutputPath:     "output/test_audio_encoding_flac.mkv",
+		AudioCodec:     moviego.AudioCodecFLAC,
+		AudioBitrate:   "320k",
+		SilentProgress: true,
+	})
+	if err == nil {
+		t.Error("Expected an error for a bitrate with a lossless codec")
+	}
+}
+
+func TestGeneratedAudio(t *testing.T) {
+	tone, err := moviego.NewToneClip(1000, 1)
+	if err != nil {
+		t.Fatalf("Failed to create tone: %v", err)
+	}
+	outputPath := "output/test_tone.wav"
+	if err := tone.Write(moviego.AudioParameters{OutputPath: outputPath, SilentProgress: true}); err != nil {
+		t.Fatalf("Failed to write tone: %v", err)
+	}
+	out, err := moviego.NewAudioFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to probe tone: %v", err)
+	}
+	if math.Abs(out.GetDuration()-1) > 0.05 {
+		t.Errorf("Expected tone duration ~1, got %f", out.GetDuration())
+	}
+	peaks, err := tone.Peaks(4800)
+	if err != nil {
+		t.Fatalf("Failed to compute tone peaks: %v", err)
+	}
+	if peak := peaks.Max[0]; math.Abs(peak-0.125) > 0.01 {
+		t.Errorf("Expected a -18 dBFS tone, got peak %f", peak)
+	}
+
+	silence, err := moviego.NewSilenceClip(0.5)
+	if err != nil {
+		t.Fatalf("Failed to create silence: %v", err)
+	}
+	peaks, err = silence.Peaks(4800)
+	if err != nil {
+		t.Fatalf("Failed to compute silence peaks: %v", err)
+	}
+	for i, peak := range peaks.Max {
+		if peak != 0 {
+			t.Fatalf("Expected silence, got peak %f at pixel %d", peak, i)
+		}
+	}
+
+	if _, err := moviego.NewToneClip(0, 1); err == nil {
+		t.Error("Expected an error for a zero frequency")
+	}
+	if _, err := moviego.NewSilenceClip(0); err == nil {
+		t.Error("Expected an error for a zero duration")
+	}
+}
+
+func TestWriteVideoAudioOnly(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	cut, err := video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+
+	outputPath := "output/test_write_video_audio_only.m4a"
+	err = cut.WriteVideo(moviego.VideoParameters{OutputPath: outputPath, AudioBitrate: "96k", SilentProgress: true})
+	if err != nil {
+		t.Fatalf("Failed to write audio-only output: %v", err)
+	}
+	out, err := exec.Command("ffprobe", "-v", "error", "-show_entries", "stream=codec_type", "-of", "csv=p=0", outputPath).Output()
+	if err != nil {
+		t.Fatalf("Failed to probe %s: %v", outputPath, err)
+	}
+	if streams := string(out); streams != "audio\n" {
+		t.Errorf("Expected a single audio stream, got %q", streams)
+	}
+
+	err = cut.WriteVideo(moviego.VideoParameters{OutputPath: "output/test_audio_only_invalid.mp3", Fps: 30})
+	if err == nil {
+		t.Error("Expected an error for video settings with an audio-only output")
+	}
+}
+
+func TestWritePodcast(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	cut, err := video.Cut(0, 4)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	cover := "output/test_podcast_cover.png"
+	if err := exec.Command("ffmpeg", "-f", "lavfi", "-i", "color=c=blue:s=300x300", "-frames:v", "1", "-y", cover).Run(); err != nil {
+		t.Fatalf("Failed to create cover art: %v", err)
+	}
+
+	for _, outputPath := range []string{"output/test_podcast.mp3", "output/test_podcast.m4a"} {
+		err = cut.WritePodcast(outputPath, moviego.PodcastOptions{
+			Title:          "Episode 1",
+			Author:         "MovieGo",
+			Show:           "The Show",
+			Episode:        1,
+			CoverArt:       cover,
+			Chapters:       []moviego.PodcastChapter{{Start: 0, Title: "Intro"}, {Start: 2, Title: "Main; part"}},
+			SilentProgress: true,
+		})
+		if err != nil {
+			t.Fatalf("Failed to write %s: %v", outputPath, err)
+		}
+		out, err := exec.Command("ffprobe", "-v", "error", "-show_entries", "format_tags=title,album:chapter_tags=title",
+			"-of", "csv=p=0", outputPath).Output()
+		if err != nil {
+			t.Fatalf("Failed to probe %s: %v", outputPath, err)
+		}
+		probe := string(out)
+		for _, want := range []string{"Episode 1", "The Show", "Intro", "Main; part"} {
+			if !strings.Contains(probe, want) {
+				t.Errorf("%s: expected %q in tags and chapters, got %q", outputPath, want, probe)
+			}
+		}
+	}
+
+	if err := cut.WritePodcast("output/test_podcast.wav", moviego.PodcastOptions{}); err == nil {
+		t.Error("Expected an error for a non-podcast format")
+	}
+	err = cut.WritePodcast("output/test_podcast_bad.mp3", moviego.PodcastOptions{
+		Chapters: []moviego.PodcastChapter{{Start: 2}, {Start: 1}},
+	})
+	if err == nil {
+		t.Error("Expected an error for chapters out of order")
+	}
+}
+
+func TestVideoAudioTracks(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	cut, err := video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	commentary, err := moviego.NewToneClip(440, 2)
+	if err != nil {
+		t.Fatalf("Failed to create tone: %v", err)
+	}
+
+	outputPath := "output/test_audio_tracks.mp4"
+	err = cut.WriteVideo(moviego.VideoParameters{
+		OutputPath:    outputPath,
+		AudioLanguage: "eng",
+		AudioTitle:    "Original",
+		AudioTracks: []moviego.AudioTrack{
+			{Audio: commentary, Language: "fra", Title: "Commentary"},
+		},
+		SilentProgress: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to write video: %v", err)
+	}
+	out, err := exec.Command("ffprobe", "-v", "error", "-select_streams", "a", "-show_entries", "stream_tags=language,title",
+		"-of", "csv=p=0", outputPath).Output()
+	if err != nil {
+		t.Fatalf("Failed to probe %s: %v", outputPath, err)
+	}
+	if tracks := strings.Fields(string(out)); len(tracks) != 2 || !strings.Contains(tracks[0], "eng") || !strings.Contains(tracks[1], "fra") {
+		t.Errorf("Expected eng and fra audio tracks, got %q", out)
+	}
+
+	err = cut.WriteVideo(moviego.VideoParameters{
+		OutputPath:  "output/test_audio_tracks_copy.mp4",
+		StreamCopy:  true,
+		AudioTracks: []moviego.AudioTrack{{Audio: commentary}},
+	})
+	if err == nil {
+		t.Error("Expected an error for audio tracks with StreamCopy")
+	}
+}
+
+func TestAudioReport(t *testing.T) {
+	tone, err := moviego.NewToneClip(440, 2)
+	if err != nil {
+		t.Fatalf("Failed to create tone: %v", err)
+	}
+	report, err := tone.Report()
+	if err != nil {
+		t.Fatalf("Failed to measure tone: %v", err)
+	}
+	if math.Abs(report.Peak+18) > 0.5 || report.Clipped() {
+		t.Errorf("Expected an unclipped -18 dBFS peak, got %+v", report)
+	}
+
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	cut, err := video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	loud, err := tone.Volume(20)
+	if err != nil {
+		t.Fatalf("Failed to boost tone: %v", err)
+	}
+	rejectClipping := func(r moviego.AudioReport) error {
+		if r.Clipped() {
+			return fmt.Errorf("%d clipped samples", r.ClippedSamples)
+		}
+		return nil
+	}
+	err = cut.SetAudio(*loud).WriteVideo(moviego.VideoParameters{
+		OutputPath:     "output/test_audio_report_clipped.mp4",
+		OnAudioReport:  rejectClipping,
+		SilentProgress: true,
+	})
+	if err == nil {
+		t.Error("Expected the clipped mix to be rejected")
+	}
+	err = cut.WriteVideo(moviego.VideoParameters{
+		OutputPath:     "output/test_audio_report.mp4",
+		OnAudioReport:  rejectClipping,
+		SilentProgress: true,
+	})
+	if err != nil {
+		t.Errorf("Expected the original mix to pass: %v", err)
+	}
+}
@@ -0,0 +1,60 @@
+package bench_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/YounesseAmhend/MovieGo/bench"
+)
+
+func TestReportBestThreads(t *testing.T) {
+	report := &bench.Report{
+		Results: []bench.Result{
+			{Workload: bench.WorkloadEncode, Threads: 1, FPS: 40},
+			{Workload: bench.WorkloadEncode, Threads: 2, FPS: 75},
+			{Workload: bench.WorkloadEncode, Threads: 4, FPS: 118},
+			{Workload: bench.WorkloadEncode, Threads: 8, FPS: 120},
+			{Workload: bench.WorkloadRawPipe, Threads: 1, FPS: 300},
+		},
+	}
+	if got := report.BestThreads(bench.WorkloadEncode); got != 4 {
+		t.Errorf("expected 4 threads, got %d", got)
+	}
+	if got := report.BestThreads(bench.WorkloadFilterGraph); got != 0 {
+		t.Errorf("expected 0 for a workload that was not run, got %d", got)
+	}
+	if got := report.RecommendedThreads(); got != 4 {
+		t.Errorf("expected recommended 4 threads, got %d", got)
+	}
+}
+
+func TestReportSaveLoad(t *testing.T) {
+	report := &bench.Report{
+		CPUs:    8,
+		Results: []bench.Result{{Workload: bench.WorkloadEncode, Threads: 4, FPS: 118}},
+	}
+	path := filepath.Join(t.TempDir(), "bench_report.json")
+	if err := report.Save(path); err != nil {
+		t.Fatalf("Failed to save report: %v", err)
+	}
+	loaded, err := bench.LoadReport(path)
+	if err != nil {
+		t.Fatalf("Failed to load report: %v", err)
+	}
+	if loaded.CPUs != 8 || loaded.RecommendedThreads() != 4 {
+		t.Errorf("unexpected report after round trip: %+v", loaded)
+	}
+}
+
+func TestRun(t *testing.T) {
+	report, err := bench.Run(bench.Options{Width: 320, Height: 240, Duration: 1, ThreadCounts: []uint16{1, 2}})
+	if err != nil {
+		t.Fatalf("Failed to run benchmark: %v", err)
+	}
+	if len(report.Results) != 6 {
+		t.Errorf("expected 6 results, got %d", len(report.Results))
+	}
+	if report.RecommendedThreads() == 0 {
+		t.Error("expected a recommended thread count")
+	}
+}
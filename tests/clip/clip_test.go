@@ -0,0 +1,416 @@
+package clip_test
+
+import (
+	"image"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	moviego "github.com/YounesseAmhend/MovieGo"
+	"github.com/YounesseAmhend/MovieGo/tests/common"
+)
+
+func TestConcatenateClipsWithColor(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	cut, err := video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut: %v", err)
+	}
+	card := moviego.NewColorClip("black", video.GetWidth(), video.GetHeight(), 1).Fps(video.GetFps())
+
+	result, err := moviego.ConcatenateClips(card, cut)
+	if err != nil {
+		t.Fatalf("Failed to concatenate clips: %v", err)
+	}
+	outputPath := filepath.Join("output", "concatenate_color_clip.mp4")
+	if err := result.WriteVideo(moviego.VideoParameters{OutputPath: outputPath}); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+	out, err := moviego.NewVideoFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to load output: %v", err)
+	}
+	if math.Abs(out.GetDuration()-3) > 0.2 {
+		t.Errorf("expected duration ~3, got %f", out.GetDuration())
+	}
+}
+
+func TestTitleClip(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	cut, err := video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut: %v", err)
+	}
+	if _, err := moviego.CompositeClips(cut, moviego.NewTitleClip(moviego.TextClip{}, 320, 80, 1)); err == nil {
+		t.Error("expected error for a title without text")
+	}
+
+	title := moviego.NewTitleClip(moviego.TextClip{Text: "Chapter 1", FontSize: 48}, 320, 80, 2).
+		Fps(video.GetFps()).
+		SetPosition(moviego.Position{X: "20", Y: "20"})
+	result, err := moviego.CompositeClips(cut, title)
+	if err != nil {
+		t.Fatalf("Failed to composite title: %v", err)
+	}
+	outputPath := filepath.Join("output", "title_clip.mp4")
+	if err := result.WriteVideo(moviego.VideoParameters{OutputPath: outputPath, SilentProgress: true}); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+	out, err := moviego.NewVideoFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to load output: %v", err)
+	}
+	if out.GetWidth() != video.GetWidth() || math.Abs(out.GetDuration()-2) > 0.2 {
+		t.Errorf("expected the title over the %dx%d video for ~2s, got %dx%d for %f", video.GetWidth(), video.GetHeight(), out.GetWidth(), out.GetHeight(), out.GetDuration())
+	}
+}
+
+func TestClipValidation(t *testing.T) {
+	if _, err := moviego.ConcatenateClips(moviego.NewColorClip("", 320, 240, 1)); err == nil {
+		t.Error("expected error for empty color")
+	}
+	if _, err := moviego.CompositeClips(moviego.NewImageClip("logo.png", 0, 0, 1)); err == nil {
+		t.Error("expected error for zero-size image")
+	}
+	if _, err := moviego.NewTimeline().AddClip(nil); err == nil {
+		t.Error("expected error for nil clip")
+	}
+}
+
+func TestOverlayOrder(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	cut, err := video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut: %v", err)
+	}
+	red := moviego.NewColorClip("red", 160, 120, 2).Fps(video.GetFps())
+	blue := moviego.NewColorClip("blue", 160, 120, 2).Fps(video.GetFps())
+	green := moviego.NewColorClip("green", 160, 120, 2).Fps(video.GetFps())
+	cut.AddOverlay(red, 1).AddOverlay(blue, 0).AddOverlay(green, 1)
+
+	overlays := cut.GetOverlays()
+	want := []moviego.Clip{blue, red, green}
+	if len(overlays) != len(want) {
+		t.Fatalf("expected %d overlays, got %d", len(want), len(overlays))
+	}
+	for i, o := range overlays {
+		if o.Clip != want[i] {
+			t.Errorf("overlay %d: expected %v, got %v", i, want[i], o.Clip)
+		}
+	}
+
+	outputPath := filepath.Join("output", "overlay_order.mp4")
+	if err := cut.WriteVideo(moviego.VideoParameters{OutputPath: outputPath}); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+}
+
+func TestGeneratePlayerAssets(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	dir := filepath.Join("output", "player_assets")
+	assets, err := moviego.GeneratePlayerAssets(video, dir, moviego.PlayerAssetsOptions{ThumbnailInterval: 1, Peaks: 100})
+	if err != nil {
+		t.Fatalf("Failed to generate player assets: %v", err)
+	}
+	for _, name := range []string{assets.Poster, assets.Sprite, assets.Thumbnails, "metadata.json"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("expected %s: %v", name, err)
+		}
+	}
+	if video.HasAudio() && assets.Waveform == "" {
+		t.Error("expected a waveform for a video with audio")
+	}
+}
+
+func TestRenderVariants(t *testing.T) {
+	set := moviego.VariantSet{
+		Dimensions: []moviego.VariantDimension{
+			{Name: "cta", Values: []string{"red", "blue"}},
+			{Name: "thumb", Values: []string{"0.5"}},
+		},
+		ThumbnailAt: "thumb",
+	}
+	build := func(v moviego.Variant) (*moviego.Video, error) {
+		return moviego.ConcatenateClips(moviego.NewColorClip(moviego.Color(v.Get("cta")), 320, 240, 1).Fps(30))
+	}
+
+	bad := set
+	bad.List = []map[string]string{{"cta": "green", "thumb": "0.5"}}
+	if _, err := moviego.RenderVariants(bad, build, moviego.VideoParameters{OutputPath: "output/promo.mp4"}); err == nil {
+		t.Error("expected error for a value outside its dimension")
+	}
+
+	outputs, err := moviego.RenderVariants(set, build, moviego.VideoParameters{OutputPath: "output/promo.mp4", SilentProgress: true})
+	if err != nil {
+		t.Fatalf("RenderVariants failed: %v", err)
+	}
+	want := []string{"output/promo.cta-red.thumb-0-5.mp4", "output/promo.cta-blue.thumb-0-5.mp4"}
+	if len(outputs) != len(want) {
+		t.Fatalf("expected %d outputs, got %d", len(want), len(outputs))
+	}
+	for i, out := range outputs {
+		if out.Path != want[i] {
+			t.Errorf("expected output %s, got %s", want[i], out.Path)
+		}
+		for _, path := range []string{out.Path, out.Thumbnail} {
+			if _, err := os.Stat(path); err != nil {
+				t.Errorf("missing output: %v", err)
+			}
+		}
+	}
+}
+
+func TestMain(m *testing.M) {
+	_ = os.MkdirAll("output", 0755)
+	os.Exit(m.Run())
+}
+
+type stillProvider struct{}
+
+func (stillProvider) Name() string { return "still" }
+
+func (stillProvider) Provide(source string, req moviego.ClipRequest) (moviego.ClipSource, error) {
+	return moviego.ClipSource{Kind: moviego.SourceImage, Path: filepath.Join(req.TempDir, source+".png")}, nil
+}
+
+func TestClipProvider(t *testing.T) {
+	if err := moviego.RegisterClipProvider(stillProvider{}); err != nil {
+		t.Fatalf("Failed to register provider: %v", err)
+	}
+	if err := moviego.RegisterClipProvider(stillProvider{}); err == nil {
+		t.Error("Expected an error registering a provider twice")
+	}
+
+	req := moviego.ClipRequest{Width: 320, Height: 180, Duration: 2, TempDir: filepath.Join("output", "provider")}
+	clip, err := moviego.OpenClip("still:logo", req)
+	if err != nil {
+		t.Fatalf("Failed to open clip: %v", err)
+	}
+	if clip.GetWidth() != 320 || clip.GetHeight() != 180 || clip.GetDuration() != 2 {
+		t.Errorf("Unexpected clip %dx%d, %.2fs", clip.GetWidth(), clip.GetHeight(), clip.GetDuration())
+	}
+
+	if _, err := moviego.OpenClip("missing:logo", req); err == nil {
+		t.Error("Expected an error for an unknown provider")
+	}
+	if _, err := moviego.OpenClip("logo.png", req); err == nil {
+		t.Error("Expected an error for a source without provider")
+	}
+}
+
+func TestSuggestPosterFrame(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	calls := 0
+	poster, err := video.SuggestPosterFrame("output/poster.jpg", moviego.PosterFrameOptions{
+		Candidates: 6,
+		FaceScore:  func(image.Image) float64 { calls++; return 0 },
+	})
+	if err != nil {
+		t.Fatalf("Failed to suggest poster frame: %v", err)
+	}
+	if len(poster.Candidates) == 0 || calls != len(poster.Candidates) {
+		t.Errorf("expected the face hook to run for each candidate (candidates=%d, calls=%d)", len(poster.Candidates), calls)
+	}
+	if poster.Time <= 0 || poster.Time >= video.GetDuration() {
+		t.Errorf("poster time %.4f outside the video", poster.Time)
+	}
+	if _, err := os.Stat(poster.Path); err != nil {
+		t.Errorf("poster image not written: %v", err)
+	}
+}
+
+func TestVerifyIntegrity(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	report, err := video.VerifyIntegrity(nil)
+	if err != nil {
+		t.Fatalf("Failed to verify: %v", err)
+	}
+	if !report.OK() {
+		t.Errorf("expected the test video to decode cleanly, got %+v", report.Errors)
+	}
+
+	// Overwrite a block in the middle of the media data.
+	data, err := os.ReadFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to read video: %v", err)
+	}
+	for i := len(data) / 2; i < len(data)/2+32*1024 && i < len(data); i++ {
+		data[i] = byte(i * 31)
+	}
+	corruptPath := filepath.Join("output", "corrupt.mp4")
+	if err := os.WriteFile(corruptPath, data, 0644); err != nil {
+		t.Fatalf("Failed to write corrupt copy: %v", err)
+	}
+	corrupt, err := moviego.NewVideoFile(corruptPath)
+	if err != nil {
+		t.Fatalf("Failed to load corrupt copy: %v", err)
+	}
+	report, err = corrupt.VerifyIntegrity(nil)
+	if err != nil {
+		t.Fatalf("Failed to verify: %v", err)
+	}
+	if report.OK() {
+		t.Error("expected decode errors for the corrupt copy")
+	}
+}
+
+func TestParseCodecTypes(t *testing.T) {
+	if c, err := moviego.ParseCodec(" H.264 "); err != nil || c != moviego.CodecH264 {
+		t.Errorf("ParseCodec(h.264) = %q, %v", c, err)
+	}
+	if c, err := moviego.ParseCodec("HEVC_NVENC"); err != nil || c != moviego.CodecHevcNvenc {
+		t.Errorf("ParseCodec(hevc_nvenc) = %q, %v", c, err)
+	}
+	if _, err := moviego.ParseCodec("h266"); err == nil {
+		t.Error("expected error for unknown codec")
+	}
+	if c, err := moviego.ParseAudioCodec("opus"); err != nil || c != moviego.AudioCodecOpus {
+		t.Errorf("ParseAudioCodec(opus) = %q, %v", c, err)
+	}
+	if p, err := moviego.ParsePreset("VeryFast"); err != nil || p != moviego.VeryFast {
+		t.Errorf("ParsePreset(VeryFast) = %q, %v", p, err)
+	}
+	if _, err := moviego.ParsePreset("hq"); err == nil {
+		t.Error("expected error for hardware-only preset")
+	}
+	if f, err := moviego.ParsePixelFormat("yuv420p"); err != nil || f != moviego.PixelFormatYUV420P {
+		t.Errorf("ParsePixelFormat(yuv420p) = %q, %v", f, err)
+	}
+
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	if err := video.Preset("turbo").Validate(); err == nil {
+		t.Error("expected Validate to report unknown preset")
+	}
+	outputPath := filepath.Join("output", "bad_preset.mp4")
+	if err := video.WriteVideo(moviego.VideoParameters{OutputPath: outputPath, Preset: "turbo"}); err == nil {
+		t.Error("expected WriteVideo to reject unknown preset")
+	}
+}
+
+func TestQualityProfiles(t *testing.T) {
+	defaults := moviego.DefaultVideoParameters()
+	if defaults.Preset != moviego.Medium || defaults.PixelFormat != moviego.PixelFormatYUV420P || defaults.Threads == 0 {
+		t.Errorf("unexpected defaults: %+v", defaults)
+	}
+
+	draft, err := moviego.ProfileDraft.Parameters()
+	if err != nil {
+		t.Fatalf("Failed to get draft profile: %v", err)
+	}
+	archive, err := moviego.ProfileArchive.Parameters()
+	if err != nil {
+		t.Fatalf("Failed to get archive profile: %v", err)
+	}
+	if draft.EncoderOptions.X264.CRF <= archive.EncoderOptions.X264.CRF {
+		t.Errorf("expected draft CRF above archive CRF, got %g and %g", draft.EncoderOptions.X264.CRF, archive.EncoderOptions.X264.CRF)
+	}
+	if _, err := moviego.QualityProfile("ultra").Parameters(); err == nil {
+		t.Error("expected error for unknown profile")
+	}
+
+	outputPath := filepath.Join("output", "profile_draft.mp4")
+	parms := draft.Merge(moviego.VideoParameters{
+		OutputPath:     outputPath,
+		SilentProgress: true,
+		Keyframes:      moviego.KeyframeOptions{Interval: 1},
+	})
+	if parms.Preset != moviego.UltraFast || parms.OutputPath != outputPath || parms.Keyframes.Interval != 1 {
+		t.Errorf("merge lost fields: %+v", parms)
+	}
+	if parms.EncoderOptions.X264 == nil || parms.EncoderOptions.X264.CRF != 28 {
+		t.Error("merge with empty encoder options must keep the profile's CRF")
+	}
+
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	cut, err := video.Cut(0, 1)
+	if err != nil {
+		t.Fatalf("Failed to cut: %v", err)
+	}
+	if err := cut.WriteVideo(parms); err != nil {
+		t.Fatalf("Failed to write with draft profile: %v", err)
+	}
+}
+
+func TestFilterFlattensOverlays(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	cut, err := video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut: %v", err)
+	}
+	cut.AddOverlay(moviego.NewColorClip("red", 160, 120, 2).Fps(video.GetFps()), 0)
+
+	resized, err := cut.Resize(320, 180, moviego.ResizeStretch)
+	if err != nil {
+		t.Fatalf("Failed to resize: %v", err)
+	}
+	if n := len(resized.GetOverlays()); n != 0 {
+		t.Errorf("expected overlays to be flattened before the filter, got %d pending", n)
+	}
+	outputPath := filepath.Join("output", "overlay_resized.mp4")
+	if err := resized.WriteVideo(moviego.VideoParameters{OutputPath: outputPath, SilentProgress: true}); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+	out, err := moviego.NewVideoFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to load output: %v", err)
+	}
+	if out.GetWidth() != 320 || out.GetHeight() != 180 {
+		t.Errorf("expected 320x180, got %dx%d", out.GetWidth(), out.GetHeight())
+	}
+}
+
+func TestAudioFiltersFlattenOverlays(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	cut, err := video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut: %v", err)
+	}
+	cut.AddOverlay(moviego.NewColorClip("red", 160, 120, 2).Fps(video.GetFps()), 0)
+
+	for name, fade := range map[string]func(float64) (*moviego.Video, error){
+		"AudioFadeIn":  cut.AudioFadeIn,
+		"AudioFadeOut": cut.AudioFadeOut,
+		"SetVolumeEnvelope": func(d float64) (*moviego.Video, error) {
+			return cut.SetVolumeEnvelope([]moviego.VolumeKeyframe{{Time: 0, Level: 1}, {Time: d, Level: 0.5}})
+		},
+	} {
+		faded, err := fade(0.5)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if n := len(faded.GetOverlays()); n != 0 {
+			t.Errorf("%s: expected overlays to be flattened before the filter, got %d pending", name, n)
+		}
+	}
+}
@@ -1,8 +1,11 @@
 package composite_test
 
 import (
+	"fmt"
 	"math"
 	"os"
+	"os/exec"
+	"path/filepath"
 	"testing"
 
 	moviego "github.com/YounesseAmhend/MovieGo"
@@ -175,3 +178,533 @@ func TestCompositeClipMultiLayer(t *testing.T) {
 			bg.GetWidth(), bg.GetHeight(), out.GetWidth(), out.GetHeight())
 	}
 }
+
+func TestCompositeClipGapFillBlack(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+
+	bg, err := video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut background: %v", err)
+	}
+	fg, err := video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut foreground: %v", err)
+	}
+	fg, err = fg.ScaleRatio(0.5)
+	if err != nil {
+		t.Fatalf("Failed to scale foreground: %v", err)
+	}
+	fg.SetCompositeStart(1.5)
+
+	const expectedDuration = 3.5
+	result, err := moviego.CompositeClipWithOptions([]moviego.Video{*bg, *fg}, moviego.CompositeOptions{Fill: moviego.GapFillBlack})
+	if err != nil {
+		t.Fatalf("Failed to composite: %v", err)
+	}
+	if math.Abs(result.GetDuration()-expectedDuration) > 0.01 {
+		t.Fatalf("Expected planned duration %f, got %f", expectedDuration, result.GetDuration())
+	}
+
+	const outputPath = "output/composite_gap_black.mp4"
+	if err := result.WriteVideo(moviego.VideoParameters{OutputPath: outputPath}); err != nil {
+		t.Fatalf("Failed to write composite video: %v", err)
+	}
+
+	out, err := moviego.NewVideoFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to load output: %v", err)
+	}
+	if math.Abs(out.GetDuration()-expectedDuration) > 0.2 {
+		t.Fatalf("Expected duration ~%f, got %f", expectedDuration, out.GetDuration())
+	}
+}
+
+func TestCompositeClipGapFillColorRequiresColor(t *testing.T) {
+	bg, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	fg := *bg
+	fg.SetCompositeStart(1)
+
+	_, err = moviego.CompositeClipWithOptions([]moviego.Video{*bg, fg}, moviego.CompositeOptions{Fill: moviego.GapFillColor})
+	if err == nil {
+		t.Fatal("Expected error for GapFillColor without FillColor")
+	}
+}
+
+func TestCompositeClipGapFillClip(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	bg, err := video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut background: %v", err)
+	}
+	fg, err := video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut foreground: %v", err)
+	}
+	fg.SetCompositeStart(1.5)
+	filler, err := video.Cut(2, 3)
+	if err != nil {
+		t.Fatalf("Failed to cut fill clip: %v", err)
+	}
+
+	if _, err := moviego.CompositeClipWithOptions([]moviego.Video{*bg, *fg}, moviego.CompositeOptions{Fill: moviego.GapFillClip}); err == nil {
+		t.Fatal("Expected error for GapFillClip without FillClip")
+	}
+
+	const expectedDuration = 3.5
+	result, err := moviego.CompositeClipWithOptions([]moviego.Video{*bg, *fg}, moviego.CompositeOptions{Fill: moviego.GapFillClip, FillClip: filler})
+	if err != nil {
+		t.Fatalf("Failed to composite: %v", err)
+	}
+	if math.Abs(result.GetDuration()-expectedDuration) > 0.01 {
+		t.Fatalf("Expected planned duration %f, got %f", expectedDuration, result.GetDuration())
+	}
+	if result.GetWidth() != bg.GetWidth() || result.GetFps() != bg.GetFps() {
+		t.Fatalf("Expected the background size and rate, got %dx%d@%d", result.GetWidth(), result.GetHeight(), result.GetFps())
+	}
+
+	const outputPath = "output/composite_gap_clip.mp4"
+	if err := result.WriteVideo(moviego.VideoParameters{OutputPath: outputPath}); err != nil {
+		t.Fatalf("Failed to write composite video: %v", err)
+	}
+	out, err := moviego.NewVideoFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to load output: %v", err)
+	}
+	if math.Abs(out.GetDuration()-expectedDuration) > 0.2 {
+		t.Fatalf("Expected duration ~%f, got %f", expectedDuration, out.GetDuration())
+	}
+}
+func TestPointPosition(t *testing.T) {
+	pos := moviego.PointAt(50, 50, moviego.AnchorCenter).Position()
+	if pos.X != "W*0.5-w*0.5" || pos.Y != "H*0.5-h*0.5" {
+		t.Errorf("unexpected center position %+v", pos)
+	}
+	if pos := moviego.TopLeftPosition(); pos.X != "0" || pos.Y != "0" {
+		t.Errorf("unexpected top-left position %+v", pos)
+	}
+	if pos := moviego.BottomRightPosition(); pos.X != "W-w" || pos.Y != "H-h" {
+		t.Errorf("unexpected bottom-right position %+v", pos)
+	}
+	pos = moviego.Point{X: moviego.Expr("W-20"), Y: moviego.Px(10), Anchor: moviego.AnchorTopRight}.TextPosition()
+	if pos.X != "(W-20)-tw" || pos.Y != "10" {
+		t.Errorf("unexpected text position %+v", pos)
+	}
+}
+
+func TestCropRect(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	cropped, err := video.CropRect(moviego.Rect{X: moviego.Percent(25), Y: moviego.Px(0), Width: moviego.Percent(50), Height: moviego.Percent(50)})
+	if err != nil {
+		t.Fatalf("Failed to crop: %v", err)
+	}
+	if cropped.GetWidth() != video.GetWidth()/2 {
+		t.Errorf("expected width %d, got %d", video.GetWidth()/2, cropped.GetWidth())
+	}
+	if _, err := video.CropRect(moviego.Rect{Width: moviego.Expr("iw/2"), Height: moviego.Px(10)}); err == nil {
+		t.Error("expected error for expression width")
+	}
+}
+
+func TestCompositeAt(t *testing.T) {
+	bg, err := moviego.CompositeClips(moviego.NewColorClip("black", 320, 240, 3))
+	if err != nil {
+		t.Fatalf("Failed to build background: %v", err)
+	}
+	fgVideo, err := moviego.CompositeClips(moviego.NewColorClip("red", 100, 100, 1))
+	if err != nil {
+		t.Fatalf("Failed to build overlay: %v", err)
+	}
+	fgVideo.SetCompositeStart(1).SetAnimatedOpacity(moviego.Animation{Start: 0, End: 1, StartTime: 1, EndTime: 2})
+
+	result, err := moviego.CompositeClip([]moviego.Video{*bg, *fgVideo})
+	if err != nil {
+		t.Fatalf("Failed to composite: %v", err)
+	}
+	if layers := result.At(0.5).Layers; len(layers) != 1 {
+		t.Fatalf("expected only the background at 0.5s, got %d layers", len(layers))
+	}
+	layers := result.At(1.5).Layers
+	if len(layers) != 2 {
+		t.Fatalf("expected 2 layers at 1.5s, got %d", len(layers))
+	}
+	if math.Abs(layers[1].Opacity-0.5) > 1e-9 || math.Abs(layers[1].LocalTime-0.5) > 1e-9 {
+		t.Errorf("unexpected overlay state %+v", layers[1])
+	}
+
+	result.AddTextOverlay(moviego.TextClip{Text: "Title", StartTime: 2}, 0)
+	if overlays := result.At(2.5).Overlays; len(overlays) != 1 || overlays[0].Text != "Title" {
+		t.Errorf("expected the title overlay at 2.5s, got %+v", overlays)
+	}
+}
+
+func TestHTMLClipRenderer(t *testing.T) {
+	var got moviego.ClipRequest
+	renderer := moviego.HTMLRendererFunc(func(html string, req moviego.ClipRequest) (moviego.ClipSource, error) {
+		got = req
+		return moviego.ClipSource{Kind: moviego.SourceImage, Path: req.TempDir + "/banner.png"}, nil
+	})
+	banner := moviego.NewHTMLClip("<h1>Live</h1>", 200, 50, 2).Renderer(renderer).SetPosition(moviego.TopLeftPosition())
+
+	result, err := moviego.CompositeClips(moviego.NewColorClip("black", 320, 240, 2), banner)
+	if err != nil {
+		t.Fatalf("Failed to composite: %v", err)
+	}
+	if got.Width != 200 || got.Height != 50 || got.Duration != 2 || got.TempDir == "" {
+		t.Errorf("unexpected render request %+v", got)
+	}
+	os.RemoveAll(got.TempDir)
+	if result.GetWidth() != 320 || result.GetHeight() != 240 {
+		t.Errorf("expected 320x240, got %dx%d", result.GetWidth(), result.GetHeight())
+	}
+
+	if _, err := moviego.CompositeClips(moviego.NewHTMLClip("", 200, 50, 2).Renderer(renderer)); err == nil {
+		t.Error("expected an error for an empty document")
+	}
+}
+
+func TestLottieClip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logo.json")
+	if err := os.WriteFile(path, []byte(`{"v":"5.7.4","w":400,"h":300,"fr":25,"ip":0,"op":50,"layers":[]}`), 0644); err != nil {
+		t.Fatalf("Failed to write animation: %v", err)
+	}
+	logo, err := moviego.NewLottieFile(path)
+	if err != nil {
+		t.Fatalf("Failed to load animation: %v", err)
+	}
+	if logo.GetWidth() != 400 || logo.GetHeight() != 300 || logo.GetFps() != 25 || logo.GetDuration() != 2 {
+		t.Errorf("unexpected animation %dx%d@%d, %.2fs", logo.GetWidth(), logo.GetHeight(), logo.GetFps(), logo.GetDuration())
+	}
+
+	if _, err := moviego.CompositeClips(logo); err == nil {
+		t.Error("expected an error without renderer")
+	}
+
+	var got moviego.ClipRequest
+	logo.Size(200, 150).Renderer(moviego.LottieRendererFunc(func(filename string, req moviego.ClipRequest) (moviego.ClipSource, error) {
+		got = req
+		return moviego.ClipSource{Kind: moviego.SourceImage, Path: req.TempDir + "/frame.png"}, nil
+	}))
+	if _, err := moviego.CompositeClips(moviego.NewColorClip("black", 320, 240, 2), logo); err != nil {
+		t.Fatalf("Failed to composite: %v", err)
+	}
+	os.RemoveAll(got.TempDir)
+	if got.Width != 200 || got.Height != 150 || got.Fps != 25 {
+		t.Errorf("unexpected render request %+v", got)
+	}
+}
+
+func TestSVGClipValidate(t *testing.T) {
+	logo := moviego.NewSVGClip("logo.svg", 200, 100, 2)
+	if err := logo.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := logo.SetAnimatedScale(moviego.Animation{Start: 0, End: 2, EndTime: 1}).Validate(); err == nil {
+		t.Error("expected an error for a zero scale")
+	}
+	if err := moviego.NewSVGClip("", 200, 100, 2).Validate(); err == nil {
+		t.Error("expected an error for an empty filename")
+	}
+}
+
+func TestAddSticker(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	sticker, err := moviego.NewSticker(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load sticker: %v", err)
+	}
+	sticker.Size(96, 96).Between(1, 2).Drag(moviego.Position{X: "0", Y: "0"}, moviego.Position{X: "200", Y: "100"}, moviego.Linear)
+
+	result, err := video.AddSticker(sticker)
+	if err != nil {
+		t.Fatalf("Failed to add sticker: %v", err)
+	}
+	if overlays := result.At(0.5).Layers; len(overlays) != 1 {
+		t.Errorf("expected no sticker at 0.5s, got %d layers", len(overlays))
+	}
+	layers := result.At(1.5).Layers
+	if len(layers) != 2 || layers[1].Position.X != "100.0000" {
+		t.Errorf("expected the sticker halfway at 1.5s, got %+v", layers)
+	}
+
+	if _, err := video.AddSticker(sticker.Between(0, video.GetDuration()+1)); err == nil {
+		t.Error("expected an error for a sticker ending after the video")
+	}
+}
+
+func TestRenderStill(t *testing.T) {
+	bg, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load background video: %v", err)
+	}
+	result, err := moviego.CompositeClips(bg, moviego.NewColorClip("red", 100, 100, 1).SetPosition(moviego.TopLeftPosition()))
+	if err != nil {
+		t.Fatalf("Failed to composite: %v", err)
+	}
+	if err := result.RenderStill(result.GetDuration(), "output/still.png"); err == nil {
+		t.Error("expected an error for a time past the end")
+	}
+
+	const outputPath = "output/still.png"
+	if err := result.RenderStill(0.5, outputPath); err != nil {
+		t.Fatalf("Failed to render still: %v", err)
+	}
+	still, err := moviego.NewVideoFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to load still: %v", err)
+	}
+	if still.GetWidth() != bg.GetWidth() || still.GetHeight() != bg.GetHeight() {
+		t.Errorf("expected %dx%d, got %dx%d", bg.GetWidth(), bg.GetHeight(), still.GetWidth(), still.GetHeight())
+	}
+}
+
+func TestWaveformClip(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	clip, err := video.Cut(0, 3)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+
+	// Overlaid on the video it comes from.
+	wave := moviego.NewVideoWaveformClip(clip, clip.GetWidth(), clip.GetHeight()/4).
+		Color("#1DB954@0.8").
+		Mode(moviego.WaveformLine).
+		SetPosition(moviego.Position{X: "0", Y: "main_h-overlay_h"})
+	overlaid, err := moviego.CompositeClips(clip, wave)
+	if err != nil {
+		t.Fatalf("Failed to composite waveform: %v", err)
+	}
+	const overlayPath = "output/waveform_overlay.mp4"
+	if err := overlaid.WriteVideo(moviego.VideoParameters{OutputPath: overlayPath}); err != nil {
+		t.Fatalf("Failed to write waveform overlay: %v", err)
+	}
+
+	// Standalone audiogram of an audio file, carrying the audio.
+	audio, err := moviego.NewAudioFile(common.TestAudioPath)
+	if err != nil {
+		t.Fatalf("Failed to load audio: %v", err)
+	}
+	bg := moviego.NewColorClip("black", 640, 360, 2)
+	audiogram, err := moviego.CompositeClips(bg, moviego.NewWaveformClip(audio, 640, 120).Duration(2).WithAudio())
+	if err != nil {
+		t.Fatalf("Failed to composite audiogram: %v", err)
+	}
+	const audiogramPath = "output/waveform_audiogram.mp4"
+	if err := audiogram.WriteVideo(moviego.VideoParameters{OutputPath: audiogramPath}); err != nil {
+		t.Fatalf("Failed to write audiogram: %v", err)
+	}
+	out, err := moviego.NewVideoFile(audiogramPath)
+	if err != nil {
+		t.Fatalf("Failed to load audiogram: %v", err)
+	}
+	if math.Abs(out.GetDuration()-2) > 0.2 {
+		t.Errorf("Expected duration ~2, got %f", out.GetDuration())
+	}
+
+	if _, err := moviego.CompositeClips(bg, moviego.NewWaveformClip(audio, 0, 120)); err == nil {
+		t.Errorf("Expected an error for a zero width")
+	}
+}
+
+func TestNewPodcastClip(t *testing.T) {
+	audio, err := moviego.NewToneClip(220, 3)
+	if err != nil {
+		t.Fatalf("Failed to create audio: %v", err)
+	}
+	if err := os.MkdirAll("output", 0755); err != nil {
+		t.Fatalf("Failed to create output directory: %v", err)
+	}
+	cover := "output/podcast_cover.png"
+	if err := exec.Command("ffmpeg", "-f", "lavfi", "-i", "color=c=orange:s=400x400", "-frames:v", "1", "-y", cover).Run(); err != nil {
+		t.Fatalf("Failed to create cover image: %v", err)
+	}
+
+	clip, err := moviego.NewPodcastClip(audio, cover, "Episode 1", moviego.PodcastClipStyle{
+		Width:    640,
+		Height:   360,
+		Captions: "../testdata/sample.srt",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create podcast clip: %v", err)
+	}
+	if clip.GetWidth() != 640 || clip.GetHeight() != 360 {
+		t.Errorf("Expected 640x360, got %dx%d", clip.GetWidth(), clip.GetHeight())
+	}
+	const outputPath = "output/podcast_clip.mp4"
+	if err := clip.WriteVideo(moviego.VideoParameters{OutputPath: outputPath, SilentProgress: true}); err != nil {
+		t.Fatalf("Failed to write podcast clip: %v", err)
+	}
+	out, err := moviego.NewVideoFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to load podcast clip: %v", err)
+	}
+	if math.Abs(out.GetDuration()-3) > 0.2 || !out.HasAudio() {
+		t.Errorf("Expected ~3s with audio, got %f (audio=%v)", out.GetDuration(), out.HasAudio())
+	}
+
+	if _, err := moviego.NewPodcastClip(audio, "missing.png", "", moviego.PodcastClipStyle{}); err == nil {
+		t.Error("Expected an error for a missing cover image")
+	}
+}
+
+func TestCompositePretranscode(t *testing.T) {
+	if err := os.MkdirAll("output", 0755); err != nil {
+		t.Fatalf("Failed to create output directory: %v", err)
+	}
+	layerPath := "output/pretranscode_layer.mp4"
+	err := exec.Command("ffmpeg", "-f", "lavfi", "-i", "testsrc=s=320x240:r=12:d=2", "-f", "lavfi", "-i", "sine=d=2",
+		"-shortest", "-y", layerPath).Run()
+	if err != nil {
+		t.Fatalf("Failed to create layer: %v", err)
+	}
+	bg, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load background: %v", err)
+	}
+	bg, err = bg.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut background: %v", err)
+	}
+	layer, err := moviego.NewVideoFile(layerPath)
+	if err != nil {
+		t.Fatalf("Failed to load layer: %v", err)
+	}
+	layer.SetPosition(moviego.TopLeftPosition())
+
+	videos := []moviego.Video{*bg, *layer}
+	mismatches := moviego.CompositeMismatches(videos)
+	if len(mismatches) == 0 || mismatches[0].Layer != 1 {
+		t.Fatalf("Expected the 12 fps layer to be reported, got %+v", mismatches)
+	}
+
+	dir := filepath.Join("output", "mezzanine")
+	composite, err := moviego.CompositeClipWithOptions(videos, moviego.CompositeOptions{
+		Pretranscode: &moviego.PretranscodeOptions{Dir: dir},
+	})
+	if err != nil {
+		t.Fatalf("Failed to composite: %v", err)
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*.mkv")); len(files) != 1 {
+		t.Errorf("Expected one mezzanine file, got %v", files)
+	}
+	if err := composite.WriteVideo(moviego.VideoParameters{OutputPath: "output/pretranscode.mp4", SilentProgress: true}); err != nil {
+		t.Fatalf("Failed to write composite: %v", err)
+	}
+
+	if _, err := moviego.CompositeClipWithOptions(videos, moviego.CompositeOptions{Pretranscode: &moviego.PretranscodeOptions{}}); err == nil {
+		t.Error("Expected an error without a mezzanine directory")
+	}
+}
+
+func TestCompositeCanvasFitLargestClip(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	layer, err := video.Cut(0, 1)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	bg, err := layer.ScaleRatio(0.25)
+	if err != nil {
+		t.Fatalf("Failed to scale background: %v", err)
+	}
+	layer.SetPosition(moviego.PointAt(100, 100, moviego.AnchorBottomRight).Position())
+
+	composite, err := moviego.CompositeClipWithOptions([]moviego.Video{*bg, *layer}, moviego.CompositeOptions{
+		Canvas:    moviego.CanvasFitLargestClip,
+		FillColor: "#202020",
+	})
+	if err != nil {
+		t.Fatalf("Failed to composite: %v", err)
+	}
+	if composite.GetWidth() != layer.GetWidth() || composite.GetHeight() != layer.GetHeight() {
+		t.Errorf("Expected a %dx%d canvas, got %dx%d", layer.GetWidth(), layer.GetHeight(), composite.GetWidth(), composite.GetHeight())
+	}
+	const outputPath = "output/composite_canvas.mp4"
+	if err := composite.WriteVideo(moviego.VideoParameters{OutputPath: outputPath, SilentProgress: true}); err != nil {
+		t.Fatalf("Failed to write composite: %v", err)
+	}
+	out, err := moviego.NewVideoFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to probe composite: %v", err)
+	}
+	if out.GetWidth() != composite.GetWidth() || out.GetHeight() != composite.GetHeight() {
+		t.Errorf("Expected %dx%d, got %dx%d", composite.GetWidth(), composite.GetHeight(), out.GetWidth(), out.GetHeight())
+	}
+
+	if _, err := moviego.CompositeClipWithOptions([]moviego.Video{*bg, *layer}, moviego.CompositeOptions{Canvas: "huge"}); err == nil {
+		t.Error("Expected an error for an unknown canvas policy")
+	}
+}
+
+func TestCompositeFpsPolicy(t *testing.T) {
+	if err := os.MkdirAll("output", 0755); err != nil {
+		t.Fatalf("Failed to create output directory: %v", err)
+	}
+	layerPath := "output/fps_layer.mp4"
+	if err := exec.Command("ffmpeg", "-f", "lavfi", "-i", "testsrc=s=160x120:r=12:d=1", "-y", layerPath).Run(); err != nil {
+		t.Fatalf("Failed to create layer: %v", err)
+	}
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load background: %v", err)
+	}
+	bg, err := video.Cut(0, 1)
+	if err != nil {
+		t.Fatalf("Failed to cut background: %v", err)
+	}
+	layer, err := moviego.NewVideoFile(layerPath)
+	if err != nil {
+		t.Fatalf("Failed to load layer: %v", err)
+	}
+	videos := []moviego.Video{*bg, *layer}
+
+	for name, tc := range map[string]struct {
+		policy moviego.FpsPolicy
+		fps    uint64
+	}{
+		"lowest": {moviego.FpsLowest, min(bg.GetFps(), 12)},
+		"fixed":  {moviego.FpsFixed(25), 25},
+	} {
+		composite, err := moviego.CompositeClipWithOptions(videos, moviego.CompositeOptions{Fps: tc.policy})
+		if err != nil {
+			t.Fatalf("%s: failed to composite: %v", name, err)
+		}
+		if composite.GetFps() != tc.fps {
+			t.Errorf("%s: expected %d fps, got %d", name, tc.fps, composite.GetFps())
+		}
+		outputPath := fmt.Sprintf("output/composite_fps_%s.mp4", name)
+		if err := composite.WriteVideo(moviego.VideoParameters{OutputPath: outputPath, SilentProgress: true}); err != nil {
+			t.Fatalf("%s: failed to write composite: %v", name, err)
+		}
+		out, err := moviego.NewVideoFile(outputPath)
+		if err != nil {
+			t.Fatalf("%s: failed to probe composite: %v", name, err)
+		}
+		if out.GetFps() != tc.fps {
+			t.Errorf("%s: expected %d fps output, got %d", name, tc.fps, out.GetFps())
+		}
+	}
+
+	if _, err := moviego.CompositeClipWithOptions(videos, moviego.CompositeOptions{Fps: moviego.FpsFixed(0)}); err == nil {
+		t.Error("Expected an error for a zero fixed frame rate")
+	}
+}
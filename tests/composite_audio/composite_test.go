@@ -55,6 +55,249 @@ func TestComposite(t *testing.T) {
 	}
 }
 
+func TestReplaceAudio(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	video, err = video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+
+	if _, err := video.ReplaceAudio(nil); err == nil {
+		t.Fatalf("Expected an error for nil audio")
+	}
+
+	// The track is longer than the clip and must be truncated to it.
+	replaced, err := video.SetAudioFile(common.TestAudioPath)
+	if err != nil {
+		t.Fatalf("Failed to replace audio: %v", err)
+	}
+	if replaced.GetAudio().GetDuration() != video.GetDuration() {
+		t.Errorf("Expected audio duration %f, got %f", video.GetDuration(), replaced.GetAudio().GetDuration())
+	}
+
+	outputPath := "output/test_replace_audio.mp4"
+	err = replaced.WriteVideo(moviego.VideoParameters{
+		OutputPath:     outputPath,
+		SilentProgress: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to write video: %v", err)
+	}
+
+	out, err := moviego.NewAudioFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to probe output: %v", err)
+	}
+	if math.Abs(out.GetDuration()-2) > 0.2 {
+		t.Errorf("Expected audio duration ~2s, got %f", out.GetDuration())
+	}
+}
+
+func TestRemoveAudio(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	clip, err := video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	silent, err := clip.RemoveAudio()
+	if err != nil {
+		t.Fatalf("Failed to remove audio: %v", err)
+	}
+	if silent.HasAudio() || !clip.HasAudio() {
+		t.Fatalf("Expected only the copy to lose its audio")
+	}
+	if err := silent.WriteAudio(moviego.AudioParameters{OutputPath: "output/test_remove_audio.mp3"}); err == nil {
+		t.Errorf("Expected an error writing removed audio")
+	}
+
+	outputPath := "output/test_remove_audio.mp4"
+	if err := silent.WriteVideo(moviego.VideoParameters{OutputPath: outputPath, SilentProgress: true}); err != nil {
+		t.Fatalf("Failed to write video: %v", err)
+	}
+	out, err := moviego.NewVideoFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to probe output: %v", err)
+	}
+	if out.HasAudio() {
+		t.Errorf("Expected no audio stream in %s", outputPath)
+	}
+
+	// Joined with a clip that has audio, the silent clip plays silence.
+	joined, err := moviego.Concatenate([]moviego.Video{*silent, *clip})
+	if err != nil {
+		t.Fatalf("Failed to concatenate: %v", err)
+	}
+	outputPath = "output/test_remove_audio_concat.mp4"
+	if err := joined.WriteVideo(moviego.VideoParameters{OutputPath: outputPath, SilentProgress: true}); err != nil {
+		t.Fatalf("Failed to write concatenation: %v", err)
+	}
+	audio, err := moviego.NewAudioFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to probe output: %v", err)
+	}
+	if math.Abs(audio.GetDuration()-4) > 0.3 {
+		t.Errorf("Expected audio duration ~4s, got %f", audio.GetDuration())
+	}
+
+	// Layered over a clip with audio, the silent layer is left out of the mix.
+	composite, err := moviego.CompositeClip([]moviego.Video{*clip, *silent})
+	if err != nil {
+		t.Fatalf("Failed to composite: %v", err)
+	}
+	if !composite.HasAudio() {
+		t.Errorf("Expected the composite to keep the background audio")
+	}
+	if err := composite.WriteVideo(moviego.VideoParameters{OutputPath: "output/test_remove_audio_composite.mp4", SilentProgress: true}); err != nil {
+		t.Fatalf("Failed to write composite: %v", err)
+	}
+}
+
+func TestAddBackgroundMusic(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	music, err := moviego.NewAudioFile(common.TestAudioPath)
+	if err != nil {
+		t.Fatalf("Failed to load music: %v", err)
+	}
+	// Lay out the video twice so the music has to loop.
+	long, err := moviego.Concatenate([]moviego.Video{*video, *video})
+	if err != nil {
+		t.Fatalf("Failed to concatenate: %v", err)
+	}
+	if long.GetDuration() <= music.GetDuration() {
+		t.Fatalf("Expected the video (%f) to be longer than the music (%f)", long.GetDuration(), music.GetDuration())
+	}
+
+	if _, err := long.AddBackgroundMusic(common.TestAudioPath, -1); err == nil {
+		t.Fatalf("Expected an error for a negative volume")
+	}
+
+	mixed, err := long.AddBackgroundMusic(common.TestAudioPath, 0.3)
+	if err != nil {
+		t.Fatalf("Failed to add background music: %v", err)
+	}
+
+	outputPath := "output/test_background_music.mp4"
+	err = mixed.WriteVideo(moviego.VideoParameters{
+		OutputPath:     outputPath,
+		SilentProgress: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to write video: %v", err)
+	}
+
+	out, err := moviego.NewAudioFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to probe output: %v", err)
+	}
+	if math.Abs(out.GetDuration()-long.GetDuration()) > 0.5 {
+		t.Errorf("Expected audio duration ~%f, got %f", long.GetDuration(), out.GetDuration())
+	}
+
+	// Music over a video without audio is mixed over silence.
+	silent, err := video.RemoveAudio()
+	if err != nil {
+		t.Fatalf("Failed to remove audio: %v", err)
+	}
+	muted, err := silent.AddBackgroundMusic(common.TestAudioPath, 0.3)
+	if err != nil {
+		t.Fatalf("Failed to add background music to a silent video: %v", err)
+	}
+	if err := muted.WriteVideo(moviego.VideoParameters{OutputPath: "output/test_background_music_silent.mp4", SilentProgress: true}); err != nil {
+		t.Fatalf("Failed to write silent video with music: %v", err)
+	}
+}
+
+func TestDuckMix(t *testing.T) {
+	voice, err := moviego.NewAudioFile(common.TestAudioPath)
+	if err != nil {
+		t.Fatalf("Failed to load voice: %v", err)
+	}
+	music, err := moviego.NewAudioFile(common.TestAudioPath)
+	if err != nil {
+		t.Fatalf("Failed to load music: %v", err)
+	}
+
+	if _, err := moviego.DuckMix(*voice, *music, moviego.DuckParams{Ratio: 50}); err == nil {
+		t.Fatalf("Expected an error for an out of range ratio")
+	}
+
+	mixed, err := moviego.DuckMix(*voice, *music, moviego.DuckParams{Threshold: 0.1, Release: 500})
+	if err != nil {
+		t.Fatalf("Failed to duck: %v", err)
+	}
+	outputPath := "output/test_duck_mix.mp3"
+	err = mixed.Write(moviego.AudioParameters{
+		OutputPath:     outputPath,
+		SilentProgress: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to write ducked mix: %v", err)
+	}
+
+	out, err := moviego.NewAudioFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to probe output: %v", err)
+	}
+	if math.Abs(out.GetDuration()-voice.GetDuration()) > 0.5 {
+		t.Errorf("Expected duration ~%f, got %f", voice.GetDuration(), out.GetDuration())
+	}
+}
+
+func TestAddAudio(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	video, err = video.Cut(0, 3)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	music, err := moviego.NewAudioFile(common.TestAudioPath)
+	if err != nil {
+		t.Fatalf("Failed to load music: %v", err)
+	}
+
+	if _, err := video.AddAudio(&moviego.CompositeAudioItem{Audio: music, Start: 5}); err == nil {
+		t.Fatalf("Expected an error for an item starting after the video")
+	}
+
+	mixed, err := video.AddAudio(&moviego.CompositeAudioItem{
+		Audio:    music,
+		Start:    0.5,
+		Volume:   moviego.F(0.5),
+		Envelope: []moviego.VolumeKeyframe{{Time: 0, Level: 0}, {Time: 1, Level: 1}},
+		Duck:     &moviego.DuckParams{},
+	})
+	if err != nil {
+		t.Fatalf("Failed to add audio: %v", err)
+	}
+	outputPath := "output/test_add_audio.mp4"
+	err = mixed.WriteVideo(moviego.VideoParameters{
+		OutputPath:     outputPath,
+		SilentProgress: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to write video: %v", err)
+	}
+
+	out, err := moviego.NewAudioFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to probe output: %v", err)
+	}
+	if math.Abs(out.GetDuration()-3) > 0.3 {
+		t.Errorf("Expected audio duration ~3s, got %f", out.GetDuration())
+	}
+}
+
 func TestMain(m *testing.M) {
 	_ = os.MkdirAll("output", 0755)
 	
@@ -3,6 +3,9 @@ package concatenate_test
 import (
 	"math"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	moviego "github.com/YounesseAmhend/MovieGo"
@@ -152,3 +155,214 @@ func TestInsaneConcatenate(t *testing.T) {
 		t.Fatalf("Expected duration %f, got %f", expected, out.GetDuration())
 	}
 }
+
+func TestConcatenateAudioCrossfade(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to create video file: %v", err)
+	}
+	cut1, err := video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	cut2, err := video.Cut(3, 5)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+
+	result, err := moviego.ConcatenateWithOptions([]moviego.Video{*cut1, *cut2}, moviego.ConcatenateOptions{AudioCrossfade: 0.05})
+	if err != nil {
+		t.Fatalf("Failed to concatenate: %v", err)
+	}
+
+	const outputPath = "output/concatenated_audio_crossfade.mp4"
+	if err := result.WriteVideo(moviego.VideoParameters{OutputPath: outputPath}); err != nil {
+		t.Fatalf("Failed to write video: %v", err)
+	}
+
+	out, err := moviego.NewVideoFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to load output: %v", err)
+	}
+	const expected = 4.0
+	if math.Abs(out.GetDuration()-expected) > 0.15 {
+		t.Fatalf("Expected duration %f, got %f", expected, out.GetDuration())
+	}
+	if math.Abs(out.GetAudio().GetDuration()-expected) > 0.15 {
+		t.Fatalf("Expected audio duration %f, got %f", expected, out.GetAudio().GetDuration())
+	}
+}
+
+func TestConcatenateAudioCrossfadeAlignment(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to create video file: %v", err)
+	}
+	silent, err := video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	silent, err = silent.SetVolumeEnvelope([]moviego.VolumeKeyframe{{Time: 0, Level: 0}})
+	if err != nil {
+		t.Fatalf("Failed to mute video: %v", err)
+	}
+	third, err := video.Cut(3, 5)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+
+	const crossfade = 0.5
+	result, err := moviego.ConcatenateWithOptions([]moviego.Video{*silent, *silent, *third}, moviego.ConcatenateOptions{AudioCrossfade: crossfade})
+	if err != nil {
+		t.Fatalf("Failed to concatenate: %v", err)
+	}
+	samples, rate, err := result.GetAudioSamples(0, result.GetDuration())
+	if err != nil {
+		t.Fatalf("Failed to read audio samples: %v", err)
+	}
+	onset := -1.0
+	for i, s := range samples {
+		if math.Abs(s) > 1e-3 {
+			onset = float64(i) / float64(rate)
+			break
+		}
+	}
+	// The third clip's audio fades in from its cut at 4 s, not earlier.
+	if onset < 4-0.02 || onset > 4+crossfade/2 {
+		t.Fatalf("Expected the third clip's audio to start at ~4s, got %f", onset)
+	}
+}
+
+func TestConcatenateAudioCrossfadeCurve(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to create video file: %v", err)
+	}
+	cut1, err := video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	cut2, err := video.Cut(3, 5)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+
+	if _, err := moviego.ConcatenateWithOptions([]moviego.Video{*cut1, *cut2}, moviego.ConcatenateOptions{AudioCrossfade: 0.1, AudioCrossfadeCurve: "wobble"}); err == nil {
+		t.Fatalf("Expected an error for an unknown crossfade curve")
+	}
+
+	opts := moviego.ConcatenateOptions{AudioCrossfade: 0.1, AudioCrossfadeCurve: moviego.CrossfadeEqualPower}
+	result, err := moviego.NewTimeline().SetConcatenateOptions(opts).Add(cut1).Add(cut2).Add(cut1).Render()
+	if err != nil {
+		t.Fatalf("Failed to render timeline: %v", err)
+	}
+
+	const outputPath = "output/timeline_audio_crossfade.mp4"
+	if err := result.WriteVideo(moviego.VideoParameters{OutputPath: outputPath}); err != nil {
+		t.Fatalf("Failed to write video: %v", err)
+	}
+	out, err := moviego.NewVideoFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to load output: %v", err)
+	}
+	const expected = 6.0
+	if math.Abs(out.GetAudio().GetDuration()-expected) > 0.15 {
+		t.Fatalf("Expected audio duration %f, got %f", expected, out.GetAudio().GetDuration())
+	}
+}
+
+func TestTimelineAudioCrossfadeAlignment(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to create video file: %v", err)
+	}
+	silent, err := video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	silent, err = silent.SetVolumeEnvelope([]moviego.VolumeKeyframe{{Time: 0, Level: 0}})
+	if err != nil {
+		t.Fatalf("Failed to mute video: %v", err)
+	}
+	last, err := video.Cut(3, 5)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+
+	opts := moviego.ConcatenateOptions{AudioCrossfade: 0.5, AudioCrossfadeCurve: moviego.CrossfadeEqualPower}
+	timeline := moviego.NewTimeline().SetConcatenateOptions(opts)
+	for i := 0; i < 4; i++ {
+		timeline.Add(silent)
+	}
+	result, err := timeline.Add(last).Render()
+	if err != nil {
+		t.Fatalf("Failed to render timeline: %v", err)
+	}
+	samples, rate, err := result.GetAudioSamples(0, result.GetDuration())
+	if err != nil {
+		t.Fatalf("Failed to read audio samples: %v", err)
+	}
+	onset := -1.0
+	for i, s := range samples {
+		if math.Abs(s) > 1e-3 {
+			onset = float64(i) / float64(rate)
+			break
+		}
+	}
+	// Four cuts in, the last clip's audio still starts with its picture at 8 s.
+	if onset < 8-0.02 || onset > 8+opts.AudioCrossfade/2 {
+		t.Fatalf("Expected the last clip's audio to start at ~8s, got %f", onset)
+	}
+}
+
+func TestMixedAudioFormats(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to create video file: %v", err)
+	}
+	cut, err := video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	dir := t.TempDir()
+	load := func(name string, rate uint64, channels uint8) *moviego.Video {
+		path := filepath.Join(dir, name)
+		if err := cut.WriteVideo(moviego.VideoParameters{OutputPath: path, AudioSampleRate: rate, AudioChannels: channels, SilentProgress: true}); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		v, err := moviego.NewVideoFile(path)
+		if err != nil {
+			t.Fatalf("Failed to load %s: %v", name, err)
+		}
+		if v.GetAudio().GetSampleRate() != rate || v.GetAudio().GetChannels() != channels {
+			t.Fatalf("expected %s at %d Hz with %d channels, got %d Hz with %d", name, rate, channels, v.GetAudio().GetSampleRate(), v.GetAudio().GetChannels())
+		}
+		return v
+	}
+	mono := load("mono44k.mp4", 44100, 1)
+	stereo := load("stereo48k.mp4", 48000, 2)
+
+	concatenated, err := moviego.Concatenate([]moviego.Video{*mono, *stereo})
+	if err != nil {
+		t.Fatalf("Failed to concatenate: %v", err)
+	}
+	composited, err := moviego.CompositeClip([]moviego.Video{*mono, *stereo})
+	if err != nil {
+		t.Fatalf("Failed to composite: %v", err)
+	}
+	for name, result := range map[string]*moviego.Video{"concatenate": concatenated, "composite": composited} {
+		outputPath := filepath.Join(dir, name+".mp4")
+		if err := result.WriteVideo(moviego.VideoParameters{OutputPath: outputPath, SilentProgress: true}); err != nil {
+			t.Fatalf("%s: failed to write: %v", name, err)
+		}
+		// The inputs are converted to the highest rate and channel count.
+		out, err := exec.Command("ffprobe", "-v", "error", "-select_streams", "a:0", "-show_entries", "stream=sample_rate,channel_layout",
+			"-of", "csv=p=0", outputPath).Output()
+		if err != nil {
+			t.Fatalf("%s: failed to probe: %v", name, err)
+		}
+		if got := strings.TrimSpace(string(out)); got != "48000,stereo" {
+			t.Errorf("%s: expected 48000 Hz stereo, got %q", name, got)
+		}
+	}
+}
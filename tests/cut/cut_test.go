@@ -1,8 +1,16 @@
 package cut_test
 
 import (
+	"encoding/json"
+	"errors"
 	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	moviego "github.com/YounesseAmhend/MovieGo"
 	"github.com/YounesseAmhend/MovieGo/tests/common"
@@ -96,3 +104,554 @@ func TestNestedCut(t *testing.T) {
 		t.Fatalf("Expected height %d, got %d", video.GetHeight(), exportedVideo.GetHeight())
 	}
 }
+
+func TestCutAdaptiveThreads(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to create video file: %v", err)
+	}
+	cut, err := video.Cut(0, 3)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+
+	const exportPath = "output/adaptive_threads_cut.mp4"
+	err = cut.WriteVideo(moviego.VideoParameters{
+		OutputPath:      exportPath,
+		Threads:         4,
+		AdaptiveThreads: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to write video: %v", err)
+	}
+
+	out, err := moviego.NewVideoFile(exportPath)
+	if err != nil {
+		t.Fatalf("Failed to load output: %v", err)
+	}
+	if math.Abs(out.GetDuration()-3) > 0.2 {
+		t.Errorf("expected duration ~3, got %f", out.GetDuration())
+	}
+}
+
+func TestCutWorkingPixelFormat(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to create video file: %v", err)
+	}
+	cut, err := video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+
+	for _, pf := range []moviego.PixelFormat{moviego.PixelFormatYUV420P, moviego.PixelFormatSource} {
+		exportPath := "output/working_format_" + string(pf) + ".mp4"
+		err = cut.WriteVideo(moviego.VideoParameters{
+			OutputPath:         exportPath,
+			WorkingPixelFormat: pf,
+		})
+		if err != nil {
+			t.Fatalf("Failed to write video with working format %s: %v", pf, err)
+		}
+	}
+
+	err = cut.WriteVideo(moviego.VideoParameters{
+		OutputPath:         "output/working_format_invalid.mp4",
+		WorkingPixelFormat: "nv12le",
+	})
+	if err == nil {
+		t.Error("expected error for unsupported working pixel format")
+	}
+}
+
+func TestCutDirectWrite(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to create video file: %v", err)
+	}
+	cut, err := video.Cut(1, 5)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	nested, err := cut.Cut(1, 3)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+
+	const exportPath = "output/direct_cut.mp4"
+	if err := nested.WriteVideo(moviego.VideoParameters{OutputPath: exportPath}); err != nil {
+		t.Fatalf("Failed to write video: %v", err)
+	}
+	out, err := moviego.NewVideoFile(exportPath)
+	if err != nil {
+		t.Fatalf("Failed to load output: %v", err)
+	}
+	if math.Abs(out.GetDuration()-2) > 0.2 {
+		t.Errorf("expected duration ~2, got %f", out.GetDuration())
+	}
+
+	const copyPath = "output/direct_copy.mp4"
+	if err := video.WriteVideo(moviego.VideoParameters{OutputPath: copyPath, StreamCopy: true}); err != nil {
+		t.Fatalf("Failed to stream copy video: %v", err)
+	}
+
+	saturated, err := cut.Saturation(1.2)
+	if err != nil {
+		t.Fatalf("Failed to saturate: %v", err)
+	}
+	if err := saturated.WriteVideo(moviego.VideoParameters{OutputPath: "output/direct_invalid.mp4", StreamCopy: true}); err == nil {
+		t.Error("expected error for StreamCopy on a filtered video")
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	cases := map[string]float64{
+		"83.5":          83.5,
+		"1:23.5":        83.5,
+		"00:01:23.5":    83.5,
+		"1m23.5s":       83.5,
+		"2505@30":       83.5,
+		"30@30000/1001": 1.001,
+	}
+	for in, want := range cases {
+		got, err := moviego.ParseTime(in)
+		if err != nil {
+			t.Errorf("ParseTime(%q): %v", in, err)
+			continue
+		}
+		if math.Abs(got.Seconds()-want) > 1e-9 {
+			t.Errorf("ParseTime(%q) = %f, want %f", in, got.Seconds(), want)
+		}
+	}
+	for _, in := range []string{"", "abc", "1:75", "1.5:00", "10@0", "10@30000/0"} {
+		if _, err := moviego.ParseTime(in); err == nil {
+			t.Errorf("ParseTime(%q): expected error", in)
+		}
+	}
+	if s := moviego.Seconds(83.5).String(); s != "00:01:23.500" {
+		t.Errorf("unexpected String %q", s)
+	}
+	if f := moviego.Seconds(0.1).Frame(30); f != 3 {
+		t.Errorf("expected frame 3, got %d", f)
+	}
+	if f := moviego.Seconds(1.0 / 30).Frame(30); f != 1 {
+		t.Errorf("expected float seconds on a frame boundary to snap to frame 1, got %d", f)
+	}
+
+	// Frame times add up exactly, without float drift.
+	sum := moviego.Time{}
+	for i := 0; i < 3000; i++ {
+		sum = sum.Add(moviego.FrameTime(1, 30))
+	}
+	if ticks, base := sum.Rational(); ticks != 100 || base != 1 {
+		t.Errorf("expected exactly 100/1 s, got %d/%d", ticks, base)
+	}
+	if f := sum.Sub(moviego.FrameTime(1, 60)).Frame(30); f != 2999 {
+		t.Errorf("expected frame 2999, got %d", f)
+	}
+	if c := moviego.FromDuration(1500 * time.Millisecond).Compare(moviego.Seconds(1.5)); c != 0 {
+		t.Errorf("expected equal times, got %d", c)
+	}
+
+	// NTSC frames are exact at 30000/1001.
+	ntsc := moviego.FrameTimeRational(1798, 30000, 1001)
+	if ticks, base := ntsc.Rational(); ticks != 899899 || base != 15000 {
+		t.Errorf("expected exactly 899899/15000 s, got %d/%d", ticks, base)
+	}
+	if f := ntsc.FrameRational(30000, 1001); f != 1798 {
+		t.Errorf("expected frame 1798, got %d", f)
+	}
+	if f := moviego.Seconds(ntsc.Seconds()).FrameRational(30000, 1001); f != 1798 {
+		t.Errorf("expected float seconds on an NTSC frame boundary to snap to frame 1798, got %d", f)
+	}
+	if c := moviego.Seconds(60).SnapRational(30000, 1001).Compare(ntsc); c != 0 {
+		t.Errorf("expected 60s to snap back to frame 1798, got %s", moviego.Seconds(60).SnapRational(30000, 1001))
+	}
+}
+
+func TestCutSidecar(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to create video file: %v", err)
+	}
+	cut, err := video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	const exportPath = "output/sidecar_cut.mp4"
+	if err := cut.WriteVideo(moviego.VideoParameters{OutputPath: exportPath, Sidecar: true, SilentProgress: true}); err != nil {
+		t.Fatalf("Failed to write video: %v", err)
+	}
+	data, err := os.ReadFile(exportPath + ".json")
+	if err != nil {
+		t.Fatalf("Failed to read sidecar: %v", err)
+	}
+	var sidecar moviego.Sidecar
+	if err := json.Unmarshal(data, &sidecar); err != nil {
+		t.Fatalf("Failed to parse sidecar: %v", err)
+	}
+	if len(sidecar.SHA256) != 64 || len(sidecar.Streams) == 0 || len(sidecar.Command) == 0 || sidecar.Encoding.Encoder == "" {
+		t.Errorf("incomplete sidecar: %+v", sidecar)
+	}
+	if math.Abs(sidecar.Duration-2) > 0.2 {
+		t.Errorf("expected duration ~2, got %f", sidecar.Duration)
+	}
+}
+
+func TestCutEncoderOptions(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to create video file: %v", err)
+	}
+	cut, err := video.Cut(0, 1)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	parms := moviego.VideoParameters{
+		OutputPath:     "output/encoder_options.mp4",
+		Codec:          moviego.CodecLibx264,
+		SilentProgress: true,
+		EncoderOptions: moviego.EncoderOptions{
+			X264:  &moviego.X264Options{Tune: "film", CRF: 20, Params: map[string]string{"bframes": "2"}},
+			NVENC: &moviego.NVENCOptions{RateControl: "vbr"}, // ignored for libx264
+		},
+	}
+	if err := cut.WriteVideo(parms); err != nil {
+		t.Fatalf("Failed to write video: %v", err)
+	}
+}
+
+func TestCutTune(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to create video file: %v", err)
+	}
+	cut, err := video.Cut(0, 1)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	for _, tune := range []moviego.Tune{moviego.TuneFilm, moviego.TuneAnimation, moviego.TuneScreen, moviego.TuneLowLatency} {
+		parms := moviego.VideoParameters{OutputPath: "output/tune_" + string(tune) + ".mp4", Codec: moviego.CodecLibx264, Tune: tune, SilentProgress: true}
+		if err := cut.WriteVideo(parms); err != nil {
+			t.Errorf("Failed to write with tune %s: %v", tune, err)
+		}
+	}
+	if err := cut.WriteVideo(moviego.VideoParameters{OutputPath: "output/tune_invalid.mp4", Tune: "grainy"}); err == nil {
+		t.Error("expected an error for an unknown tune")
+	}
+}
+
+func TestCutKeyframes(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to create video file: %v", err)
+	}
+	cut, err := video.Cut(0, 3)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	outputPath := "output/keyframes.mp4"
+	err = cut.WriteVideo(moviego.VideoParameters{
+		OutputPath:     outputPath,
+		Codec:          moviego.CodecLibx264,
+		Fps:            30,
+		Keyframes:      moviego.KeyframeOptions{Interval: 1, MinInterval: 1, At: []float64{1.5}, NoSceneCut: true},
+		SilentProgress: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to write video: %v", err)
+	}
+
+	out, err := exec.Command("ffprobe", "-v", "error", "-select_streams", "v", "-skip_frame", "nokey",
+		"-show_entries", "frame=pts_time", "-of", "csv=p=0", outputPath).Output()
+	if err != nil {
+		t.Fatalf("Failed to probe keyframes: %v", err)
+	}
+	var keyframes []float64
+	for _, line := range strings.Fields(string(out)) {
+		if ts, err := strconv.ParseFloat(strings.TrimSuffix(line, ","), 64); err == nil {
+			keyframes = append(keyframes, ts)
+		}
+	}
+	for _, want := range []float64{0, 1, 1.5} {
+		found := false
+		for _, ts := range keyframes {
+			if math.Abs(ts-want) < 0.02 {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Expected a keyframe at %.2fs, got %v", want, keyframes)
+		}
+	}
+
+	// With libx265, scenecut=0 joins the encoder's own -x265-params.
+	params := map[string]string{"bframes": "2"}
+	err = cut.WriteVideo(moviego.VideoParameters{
+		OutputPath:     "output/keyframes_x265.mp4",
+		Codec:          moviego.CodecLibx265,
+		Keyframes:      moviego.KeyframeOptions{Interval: 1, NoSceneCut: true},
+		EncoderOptions: moviego.EncoderOptions{X264: &moviego.X264Options{Params: params}},
+		SilentProgress: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to write x265 video: %v", err)
+	}
+	if len(params) != 1 {
+		t.Errorf("expected the caller's params to be left unchanged, got %v", params)
+	}
+
+	bad := moviego.KeyframeOptions{Interval: 1, MinInterval: 2}
+	if err := cut.WriteVideo(moviego.VideoParameters{OutputPath: "output/keyframes_invalid.mp4", Keyframes: bad}); err == nil {
+		t.Error("expected an error for a min interval above the interval")
+	}
+}
+
+func TestCutBroadcastDelivery(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to create video file: %v", err)
+	}
+	cut, err := video.Cut(0, 1)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	profile := moviego.DeliveryXDCAMHD422_1080i50
+	if err := cut.WriteVideo(moviego.VideoParameters{OutputPath: "output/broadcast.mp4", Delivery: &profile}); err == nil {
+		t.Error("expected an error for an output outside the profile's container")
+	}
+
+	outputPath := "output/broadcast.mxf"
+	err = cut.WriteVideo(moviego.VideoParameters{OutputPath: outputPath, Delivery: &profile, SilentProgress: true})
+	if err != nil {
+		t.Fatalf("Failed to write broadcast file: %v", err)
+	}
+	out, err := exec.Command("ffprobe", "-v", "error", "-select_streams", "v:0",
+		"-show_entries", "stream=codec_name,width,height,pix_fmt,field_order", "-of", "csv=p=0", outputPath).Output()
+	if err != nil {
+		t.Fatalf("Failed to probe output: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "mpeg2video,1920,1080,yuv422p,tt" {
+		t.Errorf("Expected mpeg2video,1920,1080,yuv422p,tt, got %s", got)
+	}
+}
+
+func TestCutCaptions(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to create video file: %v", err)
+	}
+	cut, err := video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	scc := filepath.Join(t.TempDir(), "captions.scc")
+	// "HELLO" in pop-on mode at 00:00:00:00.
+	data := "Scenarist_SCC V1.0\n\n00:00:00:00\t9420 9420 94ae 94ae 9452 9452 97a2 97a2 c845 4c4c cf80 942c 942c 942f 942f\n\n"
+	if err := os.WriteFile(scc, []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write SCC: %v", err)
+	}
+
+	if err := cut.WriteVideo(moviego.VideoParameters{OutputPath: "output/captions.mp4", Captions: moviego.CaptionOptions{SCC: scc}}); err == nil {
+		t.Error("expected an error for SCC captions outside a .mov")
+	}
+	if err := cut.WriteVideo(moviego.VideoParameters{OutputPath: "output/captions.mov", Codec: moviego.CodecLibvpxVP9, Captions: moviego.CaptionOptions{Preserve: true}}); err == nil {
+		t.Error("expected an error for an encoder without caption support")
+	}
+
+	outputPath := "output/captions.mov"
+	err = cut.WriteVideo(moviego.VideoParameters{
+		OutputPath:     outputPath,
+		Codec:          moviego.CodecLibx264,
+		Captions:       moviego.CaptionOptions{Preserve: true, SCC: scc},
+		SilentProgress: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to write video with captions: %v", err)
+	}
+	out, err := exec.Command("ffprobe", "-v", "error", "-select_streams", "s",
+		"-show_entries", "stream=codec_name", "-of", "csv=p=0", outputPath).Output()
+	if err != nil {
+		t.Fatalf("Failed to probe output: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "eia_608" {
+		t.Errorf("Expected an eia_608 caption track, got %q", got)
+	}
+}
+
+func TestCutTimecode(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to create video file: %v", err)
+	}
+	if _, err := video.SetStartTimecode("01:00:00:99"); err == nil {
+		t.Error("expected an error for a frame number above the frame rate")
+	}
+	if _, err := video.SetStartTimecode("1h"); err == nil {
+		t.Error("expected an error for a malformed timecode")
+	}
+
+	stamped, err := video.SetStartTimecode("01:00:00:00")
+	if err != nil {
+		t.Fatalf("Failed to set timecode: %v", err)
+	}
+	cut, err := stamped.Cut(1, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	if got := cut.GetStartTimecode(); got != "01:00:01:00" {
+		t.Errorf("Expected the cut to start at 01:00:01:00, got %s", got)
+	}
+
+	outputPath := "output/timecode.mov"
+	if err := cut.WriteVideo(moviego.VideoParameters{OutputPath: outputPath, SilentProgress: true}); err != nil {
+		t.Fatalf("Failed to write video: %v", err)
+	}
+	out, err := moviego.NewVideoFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to probe output: %v", err)
+	}
+	if got := out.GetStartTimecode(); got != "01:00:01:00" {
+		t.Errorf("Expected output timecode 01:00:01:00, got %q", got)
+	}
+}
+
+func TestCutNormalizeLoudness(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to create video file: %v", err)
+	}
+	cut, err := video.Cut(0, 3)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	invalid := moviego.LoudnessTarget{Integrated: 3}
+	if err := cut.WriteVideo(moviego.VideoParameters{OutputPath: "output/loudness.mp4", NormalizeLoudness: &invalid}); err == nil {
+		t.Error("expected an error for a positive loudness target")
+	}
+
+	outputPath := "output/loudness.mp4"
+	target := moviego.LoudnessYouTube
+	err = cut.WriteVideo(moviego.VideoParameters{OutputPath: outputPath, NormalizeLoudness: &target, SilentProgress: true})
+	if err != nil {
+		t.Fatalf("Failed to write normalized video: %v", err)
+	}
+	out, err := exec.Command("ffmpeg", "-hide_banner", "-nostats", "-i", outputPath,
+		"-af", "loudnorm=print_format=json", "-f", "null", "-").CombinedOutput()
+	if err != nil {
+		t.Fatalf("Failed to measure output: %v", err)
+	}
+	report := string(out)
+	var measured struct {
+		InputI string `json:"input_i"`
+	}
+	if err := json.Unmarshal([]byte(report[strings.LastIndex(report, "{"):strings.LastIndex(report, "}")+1]), &measured); err != nil {
+		t.Fatalf("Failed to parse loudness: %v", err)
+	}
+	integrated, err := strconv.ParseFloat(measured.InputI, 64)
+	if err != nil {
+		t.Fatalf("Failed to parse integrated loudness %q: %v", measured.InputI, err)
+	}
+	if math.Abs(integrated-target.Integrated) > 1 {
+		t.Errorf("Expected integrated loudness %.1f LUFS, got %.2f", target.Integrated, integrated)
+	}
+
+	// 0 dBTP is a valid ceiling, and a video without audio is left as is.
+	zero := 0.0
+	ceiling := moviego.LoudnessTarget{Integrated: -14, TruePeak: &zero}
+	silentPath := "output/loudness_no_audio_src.mp4"
+	if out, err := exec.Command("ffmpeg", "-y", "-i", common.TestVideoPath, "-t", "2", "-an", silentPath).CombinedOutput(); err != nil {
+		t.Fatalf("Failed to create a video without audio: %v\n%s", err, out)
+	}
+	silent, err := moviego.NewVideoFile(silentPath)
+	if err != nil {
+		t.Fatalf("Failed to load video without audio: %v", err)
+	}
+	err = silent.WriteVideo(moviego.VideoParameters{OutputPath: "output/loudness_silent.mp4", NormalizeLoudness: &ceiling, SilentProgress: true})
+	if err != nil {
+		t.Fatalf("Failed to write silent video with a loudness target: %v", err)
+	}
+}
+
+func TestVerifyDuration(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	cut, err := video.Cut(1, 3)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	err = cut.WriteVideo(moviego.VideoParameters{
+		OutputPath:     "output/verify_duration.mp4",
+		VerifyDuration: &moviego.DurationCheck{},
+		SilentProgress: true,
+	})
+	if err != nil {
+		t.Fatalf("Expected the cut to match its plan: %v", err)
+	}
+
+	// A plan longer than the content the graph produces.
+	planned := *cut
+	planned.Duration(5)
+	err = planned.WriteVideo(moviego.VideoParameters{
+		OutputPath:     "output/verify_duration_mismatch.mp4",
+		VerifyDuration: &moviego.DurationCheck{Tolerance: 0.2},
+		SilentProgress: true,
+	})
+	var mismatch *moviego.DurationMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("Expected a DurationMismatchError, got %v", err)
+	}
+	err = planned.WriteVideo(moviego.VideoParameters{
+		OutputPath:     "output/verify_duration_warn.mp4",
+		VerifyDuration: &moviego.DurationCheck{WarnOnly: true},
+		SilentProgress: true,
+	})
+	if err != nil {
+		t.Errorf("Expected only a warning, got %v", err)
+	}
+}
+
+func TestLoop(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	cut, err := video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut: %v", err)
+	}
+
+	looped, err := cut.Loop(3)
+	if err != nil {
+		t.Fatalf("Failed to loop: %v", err)
+	}
+	if math.Abs(looped.GetDuration()-6) > 1e-9 {
+		t.Errorf("expected planned duration 6, got %f", looped.GetDuration())
+	}
+	outputPath := filepath.Join("output", "loop_3.mp4")
+	check := &moviego.DurationCheck{Tolerance: 0.15}
+	if err := looped.WriteVideo(moviego.VideoParameters{OutputPath: outputPath, VerifyDuration: check, SilentProgress: true}); err != nil {
+		t.Fatalf("Failed to write loop: %v", err)
+	}
+
+	extended, err := cut.LoopToDuration(5)
+	if err != nil {
+		t.Fatalf("Failed to loop to duration: %v", err)
+	}
+	if math.Abs(extended.GetDuration()-5) > 1e-9 {
+		t.Errorf("expected planned duration 5, got %f", extended.GetDuration())
+	}
+	outputPath = filepath.Join("output", "loop_to_5s.mp4")
+	if err := extended.WriteVideo(moviego.VideoParameters{OutputPath: outputPath, VerifyDuration: check, SilentProgress: true}); err != nil {
+		t.Fatalf("Failed to write loop to duration: %v", err)
+	}
+
+	if _, err := cut.Loop(0); err == nil {
+		t.Error("expected error for zero count")
+	}
+	if _, err := cut.LoopToDuration(-1); err == nil {
+		t.Error("expected error for negative duration")
+	}
+}
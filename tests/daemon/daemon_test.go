@@ -0,0 +1,74 @@
+package daemon_test
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	moviego "github.com/YounesseAmhend/MovieGo"
+	"github.com/YounesseAmhend/MovieGo/tests/common"
+)
+
+func TestDaemonRender(t *testing.T) {
+	if err := os.MkdirAll("output", 0755); err != nil {
+		t.Fatal(err)
+	}
+	socket := filepath.Join(t.TempDir(), "moviego.sock")
+	d, err := moviego.NewDaemon(moviego.DaemonConfig{
+		Socket:   socket,
+		Encoders: []string{"libx264"},
+		Jobs: map[string]moviego.DaemonJob{
+			"clip": func(args map[string]string, _ *moviego.AssetManager) (*moviego.Video, error) {
+				end, err := strconv.ParseFloat(args["end"], 64)
+				if err != nil {
+					return nil, err
+				}
+				v, err := moviego.NewVideoFile(common.TestVideoPath)
+				if err != nil {
+					return nil, err
+				}
+				return v.Cut(0, end)
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to start daemon: %v", err)
+	}
+	if info, err := os.Stat(socket); err != nil {
+		t.Errorf("expected the socket: %v", err)
+	} else if info.Mode().Perm() != 0600 {
+		t.Errorf("expected a 0600 socket, got %v", info.Mode().Perm())
+	}
+	stop := make(chan struct{})
+	done := make(chan error)
+	go func() { done <- d.Serve(stop) }()
+
+	outputPath := filepath.Join("output", "daemon.mp4")
+	resp, err := moviego.DaemonRender(socket, moviego.DaemonRequest{ID: "1", Job: "clip", Output: outputPath, Args: map[string]string{"end": "1"}})
+	if err != nil {
+		t.Fatalf("DaemonRender: %v", err)
+	}
+	if !resp.Success || resp.ID != "1" {
+		t.Errorf("expected a successful render with id 1, got %+v", resp)
+	}
+	if _, err := os.Stat(outputPath); err != nil {
+		t.Errorf("expected output file: %v", err)
+	}
+
+	resp, err = moviego.DaemonRender(socket, moviego.DaemonRequest{Job: "missing", Output: outputPath})
+	if err != nil {
+		t.Fatalf("DaemonRender: %v", err)
+	}
+	if resp.Success || resp.Error == "" {
+		t.Errorf("expected an error for an unknown job, got %+v", resp)
+	}
+
+	close(stop)
+	if err := <-done; err != nil {
+		t.Errorf("Serve: %v", err)
+	}
+	if _, err := os.Stat(socket); !os.IsNotExist(err) {
+		t.Errorf("expected the socket to be removed, got %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package encryption_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	moviego "github.com/YounesseAmhend/MovieGo"
+	"github.com/YounesseAmhend/MovieGo/tests/common"
+)
+
+func TestMain(m *testing.M) {
+	_ = os.MkdirAll("output/hls", 0755)
+	_ = os.MkdirAll("output/hls_sample_aes", 0755)
+	os.Exit(m.Run())
+}
+
+func cutVideo(t *testing.T) *moviego.Video {
+	t.Helper()
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	cut, err := video.Cut(0, 4)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	return cut
+}
+
+func TestEncryptedHLS(t *testing.T) {
+	cut := cutVideo(t)
+	key := bytes.Repeat([]byte{0x42}, 16)
+	playlist := "output/hls/index.m3u8"
+	err := cut.WriteVideo(moviego.VideoParameters{
+		OutputPath: playlist,
+		HLS: moviego.HLSOptions{
+			SegmentDuration: 2,
+			Encryption: &moviego.HLSEncryption{
+				Key: func() ([]byte, string, error) { return key, "https://keys.example.com/42", nil },
+			},
+		},
+		SilentProgress: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to write HLS: %v", err)
+	}
+	data, err := os.ReadFile(playlist)
+	if err != nil {
+		t.Fatalf("Failed to read playlist: %v", err)
+	}
+	if !strings.Contains(string(data), `#EXT-X-KEY:METHOD=AES-128,URI="https://keys.example.com/42"`) {
+		t.Errorf("Expected an AES-128 key tag, got:\n%s", data)
+	}
+	segments, _ := filepath.Glob("output/hls/index_*.ts")
+	if len(segments) < 2 {
+		t.Errorf("Expected at least 2 segments, got %v", segments)
+	}
+
+	err = cut.WriteVideo(moviego.VideoParameters{
+		OutputPath: "output/hls_sample_aes/index.m3u8",
+		HLS: moviego.HLSOptions{Encryption: &moviego.HLSEncryption{
+			Method: moviego.HLSSampleAES,
+			Key:    func() ([]byte, string, error) { return key, "key", nil },
+		}},
+	})
+	if err == nil {
+		t.Error("Expected an error for SAMPLE-AES")
+	}
+}
+
+func TestCENC(t *testing.T) {
+	cut := cutVideo(t)
+	outputPath := "output/cenc.mp4"
+	err := cut.WriteVideo(moviego.VideoParameters{
+		OutputPath:     outputPath,
+		CENC:           &moviego.CENCEncryption{KeyID: bytes.Repeat([]byte{1}, 16), Key: bytes.Repeat([]byte{2}, 16)},
+		SilentProgress: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to write encrypted video: %v", err)
+	}
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	// Encrypted tracks carry a protection scheme box.
+	if !bytes.Contains(data, []byte("schm")) || !bytes.Contains(data, []byte("cenc")) {
+		t.Error("Expected a cenc protection scheme in the output")
+	}
+
+	err = cut.WriteVideo(moviego.VideoParameters{OutputPath: "output/cenc_short_key.mp4", CENC: &moviego.CENCEncryption{KeyID: []byte{1}, Key: []byte{2}}})
+	if err == nil {
+		t.Error("Expected an error for a short key")
+	}
+}
@@ -0,0 +1,86 @@
+package executor_test
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	moviego "github.com/YounesseAmhend/MovieGo"
+	"github.com/YounesseAmhend/MovieGo/tests/common"
+)
+
+// recordingExecutor runs commands locally and records their programs.
+type recordingExecutor struct {
+	moviego.LocalExecutor
+	mu       sync.Mutex
+	programs []string
+}
+
+func (r *recordingExecutor) Run(cmd *moviego.Command) error {
+	r.mu.Lock()
+	r.programs = append(r.programs, filepath.Base(cmd.Path))
+	r.mu.Unlock()
+	return r.LocalExecutor.Run(cmd)
+}
+
+// offlineExecutor refuses to run anything, like a sandbox without processes.
+type offlineExecutor struct{}
+
+var errOffline = errors.New("process execution is not available")
+
+func (offlineExecutor) LookPath(name string) (string, error) { return name, nil }
+func (offlineExecutor) Run(*moviego.Command) error           { return errOffline }
+
+func TestSetExecutor(t *testing.T) {
+	defer moviego.SetExecutor(nil)
+
+	recorder := &recordingExecutor{}
+	moviego.SetExecutor(recorder)
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	cut, err := video.Cut(0, 1)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	if err := cut.WriteVideo(moviego.VideoParameters{OutputPath: "output/executor.mp4", SilentProgress: true}); err != nil {
+		t.Fatalf("Failed to write video: %v", err)
+	}
+	programs := strings.Join(recorder.programs, " ")
+	if !strings.Contains(programs, "ffprobe") || !strings.Contains(programs, "ffmpeg") {
+		t.Errorf("expected ffprobe and ffmpeg to run through the executor, got %q", programs)
+	}
+
+	moviego.SetExecutor(offlineExecutor{})
+	if _, err := moviego.NewVideoFile(common.TestVideoPath); !errors.Is(err, errOffline) {
+		t.Errorf("expected the executor error, got %v", err)
+	}
+}
+
+func TestSetExecutorConcurrentProbes(t *testing.T) {
+	defer moviego.SetExecutor(nil)
+
+	// Swapping executors while other goroutines probe must not race (go test -race).
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			moviego.SetExecutor(offlineExecutor{})
+		}()
+		go func() {
+			defer wg.Done()
+			if path, err := moviego.FFmpegPath(); err == nil && path == "" {
+				t.Error("expected an ffmpeg path")
+			}
+		}()
+	}
+	wg.Wait()
+	moviego.SetExecutor(offlineExecutor{})
+	if path, err := moviego.FFmpegPath(); err != nil || path != "ffmpeg" {
+		t.Errorf("expected the path of the current executor, got %q (%v)", path, err)
+	}
+}
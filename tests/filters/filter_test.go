@@ -236,3 +236,216 @@ func TestMain(m *testing.M) {
 	_ = os.MkdirAll("output", 0755)
 	os.Exit(m.Run())
 }
+
+func TestAutoCrop(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to create video file: %v", err)
+	}
+	cut, err := video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	w, h := int(cut.GetWidth())&^1, int(cut.GetHeight())&^1
+	letterboxed, err := cut.Pad(moviego.PadParams{Width: w, Height: h + 200, X: 0, Y: 100})
+	if err != nil {
+		t.Fatalf("Failed to pad video: %v", err)
+	}
+
+	crop, err := letterboxed.DetectCrop(moviego.CropDetectOptions{})
+	if err != nil {
+		t.Fatalf("Failed to detect crop: %v", err)
+	}
+	if crop.Y < 90 || crop.Y > 110 || crop.Height < h-20 || crop.Height > h+4 {
+		t.Errorf("expected the 100px bars to be detected, got %+v", crop)
+	}
+
+	cropped, err := letterboxed.AutoCrop(moviego.CropDetectOptions{})
+	if err != nil {
+		t.Fatalf("Failed to auto crop: %v", err)
+	}
+	if int(cropped.GetHeight()) != crop.Height {
+		t.Errorf("expected height %d, got %d", crop.Height, cropped.GetHeight())
+	}
+}
+
+func TestAnalyzeDuplicates(t *testing.T) {
+	static, err := moviego.CompositeClips(moviego.NewColorClip("red", 320, 240, 2).Fps(30))
+	if err != nil {
+		t.Fatalf("Failed to build clip: %v", err)
+	}
+	report, err := static.AnalyzeDuplicates(nil)
+	if err != nil {
+		t.Fatalf("Failed to analyze duplicates: %v", err)
+	}
+	if report.TotalFrames < 55 || report.Percentage < 90 || len(report.Runs) != 1 {
+		t.Errorf("expected a static clip to be reported as one run of duplicates, got %d frames, %.1f%%, %d runs",
+			report.TotalFrames, report.Percentage, len(report.Runs))
+	}
+
+	decimated, err := static.Decimate(10)
+	if err != nil {
+		t.Fatalf("Failed to decimate: %v", err)
+	}
+	if decimated.GetFps() != 10 || math.Abs(decimated.GetDuration()-2) > 0.01 {
+		t.Errorf("expected 10fps over 2s, got %dfps over %.2fs", decimated.GetFps(), decimated.GetDuration())
+	}
+}
+
+func TestEncodeROI(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	cut, err := video.Cut(0, 3)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	center := moviego.Rect{X: moviego.Percent(25), Y: moviego.Percent(25), Width: moviego.Percent(50), Height: moviego.Percent(50)}
+	corner := moviego.Rect{X: moviego.Px(0), Y: moviego.Px(0), Width: moviego.Percent(25), Height: moviego.Percent(25)}
+
+	for name, regions := range map[string][]moviego.ROI{
+		"static": {{Region: center, QOffset: -0.5}},
+		"tracked": {{Track: []moviego.ROIKey{{Time: 0, Region: corner}, {Time: 1.5, Region: center}}},
+			{Region: corner, Start: 1, End: 2, QOffset: 0.5}},
+	} {
+		roi, err := cut.EncodeROI(regions...)
+		if err != nil {
+			t.Fatalf("%s: failed to add regions: %v", name, err)
+		}
+		outputPath := fmt.Sprintf("output/test_roi_%s.mp4", name)
+		if err := roi.WriteVideo(moviego.VideoParameters{OutputPath: outputPath, Codec: moviego.CodecLibx264, Bitrate: "300k", SilentProgress: true}); err != nil {
+			t.Fatalf("%s: failed to write video: %v", name, err)
+		}
+		out, err := moviego.NewVideoFile(outputPath)
+		if err != nil {
+			t.Fatalf("%s: failed to probe output: %v", name, err)
+		}
+		if math.Abs(out.GetDuration()-3) > 0.15 {
+			t.Errorf("%s: expected duration ~3, got %f", name, out.GetDuration())
+		}
+	}
+
+	if _, err := cut.EncodeROI(moviego.ROI{Region: center, QOffset: -2}); err == nil {
+		t.Error("Expected an error for an out of range qoffset")
+	}
+	if _, err := cut.EncodeROI(moviego.ROI{Region: center, Start: 2, End: 1}); err == nil {
+		t.Error("Expected an error for an inverted time range")
+	}
+}
+
+func TestResize(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	cut, err := video.Cut(0, 1)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+
+	for _, mode := range []moviego.ResizeMode{moviego.ResizeFit, moviego.ResizeFill, moviego.ResizeStretch, moviego.ResizeLetterbox} {
+		resized, err := cut.Resize(301, 301, mode)
+		if err != nil {
+			t.Fatalf("%s: failed to resize: %v", mode, err)
+		}
+		if resized.GetWidth()%2 != 0 || resized.GetHeight()%2 != 0 {
+			t.Errorf("%s: expected even dimensions, got %dx%d", mode, resized.GetWidth(), resized.GetHeight())
+		}
+		if mode != moviego.ResizeFit && (resized.GetWidth() != 300 || resized.GetHeight() != 300) {
+			t.Errorf("%s: expected 300x300, got %dx%d", mode, resized.GetWidth(), resized.GetHeight())
+		}
+		outputPath := fmt.Sprintf("output/test_resize_%s.mp4", mode)
+		if err := resized.WriteVideo(moviego.VideoParameters{OutputPath: outputPath, SilentProgress: true}); err != nil {
+			t.Fatalf("%s: failed to write video: %v", mode, err)
+		}
+		out, err := moviego.NewVideoFile(outputPath)
+		if err != nil {
+			t.Fatalf("%s: failed to probe output: %v", mode, err)
+		}
+		if out.GetWidth() != resized.GetWidth() || out.GetHeight() != resized.GetHeight() {
+			t.Errorf("%s: expected %dx%d, got %dx%d", mode, resized.GetWidth(), resized.GetHeight(), out.GetWidth(), out.GetHeight())
+		}
+	}
+
+	if _, err := cut.Resize(320, 240, "zoom"); err == nil {
+		t.Error("Expected an error for an unknown mode")
+	}
+}
+
+func TestPadEdges(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	cut, err := video.Cut(0, 1)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	w, h := int(cut.GetWidth()), int(cut.GetHeight())
+	side := max(w, h)
+	square, err := cut.PadEdges((side-h)/2, (side-h)/2, (side-w)/2, (side-w)/2, "#1DB954")
+	if err != nil {
+		t.Fatalf("Failed to pad video: %v", err)
+	}
+	const outputPath = "output/test_pad_edges.mp4"
+	if err := square.WriteVideo(moviego.VideoParameters{OutputPath: outputPath, SilentProgress: true}); err != nil {
+		t.Fatalf("Failed to write video: %v", err)
+	}
+	out, err := moviego.NewVideoFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to probe output: %v", err)
+	}
+	if out.GetWidth() != square.GetWidth() || out.GetHeight() != square.GetHeight() || out.GetWidth() != out.GetHeight() {
+		t.Errorf("Expected a %dx%d square, got %dx%d", square.GetWidth(), square.GetHeight(), out.GetWidth(), out.GetHeight())
+	}
+
+	if _, err := cut.PadEdges(-1, 0, 0, 0, ""); err == nil {
+		t.Error("Expected an error for negative padding")
+	}
+}
+
+func TestStaticRotation(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	cut, err := video.Cut(0, 1)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	w, h := cut.GetWidth(), cut.GetHeight()
+
+	for name, rotate := range map[string]func() (*moviego.Video, error){
+		"90":  cut.Rotate90,
+		"180": cut.Rotate180,
+		"-90": func() (*moviego.Video, error) { return cut.RotateDegrees(-90) },
+		"30":  func() (*moviego.Video, error) { return cut.RotateDegrees(30) },
+	} {
+		rotated, err := rotate()
+		if err != nil {
+			t.Fatalf("%s: failed to rotate: %v", name, err)
+		}
+		switch name {
+		case "90", "-90":
+			if rotated.GetWidth() != h || rotated.GetHeight() != w {
+				t.Errorf("%s: expected %dx%d, got %dx%d", name, h, w, rotated.GetWidth(), rotated.GetHeight())
+			}
+		case "30":
+			if rotated.GetWidth() <= w || rotated.GetHeight() <= h {
+				t.Errorf("%s: expected the frame to grow from %dx%d, got %dx%d", name, w, h, rotated.GetWidth(), rotated.GetHeight())
+			}
+		}
+		outputPath := fmt.Sprintf("output/test_rotate_%s.mp4", name)
+		if err := rotated.WriteVideo(moviego.VideoParameters{OutputPath: outputPath, SilentProgress: true}); err != nil {
+			t.Fatalf("%s: failed to write video: %v", name, err)
+		}
+		out, err := moviego.NewVideoFile(outputPath)
+		if err != nil {
+			t.Fatalf("%s: failed to probe output: %v", name, err)
+		}
+		if out.GetWidth() != rotated.GetWidth() || out.GetHeight() != rotated.GetHeight() {
+			t.Errorf("%s: expected %dx%d, got %dx%d", name, rotated.GetWidth(), rotated.GetHeight(), out.GetWidth(), out.GetHeight())
+		}
+	}
+}
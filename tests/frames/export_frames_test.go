@@ -0,0 +1,59 @@
+package frames_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	moviego "github.com/YounesseAmhend/MovieGo"
+	"github.com/YounesseAmhend/MovieGo/tests/common"
+)
+
+func TestExportFrames(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	cut, err := video.Cut(0, 3)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+
+	dir := "output/export_frames"
+	manifest, err := cut.ExportFrames(dir, 1, moviego.ExportFramesOptions{
+		Format: "png",
+		Crop:   &moviego.Rect{X: moviego.Percent(25), Y: moviego.Percent(25), Width: moviego.Percent(50), Height: moviego.Percent(50)},
+		Width:  128,
+	})
+	if err != nil {
+		t.Fatalf("Failed to export frames: %v", err)
+	}
+	if len(manifest.Frames) != 3 {
+		t.Fatalf("Expected 3 frames, got %d", len(manifest.Frames))
+	}
+	if manifest.Width != 128 || manifest.Crop == nil {
+		t.Errorf("Unexpected size or crop: %dx%d, crop=%v", manifest.Width, manifest.Height, manifest.Crop)
+	}
+	for i, f := range manifest.Frames {
+		if _, err := os.Stat(filepath.Join(dir, f.File)); err != nil {
+			t.Errorf("Frame %d was not written: %v", i, err)
+		}
+		if f.Time != float64(i) || f.Index != int64(i)*int64(video.GetFps()) {
+			t.Errorf("Frame %d: unexpected time %f or index %d", i, f.Time, f.Index)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		t.Fatalf("Failed to read manifest: %v", err)
+	}
+	var saved moviego.FrameManifest
+	if err := json.Unmarshal(data, &saved); err != nil || len(saved.Frames) != 3 {
+		t.Errorf("Unexpected manifest: %v\n%s", err, data)
+	}
+
+	if _, err := cut.ExportFrames(dir, 0, moviego.ExportFramesOptions{}); err == nil {
+		t.Error("Expected an error for a zero interval")
+	}
+}
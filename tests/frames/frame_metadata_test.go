@@ -0,0 +1,54 @@
+package frames_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	moviego "github.com/YounesseAmhend/MovieGo"
+	"github.com/YounesseAmhend/MovieGo/tests/common"
+)
+
+func TestFrameMetadata(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	cut, err := video.Cut(0, 1)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	analyzed, err := cut.EmitFrameMetadata(moviego.FrameBrightness)
+	if err != nil {
+		t.Fatalf("Failed to add analyzer: %v", err)
+	}
+
+	outputPath := "output/test_frame_metadata.mp4"
+	err = analyzed.WriteVideo(moviego.VideoParameters{OutputPath: outputPath, FrameMetadata: true, SilentProgress: true})
+	if err != nil {
+		t.Fatalf("Failed to write video: %v", err)
+	}
+	data, err := os.ReadFile(outputPath + ".frames.json")
+	if err != nil {
+		t.Fatalf("Failed to read frame metadata: %v", err)
+	}
+	var frames []moviego.FrameMetadata
+	if err := json.Unmarshal(data, &frames); err != nil {
+		t.Fatalf("Invalid frame metadata: %v", err)
+	}
+	if len(frames) < int(video.GetFps())-1 {
+		t.Fatalf("Expected a record per frame, got %d", len(frames))
+	}
+	for i, f := range frames {
+		if _, ok := f.Values["lavfi.signalstats.YAVG"]; !ok {
+			t.Fatalf("Frame %d has no brightness: %v", i, f.Values)
+		}
+		if i > 0 && f.Time <= frames[i-1].Time {
+			t.Fatalf("Frame %d: timestamps are not increasing", i)
+		}
+	}
+
+	if _, err := cut.EmitFrameMetadata(""); err == nil {
+		t.Error("Expected an error for an empty analyzer")
+	}
+}
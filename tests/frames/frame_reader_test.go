@@ -0,0 +1,65 @@
+package frames_test
+
+import (
+	"io"
+	"testing"
+
+	moviego "github.com/YounesseAmhend/MovieGo"
+	"github.com/YounesseAmhend/MovieGo/tests/common"
+)
+
+func TestFrameReader(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to create video file: %v", err)
+	}
+	clip, err := video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	reader, err := moviego.NewFrameReader(clip)
+	if err != nil {
+		t.Fatalf("Failed to open frame reader: %v", err)
+	}
+
+	// Random access: jump forward, then back to the first frame.
+	if err := reader.Seek(1.5); err != nil {
+		t.Fatalf("Failed to seek: %v", err)
+	}
+	late, err := reader.Next()
+	if err != nil {
+		t.Fatalf("Failed to read frame: %v", err)
+	}
+	if late.Time > 1.5 || 1.5-late.Time > 0.1 {
+		t.Errorf("Expected the frame at 1.5s, got %.4fs", late.Time)
+	}
+	bounds := late.Image.Bounds()
+	if bounds.Dx() != int(clip.GetWidth()) || bounds.Dy() != int(clip.GetHeight()) {
+		t.Errorf("Expected %dx%d frames, got %dx%d", clip.GetWidth(), clip.GetHeight(), bounds.Dx(), bounds.Dy())
+	}
+
+	if err := reader.SeekFrame(0); err != nil {
+		t.Fatalf("Failed to seek: %v", err)
+	}
+	count := 0
+	for {
+		frame, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to read frame %d: %v", count, err)
+		}
+		if frame.Index != count {
+			t.Fatalf("Expected frame %d, got %d", count, frame.Index)
+		}
+		count++
+	}
+	if count != reader.Len() {
+		t.Errorf("Expected %d frames, read %d", reader.Len(), count)
+	}
+
+	if err := reader.Seek(-1); err == nil {
+		t.Errorf("Expected an error seeking before the start")
+	}
+}
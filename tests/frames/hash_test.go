@@ -0,0 +1,50 @@
+package frames_test
+
+import (
+	"testing"
+
+	moviego "github.com/YounesseAmhend/MovieGo"
+	"github.com/YounesseAmhend/MovieGo/tests/common"
+)
+
+func TestPerceptualHash(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	hash, err := video.PerceptualHash()
+	if err != nil {
+		t.Fatalf("Failed to hash video: %v", err)
+	}
+	if len(hash) == 0 {
+		t.Fatal("Expected sampled frame hashes")
+	}
+	if score := moviego.SimilarityScore(hash, hash); score != 1 {
+		t.Errorf("Expected a video to be identical to itself, got %f", score)
+	}
+
+	// A resized copy is near-identical.
+	resized, err := video.ScaleRatio(0.5)
+	if err != nil {
+		t.Fatalf("Failed to resize video: %v", err)
+	}
+	resizedHash, err := resized.PerceptualHash()
+	if err != nil {
+		t.Fatalf("Failed to hash resized video: %v", err)
+	}
+	if score := moviego.SimilarityScore(hash, resizedHash); score < 0.9 {
+		t.Errorf("Expected a resized copy to score above 0.9, got %f", score)
+	}
+
+	other, err := moviego.NewVideoFile(common.TestVideo2Path)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	otherHash, err := other.PerceptualHash()
+	if err != nil {
+		t.Fatalf("Failed to hash video: %v", err)
+	}
+	if score := moviego.SimilarityScore(hash, otherHash); score >= 0.9 {
+		t.Errorf("Expected different videos to score below 0.9, got %f", score)
+	}
+}
@@ -0,0 +1,69 @@
+//go:build cgo && moviego_libav
+
+package frames_test
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"testing"
+
+	moviego "github.com/YounesseAmhend/MovieGo"
+	"github.com/YounesseAmhend/MovieGo/tests/common"
+)
+
+// TestLibavBackendMatchesExec compares the frames of the in-process libav
+// backend with the ones the ffmpeg executable decodes from the same range.
+func TestLibavBackendMatchesExec(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to create video file: %v", err)
+	}
+	clip, err := video.Cut(1, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	reader, err := moviego.NewFrameReader(clip)
+	if err != nil {
+		t.Fatalf("Failed to open frame reader: %v", err)
+	}
+
+	ffmpegPath, err := moviego.FFmpegPath()
+	if err != nil {
+		t.Fatalf("Failed to get ffmpeg path: %v", err)
+	}
+	want, err := exec.Command(ffmpegPath, "-v", "error", "-ss", "1", "-to", "2", "-i", common.TestVideoPath,
+		"-an", "-frames:v", fmt.Sprint(reader.Len()), "-pix_fmt", "rgb24", "-f", "rawvideo", "-").Output()
+	if err != nil {
+		t.Fatalf("Failed to decode with ffmpeg: %v", err)
+	}
+	size := int(clip.GetWidth() * clip.GetHeight() * 3)
+	if len(want) != reader.Len()*size {
+		t.Fatalf("Expected %d frames from ffmpeg, got %d bytes", reader.Len(), len(want))
+	}
+
+	for i := 0; ; i++ {
+		frame, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to read frame %d: %v", i, err)
+		}
+		// Decoders may round differently; compare the mean difference.
+		expected := want[i*size : (i+1)*size]
+		var diff int
+		for p := 0; p < size/3; p++ {
+			for c := 0; c < 3; c++ {
+				d := int(frame.Image.Pix[p*4+c]) - int(expected[p*3+c])
+				if d < 0 {
+					d = -d
+				}
+				diff += d
+			}
+		}
+		if mean := float64(diff) / float64(size); mean > 2 {
+			t.Errorf("Frame %d differs from ffmpeg by %.2f on average", i, mean)
+		}
+	}
+}
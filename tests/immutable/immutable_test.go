@@ -0,0 +1,74 @@
+package immutable_test
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	moviego "github.com/YounesseAmhend/MovieGo"
+	"github.com/YounesseAmhend/MovieGo/tests/common"
+)
+
+func TestEditBranches(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	originalDuration := video.GetDuration()
+
+	base := moviego.Edit(video).WithCut(0, 4)
+	fast, err := base.WithSpeed(2).Video()
+	if err != nil {
+		t.Fatalf("Failed to speed up: %v", err)
+	}
+	slow, err := base.WithSpeed(0.5).Video()
+	if err != nil {
+		t.Fatalf("Failed to slow down: %v", err)
+	}
+	cut, err := base.Video()
+	if err != nil {
+		t.Fatalf("Failed to cut: %v", err)
+	}
+
+	if math.Abs(fast.GetDuration()-2) > 0.01 {
+		t.Errorf("expected fast duration 2, got %f", fast.GetDuration())
+	}
+	if math.Abs(slow.GetDuration()-8) > 0.01 {
+		t.Errorf("expected slow duration 8, got %f", slow.GetDuration())
+	}
+	if math.Abs(cut.GetDuration()-4) > 0.01 {
+		t.Errorf("expected branch base to stay at 4, got %f", cut.GetDuration())
+	}
+	if video.GetDuration() != originalDuration {
+		t.Errorf("expected source video to be unchanged")
+	}
+
+	outputPath := filepath.Join("output", "immutable_fast.mp4")
+	if err := fast.WriteVideo(moviego.VideoParameters{OutputPath: outputPath}); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+	outputPath = filepath.Join("output", "immutable_slow.mp4")
+	if err := slow.WriteVideo(moviego.VideoParameters{OutputPath: outputPath}); err != nil {
+		t.Fatalf("Failed to write after writing a sibling branch: %v", err)
+	}
+}
+
+func TestEditErrorStopsChain(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	value := moviego.Edit(video).WithCut(3, 1).WithSpeed(2)
+	if value.Err() == nil {
+		t.Fatal("expected error from invalid cut")
+	}
+	if _, err := value.Video(); err == nil {
+		t.Error("expected Video to return the chain error")
+	}
+}
+
+func TestMain(m *testing.M) {
+	_ = os.MkdirAll("output", 0755)
+	os.Exit(m.Run())
+}
@@ -0,0 +1,75 @@
+package metadata_test
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	moviego "github.com/YounesseAmhend/MovieGo"
+	"github.com/YounesseAmhend/MovieGo/tests/common"
+)
+
+// formatTags returns the container tags of path, as key=value lines.
+func formatTags(t *testing.T, path string) string {
+	t.Helper()
+	out, err := exec.Command("ffprobe", "-v", "error", "-show_entries", "format_tags", "-of", "default=nw=1", path).Output()
+	if err != nil {
+		t.Fatalf("Failed to probe %s: %v", path, err)
+	}
+	return string(out)
+}
+
+func TestStripMetadata(t *testing.T) {
+	if err := os.MkdirAll("output", 0755); err != nil {
+		t.Fatal(err)
+	}
+	tagged := "output/tagged.mp4"
+	cmd := exec.Command("ffmpeg", "-y", "-i", common.TestVideoPath, "-t", "2", "-c", "copy",
+		"-metadata", "location=+48.8584+002.2945/", "-metadata", "make=TestCam", tagged)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to create tagged video: %v\n%s", err, out)
+	}
+	if tags := formatTags(t, tagged); !strings.Contains(tags, "location") {
+		t.Fatalf("Expected the input to carry a location, got %q", tags)
+	}
+
+	video, err := moviego.NewVideoFile(tagged)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	outputPath := "output/stripped.mp4"
+	if err := video.WriteVideo(moviego.VideoParameters{OutputPath: outputPath, StripMetadata: true, SilentProgress: true}); err != nil {
+		t.Fatalf("Failed to write video: %v", err)
+	}
+	tags := formatTags(t, outputPath)
+	for _, key := range []string{"location", "make", "encoder"} {
+		if strings.Contains(tags, key) {
+			t.Errorf("Expected %s to be stripped, got %q", key, tags)
+		}
+	}
+}
+
+func TestBitstreamFilters(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	cut, err := video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+	outputPath := "output/annexb.ts"
+	err = cut.WriteVideo(moviego.VideoParameters{
+		OutputPath:       outputPath,
+		StreamCopy:       true,
+		BitstreamFilters: []moviego.BitstreamFilter{moviego.BSFH264AnnexB},
+		SilentProgress:   true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to write video: %v", err)
+	}
+	if _, err := moviego.NewVideoFile(outputPath); err != nil {
+		t.Errorf("Failed to probe output: %v", err)
+	}
+}
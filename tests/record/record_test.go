@@ -0,0 +1,62 @@
+package record_test
+
+import (
+	"math"
+	"os"
+	"os/exec"
+	"testing"
+
+	moviego "github.com/YounesseAmhend/MovieGo"
+	"github.com/YounesseAmhend/MovieGo/tests/common"
+)
+
+func TestRecordStream(t *testing.T) {
+	var segments []string
+	rec, err := moviego.RecordStream(common.TestVideoPath, moviego.RecordOptions{
+		OutputPath:      "output/record.mp4",
+		Duration:        2,
+		SegmentDuration: 1,
+		MaxReconnects:   -1,
+		OnSegment:       func(path string) { segments = append(segments, path) },
+	}, nil)
+	if err != nil {
+		t.Fatalf("Failed to record stream: %v", err)
+	}
+	if len(rec.Files) == 0 || len(segments) != len(rec.Files) {
+		t.Fatalf("Expected recorded segments, got files=%v segments=%v", rec.Files, segments)
+	}
+	for _, f := range rec.Files {
+		if _, err := os.Stat(f); err != nil {
+			t.Errorf("Segment %s was not written: %v", f, err)
+		}
+	}
+
+	if _, err := moviego.RecordStream("", moviego.RecordOptions{OutputPath: "output/empty.mp4"}, nil); err == nil {
+		t.Error("Expected an error for an empty url")
+	}
+}
+
+func TestClipRemote(t *testing.T) {
+	// A local HLS stream stands in for a remote one.
+	if err := os.MkdirAll("output/hls", 0755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := "output/hls/index.m3u8"
+	cmd := exec.Command("ffmpeg", "-y", "-i", common.TestVideoPath, "-c:v", "libx264", "-g", "30", "-c:a", "aac",
+		"-f", "hls", "-hls_time", "1", "-hls_playlist_type", "vod", manifest)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to create HLS stream: %v\n%s", err, out)
+	}
+
+	clip, err := moviego.ClipRemote(manifest, 1, 3, "output/remote_clip.mp4", moviego.RemoteClipOptions{Accurate: true})
+	if err != nil {
+		t.Fatalf("Failed to clip stream: %v", err)
+	}
+	if math.Abs(clip.GetDuration()-2) > 0.2 {
+		t.Errorf("Expected duration ~2, got %f", clip.GetDuration())
+	}
+
+	if _, err := moviego.ClipRemote(manifest, 3, 1, "output/invalid.mp4", moviego.RemoteClipOptions{}); err == nil {
+		t.Error("Expected an error for an inverted time range")
+	}
+}
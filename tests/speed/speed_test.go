@@ -1,6 +1,7 @@
 package speed_test
 
 import (
+	"fmt"
 	"math"
 	"testing"
 
@@ -233,3 +234,92 @@ func TestSpeedWithPitch(t *testing.T) {
 		t.Fatalf("Expected duration %f, got %f", expectedDuration, exportedVideo.GetDuration())
 	}
 }
+
+func TestSpeedRamp(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to create video file: %v", err)
+	}
+	cut, err := video.Cut(0, 4)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+
+	// 1s at 1x, 1s at 0.5x, 2s at 1x = 1 + 2 + 2 seconds
+	ramped, err := cut.SpeedRamp([]moviego.SpeedKeyframe{
+		{Time: 1, Speed: 1},
+		{Time: 1.0001, Speed: 0.5},
+		{Time: 1.9999, Speed: 0.5},
+		{Time: 2, Speed: 1},
+	})
+	if err != nil {
+		t.Fatalf("Failed to ramp speed: %v", err)
+	}
+	const expectedDuration = 5.0
+	if math.Abs(ramped.GetDuration()-expectedDuration) > 0.01 {
+		t.Fatalf("Expected planned duration %f, got %f", expectedDuration, ramped.GetDuration())
+	}
+
+	const outputPath = "output/speed_ramp.mp4"
+	if err := ramped.WriteVideo(moviego.VideoParameters{OutputPath: outputPath}); err != nil {
+		t.Fatalf("Failed to write video: %v", err)
+	}
+	exportedVideo, err := moviego.NewVideoFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to load output: %v", err)
+	}
+	if math.Abs(exportedVideo.GetDuration()-expectedDuration) > 0.2 {
+		t.Fatalf("Expected duration %f, got %f", expectedDuration, exportedVideo.GetDuration())
+	}
+}
+
+func TestSpeedRampInvalid(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to create video file: %v", err)
+	}
+	if _, err := video.SpeedRamp(nil); err == nil {
+		t.Fatal("Expected error for empty keyframes")
+	}
+	if _, err := video.SpeedRamp([]moviego.SpeedKeyframe{{Time: 2, Speed: 1}, {Time: 1, Speed: 2}}); err == nil {
+		t.Fatal("Expected error for unordered keyframes")
+	}
+	if _, err := video.SpeedRamp([]moviego.SpeedKeyframe{{Time: 1, Speed: 0}}); err == nil {
+		t.Fatal("Expected error for zero speed")
+	}
+}
+
+func TestSpeedX(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to create video file: %v", err)
+	}
+	cut, err := video.Cut(0, 3)
+	if err != nil {
+		t.Fatalf("Failed to cut video: %v", err)
+	}
+
+	// Both factors are outside atempo's 0.5-2 range and need a chain.
+	for _, factor := range []float64{3, 0.25} {
+		changed, err := cut.SpeedX(factor)
+		if err != nil {
+			t.Fatalf("%gx: failed to change speed: %v", factor, err)
+		}
+		want := 3 / factor
+		if math.Abs(changed.GetDuration()-want) > 1e-9 || math.Abs(changed.GetAudio().GetDuration()-want) > 1e-9 {
+			t.Errorf("%gx: expected planned durations of %f, got video %f and audio %f", factor, want, changed.GetDuration(), changed.GetAudio().GetDuration())
+		}
+		if frames := changed.GetFrames(); frames != int64(float64(changed.GetFps())*want) {
+			t.Errorf("%gx: expected %d frames, got %d", factor, int64(float64(changed.GetFps())*want), frames)
+		}
+		outputPath := fmt.Sprintf("output/speed_x%g.mp4", factor)
+		err = changed.WriteVideo(moviego.VideoParameters{OutputPath: outputPath, VerifyDuration: &moviego.DurationCheck{Tolerance: 0.15}, SilentProgress: true})
+		if err != nil {
+			t.Fatalf("%gx: failed to write video: %v", factor, err)
+		}
+	}
+
+	if _, err := cut.SpeedX(0); err == nil {
+		t.Error("Expected an error for a zero factor")
+	}
+}
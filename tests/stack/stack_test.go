@@ -21,7 +21,7 @@ type tileSpec struct {
 	path        string
 	label       string
 	fontPath    string
-	fontColor   string
+	fontColor   moviego.Color
 	textShaping bool
 	background  moviego.Background
 	stroke      moviego.Stroke
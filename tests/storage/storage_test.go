@@ -0,0 +1,116 @@
+package storage_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	moviego "github.com/YounesseAmhend/MovieGo"
+)
+
+func roundTrip(t *testing.T, s moviego.Storage, name string) {
+	t.Helper()
+	w, err := s.Create(name)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := io.WriteString(w, "payload"); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	r, err := s.Open(name)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if string(data) != "payload" {
+		t.Errorf("expected payload, got %q", data)
+	}
+}
+
+func TestDirStorage(t *testing.T) {
+	roundTrip(t, moviego.DirStorage(t.TempDir()), "renders/a.txt")
+}
+
+func TestDirStorageRejectsEscapingNames(t *testing.T) {
+	dir := t.TempDir()
+	s := moviego.DirStorage(filepath.Join(dir, "root"))
+	for _, name := range []string{"../x.txt", "../../etc/x", "a/../../x.txt", "..", filepath.Join(dir, "abs.txt")} {
+		if _, err := s.Create(name); err == nil {
+			t.Errorf("expected Create(%q) to fail", name)
+		}
+		if _, err := s.Open(name); err == nil {
+			t.Errorf("expected Open(%q) to fail", name)
+		}
+		if _, err := s.StreamURL(name); err == nil {
+			t.Errorf("expected StreamURL(%q) to fail", name)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "x.txt")); !os.IsNotExist(err) {
+		t.Errorf("expected no file outside the storage directory, got %v", err)
+	}
+	roundTrip(t, s, "a/../..x.txt")
+}
+
+func TestHTTPStorage(t *testing.T) {
+	var mu sync.Mutex
+	objects := map[string][]byte{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			if r.ContentLength < 0 {
+				w.WriteHeader(http.StatusLengthRequired)
+				return
+			}
+			objects[r.URL.Path], _ = io.ReadAll(r.Body)
+		case http.MethodGet:
+			data, ok := objects[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(data)
+		}
+	}))
+	defer server.Close()
+
+	s := &moviego.HTTPStorage{
+		URL:   func(method, name string) (string, error) { return server.URL + "/" + name, nil },
+		Token: func() (string, error) { return "secret", nil },
+	}
+	roundTrip(t, s, "renders/a.txt")
+	if _, err := s.Open("missing.txt"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("expected 404 error, got %v", err)
+	}
+}
+
+func TestS3StreamURL(t *testing.T) {
+	s := moviego.NewS3Storage(moviego.S3Config{Bucket: "media", Region: "eu-west-1", AccessKeyID: "AKID", SecretAccessKey: "secret"})
+	u, err := s.StreamURL("in/clip one.mp4")
+	if err != nil {
+		t.Fatalf("StreamURL: %v", err)
+	}
+	if !strings.HasPrefix(u, "https://s3.eu-west-1.amazonaws.com/media/in/clip%20one.mp4?") || !strings.Contains(u, "X-Amz-Signature=") {
+		t.Errorf("unexpected presigned URL %s", u)
+	}
+	if _, err := moviego.NewS3Storage(moviego.S3Config{}).StreamURL("a.mp4"); err == nil {
+		t.Error("expected error for missing credentials")
+	}
+}
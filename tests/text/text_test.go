@@ -4,6 +4,7 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	moviego "github.com/YounesseAmhend/MovieGo"
@@ -141,6 +142,50 @@ func TestAddTextRequiresText(t *testing.T) {
 	}
 }
 
+func TestTextClipValidate(t *testing.T) {
+	clip := moviego.TextClip{
+		Text:       "Hello",
+		FontFamily: "/missing/fonts/NotAFont.ttf",
+		Opacity:    1.5,
+		StartTime:  3,
+		EndTime:    2,
+	}
+	err := clip.Validate()
+	if err == nil {
+		t.Fatal("Expected validation error")
+	}
+	for _, want := range []string{"font file", "Opacity", "EndTime"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to mention %q, got: %v", want, err)
+		}
+	}
+}
+
+func TestVideoSetterValidation(t *testing.T) {
+	video := mustLoadVideo(t, common.TestVideoPath)
+	if err := video.Validate(); err != nil {
+		t.Fatalf("Expected loaded video to be valid, got: %v", err)
+	}
+
+	video.SetCompositeStart(-1).SetFps(0)
+	err := video.Validate()
+	if err == nil {
+		t.Fatal("Expected validation error after invalid setters")
+	}
+	if !strings.Contains(err.Error(), "SetCompositeStart") || !strings.Contains(err.Error(), "SetFps") {
+		t.Errorf("expected both setters to be reported, got: %v", err)
+	}
+	if err := video.WriteVideo(moviego.VideoParameters{OutputPath: filepath.Join("output", "invalid.mp4")}); err == nil {
+		t.Error("Expected WriteVideo to refuse an invalid video")
+	}
+
+	// A later valid call of the same setter clears its error.
+	video.SetCompositeStart(0).SetFps(30)
+	if err := video.Validate(); err != nil {
+		t.Errorf("expected corrected setters to clear their errors, got: %v", err)
+	}
+}
+
 func TestAddTextsNilClip(t *testing.T) {
 	video := mustLoadVideo(t, common.TestVideoPath)
 
@@ -150,6 +195,23 @@ func TestAddTextsNilClip(t *testing.T) {
 	}
 }
 
+func TestParseColor(t *testing.T) {
+	for _, in := range []string{"white", "Black@0.5", "#fff", "#FF0000", "#FF000080", "0x202020", "rgb(255, 0, 0)", "rgba(0,0,0,0.25)"} {
+		if _, err := moviego.ParseColor(in); err != nil {
+			t.Errorf("ParseColor(%q): %v", in, err)
+		}
+	}
+	for _, in := range []string{"", "notacolor", "#12", "#GGGGGG", "white@2", "rgb(256,0,0)", "rgba(0,0,0)"} {
+		if _, err := moviego.ParseColor(in); err == nil {
+			t.Errorf("ParseColor(%q): expected error", in)
+		}
+	}
+	err := moviego.TextClip{Text: "x", FontColor: "#12", Stroke: moviego.Stroke{Width: 1, Color: "bad"}}.Validate()
+	if err == nil || !strings.Contains(err.Error(), "FontColor") || !strings.Contains(err.Error(), "Stroke.Color") {
+		t.Errorf("expected FontColor and Stroke.Color errors, got %v", err)
+	}
+}
+
 func TestMain(m *testing.M) {
 	_ = os.MkdirAll("output", 0755)
 	os.Exit(m.Run())
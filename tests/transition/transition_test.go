@@ -3,7 +3,9 @@ package transition_test
 import (
 	"math"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	moviego "github.com/YounesseAmhend/MovieGo"
@@ -282,7 +284,389 @@ func TestTransitionChained(t *testing.T) {
 	}
 }
 
+func TestParseTransition(t *testing.T) {
+	tests := map[string]moviego.Transition{
+		"dissolve":     moviego.TransitionDissolve,
+		"dip-to-black": moviego.TransitionFadeBlack,
+		"Blur-Through": moviego.TransitionBlurThrough,
+		"luma-wipe":    moviego.TransitionLumaWipe,
+		"wipeleft":     moviego.TransitionWipeLeft,
+	}
+	for name, expected := range tests {
+		got, err := moviego.ParseTransition(name)
+		if err != nil {
+			t.Fatalf("ParseTransition(%q) failed: %v", name, err)
+		}
+		if got != expected {
+			t.Errorf("ParseTransition(%q) = %q, expected %q", name, got, expected)
+		}
+	}
+	if _, err := moviego.ParseTransition("spin"); err == nil {
+		t.Error("expected error for unknown transition")
+	}
+}
+
+func TestTimelineTransitions(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideo2Path)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	c1, err := video.Cut(0, 3)
+	if err != nil {
+		t.Fatalf("Failed to cut: %v", err)
+	}
+	c2, err := video.Cut(1, 4)
+	if err != nil {
+		t.Fatalf("Failed to cut: %v", err)
+	}
+	c3, err := video.Cut(2, 5)
+	if err != nil {
+		t.Fatalf("Failed to cut: %v", err)
+	}
+
+	timeline := moviego.NewTimeline().
+		Add(c1).
+		AddWithTransition(c2, moviego.TransitionParams{Transition: moviego.TransitionDipToBlack, Duration: 0.5}).
+		Add(c3)
+	expectedDuration := 3.0 + 3.0 - 0.5 + 3.0
+	if math.Abs(timeline.GetDuration()-expectedDuration) > 0.01 {
+		t.Fatalf("expected timeline duration %f, got %f", expectedDuration, timeline.GetDuration())
+	}
+
+	result, err := timeline.Render()
+	if err != nil {
+		t.Fatalf("Failed to render timeline: %v", err)
+	}
+	outputPath := filepath.Join("output", "timeline_transitions.mp4")
+	if err := result.WriteVideo(moviego.VideoParameters{OutputPath: outputPath}); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+	out, err := moviego.NewVideoFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to load output: %v", err)
+	}
+	if math.Abs(out.GetDuration()-expectedDuration) > 0.5 {
+		t.Errorf("expected duration ~%f, got %f", expectedDuration, out.GetDuration())
+	}
+}
+
+func TestTimelineLanes(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideo2Path)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	music, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load music: %v", err)
+	}
+	c1, err := video.Cut(0, 3)
+	if err != nil {
+		t.Fatalf("Failed to cut: %v", err)
+	}
+	c2, err := video.Cut(1, 4)
+	if err != nil {
+		t.Fatalf("Failed to cut: %v", err)
+	}
+
+	timeline := moviego.NewTimeline().
+		Add(c1).
+		Add(c2).
+		AddAudio(moviego.LaneMusic, music.GetAudio(), 0).
+		AddAudio(moviego.LaneSFX, music.GetAudio(), 2.5)
+	if !timeline.GetLaneSettings(moviego.LaneMusic).DuckUnderDialogue {
+		t.Errorf("expected music lane to duck under dialogue by default")
+	}
+
+	result, err := timeline.Render()
+	if err != nil {
+		t.Fatalf("Failed to render timeline: %v", err)
+	}
+	outputPath := filepath.Join("output", "timeline_lanes.mp4")
+	if err := result.WriteVideo(moviego.VideoParameters{OutputPath: outputPath}); err != nil {
+		t.Fatalf("Failed to write: %v", err)
+	}
+	out, err := moviego.NewVideoFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to load output: %v", err)
+	}
+	if math.Abs(out.GetDuration()-6) > 0.5 {
+		t.Errorf("expected duration ~6, got %f", out.GetDuration())
+	}
+}
+
+func TestTimelineAddSFXInvalid(t *testing.T) {
+	timeline := moviego.NewTimeline()
+	if _, err := timeline.AddSFX("whoosh.wav", -1, 1); err == nil {
+		t.Error("expected error for negative position")
+	}
+	if _, err := timeline.AddSFX("whoosh.wav", 1, -0.5); err == nil {
+		t.Error("expected error for negative volume")
+	}
+	if _, err := timeline.AddSFX("missing_whoosh.wav", 1, 1); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestTimelineTransitionSoundMissing(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideo2Path)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	c1, err := video.Cut(0, 3)
+	if err != nil {
+		t.Fatalf("Failed to cut: %v", err)
+	}
+	c2, err := video.Cut(1, 4)
+	if err != nil {
+		t.Fatalf("Failed to cut: %v", err)
+	}
+	params := moviego.TransitionParams{
+		Transition: moviego.TransitionSlide,
+		Duration:   0.5,
+		Sound:      "missing_whoosh.wav",
+	}
+	if _, err := moviego.NewTimeline().Add(c1).AddWithTransition(c2, params).Render(); err == nil {
+		t.Error("expected error for missing transition sound")
+	}
+	if _, err := moviego.ConcatenateWithTransition(c1, c2, params); err == nil {
+		t.Error("expected error for missing transition sound")
+	}
+}
+
+func TestTimelineRenderLanguages(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideo2Path)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	voice, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load voiceover: %v", err)
+	}
+	clip, err := video.Cut(0, 3)
+	if err != nil {
+		t.Fatalf("Failed to cut: %v", err)
+	}
+	subtitles := filepath.Join(t.TempDir(), "fra.srt")
+	if err := os.WriteFile(subtitles, []byte("1\n00:00:00,500 --> 00:00:02,000\nBonjour\n"), 0644); err != nil {
+		t.Fatalf("Failed to write subtitles: %v", err)
+	}
+
+	timeline := moviego.NewTimeline().Add(clip)
+	if _, err := timeline.RenderLanguages(moviego.VideoParameters{OutputPath: "output/film.mp4"}); err == nil {
+		t.Error("expected error without language variants")
+	}
+	timeline.
+		AddLanguage(moviego.LanguageVariant{Language: "eng"}).
+		AddLanguage(moviego.LanguageVariant{Language: "fra", Voiceover: voice.GetAudio(), VoiceoverAt: 0.5, ReplaceDialogue: true, Subtitles: subtitles})
+
+	outputs, err := timeline.RenderLanguages(moviego.VideoParameters{OutputPath: filepath.Join("output", "film.mp4"), SilentProgress: true})
+	if err != nil {
+		t.Fatalf("Failed to render languages: %v", err)
+	}
+	for _, lang := range []string{"eng", "fra"} {
+		want := filepath.Join("output", "film."+lang+".mp4")
+		if outputs[lang] != want {
+			t.Errorf("expected %s output %s, got %s", lang, want, outputs[lang])
+		}
+		out, err := moviego.NewVideoFile(want)
+		if err != nil {
+			t.Fatalf("Failed to load %s output: %v", lang, err)
+		}
+		if math.Abs(out.GetDuration()-3) > 0.5 {
+			t.Errorf("expected %s duration ~3, got %f", lang, out.GetDuration())
+		}
+	}
+}
+
+func TestTimelineRenderLanguagesAudioOptions(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideo2Path)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	commentary, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load commentary: %v", err)
+	}
+	clip, err := video.Cut(0, 3)
+	if err != nil {
+		t.Fatalf("Failed to cut: %v", err)
+	}
+	timeline := moviego.NewTimeline().Add(clip).
+		AddLanguage(moviego.LanguageVariant{Language: "eng"}).
+		AddLanguage(moviego.LanguageVariant{Language: "fra", Voiceover: commentary.GetAudio(), ReplaceDialogue: true})
+
+	for name, parms := range map[string]moviego.VideoParameters{
+		"HLS":  {OutputPath: "output/film.m3u8"},
+		"CENC": {OutputPath: "output/film.mp4", CENC: &moviego.CENCEncryption{KeyID: make([]byte, 16), Key: make([]byte, 16)}},
+	} {
+		if _, err := timeline.RenderLanguages(parms); err == nil {
+			t.Errorf("%s: expected RenderLanguages to reject it", name)
+		}
+	}
+
+	var reports []moviego.AudioReport
+	outputs, err := timeline.RenderLanguages(moviego.VideoParameters{
+		OutputPath:        filepath.Join("output", "film_loud.mp4"),
+		SilentProgress:    true,
+		NormalizeLoudness: &moviego.LoudnessEBUR128,
+		AudioTracks:       []moviego.AudioTrack{{Audio: commentary.GetAudio(), Language: "eng", Title: "Commentary"}},
+		VerifyDuration:    &moviego.DurationCheck{Tolerance: 0.5},
+		OnAudioReport: func(r moviego.AudioReport) error {
+			reports = append(reports, r)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to render languages: %v", err)
+	}
+	if len(reports) != 2 {
+		t.Fatalf("expected an audio report per language, got %d", len(reports))
+	}
+	for i, r := range reports {
+		if math.Abs(r.Integrated-moviego.LoudnessEBUR128.Integrated) > 2 {
+			t.Errorf("expected mix %d at ~%f LUFS, got %f", i, moviego.LoudnessEBUR128.Integrated, r.Integrated)
+		}
+	}
+	out, err := exec.Command("ffprobe", "-v", "error", "-select_streams", "a", "-show_entries", "stream_tags=language,title",
+		"-of", "csv=p=0", outputs["fra"]).Output()
+	if err != nil {
+		t.Fatalf("Failed to probe %s: %v", outputs["fra"], err)
+	}
+	if tracks := strings.Fields(string(out)); len(tracks) != 2 || !strings.Contains(tracks[0], "fra") || !strings.Contains(tracks[1], "Commentary") {
+		t.Errorf("Expected the fra mix and the commentary track, got %q", out)
+	}
+}
+
 func TestMain(m *testing.M) {
 	_ = os.MkdirAll("output", 0755)
 	os.Exit(m.Run())
 }
+
+func TestTimelineWriteIncremental(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	build := func(title string) *moviego.Timeline {
+		clip1, err := video.Cut(0, 2)
+		if err != nil {
+			t.Fatalf("Failed to cut: %v", err)
+		}
+		clip2, err := video.Cut(2, 4)
+		if err != nil {
+			t.Fatalf("Failed to cut: %v", err)
+		}
+		clip2, err = clip2.AddText(moviego.TextClip{Text: title, FontSize: 32})
+		if err != nil {
+			t.Fatalf("Failed to add text: %v", err)
+		}
+		return moviego.NewTimeline().Add(clip1).Add(clip2)
+	}
+	outputPath := "output/incremental.mp4"
+	_ = os.RemoveAll(outputPath + ".parts")
+	parms := moviego.VideoParameters{OutputPath: outputPath, SilentProgress: true}
+
+	result, err := build("Draft").WriteIncremental(parms)
+	if err != nil {
+		t.Fatalf("Failed to write timeline: %v", err)
+	}
+	if result.Segments != 2 || len(result.Rendered) != 1 || result.Rendered[0] != (moviego.TimeRange{Start: 0, End: 4}) {
+		t.Errorf("Expected the whole timeline to be rendered, got %+v", result)
+	}
+
+	result, err = build("Draft").WriteIncremental(parms)
+	if err != nil {
+		t.Fatalf("Failed to rewrite timeline: %v", err)
+	}
+	if len(result.Rendered) != 0 {
+		t.Errorf("Expected nothing to be rendered for an unchanged timeline, got %+v", result.Rendered)
+	}
+
+	result, err = build("Final").WriteIncremental(parms)
+	if err != nil {
+		t.Fatalf("Failed to rewrite timeline: %v", err)
+	}
+	if len(result.Rendered) != 1 || result.Rendered[0] != (moviego.TimeRange{Start: 2, End: 4}) {
+		t.Errorf("Expected only the changed clip to be rendered, got %+v", result.Rendered)
+	}
+	out, err := moviego.NewVideoFile(outputPath)
+	if err != nil {
+		t.Fatalf("Failed to open output: %v", err)
+	}
+	if math.Abs(out.GetDuration()-4) > 0.3 {
+		t.Errorf("expected duration ~4, got %f", out.GetDuration())
+	}
+
+	// A segment led in by a transition is rendered from the previous clip's tail.
+	clip1, err := video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut: %v", err)
+	}
+	clip2, err := video.Cut(2, 4)
+	if err != nil {
+		t.Fatalf("Failed to cut: %v", err)
+	}
+	faded := moviego.NewTimeline().Add(clip1).AddWithTransition(clip2, moviego.TransitionParams{Transition: moviego.TransitionFade, Duration: 0.5})
+	fadedPath := "output/incremental_fade.mp4"
+	_ = os.RemoveAll(fadedPath + ".parts")
+	if _, err := faded.WriteIncremental(moviego.VideoParameters{OutputPath: fadedPath, SilentProgress: true}); err != nil {
+		t.Fatalf("Failed to write timeline with a transition: %v", err)
+	}
+	out, err = moviego.NewVideoFile(fadedPath)
+	if err != nil {
+		t.Fatalf("Failed to open output: %v", err)
+	}
+	if math.Abs(out.GetDuration()-faded.GetDuration()) > 0.3 {
+		t.Errorf("expected duration ~%f, got %f", faded.GetDuration(), out.GetDuration())
+	}
+}
+
+func TestTimelineWriteIncrementalOutputOptions(t *testing.T) {
+	video, err := moviego.NewVideoFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to load video: %v", err)
+	}
+	clip1, err := video.Cut(0, 2)
+	if err != nil {
+		t.Fatalf("Failed to cut: %v", err)
+	}
+	clip2, err := video.Cut(2, 4)
+	if err != nil {
+		t.Fatalf("Failed to cut: %v", err)
+	}
+	timeline := moviego.NewTimeline().Add(clip1).Add(clip2)
+
+	outputPath := "output/incremental_options.mp4"
+	key := func() ([]byte, string, error) { return make([]byte, 16), "key.bin", nil }
+	for name, parms := range map[string]moviego.VideoParameters{
+		"NormalizeLoudness": {OutputPath: outputPath, NormalizeLoudness: &moviego.LoudnessEBUR128},
+		"HLS":               {OutputPath: "output/incremental_options.m3u8"},
+		"HLSEncryption":     {OutputPath: "output/incremental_options.m3u8", HLS: moviego.HLSOptions{Encryption: &moviego.HLSEncryption{Key: key}}},
+		"CENC":              {OutputPath: outputPath, CENC: &moviego.CENCEncryption{KeyID: make([]byte, 16), Key: make([]byte, 16)}},
+		"FrameMetadata":     {OutputPath: outputPath, FrameMetadata: true},
+	} {
+		parms.SilentProgress = true
+		if _, err := timeline.WriteIncremental(parms); err == nil {
+			t.Errorf("%s: expected WriteIncremental to reject it", name)
+		}
+	}
+
+	// VerifyDuration and OnAudioReport check the joined output once.
+	_ = os.RemoveAll(outputPath + ".parts")
+	reports := 0
+	_, err = timeline.WriteIncremental(moviego.VideoParameters{
+		OutputPath:     outputPath,
+		SilentProgress: true,
+		VerifyDuration: &moviego.DurationCheck{},
+		OnAudioReport: func(moviego.AudioReport) error {
+			reports++
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to write timeline: %v", err)
+	}
+	if reports != 1 {
+		t.Errorf("expected one audio report for the joined output, got %d", reports)
+	}
+}
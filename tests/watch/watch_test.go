@@ -0,0 +1,118 @@
+package watch_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	moviego "github.com/YounesseAmhend/MovieGo"
+	"github.com/YounesseAmhend/MovieGo/tests/common"
+)
+
+func TestWatcherManifests(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "in")
+	output := filepath.Join(dir, "out")
+	if err := os.MkdirAll(input, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to read test video: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(input, "good.mp4"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(input, "broken.mp4"), []byte("not a video"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(input, "notes.txt"), []byte("ignored"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := moviego.NewWatcher(moviego.WatchConfig{
+		Input:     input,
+		Output:    output,
+		StableFor: time.Nanosecond,
+		Process: func(v *moviego.Video) (*moviego.Video, error) {
+			return v.Cut(0, 1)
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create watcher: %v", err)
+	}
+	results, err := w.Scan()
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 processed files, got %d", len(results))
+	}
+	if results[0].Success {
+		t.Errorf("expected broken.mp4 to fail: %+v", results[0])
+	}
+	if !results[1].Success {
+		t.Errorf("expected good.mp4 to succeed: %s", results[1].Error)
+	}
+
+	// Processed files are not picked up again.
+	if results, _ := w.Scan(); len(results) != 0 {
+		t.Errorf("expected no new files, got %d", len(results))
+	}
+	manifests, err := w.Manifests()
+	if err != nil || len(manifests) != 2 {
+		t.Errorf("expected 2 manifests, got %d (%v)", len(manifests), err)
+	}
+}
+
+func TestWatcherOutputNames(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "in")
+	output := filepath.Join(dir, "out")
+	if err := os.MkdirAll(input, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(common.TestVideoPath)
+	if err != nil {
+		t.Fatalf("Failed to read test video: %v", err)
+	}
+	// Same base name, different containers: each gets its own output.
+	for _, name := range []string{"clip.mov", "clip.mp4"} {
+		if err := os.WriteFile(filepath.Join(input, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w, err := moviego.NewWatcher(moviego.WatchConfig{
+		Input:     input,
+		Output:    output,
+		StableFor: time.Nanosecond,
+		Process: func(v *moviego.Video) (*moviego.Video, error) {
+			return v.Cut(0, 1)
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create watcher: %v", err)
+	}
+	results, err := w.Scan()
+	if err != nil {
+		t.Fatalf("Scan: %v", err)
+	}
+	outputs := map[string]bool{}
+	for _, r := range results {
+		if !r.Success {
+			t.Fatalf("expected %s to succeed: %s", r.Input, r.Error)
+		}
+		outputs[r.Output] = true
+	}
+	for _, want := range []string{"clip.mov.mp4", "clip.mp4.mp4"} {
+		path := filepath.Join(output, want)
+		if !outputs[path] {
+			t.Errorf("expected output %s, got %v", path, outputs)
+		}
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("expected %s to be written: %v", path, err)
+		}
+	}
+}
@@ -19,19 +19,19 @@ const (
 type Shadow struct {
 	X     int    // horizontal offset (default: 0)
 	Y     int    // vertical offset (default: 0)
-	Color string // shadow color (default: "black")
+	Color Color  // shadow color (default: "black")
 }
 
 // Stroke controls the outline/border around each glyph.
 type Stroke struct {
 	Width int    // outline thickness in pixels (default: 0)
-	Color string // outline color (default: "black")
+	Color Color  // outline color (default: "black")
 }
 
 // Background controls the box drawn behind text.
 type Background struct {
 	Enabled bool   // show background box
-	Color   string // box fill color, e.g. "black@0.5" (default: "white")
+	Color   Color  // box fill color, e.g. "black@0.5" (default: "white")
 	Padding string // padding inside box: "10" or per-side "10|20|30|40"
 	Width   int    // explicit box width (0 = auto-fit text)
 	Height  int    // explicit box height (0 = auto-fit text)
@@ -64,7 +64,7 @@ type TextClip struct {
 	//   otherwise -> font family name via fontconfig
 	FontFamily string // e.g. "Arial", "Sans", or "/path/to/font.ttf"
 	FontSize   int    // font size in pixels (default: 24)
-	FontColor  string // "white", "0xFF0000", "black@0.5" (default: "white")
+	FontColor  Color  // "white", "#FF0000", "black@0.5" (default: "white")
 
 	// Position & Timing
 	Position  Position // X, Y as FFmpeg drawtext expressions
@@ -157,6 +157,14 @@ func escapeDrawText(s string) string {
 	return s
 }
 
+// filterPath escapes path as an unquoted filter option value inside a filter
+// graph, e.g. for movie= or subtitles=: first for the option parser (\ ' :),
+// then for the graph parser (\ ' [ ] , ;).
+func filterPath(path string) string {
+	path = strings.NewReplacer(`\`, `\\`, `'`, `\'`, ":", `\:`).Replace(filepath.ToSlash(path))
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`, "[", `\[`, "]", `\]`, ",", `\,`, ";", `\;`).Replace(path)
+}
+
 // buildDrawTextFilter constructs the FFmpeg drawtext filter string from the TextClip.
 func (tc TextClip) buildDrawTextFilter(videoDuration float64) string {
 	parts := tc.appendContentParts(nil)
@@ -190,7 +198,7 @@ func (tc TextClip) appendFontParts(parts []string) []string {
 		parts = append(parts, fmt.Sprintf("fontsize=%d", tc.FontSize))
 	}
 	if tc.FontColor != "" {
-		parts = append(parts, "fontcolor="+tc.FontColor)
+		parts = append(parts, "fontcolor="+tc.FontColor.ffmpeg())
 	}
 	return parts
 }
@@ -248,7 +256,7 @@ func (tc TextClip) appendBackgroundParts(parts []string) []string {
 	}
 	parts = append(parts, "box=1")
 	if tc.Background.Color != "" {
-		parts = append(parts, "boxcolor="+tc.Background.Color)
+		parts = append(parts, "boxcolor="+tc.Background.Color.ffmpeg())
 	}
 	if tc.Background.Padding != "" {
 		parts = append(parts, "boxborderw="+tc.Background.Padding)
@@ -268,7 +276,7 @@ func (tc TextClip) appendStrokeParts(parts []string) []string {
 	}
 	parts = append(parts, fmt.Sprintf("borderw=%d", tc.Stroke.Width))
 	if tc.Stroke.Color != "" {
-		parts = append(parts, "bordercolor="+tc.Stroke.Color)
+		parts = append(parts, "bordercolor="+tc.Stroke.Color.ffmpeg())
 	}
 	return parts
 }
@@ -280,7 +288,7 @@ func (tc TextClip) appendShadowParts(parts []string) []string {
 	parts = append(parts, fmt.Sprintf("shadowx=%d", tc.Shadow.X))
 	parts = append(parts, fmt.Sprintf("shadowy=%d", tc.Shadow.Y))
 	if tc.Shadow.Color != "" {
-		parts = append(parts, "shadowcolor="+tc.Shadow.Color)
+		parts = append(parts, "shadowcolor="+tc.Shadow.Color.ffmpeg())
 	}
 	return parts
 }
@@ -316,8 +324,8 @@ func (tc TextClip) appendAdvancedParts(parts []string) []string {
 
 // AddText adds a single text overlay to the video.
 func (v *Video) AddText(clip TextClip) (*Video, error) {
-	if clip.Text == "" && clip.TextFile == "" {
-		return nil, fmt.Errorf("AddText: Text or TextFile is required")
+	if err := clip.Validate(); err != nil {
+		return nil, fmt.Errorf("AddText: %w", err)
 	}
 	if clip.Typewriter != nil {
 		return v.addTextTypewriter(clip)
@@ -0,0 +1,69 @@
+package moviego
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"time"
+)
+
+// profileSeconds is how much of the render is timed by adaptive thread allocation.
+const profileSeconds = 2.0
+
+// stageProfile holds the time FFmpeg takes for the first seconds of a render,
+// once with the encoder replaced by a null sink and once with the real encoder.
+// The difference between the two is the cost of encoding.
+type stageProfile struct {
+	graph time.Duration // decode + filter graph
+	full  time.Duration // decode + filter graph + encode
+}
+
+// split divides budget threads between the filter graph and the encoder in
+// proportion to the time spent in each stage. Both stages get at least one thread.
+func (p stageProfile) split(budget uint16) (filterThreads, encoderThreads uint16) {
+	if budget < 2 {
+		return 1, 1
+	}
+	share := 0.5
+	if p.full > 0 {
+		share = math.Min(float64(p.graph)/float64(p.full), 1)
+	}
+	filterThreads = uint16(math.Round(float64(budget) * share))
+	if filterThreads < 1 {
+		filterThreads = 1
+	}
+	if filterThreads > budget-1 {
+		filterThreads = budget - 1
+	}
+	return filterThreads, budget - filterThreads
+}
+
+// profileStages times graphArgs (inputs, filter graph and maps) and fullArgs
+// (the same plus the encoder settings) over the first profileSeconds.
+func profileStages(env *RenderEnv, ffmpegPath string, graphArgs, fullArgs []string) (stageProfile, error) {
+	run := func(args []string) (time.Duration, error) {
+		args = append(append([]string{"-hide_banner", "-v", "error"}, args...),
+			"-t", fmt.Sprintf("%.4f", profileSeconds), "-f", "null", "-")
+		cmd, err := env.command(ffmpegPath, args...)
+		if err != nil {
+			return 0, err
+		}
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		start := time.Now()
+		if err := runCommand(cmd); err != nil {
+			return 0, fmt.Errorf("%w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+		}
+		return time.Since(start), nil
+	}
+
+	var p stageProfile
+	var err error
+	if p.graph, err = run(graphArgs); err != nil {
+		return p, fmt.Errorf("profiling filter graph: %w", err)
+	}
+	if p.full, err = run(fullArgs); err != nil {
+		return p, fmt.Errorf("profiling encoder: %w", err)
+	}
+	return p, nil
+}
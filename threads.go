@@ -0,0 +1,34 @@
+package moviego
+
+import (
+	"runtime"
+	"sync/atomic"
+)
+
+// tunedThreads holds the thread count set with SetDefaultThreads (0 = heuristic).
+var tunedThreads atomic.Uint32
+
+// SetDefaultThreads sets the FFmpeg thread count used when a render does not
+// set Threads, typically from a bench report measured on this machine.
+// Passing 0 restores the built-in heuristic.
+func SetDefaultThreads(threads uint16) {
+	tunedThreads.Store(uint32(threads))
+}
+
+// defaultThreads returns the FFmpeg thread count for renders without an
+// explicit Threads value. Without a tuned value FFmpeg gets 60% of the CPUs,
+// leaving the rest for the caller.
+func defaultThreads() uint16 {
+	if n := tunedThreads.Load(); n > 0 {
+		return uint16(n)
+	}
+	totalCPUs := runtime.GOMAXPROCS(0)
+	if totalCPUs <= 2 {
+		return uint16(totalCPUs)
+	}
+	threads := uint16((totalCPUs * 6) / 10)
+	if threads < 2 {
+		threads = 2
+	}
+	return threads
+}
@@ -0,0 +1,245 @@
+package moviego
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Time is a position or length on a video timeline, stored as a rational
+// number of seconds: a count of ticks and the ticks per second (the
+// timebase). Times built from frames keep the frame rate as their timebase,
+// so frame arithmetic is exact; times built from seconds use microseconds,
+// FFmpeg's AV_TIME_BASE.
+//
+// It is built from seconds, a time.Duration, a timecode string or a frame
+// number, and bare numbers do not convert to it, so callers never have to
+// guess the unit:
+//
+//	start, _ := moviego.ParseTime("00:01:23.5")
+//	end := start.Add(moviego.FromDuration(10 * time.Second))
+//	cut, err := video.CutTime(start, end)
+//
+// Time is the exact form for building, converting and snapping times. The
+// editing APIs still take float64 seconds; CutTime is the Time form of Cut,
+// and the others accept t.Seconds(). Frame rates are whole numbers in most
+// of the API (see VideoParameters.Fps); the Rational functions take
+// num/den rates such as 30000/1001 for 29.97 fps. The zero value is 0s.
+type Time struct {
+	ticks int64 // position in 1/base seconds
+	base  int64 // ticks per second; 0 in the zero value, treated as 1
+}
+
+// microsecondBase is the timebase of times built from float seconds.
+const microsecondBase = 1_000_000
+
+// Seconds returns a Time of s seconds, rounded to the microsecond.
+func Seconds(s float64) Time {
+	return Time{ticks: int64(math.Round(s * microsecondBase)), base: microsecondBase}
+}
+
+// FromDuration converts a time.Duration exactly.
+func FromDuration(d time.Duration) Time {
+	return Time{ticks: int64(d), base: int64(time.Second)}.reduce()
+}
+
+// FrameTime returns the start time of frame number frame at fps.
+func FrameTime(frame int64, fps uint64) Time {
+	return FrameTimeRational(frame, fps, 1)
+}
+
+// FrameTimeRational returns the start time of frame number frame at num/den
+// frames per second, e.g. 30000/1001.
+func FrameTimeRational(frame int64, num, den uint64) Time {
+	if num == 0 || den == 0 {
+		return Time{}
+	}
+	return Time{ticks: frame * int64(den), base: int64(num)}.reduce()
+}
+
+// ParseTime parses seconds ("83.5"), timecodes ("1:23.5", "00:01:23.5"),
+// Go durations ("1m23.5s") and frame counts at a rate ("2505@30",
+// "2505@30000/1001").
+func ParseTime(s string) (Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Time{}, fmt.Errorf("ParseTime: empty time")
+	}
+	if frame, fps, ok := strings.Cut(s, "@"); ok {
+		n, err := strconv.ParseInt(frame, 10, 64)
+		if err != nil {
+			return Time{}, fmt.Errorf("ParseTime: invalid frame number in %q", s)
+		}
+		num, den, rational := strings.Cut(fps, "/")
+		rate, err := strconv.ParseUint(num, 10, 64)
+		scale := uint64(1)
+		if err == nil && rational {
+			scale, err = strconv.ParseUint(den, 10, 64)
+		}
+		if err != nil || rate == 0 || scale == 0 {
+			return Time{}, fmt.Errorf("ParseTime: invalid frame rate in %q", s)
+		}
+		return FrameTimeRational(n, rate, scale), nil
+	}
+	if strings.Contains(s, ":") {
+		return parseTimecode(s)
+	}
+	if seconds, err := strconv.ParseFloat(s, 64); err == nil {
+		return Seconds(seconds), nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return FromDuration(d), nil
+	}
+	return Time{}, fmt.Errorf("ParseTime: invalid time %q", s)
+}
+
+// parseTimecode parses [HH:]MM:SS[.fff].
+func parseTimecode(s string) (Time, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) > 3 {
+		return Time{}, fmt.Errorf("ParseTime: invalid timecode %q", s)
+	}
+	var total float64
+	for i, part := range parts {
+		last := i == len(parts)-1
+		value, err := strconv.ParseFloat(part, 64)
+		if err != nil || value < 0 || (!last && value != math.Trunc(value)) || (i > 0 && value >= 60) {
+			return Time{}, fmt.Errorf("ParseTime: invalid timecode %q", s)
+		}
+		total = total*60 + value
+	}
+	return Seconds(total), nil
+}
+
+// timebase returns the ticks per second of t.
+func (t Time) timebase() int64 {
+	if t.base == 0 {
+		return 1
+	}
+	return t.base
+}
+
+// reduce divides the ticks and the timebase by their greatest common divisor.
+func (t Time) reduce() Time {
+	base := t.timebase()
+	g := gcd(abs64(t.ticks), base)
+	return Time{ticks: t.ticks / g, base: base / g}
+}
+
+// Rational returns t as ticks/base seconds.
+func (t Time) Rational() (ticks, base int64) {
+	return t.ticks, t.timebase()
+}
+
+// Seconds returns t in seconds.
+func (t Time) Seconds() float64 {
+	return float64(t.ticks) / float64(t.timebase())
+}
+
+// Duration converts t to a time.Duration, rounded to the nanosecond.
+func (t Time) Duration() time.Duration {
+	return time.Duration(math.Round(t.Seconds() * float64(time.Second)))
+}
+
+// Add returns t+d, exactly.
+func (t Time) Add(d Time) Time {
+	a, b := t.timebase(), d.timebase()
+	base := a / gcd(a, b) * b
+	return Time{ticks: t.ticks*(base/a) + d.ticks*(base/b), base: base}.reduce()
+}
+
+// Sub returns t-d, exactly.
+func (t Time) Sub(d Time) Time {
+	return t.Add(Time{ticks: -d.ticks, base: d.base})
+}
+
+// Compare returns -1, 0 or +1 as t is before, equal to or after u.
+func (t Time) Compare(u Time) int {
+	return t.Sub(u).sign()
+}
+
+func (t Time) sign() int {
+	switch {
+	case t.ticks < 0:
+		return -1
+	case t.ticks > 0:
+		return 1
+	}
+	return 0
+}
+
+// Frame returns the number of the frame shown at t at fps. Times within a
+// microsecond before a frame boundary, as left by float seconds, count as
+// being on it.
+func (t Time) Frame(fps uint64) int64 {
+	return t.FrameRational(fps, 1)
+}
+
+// FrameRational is Frame at num/den frames per second.
+func (t Time) FrameRational(num, den uint64) int64 {
+	if den == 0 {
+		return 0
+	}
+	base := t.timebase() * int64(den)
+	n := t.ticks * int64(num)
+	frame := n / base
+	rem := n % base
+	if rem < 0 {
+		frame--
+		rem += base
+	}
+	// The distance to the next frame, (base-rem)/base frames, in seconds.
+	if rem != 0 && (base-rem)*int64(den)*microsecondBase <= int64(num)*base {
+		frame++
+	}
+	return frame
+}
+
+// Snap moves t back to the start of its frame at fps.
+func (t Time) Snap(fps uint64) Time {
+	return t.SnapRational(fps, 1)
+}
+
+// SnapRational is Snap at num/den frames per second.
+func (t Time) SnapRational(num, den uint64) Time {
+	if num == 0 || den == 0 {
+		return t
+	}
+	return FrameTimeRational(t.FrameRational(num, den), num, den)
+}
+
+// String formats t as HH:MM:SS.mmm, the form FFmpeg accepts for -ss and -to.
+func (t Time) String() string {
+	sign := ""
+	ms := int64(math.Round(t.Seconds() * 1000))
+	if ms < 0 {
+		sign = "-"
+		ms = -ms
+	}
+	return fmt.Sprintf("%s%02d:%02d:%02d.%03d", sign, ms/3600000, ms/60000%60, ms/1000%60, ms%1000)
+}
+
+func gcd(a, b int64) int64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	if a == 0 {
+		return 1
+	}
+	return a
+}
+
+func abs64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// CutTime is Cut with Time bounds. Both bounds are snapped to the video's
+// frame grid, so the cut starts and ends on whole frames.
+func (v *Video) CutTime(start, end Time) (*Video, error) {
+	return v.Cut(start.Snap(v.fps).Seconds(), end.Snap(v.fps).Seconds())
+}
@@ -0,0 +1,153 @@
+package moviego
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// smpteTimecode is a parsed HH:MM:SS:FF timecode. Drop-frame timecodes use
+// ';' before the frames (HH:MM:SS;FF) and skip frame numbers to stay in step
+// with the clock at 29.97 and 59.94 fps.
+type smpteTimecode struct {
+	hours, minutes, seconds, frames int64
+	drop                            bool
+}
+
+// parseSMPTETimecode parses s for the nominal frame rate fps.
+func parseSMPTETimecode(s string, fps uint64) (smpteTimecode, error) {
+	var tc smpteTimecode
+	s = strings.TrimSpace(s)
+	if i := strings.LastIndexAny(s, ":;."); i > 0 && s[i] != ':' {
+		tc.drop = true
+		s = s[:i] + ":" + s[i+1:]
+	}
+	parts := strings.Split(s, ":")
+	if len(parts) != 4 {
+		return tc, fmt.Errorf("invalid timecode %q (want HH:MM:SS:FF or HH:MM:SS;FF)", s)
+	}
+	values := make([]int64, 4)
+	for i, part := range parts {
+		n, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || n < 0 || len(part) != 2 {
+			return tc, fmt.Errorf("invalid timecode %q (want HH:MM:SS:FF or HH:MM:SS;FF)", s)
+		}
+		values[i] = n
+	}
+	tc.hours, tc.minutes, tc.seconds, tc.frames = values[0], values[1], values[2], values[3]
+	if tc.hours > 23 || tc.minutes > 59 || tc.seconds > 59 || fps == 0 || tc.frames >= int64(fps) {
+		return tc, fmt.Errorf("timecode %q out of range at %d fps", s, fps)
+	}
+	if tc.drop {
+		if fps%30 != 0 {
+			return tc, fmt.Errorf("drop-frame timecode %q requires 30 or 60 fps (got=%d)", s, fps)
+		}
+		if tc.seconds == 0 && tc.minutes%10 != 0 && tc.frames < int64(fps/15) {
+			return tc, fmt.Errorf("timecode %q is a dropped frame number", s)
+		}
+	}
+	return tc, nil
+}
+
+// frame returns the frame number of the timecode counted from 00:00:00:00.
+func (tc smpteTimecode) frame(fps uint64) int64 {
+	n := int64(fps)
+	frame := (tc.hours*3600+tc.minutes*60+tc.seconds)*n + tc.frames
+	if tc.drop {
+		minutes := tc.hours*60 + tc.minutes
+		frame -= int64(fps/15) * (minutes - minutes/10)
+	}
+	return frame
+}
+
+// smpteTimecodeAt returns the timecode of frame number frame.
+func smpteTimecodeAt(frame int64, fps uint64, drop bool) smpteTimecode {
+	n := int64(fps)
+	frame %= 24 * 3600 * n
+	if drop {
+		// Add back the frame numbers skipped at the start of each minute
+		// except every tenth.
+		d := int64(fps / 15)
+		perTen := n*600 - d*9
+		perMinute := n*60 - d
+		tens, rem := frame/perTen, frame%perTen
+		frame += d * 9 * tens
+		if rem > d {
+			frame += d * ((rem - d) / perMinute)
+		}
+	}
+	return smpteTimecode{
+		hours:   frame / (3600 * n),
+		minutes: frame / (60 * n) % 60,
+		seconds: frame / n % 60,
+		frames:  frame % n,
+		drop:    drop,
+	}
+}
+
+func (tc smpteTimecode) String() string {
+	sep := ":"
+	if tc.drop {
+		sep = ";"
+	}
+	return fmt.Sprintf("%02d:%02d:%02d%s%02d", tc.hours, tc.minutes, tc.seconds, sep, tc.frames)
+}
+
+// SetStartTimecode returns a copy of the video whose output starts at the
+// SMPTE timecode tc ("01:00:00:00", or "01:00:00;00" for drop-frame at
+// 29.97/59.94 fps). WriteVideo writes it as the timecode track of MOV, MP4
+// and MXF outputs. Cuts advance it, so a cut at 10s of a video starting at
+// 01:00:00:00 starts at 01:00:10:00.
+func (v *Video) SetStartTimecode(tc string) (*Video, error) {
+	parsed, err := parseSMPTETimecode(tc, v.fps)
+	if err != nil {
+		return nil, fmt.Errorf("SetStartTimecode: %w (file=%s, label=%s)", err, safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	out := v.clone()
+	out.startTimecode = parsed.String()
+	return &out, nil
+}
+
+// GetStartTimecode returns the start timecode of the video: the timecode of
+// the source file (probed by NewVideoFile) or the one set with
+// SetStartTimecode. It is empty when the video has none.
+func (v *Video) GetStartTimecode() string {
+	return v.startTimecode
+}
+
+// offsetTimecode returns the timecode seconds after tc, or "" if tc is not
+// a valid timecode at fps.
+func offsetTimecode(tc string, seconds float64, fps uint64) string {
+	if tc == "" {
+		return ""
+	}
+	parsed, err := parseSMPTETimecode(tc, fps)
+	if err != nil {
+		return ""
+	}
+	return smpteTimecodeAt(parsed.frame(fps)+Seconds(seconds).Frame(fps), fps, parsed.drop).String()
+}
+
+// probeTimecode returns the timecode tag of a probed file: the format tags
+// (MXF, MOV) or a stream's tags (tmcd data streams, some MP4s).
+func probeTimecode(result map[string]interface{}) string {
+	tagged := func(m map[string]interface{}) string {
+		tags, _ := m["tags"].(map[string]interface{})
+		tc, _ := tags["timecode"].(string)
+		return tc
+	}
+	if format, ok := result["format"].(map[string]interface{}); ok {
+		if tc := tagged(format); tc != "" {
+			return tc
+		}
+	}
+	streams, _ := result["streams"].([]interface{})
+	for _, stream := range streams {
+		if streamMap, ok := stream.(map[string]interface{}); ok {
+			if tc := tagged(streamMap); tc != "" {
+				return tc
+			}
+		}
+	}
+	return ""
+}
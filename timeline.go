@@ -0,0 +1,133 @@
+package moviego
+
+import "fmt"
+
+// Timeline arranges clips back to back. Each cut between two clips is either a
+// hard cut or a transition from the transition library. Like Concatenate, the
+// timeline is compiled lazily: nothing is encoded until WriteVideo is called
+// on the result of Render.
+type Timeline struct {
+	clips []Video
+	// transitions[i] joins clips[i] and clips[i+1]; nil means a hard cut.
+	transitions []*TransitionParams
+	// audioItems are the lane layers mixed over the clip audio.
+	audioItems   []laneItem
+	laneSettings map[Lane]LaneSettings
+	// languages are the localized variants rendered by RenderLanguages.
+	languages []LanguageVariant
+	// concat configures the hard cuts, see SetConcatenateOptions.
+	concat ConcatenateOptions
+}
+
+// NewTimeline creates an empty timeline.
+func NewTimeline() *Timeline {
+	return &Timeline{}
+}
+
+// Add appends a clip joined to the previous one by a hard cut.
+func (t *Timeline) Add(clip *Video) *Timeline {
+	if len(t.clips) > 0 {
+		t.transitions = append(t.transitions, nil)
+	}
+	t.clips = append(t.clips, *clip)
+	return t
+}
+
+// AddClip appends a clip of any type joined to the previous one by a hard cut.
+func (t *Timeline) AddClip(clip Clip) (*Timeline, error) {
+	if clip == nil {
+		return nil, fmt.Errorf("Timeline.AddClip: clip is nil")
+	}
+	v, err := clip.toVideo()
+	if err != nil {
+		return nil, fmt.Errorf("Timeline.AddClip: %w", err)
+	}
+	return t.Add(v), nil
+}
+
+// AddWithTransition appends a clip joined to the previous one by a transition.
+// The transition is ignored for the first clip of the timeline.
+func (t *Timeline) AddWithTransition(clip *Video, params TransitionParams) *Timeline {
+	if len(t.clips) > 0 {
+		t.transitions = append(t.transitions, &params)
+	}
+	t.clips = append(t.clips, *clip)
+	return t
+}
+
+// SetConcatenateOptions configures the hard cuts of the timeline, e.g. an
+// audio crossfade at every cut. Transitions are not affected, and the audio
+// of every clip stays aligned with its picture.
+func (t *Timeline) SetConcatenateOptions(opts ConcatenateOptions) *Timeline {
+	t.concat = opts
+	return t
+}
+
+// GetClips returns the clips of the timeline in playback order.
+func (t *Timeline) GetClips() []Video {
+	return t.clips
+}
+
+// GetDuration returns the duration of the rendered timeline, taking the
+// overlap of transitions into account.
+func (t *Timeline) GetDuration() float64 {
+	var duration float64
+	for _, clip := range t.clips {
+		duration += clip.duration
+	}
+	for _, tr := range t.transitions {
+		if tr != nil {
+			duration -= tr.Duration
+		}
+	}
+	return duration
+}
+
+// Render compiles the timeline into a single Video. Lane audio, if any, is
+// mixed over the clip audio with the processing of each lane, together with
+// the sound effects carried by transitions.
+func (t *Timeline) Render() (*Video, error) {
+	return t.render(nil, false)
+}
+
+// render compiles the timeline with extra lane items. muteClips silences the
+// clip audio, e.g. to replace the original dialogue with a dubbed one.
+func (t *Timeline) render(extra []laneItem, muteClips bool) (*Video, error) {
+	if len(t.clips) == 0 {
+		return nil, fmt.Errorf("Timeline.Render: timeline is empty")
+	}
+
+	items := append(t.audioItems[:len(t.audioItems):len(t.audioItems)], extra...)
+	result := t.clips[0]
+	for i := 1; i < len(t.clips); i++ {
+		clip := t.clips[i]
+		var next *Video
+		var err error
+		if tr := t.transitions[i-1]; tr != nil {
+			params := *tr
+			if params.Sound != "" {
+				item, sfxErr := transitionSound(params, result.duration-params.Duration)
+				if sfxErr != nil {
+					return nil, fmt.Errorf("Timeline.Render: cut %d (file=%s): %w", i, safeFirstFilename(clip.filenames), sfxErr)
+				}
+				items = append(items, item)
+				params.Sound = ""
+			}
+			next, err = ConcatenateWithTransition(&result, &clip, params)
+		} else {
+			next, err = ConcatenateWithOptions([]Video{result, clip}, t.concat)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Timeline.Render: cut %d (file=%s): %w", i, safeFirstFilename(clip.filenames), err)
+		}
+		result = *next
+	}
+	if muteClips {
+		initRawVideo(&result)
+		result.audio = result.audio.chain("volume=0")
+	}
+	if len(items) > 0 {
+		return t.mixLanes(&result, items)
+	}
+	return &result, nil
+}
@@ -0,0 +1,282 @@
+package moviego
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Lane groups timeline audio by role so each group gets its own default
+// processing when the timeline is mixed.
+type Lane string
+
+const (
+	// LaneDialogue carries speech. The audio of the timeline clips is always
+	// part of this lane.
+	LaneDialogue Lane = "dialogue"
+	// LaneMusic carries music beds; by default it is ducked under dialogue.
+	LaneMusic Lane = "music"
+	// LaneSFX carries sound effects; by default it is trimmed by about 3 dB.
+	LaneSFX Lane = "sfx"
+)
+
+// DuckParams configures sidechain ducking (FFmpeg sidechaincompress).
+// Zero fields use the defaults listed below.
+type DuckParams struct {
+	Threshold float64 // level above which the key signal triggers ducking, 0-1 (default: 0.05)
+	Ratio     float64 // compression ratio, 1-20 (default: 8)
+	Attack    float64 // attack time in milliseconds (default: 20)
+	Release   float64 // release time in milliseconds (default: 300)
+}
+
+// LaneSettings holds the processing applied to every item of a lane.
+type LaneSettings struct {
+	Volume            float64 // gain applied to the lane (1.0 = unchanged)
+	DuckUnderDialogue bool    // duck the lane whenever the dialogue lane is active
+	Duck              DuckParams
+}
+
+// DefaultLaneSettings returns the default processing for a lane: music is
+// ducked under dialogue and sound effects are trimmed to 0.7 (about -3 dB).
+func DefaultLaneSettings(lane Lane) LaneSettings {
+	switch lane {
+	case LaneMusic:
+		return LaneSettings{Volume: 1, DuckUnderDialogue: true}
+	case LaneSFX:
+		return LaneSettings{Volume: 0.7}
+	default:
+		return LaneSettings{Volume: 1}
+	}
+}
+
+// laneItem is an audio layer placed on a timeline lane.
+type laneItem struct {
+	lane   Lane
+	audio  Audio
+	at     float64
+	volume float64
+}
+
+// AddAudio places audio on a lane, starting at the given timeline time in seconds.
+func (t *Timeline) AddAudio(lane Lane, audio *Audio, at float64) *Timeline {
+	t.audioItems = append(t.audioItems, laneItem{lane: lane, audio: *audio, at: at, volume: 1})
+	return t
+}
+
+// AddSFX loads a short sound effect and places it on the SFX lane at the given
+// timeline time. volume scales the effect on top of the lane trim (1.0 = unchanged).
+// Effects are mixed over the clip audio and never change its timing or length.
+func (t *Timeline) AddSFX(path string, at, volume float64) (*Timeline, error) {
+	if at < 0 {
+		return nil, fmt.Errorf("Timeline.AddSFX: at must be >= 0 (got=%.4f, file=%s)", at, path)
+	}
+	if volume < 0 {
+		return nil, fmt.Errorf("Timeline.AddSFX: volume must be >= 0 (got=%.4f, file=%s)", volume, path)
+	}
+	sfx, err := NewAudioFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Timeline.AddSFX: %w", err)
+	}
+	t.audioItems = append(t.audioItems, laneItem{lane: LaneSFX, audio: *sfx, at: at, volume: volume})
+	return t, nil
+}
+
+// transitionSound loads the sound effect of a transition starting at cut.
+func transitionSound(params TransitionParams, cut float64) (laneItem, error) {
+	at := cut + params.SoundOffset
+	if at < 0 {
+		return laneItem{}, fmt.Errorf("transition sound starts before 0 (at=%.4f, file=%s)", at, params.Sound)
+	}
+	volume := params.SoundVolume
+	if volume == 0 {
+		volume = 1
+	}
+	if volume < 0 {
+		return laneItem{}, fmt.Errorf("transition sound volume must be >= 0 (got=%.4f, file=%s)", volume, params.Sound)
+	}
+	sfx, err := NewAudioFile(params.Sound)
+	if err != nil {
+		return laneItem{}, err
+	}
+	return laneItem{lane: LaneSFX, audio: *sfx, at: at, volume: volume}, nil
+}
+
+// SetLaneSettings overrides the default processing of a lane.
+func (t *Timeline) SetLaneSettings(lane Lane, settings LaneSettings) *Timeline {
+	if t.laneSettings == nil {
+		t.laneSettings = make(map[Lane]LaneSettings)
+	}
+	t.laneSettings[lane] = settings
+	return t
+}
+
+// GetLaneSettings returns the processing applied to a lane.
+func (t *Timeline) GetLaneSettings(lane Lane) LaneSettings {
+	if s, ok := t.laneSettings[lane]; ok {
+		return s
+	}
+	return DefaultLaneSettings(lane)
+}
+
+// withDefaults fills zero fields with the sidechaincompress defaults used by MovieGo.
+func (p DuckParams) withDefaults() DuckParams {
+	if p.Threshold == 0 {
+		p.Threshold = 0.05
+	}
+	if p.Ratio == 0 {
+		p.Ratio = 8
+	}
+	if p.Attack == 0 {
+		p.Attack = 20
+	}
+	if p.Release == 0 {
+		p.Release = 300
+	}
+	return p
+}
+
+func (p DuckParams) validate() error {
+	if p.Threshold <= 0 || p.Threshold > 1 {
+		return fmt.Errorf("duck threshold must be in (0, 1] (got=%.4f)", p.Threshold)
+	}
+	if p.Ratio < 1 || p.Ratio > 20 {
+		return fmt.Errorf("duck ratio must be 1-20 (got=%.4f)", p.Ratio)
+	}
+	if p.Attack <= 0 || p.Release <= 0 {
+		return fmt.Errorf("duck attack and release must be positive (attack=%.4f, release=%.4f)", p.Attack, p.Release)
+	}
+	return nil
+}
+
+// buildDuckFilter returns a sidechaincompress chain ducking [main] whenever [key] is active.
+func buildDuckFilter(main, key string, p DuckParams) string {
+	return fmt.Sprintf("[%s][%s]sidechaincompress=threshold=%.4f:ratio=%.4f:attack=%.4f:release=%.4f",
+		main, key, p.Threshold, p.Ratio, p.Attack, p.Release)
+}
+
+// buildBus mixes labels into one bus labelled out. A single label is passed through.
+func buildBus(b *strings.Builder, labels []string, duration, out string) {
+	for _, l := range labels {
+		fmt.Fprintf(b, "[%s]", l)
+	}
+	if len(labels) == 1 {
+		fmt.Fprintf(b, "anull[%s];", out)
+		return
+	}
+	fmt.Fprintf(b, "amix=inputs=%d:duration=%s:normalize=0[%s];", len(labels), duration, out)
+}
+
+// mixLanes mixes the lane items into the audio of v. The clip audio is the
+// first dialogue input and defines the length of the mix.
+func (t *Timeline) mixLanes(v *Video, items []laneItem) (*Video, error) {
+	out := *v
+	initRawVideo(&out)
+
+	audios := []*Audio{&out.audio}
+	for i := range items {
+		audios = append(audios, &items[i].audio)
+	}
+	format := resolveAudioFormat(audios...)
+	clipAudio := out.audio.normalized(format)
+
+	filenames := append([]string{}, out.audio.filenames...)
+	seen := make(map[string]struct{}, len(filenames))
+	for _, f := range filenames {
+		seen[f] = struct{}{}
+	}
+	audioFilterComplex := clipAudio.filterComplex
+	buses := map[Lane][]string{LaneDialogue: {clipAudio.lastAudioLabel()}}
+	var lanes []Lane
+
+	for i, item := range items {
+		if item.at < 0 {
+			return nil, fmt.Errorf("Timeline.Render: audio item %d on lane %s starts before 0 (at=%.4f)", i, item.lane, item.at)
+		}
+		if len(item.audio.filenames) == 0 {
+			return nil, fmt.Errorf("Timeline.Render: audio item %d on lane %s has no file", i, item.lane)
+		}
+		a := item.audio
+		initRawAudio(&a)
+		a = a.normalized(format)
+		if vol := item.volume * t.GetLaneSettings(item.lane).Volume; vol != 1 {
+			a = a.chain(fmt.Sprintf("volume=%.4f", vol))
+		}
+		if item.at > 0 {
+			a = a.chain(fmt.Sprintf("adelay=delays=%d:all=1", int64(item.at*1000)))
+		}
+		audioFilterComplex = append(audioFilterComplex, a.filterComplex...)
+		for _, f := range a.filenames {
+			if _, exists := seen[f]; !exists {
+				seen[f] = struct{}{}
+				filenames = append(filenames, f)
+			}
+		}
+		if _, exists := buses[item.lane]; !exists {
+			lanes = append(lanes, item.lane)
+		}
+		buses[item.lane] = append(buses[item.lane], a.lastAudioLabel())
+	}
+
+	label := fmt.Sprintf("lanes_%d", incrementGlobalCounter())
+	var b strings.Builder
+	if s := t.GetLaneSettings(LaneDialogue); s.Volume != 1 {
+		buildBus(&b, buses[LaneDialogue], "first", label+"_dlg_raw")
+		fmt.Fprintf(&b, "[%s_dlg_raw]volume=%.4f[%s_dlg];", label, s.Volume, label)
+	} else {
+		buildBus(&b, buses[LaneDialogue], "first", label+"_dlg")
+	}
+
+	var ducked []Lane
+	for _, lane := range lanes {
+		if lane != LaneDialogue && t.GetLaneSettings(lane).DuckUnderDialogue {
+			ducked = append(ducked, lane)
+		}
+	}
+	dialogue := label + "_dlg"
+	keys := make([]string, len(ducked))
+	if len(ducked) > 0 {
+		fmt.Fprintf(&b, "[%s_dlg]asplit=%d[%s_dlg_mix]", label, len(ducked)+1, label)
+		for i := range ducked {
+			keys[i] = fmt.Sprintf("%s_key%d", label, i)
+			fmt.Fprintf(&b, "[%s]", keys[i])
+		}
+		b.WriteString(";")
+		dialogue = label + "_dlg_mix"
+	}
+
+	mix := []string{dialogue}
+	for _, lane := range lanes {
+		if lane == LaneDialogue {
+			continue
+		}
+		bus := fmt.Sprintf("%s_%s", label, sanitize(string(lane)))
+		buildBus(&b, buses[lane], "longest", bus)
+		for i, d := range ducked {
+			if d != lane {
+				continue
+			}
+			params := t.GetLaneSettings(lane).Duck.withDefaults()
+			if err := params.validate(); err != nil {
+				return nil, fmt.Errorf("Timeline.Render: lane %s: %w", lane, err)
+			}
+			fmt.Fprintf(&b, "%s[%s_ducked];", buildDuckFilter(bus, keys[i], params), bus)
+			bus += "_ducked"
+		}
+		mix = append(mix, bus)
+	}
+	for _, l := range mix {
+		fmt.Fprintf(&b, "[%s]", l)
+	}
+	fmt.Fprintf(&b, "amix=inputs=%d:duration=first:normalize=0", len(mix))
+
+	audioFilterComplex = append(audioFilterComplex, FilterComplex{
+		Order:         incrementOrderCounter(),
+		Label:         label + "_a",
+		FilterElement: b.String(),
+	})
+
+	out.audio = clipAudio
+	out.audio.filenames = filenames
+	out.audio.filterComplex = audioFilterComplex
+	out.audioRemoved = false
+	return &out, nil
+}
@@ -0,0 +1,420 @@
+package moviego
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// IncrementalResult reports what WriteIncremental re-encoded.
+type IncrementalResult struct {
+	Segments int         // segments of the timeline, one per clip
+	Rendered []TimeRange // time ranges encoded by this call, merged
+}
+
+// incrementalManifest records the segments of an incremental render, in
+// <OutputPath>.parts/manifest.json.
+type incrementalManifest struct {
+	Segments []incrementalSegment `json:"segments"`
+}
+
+type incrementalSegment struct {
+	Start       float64 `json:"start"`
+	End         float64 `json:"end"`
+	Fingerprint string  `json:"fingerprint"`
+	File        string  `json:"file"`
+}
+
+// WriteIncremental renders the timeline to parms.OutputPath, re-encoding only
+// the time ranges that changed since the previous WriteIncremental to the
+// same path. The timeline is encoded as one segment per clip, kept in
+// <OutputPath>.parts; a segment is reused while its clip, the transition
+// into it and the encoding parameters are unchanged, and all segments are
+// joined with a stream copy. Changing a text overlay of one clip therefore
+// only re-encodes that clip.
+//
+// A segment is built from its own clip, plus the tail of the previous clip
+// when a transition leads into it, so re-encoding a late segment does not
+// decode the timeline before it. Lane audio, transition sounds and audio
+// crossfades at hard cuts span segment boundaries; with any of them every
+// segment is cut from the full timeline instead. Lane audio also means
+// segments are only reused at the same position, and changing the lanes
+// re-encodes everything. Each segment starts with a keyframe, so the output
+// has a keyframe at every clip boundary.
+//
+// VerifyDuration and OnAudioReport check the joined output. Options that
+// process the output as a whole (loudness normalization, AudioTracks, HLS,
+// CENC and FrameMetadata) are not supported.
+func (t *Timeline) WriteIncremental(parms VideoParameters) (*IncrementalResult, error) {
+	if parms.OutputPath == "" {
+		return nil, fmt.Errorf("Timeline.WriteIncremental: output path is empty")
+	}
+	if parms.Sidecar || parms.StreamCopy {
+		return nil, fmt.Errorf("Timeline.WriteIncremental: Sidecar and StreamCopy are not supported (file=%s)", parms.OutputPath)
+	}
+	// These apply to the whole output and would be applied to each segment.
+	switch {
+	case parms.NormalizeLoudness != nil || parms.Delivery != nil && parms.Delivery.Loudness != 0:
+		return nil, fmt.Errorf("Timeline.WriteIncremental: loudness normalization is not supported (file=%s)", parms.OutputPath)
+	case len(parms.AudioTracks) > 0:
+		return nil, fmt.Errorf("Timeline.WriteIncremental: AudioTracks are not supported (file=%s)", parms.OutputPath)
+	case strings.EqualFold(filepath.Ext(parms.OutputPath), ".m3u8") || parms.HLS.Encryption != nil:
+		return nil, fmt.Errorf("Timeline.WriteIncremental: HLS outputs are not supported (file=%s)", parms.OutputPath)
+	case parms.CENC != nil:
+		return nil, fmt.Errorf("Timeline.WriteIncremental: CENC encryption is not supported (file=%s)", parms.OutputPath)
+	case parms.FrameMetadata:
+		return nil, fmt.Errorf("Timeline.WriteIncremental: FrameMetadata is not supported (file=%s)", parms.OutputPath)
+	}
+	segments, err := t.segments(parms)
+	if err != nil {
+		return nil, fmt.Errorf("Timeline.WriteIncremental: %w", err)
+	}
+
+	dir := parms.OutputPath + ".parts"
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("Timeline.WriteIncremental: failed to create '%s': %w", dir, err)
+	}
+	manifestPath := filepath.Join(dir, "manifest.json")
+	var previous incrementalManifest
+	if data, err := os.ReadFile(manifestPath); err == nil {
+		// A corrupt manifest only costs a full render.
+		_ = json.Unmarshal(data, &previous)
+	}
+	reusable := make(map[string]string, len(previous.Segments))
+	for _, s := range previous.Segments {
+		if _, err := os.Stat(filepath.Join(dir, s.File)); err == nil {
+			reusable[s.Fingerprint] = s.File
+		}
+	}
+
+	result := &IncrementalResult{Segments: len(segments)}
+	local := t.localSegments()
+	var full *Video
+	ext := filepath.Ext(parms.OutputPath)
+	for i := range segments {
+		s := &segments[i]
+		s.File = s.Fingerprint[:16] + ext
+		if file, ok := reusable[s.Fingerprint]; ok {
+			s.File = file
+			continue
+		}
+		var part *Video
+		if local {
+			part, err = t.segmentVideo(i, *s)
+		} else {
+			if full == nil {
+				if full, err = t.Render(); err != nil {
+					return nil, fmt.Errorf("Timeline.WriteIncremental: %w", err)
+				}
+			}
+			part, err = full.Cut(s.Start, s.End)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Timeline.WriteIncremental: segment %d: %w", i, err)
+		}
+		segmentParms := parms
+		segmentParms.OutputPath = filepath.Join(dir, s.File)
+		// Checked on the joined output instead.
+		segmentParms.VerifyDuration, segmentParms.OnAudioReport = nil, nil
+		if err := part.WriteVideo(segmentParms); err != nil {
+			return nil, fmt.Errorf("Timeline.WriteIncremental: segment %d: %w", i, err)
+		}
+		if n := len(result.Rendered); n > 0 && result.Rendered[n-1].End == s.Start {
+			result.Rendered[n-1].End = s.End
+		} else {
+			result.Rendered = append(result.Rendered, TimeRange{Start: s.Start, End: s.End})
+		}
+	}
+
+	if err := joinSegments(dir, segments, parms); err != nil {
+		return nil, err
+	}
+	if check := parms.VerifyDuration; check != nil {
+		if err := verifyJoined(dir, segments, parms, *check); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkAudioReport("Timeline.WriteIncremental", parms); err != nil {
+		return nil, err
+	}
+	if err := writeJSON(manifestPath, incrementalManifest{Segments: segments}); err != nil {
+		return nil, fmt.Errorf("Timeline.WriteIncremental: %w", err)
+	}
+	// Drop the segments of previous renders that are no longer used.
+	keep := map[string]bool{"manifest.json": true, "segments.txt": true}
+	for _, s := range segments {
+		keep[s.File] = true
+	}
+	for _, s := range previous.Segments {
+		if !keep[s.File] {
+			_ = os.Remove(filepath.Join(dir, s.File))
+		}
+	}
+	return result, nil
+}
+
+// localSegments reports whether every segment can be built from its own
+// clips: nothing in the audio reaches across segment boundaries.
+func (t *Timeline) localSegments() bool {
+	if len(t.audioItems) > 0 || t.concat.AudioCrossfade > 0 {
+		return false
+	}
+	for _, tr := range t.transitions {
+		if tr != nil && tr.Sound != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// segmentVideo builds segment i from clip i. A transition into the clip is
+// rendered from a tail of the previous clip, which must be longer than the
+// transition; the segment starts where the transition does.
+func (t *Timeline) segmentVideo(i int, s incrementalSegment) (*Video, error) {
+	clip := t.clips[i]
+	if i == 0 || t.transitions[i-1] == nil {
+		// Segment bounds are rounded to frames and may pass the clip end.
+		return clip.Cut(0, min(clip.duration, s.End-s.Start))
+	}
+	tr := *t.transitions[i-1]
+	prev := t.clips[i-1]
+	lead := min(prev.duration, 2*tr.Duration)
+	tail, err := prev.Cut(prev.duration-lead, prev.duration)
+	if err != nil {
+		return nil, err
+	}
+	joined, err := ConcatenateWithTransition(tail, &clip, tr)
+	if err != nil {
+		return nil, err
+	}
+	start := lead - tr.Duration
+	return joined.Cut(start, min(joined.duration, start+s.End-s.Start))
+}
+
+// joinSegments concatenates the segment files into parms.OutputPath without
+// re-encoding (FFmpeg concat demuxer).
+func joinSegments(dir string, segments []incrementalSegment, parms VideoParameters) error {
+	var list strings.Builder
+	var duration float64
+	for _, s := range segments {
+		fmt.Fprintf(&list, "file '%s'\n", s.File)
+		duration += s.End - s.Start
+	}
+	listPath := filepath.Join(dir, "segments.txt")
+	if err := os.WriteFile(listPath, []byte(list.String()), 0644); err != nil {
+		return fmt.Errorf("Timeline.WriteIncremental: failed to write '%s': %w", listPath, err)
+	}
+	ffmpegPath, err := getFFmpegPath()
+	if err != nil {
+		return fmt.Errorf("Timeline.WriteIncremental: failed to get ffmpeg path: %w", err)
+	}
+	job := renderJob{
+		op:         "Timeline.WriteIncremental",
+		args:       []string{"-f", "concat", "-safe", "0", "-i", listPath},
+		outputArgs: []string{"-map", "0", "-c", "copy"},
+		output:     parms.OutputPath,
+		duration:   duration,
+		env:        parms.Env,
+		onProgress: parms.OnProgress,
+		silent:     parms.SilentProgress,
+	}
+	return job.run(ffmpegPath)
+}
+
+// verifyJoined compares the joined output with the summed segment durations;
+// audio is expected when the first segment has some.
+func verifyJoined(dir string, segments []incrementalSegment, parms VideoParameters, check DurationCheck) error {
+	var planned float64
+	for _, s := range segments {
+		planned += s.End - s.Start
+	}
+	first, err := NewVideoFile(filepath.Join(dir, segments[0].File))
+	if err != nil {
+		return fmt.Errorf("Timeline.WriteIncremental: verify duration: %w", err)
+	}
+	withAudio := first.GetAudio().GetCodec() != ""
+	return verifyDuration("Timeline.WriteIncremental", parms.OutputPath, planned, resolveFps(parms.Fps, first.GetFps()), withAudio, check)
+}
+
+// segments splits the timeline at the clip starts and fingerprints each
+// segment. Segment i runs from the start of clip i to the start of clip i+1,
+// so a transition belongs to the segment of the clip it leads into.
+func (t *Timeline) segments(parms VideoParameters) ([]incrementalSegment, error) {
+	if len(t.clips) == 0 {
+		return nil, fmt.Errorf("timeline is empty")
+	}
+	params, err := fingerprintParams(parms)
+	if err != nil {
+		return nil, fmt.Errorf("encoding parameters: %w", err)
+	}
+	shared := []string{params, fmt.Sprintf("%+v", t.concat)}
+	if len(t.audioItems) > 0 {
+		lanes, err := t.laneFingerprint()
+		if err != nil {
+			return nil, err
+		}
+		shared = append(shared, lanes)
+	}
+
+	fps := float64(t.clips[0].fps)
+	frame := func(sec float64) float64 {
+		if fps <= 0 {
+			return sec
+		}
+		return math.Round(sec*fps) / fps
+	}
+	clips := make([]string, len(t.clips))
+	for i := range t.clips {
+		flat, err := t.clips[i].FlattenOverlays()
+		if err != nil {
+			return nil, fmt.Errorf("clip %d: %w", i, err)
+		}
+		if clips[i], err = fingerprintVideo(flat); err != nil {
+			return nil, fmt.Errorf("clip %d: %w", i, err)
+		}
+	}
+
+	segments := make([]incrementalSegment, len(t.clips))
+	var start float64
+	for i, clip := range t.clips {
+		parts := append([]string{clips[i]}, shared...)
+		if i > 0 {
+			if tr := t.transitions[i-1]; tr != nil {
+				// The transition mixes the tail of the previous clip in.
+				parts = append(parts, fmt.Sprintf("%+v", *tr), clips[i-1])
+			}
+		}
+		end := start + clip.duration
+		if i+1 < len(t.clips) {
+			if tr := t.transitions[i]; tr != nil {
+				end -= tr.Duration
+			}
+		}
+		s := incrementalSegment{Start: frame(start), End: frame(end)}
+		if len(t.audioItems) > 0 {
+			parts = append(parts, fmt.Sprintf("%.6f-%.6f", s.Start, s.End))
+		} else {
+			parts = append(parts, fmt.Sprintf("%.6f", s.End-s.Start))
+		}
+		s.Fingerprint = hashStrings(parts)
+		segments[i] = s
+		start = end
+	}
+	return segments, nil
+}
+
+// laneFingerprint fingerprints the lane audio and its processing.
+func (t *Timeline) laneFingerprint() (string, error) {
+	parts := []string{}
+	for _, item := range t.audioItems {
+		a, err := fingerprintGraph(item.audio.filenames, nil, item.audio.filterComplex)
+		if err != nil {
+			return "", err
+		}
+		parts = append(parts, fmt.Sprintf("%s|%.6f|%.6f|%s", item.lane, item.at, item.volume, a))
+	}
+	for _, lane := range []Lane{LaneDialogue, LaneMusic, LaneSFX} {
+		parts = append(parts, fmt.Sprintf("%+v", t.GetLaneSettings(lane)))
+	}
+	return hashStrings(parts), nil
+}
+
+// labelPattern matches the [label] references of a filter graph.
+var labelPattern = regexp.MustCompile(`\[([A-Za-z0-9_.:-]+)\]`)
+
+// fingerprintVideo identifies the output of v independently of the label
+// counters, which differ between runs building the same graph.
+func fingerprintVideo(v *Video) (string, error) {
+	graph, err := fingerprintGraph(v.filenames, v.filterComplex, v.audio.filterComplex)
+	if err != nil {
+		return "", err
+	}
+	return hashStrings([]string{graph,
+		fmt.Sprintf("%.6f-%.6f|%.6f|%dx%d|%d|%v", v.startTime, v.endTime, v.duration, v.width, v.height, v.fps, v.ffmpegArgs)}), nil
+}
+
+// fingerprintGraph hashes the input files (path, size and modification time)
+// and the filter graphs with labels renumbered in order of appearance.
+func fingerprintGraph(filenames []string, graphs ...[]FilterComplex) (string, error) {
+	var b strings.Builder
+	for _, f := range filenames {
+		info, err := os.Stat(f)
+		if err != nil {
+			return "", fmt.Errorf("failed to stat '%s': %w", f, err)
+		}
+		fmt.Fprintf(&b, "%s|%d|%d\n", f, info.Size(), info.ModTime().UnixNano())
+	}
+	labels := map[string]string{}
+	rename := func(label string) string {
+		if label == "" {
+			return ""
+		}
+		if _, ok := labels[label]; !ok {
+			labels[label] = fmt.Sprintf("L%d", len(labels))
+		}
+		return labels[label]
+	}
+	for _, graph := range graphs {
+		for _, fc := range graph {
+			element := labelPattern.ReplaceAllStringFunc(fc.FilterElement, func(m string) string {
+				return "[" + rename(m[1:len(m)-1]) + "]"
+			})
+			fmt.Fprintf(&b, "%s|%s|%s|%s\n", rename(fc.Label), fc.FileCopy.Filename, rename(fc.FileCopy.Label), element)
+		}
+	}
+	return hashStrings([]string{b.String()}), nil
+}
+
+// fingerprintParams hashes the encoding parameters that change the encoded
+// data of a segment. The output path, progress reporting, the environment
+// and the thread count are left out.
+func fingerprintParams(parms VideoParameters) (string, error) {
+	data, err := json.Marshal(struct {
+		Codec                 Codec
+		Fps                   uint64
+		Preset                Preset
+		WithMask              bool
+		Bitrate               string
+		PixelFormat           PixelFormat
+		AudioCodec            AudioCodec
+		AudioBitrate          string
+		AudioSampleRate       uint64
+		AudioChannels         uint8
+		AudioLanguage         string
+		AudioTitle            string
+		WorkingPixelFormat    PixelFormat
+		Tune                  Tune
+		EncoderOptions        EncoderOptions
+		Keyframes             KeyframeOptions
+		Delivery              *DeliveryProfile
+		Captions              CaptionOptions
+		BitstreamFilters      []BitstreamFilter
+		AudioBitstreamFilters []BitstreamFilter
+		StripMetadata         bool
+	}{
+		parms.Codec, parms.Fps, parms.Preset, parms.WithMask, parms.Bitrate, parms.PixelFormat,
+		parms.AudioCodec, parms.AudioBitrate, parms.AudioSampleRate, parms.AudioChannels,
+		parms.AudioLanguage, parms.AudioTitle, parms.WorkingPixelFormat, parms.Tune,
+		parms.EncoderOptions, parms.Keyframes, parms.Delivery, parms.Captions,
+		parms.BitstreamFilters, parms.AudioBitstreamFilters, parms.StripMetadata,
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func hashStrings(parts []string) string {
+	hash := sha256.New()
+	for _, p := range parts {
+		hash.Write([]byte(p))
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,189 @@
+package moviego
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LanguageVariant is a localized version of a timeline: its own voiceover
+// and subtitles over the shared picture, music and effects.
+type LanguageVariant struct {
+	Language    string // ISO 639-2 code, e.g. "eng", "fra"; tags the streams and names the output
+	Voiceover   *Audio // placed on the dialogue lane (optional)
+	VoiceoverAt float64
+	// ReplaceDialogue silences the clip audio, for dubs replacing the
+	// original speech. Music stays ducked under the voiceover.
+	ReplaceDialogue bool
+	Subtitles       string // subtitle file (SRT, ASS, ...) added as a soft subtitle track (optional)
+}
+
+// AddLanguage registers a language variant rendered by RenderLanguages.
+func (t *Timeline) AddLanguage(variant LanguageVariant) *Timeline {
+	t.languages = append(t.languages, variant)
+	return t
+}
+
+// RenderLanguages renders every language variant of the timeline. The
+// picture is encoded once with parms, without audio; each variant then only
+// renders its audio mix and is muxed with the shared video stream, its
+// subtitles and parms.AudioTracks. Loudness normalization (NormalizeLoudness
+// or the delivery profile's), VerifyDuration and OnAudioReport apply to each
+// language's output. Outputs are named after parms.OutputPath with the
+// language code before the extension (film.mp4 -> film.fra.mp4). It returns
+// the output of each language.
+//
+// HLS and CENC outputs cannot be remuxed and are not supported, nor is
+// FrameMetadata.
+func (t *Timeline) RenderLanguages(parms VideoParameters) (map[string]string, error) {
+	if len(t.languages) == 0 {
+		return nil, fmt.Errorf("Timeline.RenderLanguages: no language variants added")
+	}
+	if parms.OutputPath == "" {
+		return nil, fmt.Errorf("Timeline.RenderLanguages: output path is empty")
+	}
+	switch {
+	case strings.EqualFold(filepath.Ext(parms.OutputPath), ".m3u8") || parms.HLS.Encryption != nil:
+		return nil, fmt.Errorf("Timeline.RenderLanguages: HLS outputs are not supported (file=%s)", parms.OutputPath)
+	case parms.CENC != nil:
+		return nil, fmt.Errorf("Timeline.RenderLanguages: CENC encryption is not supported (file=%s)", parms.OutputPath)
+	case parms.FrameMetadata:
+		return nil, fmt.Errorf("Timeline.RenderLanguages: FrameMetadata is not supported (file=%s)", parms.OutputPath)
+	}
+	loudness := parms.NormalizeLoudness
+	if loudness == nil && parms.Delivery != nil {
+		loudness = parms.Delivery.loudness()
+	}
+	seen := make(map[string]bool)
+	for i, lang := range t.languages {
+		if lang.Language == "" || strings.ContainsAny(lang.Language, `/\`) {
+			return nil, fmt.Errorf("Timeline.RenderLanguages: variant %d has an invalid language code %q", i, lang.Language)
+		}
+		if seen[lang.Language] {
+			return nil, fmt.Errorf("Timeline.RenderLanguages: language %s added twice", lang.Language)
+		}
+		seen[lang.Language] = true
+		if lang.Subtitles != "" {
+			if _, err := os.Stat(lang.Subtitles); err != nil {
+				return nil, fmt.Errorf("Timeline.RenderLanguages: language %s: subtitles: %w", lang.Language, err)
+			}
+		}
+	}
+	ffmpegPath, err := getFFmpegPath()
+	if err != nil {
+		return nil, fmt.Errorf("Timeline.RenderLanguages: failed to get ffmpeg path: %w", err)
+	}
+
+	ext := filepath.Ext(parms.OutputPath)
+	tmp, err := os.MkdirTemp(parms.Env.tempDir(), "moviego-languages-*")
+	if err != nil {
+		return nil, fmt.Errorf("Timeline.RenderLanguages: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+
+	base, err := t.Render()
+	if err != nil {
+		return nil, fmt.Errorf("Timeline.RenderLanguages: %w", err)
+	}
+	silent, err := base.RemoveAudio()
+	if err != nil {
+		return nil, fmt.Errorf("Timeline.RenderLanguages: %w", err)
+	}
+	// The audio settings only apply when each language is muxed.
+	picture := parms
+	picture.OutputPath = filepath.Join(tmp, "picture"+ext)
+	picture.Sidecar = false
+	picture.AudioTracks = nil
+	picture.NormalizeLoudness = nil
+	picture.VerifyDuration = nil
+	picture.OnAudioReport = nil
+	picture.OnLevels = nil
+	if err := silent.WriteVideo(picture); err != nil {
+		return nil, fmt.Errorf("Timeline.RenderLanguages: %w", err)
+	}
+
+	outputs := make(map[string]string, len(t.languages))
+	for _, lang := range t.languages {
+		var extra []laneItem
+		if lang.Voiceover != nil {
+			if lang.VoiceoverAt < 0 {
+				return nil, fmt.Errorf("Timeline.RenderLanguages: language %s: voiceover starts before 0 (at=%.4f)", lang.Language, lang.VoiceoverAt)
+			}
+			extra = append(extra, laneItem{lane: LaneDialogue, audio: *lang.Voiceover, at: lang.VoiceoverAt, volume: 1})
+		}
+		variant, err := t.render(extra, lang.ReplaceDialogue)
+		if err != nil {
+			return nil, fmt.Errorf("Timeline.RenderLanguages: language %s: %w", lang.Language, err)
+		}
+		op := "Timeline.RenderLanguages: language " + lang.Language
+		if loudness != nil {
+			if variant, err = variant.normalizeLoudness(op, *loudness, parms.Env); err != nil {
+				return nil, err
+			}
+		}
+		// A lossless intermediate: the mix is encoded once, when muxed.
+		mix := filepath.Join(tmp, lang.Language+".wav")
+		err = variant.WriteAudio(AudioParameters{OutputPath: mix, Codec: AudioCodecPCM, Env: parms.Env, SilentProgress: true})
+		if err != nil {
+			return nil, fmt.Errorf("Timeline.RenderLanguages: language %s: %w", lang.Language, err)
+		}
+
+		output := strings.TrimSuffix(parms.OutputPath, ext) + "." + lang.Language + ext
+		if err := muxLanguage(ffmpegPath, picture.OutputPath, mix, output, lang, base.GetDuration(), parms); err != nil {
+			return nil, fmt.Errorf("Timeline.RenderLanguages: language %s: %w", lang.Language, err)
+		}
+		if check := parms.VerifyDuration; check != nil {
+			if err := verifyDuration(op, output, base.GetDuration(), resolveFps(parms.Fps, base.GetFps()), true, *check); err != nil {
+				return nil, err
+			}
+		}
+		langParms := parms
+		langParms.OutputPath = output
+		if err := checkAudioReport(op, langParms); err != nil {
+			return nil, err
+		}
+		outputs[lang.Language] = output
+	}
+	return outputs, nil
+}
+
+// muxLanguage combines the shared picture with a language's mix, subtitles
+// and the extra audio tracks of parms.
+func muxLanguage(ffmpegPath, picture, mix, output string, lang LanguageVariant, duration float64, parms VideoParameters) error {
+	args := []string{"-i", picture, "-i", mix}
+	if lang.Subtitles != "" {
+		args = append(args, "-i", lang.Subtitles)
+	}
+	args = append(args, "-map", "0:v:0", "-map", "1:a:0", "-c:v", "copy")
+	if strings.EqualFold(filepath.Ext(output), ".m4v") && parms.AudioCodec == "" {
+		parms.AudioCodec = AudioCodecAAC
+	}
+	audioArgs, _, err := audioEncoderArgs(parms, output)
+	if err != nil {
+		return err
+	}
+	args = append(args, audioArgs...)
+	if lang.Subtitles != "" {
+		args = append(args, "-map", "2:s:0", "-metadata:s:s:0", "language="+lang.Language)
+	}
+	parms.AudioLanguage = lang.Language
+	args, cleanupTracks, err := audioTrackArgs(args, parms, true)
+	if err != nil {
+		return err
+	}
+	defer cleanupTracks()
+	if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+		return err
+	}
+	job := renderJob{
+		op:         "Timeline.RenderLanguages",
+		args:       args,
+		output:     output,
+		duration:   duration,
+		env:        parms.Env,
+		onProgress: parms.OnProgress,
+		silent:     parms.SilentProgress,
+	}
+	return job.run(ffmpegPath)
+}
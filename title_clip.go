@@ -0,0 +1,96 @@
+package moviego
+
+import "fmt"
+
+// TitleClip is the clip form of a TextClip: the text drawn on a background
+// of its own for a duration, e.g. a title card for Timeline.AddClip or a
+// lower third for CompositeClips. The background is transparent unless set,
+// so the text is all that shows when the clip is composited.
+type TitleClip struct {
+	text       TextClip
+	background Color
+	width      uint64
+	height     uint64
+	duration   float64
+	fps        uint64
+	position   Position
+}
+
+// NewTitleClip creates a width x height clip showing text for duration
+// seconds. The text is placed and timed within the clip as with AddText.
+func NewTitleClip(text TextClip, width, height uint64, duration float64) *TitleClip {
+	return &TitleClip{
+		text:       text,
+		background: "black@0",
+		width:      width,
+		height:     height,
+		duration:   duration,
+	}
+}
+
+// GetWidth returns the clip width.
+func (tc *TitleClip) GetWidth() uint64 {
+	return tc.width
+}
+
+// GetHeight returns the clip height.
+func (tc *TitleClip) GetHeight() uint64 {
+	return tc.height
+}
+
+// GetDuration returns the clip duration.
+func (tc *TitleClip) GetDuration() float64 {
+	return tc.duration
+}
+
+// GetFps returns the clip frame rate (default: 30).
+func (tc *TitleClip) GetFps() uint64 {
+	if tc.fps == 0 {
+		return defaultClipFps
+	}
+	return tc.fps
+}
+
+// GetPosition returns the overlay position.
+// Returns center position if none was explicitly set.
+func (tc *TitleClip) GetPosition() Position {
+	if tc.position.X == "" && tc.position.Y == "" {
+		return CenterPosition()
+	}
+	return tc.position
+}
+
+// Fps sets the clip frame rate.
+func (tc *TitleClip) Fps(fps uint64) *TitleClip {
+	tc.fps = fps
+	return tc
+}
+
+// SetPosition sets the overlay position used by CompositeClips.
+func (tc *TitleClip) SetPosition(position Position) *TitleClip {
+	tc.position = position
+	return tc
+}
+
+// Background sets the color behind the text (default: transparent).
+func (tc *TitleClip) Background(color Color) *TitleClip {
+	tc.background = color
+	return tc
+}
+
+func (tc *TitleClip) toVideo() (*Video, error) {
+	if err := tc.Validate(); err != nil {
+		return nil, err
+	}
+	fps := tc.GetFps()
+	label := fmt.Sprintf("title_%d", incrementGlobalCounter())
+	// yuva420p keeps the alpha of the background through the overlay.
+	element := fmt.Sprintf("color=c=%s:s=%dx%d:r=%d:d=%.4f,format=yuva420p", tc.background.ffmpeg(), tc.width, tc.height, fps, tc.duration)
+	v := generatedVideo(nil, element, FileCopy{}, label, tc.width, tc.height, fps, tc.duration)
+	v.position = tc.position
+	out, err := v.AddText(tc.text)
+	if err != nil {
+		return nil, fmt.Errorf("TitleClip: %w", err)
+	}
+	return out, nil
+}
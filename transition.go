@@ -1,6 +1,9 @@
 package moviego
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // Transition defines the type of crossfade between two clips.
 type Transition string
@@ -26,12 +29,62 @@ const (
 	TransitionDiagTR     Transition = "diagtr"
 	TransitionDiagBL     Transition = "diagbl"
 	TransitionDiagBR     Transition = "diagbr"
+
+	// Editorial names for the common cut transitions.
+	TransitionDipToBlack  Transition = TransitionFadeBlack
+	TransitionSlide       Transition = TransitionSlideLeft
+	TransitionZoom        Transition = TransitionZoomIn
+	TransitionBlurThrough Transition = "hblur"
+
+	// TransitionLumaWipe reveals clip2 through a grayscale matte image: dark
+	// areas of TransitionParams.Matte switch first, bright areas last.
+	// It is compiled to a custom alphamerge/overlay graph instead of xfade.
+	TransitionLumaWipe Transition = "lumawipe"
 )
 
+// transitionNames maps library names to transitions, see ParseTransition.
+var transitionNames = map[string]Transition{
+	"dissolve":     TransitionDissolve,
+	"dip-to-black": TransitionDipToBlack,
+	"dip-to-white": TransitionFadeWhite,
+	"slide":        TransitionSlide,
+	"zoom":         TransitionZoom,
+	"blur-through": TransitionBlurThrough,
+	"luma-wipe":    TransitionLumaWipe,
+}
+
+// ParseTransition resolves a transition by library name ("dissolve",
+// "dip-to-black", "slide", "zoom", "blur-through", "luma-wipe") or by its
+// FFmpeg xfade name ("fade", "wipeleft", ...).
+func ParseTransition(name string) (Transition, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if t, ok := transitionNames[name]; ok {
+		return t, nil
+	}
+	switch Transition(name) {
+	case TransitionFade, TransitionFadeBlack, TransitionFadeWhite,
+		TransitionWipeLeft, TransitionWipeRight, TransitionWipeUp, TransitionWipeDown,
+		TransitionSlideLeft, TransitionSlideRight, TransitionSlideUp, TransitionSlideDown,
+		TransitionCircleCrop, TransitionDissolve, TransitionPixelize, TransitionRadial,
+		TransitionZoomIn, TransitionDiagTL, TransitionDiagTR, TransitionDiagBL, TransitionDiagBR,
+		TransitionBlurThrough, TransitionLumaWipe:
+		return Transition(name), nil
+	}
+	return "", fmt.Errorf("ParseTransition: unknown transition %q", name)
+}
+
 // TransitionParams holds parameters for clip-to-clip transitions.
 type TransitionParams struct {
 	Transition Transition
 	Duration   float64 // overlap duration in seconds
+	Matte      string  // grayscale matte image, required for TransitionLumaWipe
+
+	// Sound is an optional sound effect played with the transition, e.g. a
+	// whoosh shipped with a branded transition pack. It is placed on the SFX
+	// lane at the start of the transition plus SoundOffset (negative to lead in).
+	Sound       string
+	SoundOffset float64
+	SoundVolume float64 // 0 means 1.0
 }
 
 // ConcatenateWithTransition joins two clips with a transition effect.
@@ -40,6 +93,14 @@ func ConcatenateWithTransition(clip1, clip2 *Video, params TransitionParams) (*V
 	if clip1 == nil || clip2 == nil {
 		return nil, fmt.Errorf("ConcatenateWithTransition: both clips must be non-nil")
 	}
+	clip1, err := clip1.FlattenOverlays()
+	if err != nil {
+		return nil, fmt.Errorf("ConcatenateWithTransition: %w", err)
+	}
+	clip2, err = clip2.FlattenOverlays()
+	if err != nil {
+		return nil, fmt.Errorf("ConcatenateWithTransition: %w", err)
+	}
 	if params.Duration <= 0 {
 		return nil, fmt.Errorf("ConcatenateWithTransition: duration must be positive (duration=%.4f)", params.Duration)
 	}
@@ -49,9 +110,15 @@ func ConcatenateWithTransition(clip1, clip2 *Video, params TransitionParams) (*V
 	if params.Duration >= clip2.GetDuration() {
 		return nil, fmt.Errorf("ConcatenateWithTransition: duration %f must be less than clip2 duration %f", params.Duration, clip2.GetDuration())
 	}
+	if params.Sound != "" {
+		return NewTimeline().Add(clip1).AddWithTransition(clip2, params).Render()
+	}
 	if params.Transition == "" {
 		params.Transition = TransitionFade
 	}
+	if params.Transition == TransitionLumaWipe && params.Matte == "" {
+		return nil, fmt.Errorf("ConcatenateWithTransition: luma wipe requires a matte image")
+	}
 
 	initRawVideo(clip1)
 	initRawVideo(clip2)
@@ -84,18 +151,26 @@ func ConcatenateWithTransition(clip1, clip2 *Video, params TransitionParams) (*V
 
 	videoFilterComplex := append([]FilterComplex{}, clip1.filterComplex...)
 	videoFilterComplex = append(videoFilterComplex, clip2.filterComplex...)
-	audioFilterComplex := append([]FilterComplex{}, clip1.audio.filterComplex...)
-	audioFilterComplex = append(audioFilterComplex, clip2.audio.filterComplex...)
+	format := resolveAudioFormat(&clip1.audio, &clip2.audio)
+	audio1 := clip1.audio.normalized(format)
+	audio2 := clip2.audio.normalized(format)
+	audioFilterComplex := append([]FilterComplex{}, audio1.filterComplex...)
+	audioFilterComplex = append(audioFilterComplex, audio2.filterComplex...)
 
 	order := incrementOrderCounter()
 	label := fmt.Sprintf("xfade_%d", incrementGlobalCounter())
 
-	xfadeFilter := fmt.Sprintf("[%s][%s]xfade=transition=%s:duration=%.4f:offset=%.4f[%s]",
-		clip1.lastVideoLabel(), clip2.lastVideoLabel(),
-		string(params.Transition), params.Duration, offset,
-		label+"_v")
+	var xfadeFilter string
+	if params.Transition == TransitionLumaWipe {
+		xfadeFilter = buildLumaWipeFilter(clip1, clip2, params, offset, label)
+	} else {
+		xfadeFilter = fmt.Sprintf("[%s][%s]xfade=transition=%s:duration=%.4f:offset=%.4f[%s]",
+			clip1.lastVideoLabel(), clip2.lastVideoLabel(),
+			string(params.Transition), params.Duration, offset,
+			label+"_v")
+	}
 	acrossfadeFilter := fmt.Sprintf("[%s][%s]acrossfade=d=%.4f:c1=tri:c2=tri[%s]",
-		clip1.audio.lastAudioLabel(), clip2.audio.lastAudioLabel(),
+		audio1.lastAudioLabel(), audio2.lastAudioLabel(),
 		params.Duration, label+"_a")
 
 	videoFilterComplex = append(videoFilterComplex, FilterComplex{
@@ -111,7 +186,7 @@ func ConcatenateWithTransition(clip1, clip2 *Video, params TransitionParams) (*V
 
 	newDuration := clip1.GetDuration() + clip2.GetDuration() - params.Duration
 
-	newAudio := clip1.audio
+	newAudio := audio1
 	newAudio.filterComplex = audioFilterComplex
 	newAudio.duration = newDuration
 
@@ -133,5 +208,36 @@ func ConcatenateWithTransition(clip1, clip2 *Video, params TransitionParams) (*V
 		preset:             clip1.preset,
 		withMask:           clip1.withMask,
 		pixelFormat:        clip1.pixelFormat,
+		audioRemoved:       clip1.audioRemoved && clip2.audioRemoved,
 	}, nil
 }
+
+// buildLumaWipeFilter builds the custom graph for TransitionLumaWipe. Both clips
+// are split at the overlap; the matte is thresholded by transition progress and
+// used as the alpha of clip2's head, which is overlaid on clip1's tail:
+//
+//	clip1 -> [head][tail]     clip2 -> [in][rest]
+//	matte -> threshold(t/d) -> alpha of [in] -> overlay on [tail] -> [mix]
+//	[head][mix][rest]concat
+func buildLumaWipeFilter(clip1, clip2 *Video, params TransitionParams, offset float64, label string) string {
+	fps := clip1.fps
+	if fps == 0 {
+		fps = 30
+	}
+	d := params.Duration
+	l := func(name string) string { return label + "_" + name }
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s]split[%s][%s];", clip1.lastVideoLabel(), l("a1"), l("a2"))
+	fmt.Fprintf(&b, "[%s]trim=end=%.4f,setpts=PTS-STARTPTS[%s];", l("a1"), offset, l("head"))
+	fmt.Fprintf(&b, "[%s]trim=start=%.4f,setpts=PTS-STARTPTS[%s];", l("a2"), offset, l("tail"))
+	fmt.Fprintf(&b, "[%s]split[%s][%s];", clip2.lastVideoLabel(), l("b1"), l("b2"))
+	fmt.Fprintf(&b, "[%s]trim=end=%.4f,setpts=PTS-STARTPTS[%s];", l("b1"), d, l("in"))
+	fmt.Fprintf(&b, "[%s]trim=start=%.4f,setpts=PTS-STARTPTS[%s];", l("b2"), d, l("rest"))
+	fmt.Fprintf(&b, "movie=%s:loop=0,setpts=N/(%d*TB),trim=duration=%.4f,scale=%d:%d,format=gray,geq=lum='if(lte(lum(X,Y),255*T/%.4f),255,0)'[%s];",
+		filterPath(params.Matte), fps, d, clip1.width, clip1.height, d, l("mask"))
+	fmt.Fprintf(&b, "[%s][%s]alphamerge[%s];", l("in"), l("mask"), l("in_alpha"))
+	fmt.Fprintf(&b, "[%s][%s]overlay=eof_action=pass[%s];", l("tail"), l("in_alpha"), l("mix"))
+	fmt.Fprintf(&b, "[%s][%s][%s]concat=n=3:v=1:a=0[%s]", l("head"), l("mix"), l("rest"), l("v"))
+	return b.String()
+}
@@ -0,0 +1,66 @@
+package moviego
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Tune describes the content being encoded, so the encoder can be tuned for
+// it. It maps to each encoder's own tuning (x264/x265 -tune, NVENC tuning
+// info) and to a suitable GOP and B-frame setup.
+type Tune string
+
+const (
+	TuneFilm       Tune = "film"        // live action
+	TuneAnimation  Tune = "animation"   // cartoons, flat colors and sharp edges
+	TuneScreen     Tune = "screen"      // screen recordings, slides: mostly static, text
+	TuneLowLatency Tune = "low-latency" // live streaming: no B-frames, short GOP
+)
+
+// tuneArgs returns the encoder arguments for t. Options set through
+// EncoderOptions are placed after them and take precedence.
+func tuneArgs(t Tune, encoder string, fps uint64) ([]string, error) {
+	if fps == 0 {
+		fps = defaultClipFps
+	}
+	var args []string
+	switch t {
+	case "":
+		return nil, nil
+	case TuneFilm:
+		switch encoder {
+		case "libx264":
+			args = append(args, "-tune", "film")
+		case "h264_nvenc", "hevc_nvenc", "av1_nvenc":
+			args = append(args, "-tune", "hq")
+		}
+	case TuneAnimation:
+		switch encoder {
+		case "libx264", "libx265":
+			args = append(args, "-tune", "animation")
+		case "h264_nvenc", "hevc_nvenc", "av1_nvenc":
+			args = append(args, "-tune", "hq")
+		}
+	case TuneScreen:
+		switch encoder {
+		case "libx264":
+			args = append(args, "-tune", "stillimage")
+		case "h264_nvenc", "hevc_nvenc", "av1_nvenc":
+			args = append(args, "-tune", "hq")
+		}
+		// Mostly static content: long GOPs save keyframes for the changes.
+		args = append(args, "-g", strconv.FormatUint(fps*10, 10))
+	case TuneLowLatency:
+		switch encoder {
+		case "libx264", "libx265":
+			args = append(args, "-tune", "zerolatency")
+		case "h264_nvenc", "hevc_nvenc", "av1_nvenc":
+			args = append(args, "-tune", "ll", "-zerolatency", "1")
+		}
+		// Viewers can join (and recover) within a second.
+		args = append(args, "-bf", "0", "-g", strconv.FormatUint(fps, 10))
+	default:
+		return nil, fmt.Errorf("unknown tune %q (valid=film, animation, screen, low-latency)", t)
+	}
+	return args, nil
+}
@@ -0,0 +1,213 @@
+package moviego
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// setterError is an invalid value passed to a setter. It is kept until the
+// same setter is called again with a valid value.
+type setterError struct {
+	setter string
+	err    error
+}
+
+// reject records an invalid value passed to setter, replacing an earlier
+// error of the same setter. Setters keep their chainable signature; the
+// error is reported by Validate and by WriteVideo before FFmpeg runs.
+func (v *Video) reject(setter, format string, args ...any) {
+	err := fmt.Errorf("%s: %s", setter, fmt.Sprintf(format, args...))
+	v.invalid = append(v.withoutInvalid(setter), setterError{setter: setter, err: err})
+}
+
+// accept clears the error recorded by an earlier invalid call of setter.
+func (v *Video) accept(setter string) {
+	v.invalid = v.withoutInvalid(setter)
+}
+
+// withoutInvalid returns a copy of the recorded errors without those of
+// setter; derived videos may share the slice, so it is never modified.
+func (v *Video) withoutInvalid(setter string) []setterError {
+	var out []setterError
+	for _, e := range v.invalid {
+		if e.setter != setter {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Validate reports every invalid value given to the video's setters and any
+// problem that would make the render fail, so mistakes surface before
+// WriteVideo runs FFmpeg.
+func (v *Video) Validate() error {
+	var errs []error
+	for _, e := range v.invalid {
+		errs = append(errs, e.err)
+	}
+	if len(v.filenames) == 0 && len(v.filterComplex) == 0 {
+		errs = append(errs, fmt.Errorf("video has no input (file=<none>)"))
+	}
+	return errors.Join(errs...)
+}
+
+// Validate checks the text clip before it is turned into a drawtext filter:
+// content, font file, size, opacity and timing.
+func (tc TextClip) Validate() error {
+	var errs []error
+	if tc.Text == "" && tc.TextFile == "" {
+		errs = append(errs, fmt.Errorf("Text or TextFile is required"))
+	}
+	if tc.TextFile != "" {
+		if _, err := os.Stat(tc.TextFile); err != nil {
+			errs = append(errs, fmt.Errorf("TextFile '%s' is not readable: %w", tc.TextFile, err))
+		}
+	}
+	if isFontFile(tc.FontFamily) {
+		if _, err := os.Stat(tc.FontFamily); err != nil {
+			errs = append(errs, fmt.Errorf("font file '%s' is not readable: %w", tc.FontFamily, err))
+		}
+	}
+	if tc.FontSize < 0 {
+		errs = append(errs, fmt.Errorf("FontSize must be >= 0 (got=%d)", tc.FontSize))
+	}
+	if tc.Opacity < 0 || tc.Opacity > 1 {
+		errs = append(errs, fmt.Errorf("Opacity must be between 0 and 1 (got=%.4f)", tc.Opacity))
+	}
+	if tc.StartTime < 0 {
+		errs = append(errs, fmt.Errorf("StartTime must be >= 0 (got=%.4f)", tc.StartTime))
+	}
+	if tc.EndTime != 0 && tc.EndTime <= tc.StartTime {
+		errs = append(errs, fmt.Errorf("EndTime must be after StartTime (start=%.4f, end=%.4f)", tc.StartTime, tc.EndTime))
+	}
+	colors := []struct {
+		name  string
+		color Color
+	}{
+		{"FontColor", tc.FontColor},
+		{"Background.Color", tc.Background.Color},
+		{"Stroke.Color", tc.Stroke.Color},
+		{"Shadow.Color", tc.Shadow.Color},
+	}
+	for _, c := range colors {
+		if c.color == "" {
+			continue
+		}
+		if err := c.color.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", c.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Validate checks the image clip's file, size and duration.
+func (ic *ImageClip) Validate() error {
+	if ic.filename == "" {
+		return fmt.Errorf("ImageClip: filename cannot be empty")
+	}
+	if ic.width == 0 || ic.height == 0 || ic.duration <= 0 {
+		return fmt.Errorf("ImageClip: invalid size or duration (%dx%d, duration=%.4f, file=%s)", ic.width, ic.height, ic.duration, ic.filename)
+	}
+	return nil
+}
+
+// Validate checks the HTML clip's document, size and duration.
+func (hc *HTMLClip) Validate() error {
+	if hc.html == "" {
+		return fmt.Errorf("HTMLClip: html cannot be empty")
+	}
+	if hc.width == 0 || hc.height == 0 || hc.duration <= 0 {
+		return fmt.Errorf("HTMLClip: invalid size or duration (%dx%d, duration=%.4f)", hc.width, hc.height, hc.duration)
+	}
+	return nil
+}
+
+// Validate checks the Lottie clip's file, renderer, size and duration.
+func (lc *LottieClip) Validate() error {
+	if lc.filename == "" {
+		return fmt.Errorf("LottieClip: filename cannot be empty")
+	}
+	if lc.renderer == nil {
+		return fmt.Errorf("LottieClip: no renderer set (file=%s)", lc.filename)
+	}
+	if lc.width == 0 || lc.height == 0 || lc.duration <= 0 {
+		return fmt.Errorf("LottieClip: invalid size or duration (%dx%d, duration=%.4f, file=%s)", lc.width, lc.height, lc.duration, lc.filename)
+	}
+	return nil
+}
+
+// Validate checks the SVG clip's file, size, duration and scale animation.
+func (sc *SVGClip) Validate() error {
+	if sc.filename == "" {
+		return fmt.Errorf("SVGClip: filename cannot be empty")
+	}
+	if sc.width == 0 || sc.height == 0 || sc.duration <= 0 {
+		return fmt.Errorf("SVGClip: invalid size or duration (%dx%d, duration=%.4f, file=%s)", sc.width, sc.height, sc.duration, sc.filename)
+	}
+	if a := sc.animatedScale; a != nil && (a.Start <= 0 || a.End <= 0) {
+		return fmt.Errorf("SVGClip: scale start and end must be positive (start=%.4f, end=%.4f, file=%s)", a.Start, a.End, sc.filename)
+	}
+	return nil
+}
+
+// Validate checks the sticker's file, size and time range.
+func (s *Sticker) Validate() error {
+	if s.filename == "" {
+		return fmt.Errorf("Sticker: filename cannot be empty")
+	}
+	if s.width == 0 || s.height == 0 {
+		return fmt.Errorf("Sticker: invalid size (%dx%d, file=%s)", s.width, s.height, s.filename)
+	}
+	if s.start < 0 || s.end <= s.start {
+		return fmt.Errorf("Sticker: invalid time range (start=%.4f, end=%.4f, file=%s)", s.start, s.end, s.filename)
+	}
+	return nil
+}
+
+// Validate checks the color clip's color, size and duration.
+func (cc *ColorClip) Validate() error {
+	if cc.color == "" {
+		return fmt.Errorf("ColorClip: color cannot be empty")
+	}
+	if err := cc.color.Validate(); err != nil {
+		return fmt.Errorf("ColorClip: %w", err)
+	}
+	if cc.width == 0 || cc.height == 0 || cc.duration <= 0 {
+		return fmt.Errorf("ColorClip: invalid size or duration (%dx%d, duration=%.4f, color=%s)", cc.width, cc.height, cc.duration, cc.color)
+	}
+	return nil
+}
+
+// Validate checks the title clip's background, size, duration and text.
+func (tc *TitleClip) Validate() error {
+	if err := tc.background.Validate(); err != nil {
+		return fmt.Errorf("TitleClip: %w", err)
+	}
+	if tc.width == 0 || tc.height == 0 || tc.duration <= 0 {
+		return fmt.Errorf("TitleClip: invalid size or duration (%dx%d, duration=%.4f)", tc.width, tc.height, tc.duration)
+	}
+	if err := tc.text.Validate(); err != nil {
+		return fmt.Errorf("TitleClip: %w", err)
+	}
+	return nil
+}
+
+// Validate checks the waveform clip's audio, size, duration, color and mode.
+func (wc *WaveformClip) Validate() error {
+	if len(wc.audio.filenames) == 0 && len(wc.audio.filterComplex) == 0 {
+		return fmt.Errorf("WaveformClip: audio has no input")
+	}
+	if wc.width == 0 || wc.height == 0 || wc.duration <= 0 {
+		return fmt.Errorf("WaveformClip: invalid size or duration (%dx%d, duration=%.4f, file=%s)", wc.width, wc.height, wc.duration, safeFirstFilename(wc.audio.filenames))
+	}
+	if err := wc.GetColor().Validate(); err != nil {
+		return fmt.Errorf("WaveformClip: %w", err)
+	}
+	switch wc.GetMode() {
+	case WaveformCenteredLine, WaveformLine, WaveformPoint, WaveformPointToPoint:
+	default:
+		return fmt.Errorf("WaveformClip: unknown mode (got=%s)", wc.mode)
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package moviego
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// VariantDimension is a property that takes several values across variants,
+// e.g. the title text, the thumbnail frame or the call-to-action color.
+type VariantDimension struct {
+	Name   string
+	Values []string
+}
+
+// VariantSet lists the variants to render: every combination of the
+// dimensions, or only the combinations in List when it is set.
+type VariantSet struct {
+	Dimensions []VariantDimension
+	List       []map[string]string // explicit combinations, keyed by dimension name
+	// ThumbnailAt names a dimension whose values are times (see ParseTime);
+	// a JPEG still of that frame is written next to each output.
+	ThumbnailAt string
+}
+
+// Variant is one combination of dimension values.
+type Variant struct {
+	Index  int
+	Values map[string]string
+	name   string
+}
+
+// Get returns the value of the dimension name.
+func (v Variant) Get(name string) string {
+	return v.Values[name]
+}
+
+// Name returns the suffix naming the variant's outputs, e.g. "title-sale.cta-red".
+func (v Variant) Name() string {
+	return v.name
+}
+
+// VariantOutput is a rendered variant.
+type VariantOutput struct {
+	Variant   Variant
+	Path      string
+	Thumbnail string // empty unless VariantSet.ThumbnailAt is set
+}
+
+// variants expands the set in dimension order.
+func (s VariantSet) variants() ([]Variant, error) {
+	if len(s.Dimensions) == 0 {
+		return nil, fmt.Errorf("no variant dimensions")
+	}
+	slugs := make(map[string]map[string]string, len(s.Dimensions))
+	for _, d := range s.Dimensions {
+		if d.Name == "" || len(d.Values) == 0 {
+			return nil, fmt.Errorf("dimension %q needs a name and at least one value", d.Name)
+		}
+		if slugs[d.Name] != nil {
+			return nil, fmt.Errorf("dimension %q declared twice", d.Name)
+		}
+		slugs[d.Name] = variantSlugs(d.Values)
+	}
+	if s.ThumbnailAt != "" && slugs[s.ThumbnailAt] == nil {
+		return nil, fmt.Errorf("thumbnail dimension %q is not declared", s.ThumbnailAt)
+	}
+
+	combos := s.List
+	if len(combos) == 0 {
+		combos = []map[string]string{{}}
+		for _, d := range s.Dimensions {
+			next := make([]map[string]string, 0, len(combos)*len(d.Values))
+			for _, c := range combos {
+				for _, value := range d.Values {
+					combo := make(map[string]string, len(c)+1)
+					for k, v := range c {
+						combo[k] = v
+					}
+					combo[d.Name] = value
+					next = append(next, combo)
+				}
+			}
+			combos = next
+		}
+	}
+
+	variants := make([]Variant, len(combos))
+	seen := make(map[string]bool, len(combos))
+	for i, combo := range combos {
+		parts := make([]string, 0, len(s.Dimensions))
+		for _, d := range s.Dimensions {
+			value, ok := combo[d.Name]
+			if !ok {
+				return nil, fmt.Errorf("variant %d has no value for %q", i, d.Name)
+			}
+			slug, ok := slugs[d.Name][value]
+			if !ok {
+				return nil, fmt.Errorf("variant %d: %q is not a value of %q", i, value, d.Name)
+			}
+			parts = append(parts, variantSlug(d.Name)+"-"+slug)
+		}
+		if len(combo) != len(s.Dimensions) {
+			return nil, fmt.Errorf("variant %d sets an undeclared dimension", i)
+		}
+		name := strings.Join(parts, ".")
+		if seen[name] {
+			return nil, fmt.Errorf("variant %d repeats %s", i, name)
+		}
+		seen[name] = true
+		variants[i] = Variant{Index: i, Values: combo, name: name}
+	}
+	return variants, nil
+}
+
+// variantSlugs names each value for file names, falling back to its
+// position when two values would get the same name.
+func variantSlugs(values []string) map[string]string {
+	slugs := make(map[string]string, len(values))
+	used := make(map[string]bool, len(values))
+	for i, value := range values {
+		slug := variantSlug(value)
+		if slug == "" || used[slug] {
+			slug = fmt.Sprintf("%d", i+1)
+		}
+		used[slug] = true
+		slugs[value] = slug
+	}
+	return slugs
+}
+
+// variantSlug lowercases s and keeps letters and digits, at most 24 of them.
+func variantSlug(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+		if b.Len() >= 24 {
+			break
+		}
+	}
+	return b.String()
+}
+
+// RenderVariants builds and writes one output per variant of set, for A/B
+// tests of titles, thumbnails and calls to action. build returns the video
+// of a variant, typically by applying its values to a shared edit. Outputs
+// are named after parms.OutputPath with the variant name before the
+// extension: promo.mp4 -> promo.title-sale.cta-red.mp4.
+func RenderVariants(set VariantSet, build func(Variant) (*Video, error), parms VideoParameters) ([]VariantOutput, error) {
+	if build == nil {
+		return nil, fmt.Errorf("RenderVariants: build function is nil")
+	}
+	if parms.OutputPath == "" {
+		return nil, fmt.Errorf("RenderVariants: output path is empty")
+	}
+	variants, err := set.variants()
+	if err != nil {
+		return nil, fmt.Errorf("RenderVariants: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(parms.OutputPath), 0755); err != nil {
+		return nil, fmt.Errorf("RenderVariants: %w", err)
+	}
+
+	ext := filepath.Ext(parms.OutputPath)
+	base := strings.TrimSuffix(parms.OutputPath, ext)
+	outputs := make([]VariantOutput, 0, len(variants))
+	for _, variant := range variants {
+		video, err := build(variant)
+		if err != nil {
+			return nil, fmt.Errorf("RenderVariants: variant %s: %w", variant.name, err)
+		}
+		out := VariantOutput{Variant: variant, Path: base + "." + variant.name + ext}
+		vp := parms
+		vp.OutputPath = out.Path
+		if err := video.WriteVideo(vp); err != nil {
+			return nil, fmt.Errorf("RenderVariants: variant %s: %w", variant.name, err)
+		}
+		if set.ThumbnailAt != "" {
+			at, err := ParseTime(variant.Get(set.ThumbnailAt))
+			if err != nil {
+				return nil, fmt.Errorf("RenderVariants: variant %s: thumbnail: %w", variant.name, err)
+			}
+			out.Thumbnail = base + "." + variant.name + ".jpg"
+			if err := video.renderStill("RenderVariants", at.Seconds(), out.Thumbnail, parms.Env); err != nil {
+				return nil, err
+			}
+		}
+		outputs = append(outputs, out)
+	}
+	return outputs, nil
+}
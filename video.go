@@ -2,7 +2,6 @@ package moviego
 
 import (
 	"fmt"
-	"runtime"
 )
 
 var globalLabelCounter uint64
@@ -11,6 +10,8 @@ var globalOrderCounter uint64
 type FileCopy struct {
 	Filename string
 	Label    string
+	// InputArgs are placed before the file's -i, e.g. -stream_loop -1.
+	InputArgs []string
 }
 
 type FilterComplex struct {
@@ -29,12 +30,27 @@ type Position struct {
 
 // CenterPosition returns a position that centers the overlay on the background.
 func CenterPosition() Position {
-	return Position{X: "(W-w)/2", Y: "(H-h)/2"}
+	return PointAt(50, 50, AnchorCenter).Position()
 }
 
 // TopLeftPosition returns a position at the top-left corner.
 func TopLeftPosition() Position {
-	return Position{X: "0", Y: "0"}
+	return PointAt(0, 0, AnchorTopLeft).Position()
+}
+
+// TopRightPosition returns a position at the top-right corner.
+func TopRightPosition() Position {
+	return PointAt(100, 0, AnchorTopRight).Position()
+}
+
+// BottomLeftPosition returns a position at the bottom-left corner.
+func BottomLeftPosition() Position {
+	return PointAt(0, 100, AnchorBottomLeft).Position()
+}
+
+// BottomRightPosition returns a position at the bottom-right corner.
+func BottomRightPosition() Position {
+	return PointAt(100, 100, AnchorBottomRight).Position()
 }
 
 // Video represents a video file with its properties and processing options
@@ -52,14 +68,21 @@ type Video struct {
 	isTemp             bool
 	audio              Audio
 	bitRate            string
-	preset             preset
+	preset             Preset
 	withMask           bool
 	pixelFormat        PixelFormat
 	startTime          float64
 	endTime            float64
 	position           Position
+	compositeStart     float64
 	animatedPosition   *AnimatedPosition // nil = use static position
 	animatedOpacity    *Animation         // nil = fully opaque
+	trim               *directTrim        // set by Cut while the video is a plain read of one file
+	invalid            []setterError      // invalid values passed to setters, see Validate
+	overlays           []Overlay          // pending overlays, see AddOverlay
+	startTimecode      string             // SMPTE timecode of the first frame, see SetStartTimecode
+	audioRemoved       bool               // the video is written without audio, see RemoveAudio
+	layers             []compositeLayer   // composite layers, see At
 }
 
 // ============================================================================
@@ -101,6 +124,11 @@ func (v *Video) GetCodec() string {
 
 // Width sets the video width
 func (v *Video) Width(width uint64) *Video {
+	if width == 0 {
+		v.reject("Width", "must be > 0 (got=%d, file=%s)", width, safeFirstFilename(v.filenames))
+	} else {
+		v.accept("Width")
+	}
 	v.width = width
 	return v
 }
@@ -112,6 +140,11 @@ func (v *Video) GetWidth() uint64 {
 
 // Height sets the video height
 func (v *Video) Height(height uint64) *Video {
+	if height == 0 {
+		v.reject("Height", "must be > 0 (got=%d, file=%s)", height, safeFirstFilename(v.filenames))
+	} else {
+		v.accept("Height")
+	}
 	v.height = height
 	return v
 }
@@ -123,6 +156,11 @@ func (v *Video) GetHeight() uint64 {
 
 // Duration sets the video duration
 func (v *Video) Duration(duration float64) *Video {
+	if duration <= 0 {
+		v.reject("Duration", "must be > 0 (got=%.4f, file=%s)", duration, safeFirstFilename(v.filenames))
+	} else {
+		v.accept("Duration")
+	}
 	v.duration = duration
 	return v
 }
@@ -134,6 +172,11 @@ func (v *Video) GetDuration() float64 {
 
 // SetFps sets the video frames per second
 func (v *Video) SetFps(fps uint64) *Video {
+	if fps == 0 {
+		v.reject("SetFps", "must be > 0 (got=%d, file=%s)", fps, safeFirstFilename(v.filenames))
+	} else {
+		v.accept("SetFps")
+	}
 	v.fps = fps
 	return v
 }
@@ -155,13 +198,18 @@ func (v *Video) GetBitRate() string {
 }
 
 // Preset sets the video preset
-func (v *Video) Preset(p preset) *Video {
+func (v *Video) Preset(p Preset) *Video {
+	if err := p.Validate(); err != nil {
+		v.reject("Preset", "%v", err)
+		return v
+	}
+	v.accept("Preset")
 	v.preset = p
 	return v
 }
 
 // GetPreset returns the video preset
-func (v *Video) GetPreset() preset {
+func (v *Video) GetPreset() Preset {
 	return v.preset
 }
 
@@ -189,6 +237,11 @@ func (v *Video) GetPixelFormat() PixelFormat {
 
 // SetStartTime sets the start time for subclip
 func (v *Video) SetStartTime(startTime float64) *Video {
+	if startTime < 0 {
+		v.reject("SetStartTime", "must be >= 0 (got=%.4f, file=%s)", startTime, safeFirstFilename(v.filenames))
+	} else {
+		v.accept("SetStartTime")
+	}
 	v.startTime = startTime
 	return v
 }
@@ -200,6 +253,11 @@ func (v *Video) GetStartTime() float64 {
 
 // SetEndTime sets the end time for subclip
 func (v *Video) SetEndTime(endTime float64) *Video {
+	if endTime < v.startTime {
+		v.reject("SetEndTime", "must be >= start time (got=%.4f, start=%.4f, file=%s)", endTime, v.startTime, safeFirstFilename(v.filenames))
+	} else {
+		v.accept("SetEndTime")
+	}
 	v.endTime = endTime
 	return v
 }
@@ -224,6 +282,23 @@ func (v *Video) GetPosition() Position {
 	return v.position
 }
 
+// SetCompositeStart sets when the video appears in a CompositeClip, in seconds
+// from the start of the composite. The background layer must start at 0.
+func (v *Video) SetCompositeStart(start float64) *Video {
+	if start < 0 {
+		v.reject("SetCompositeStart", "must be >= 0 (got=%.4f, file=%s)", start, safeFirstFilename(v.filenames))
+	} else {
+		v.accept("SetCompositeStart")
+	}
+	v.compositeStart = start
+	return v
+}
+
+// GetCompositeStart returns when the video appears in a CompositeClip.
+func (v *Video) GetCompositeStart() float64 {
+	return v.compositeStart
+}
+
 // SetAnimatedPosition sets the overlay position animation for CompositeClip.
 func (v *Video) SetAnimatedPosition(ap AnimatedPosition) *Video {
 	v.animatedPosition = &ap
@@ -266,8 +341,13 @@ func (v *Video) GetAudio() *Audio {
 	return &v.audio
 }
 
-
+// WriteAudio extracts the audio of the video, with all its audio filters, to
+// an audio-only file such as .mp3, .m4a, .aac, .wav or .flac. The codec
+// defaults to the usual one for the extension (see AudioParameters.Codec).
 func (v *Video) WriteAudio(parms AudioParameters) error {
+	if v.audioRemoved || !v.HasAudio() && len(v.audio.filterComplex) == 0 {
+		return fmt.Errorf("WriteAudio: video has no audio stream (file=%s, label=%s)", safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
 	return v.audio.Write(parms)
 }
 
@@ -288,7 +368,7 @@ func (v *Video) GetIsTemp() bool {
 
 // HasAudio returns whether the video has an audio stream
 func (v *Video) HasAudio() bool {
-	return v.audio.codec != ""
+	return !v.audioRemoved && v.audio.codec != ""
 }
 
 // ============================================================================
@@ -319,17 +399,7 @@ func (v *Video) applyParameters(parms VideoParameters) *Video {
 		v.BitRate(parms.Bitrate)
 	}
 	if parms.Threads == 0 {
-		// Optimize thread allocation: reserve CPUs for Go workers
-		// FFmpeg gets 60% of CPUs, Go workers use 40%
-		totalCPUs := runtime.GOMAXPROCS(0)
-		if totalCPUs <= 2 {
-			parms.Threads = uint16(totalCPUs)
-		} else {
-			parms.Threads = uint16((totalCPUs * 6) / 10)
-			if parms.Threads < 2 {
-				parms.Threads = 2
-			}
-		}
+		parms.Threads = defaultThreads()
 	}
 
 	return v
@@ -345,5 +415,8 @@ func (v *Video) nextLabel(filename string) string {
 
 
 func (v *Video) lastFilename() string {
+	if len(v.filenames) == 0 {
+		return ""
+	}
 	return v.filenames[len(v.filenames)-1]
 }
@@ -0,0 +1,98 @@
+package moviego
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// DefaultVideoParameters returns the settings WriteVideo falls back to when
+// neither the parameters nor the video set them: H.264 (libx264) at the
+// medium preset in yuv420p, with the default thread count. Fps, Bitrate and
+// the audio settings are left empty so they keep following the source.
+//
+// Parameters take precedence over the video's own setters (Codec, Preset,
+// PixelFormat, ...), so starting from these defaults overrides them too.
+func DefaultVideoParameters() VideoParameters {
+	return VideoParameters{
+		Threads:            defaultThreads(),
+		Codec:              CodecH264,
+		Preset:             Medium,
+		PixelFormat:        PixelFormatYUV420P,
+		WorkingPixelFormat: PixelFormatYUVA420P,
+	}
+}
+
+// QualityProfile names a set of encoding parameters trading render time
+// for quality, see Parameters.
+type QualityProfile string
+
+const (
+	// ProfileDraft renders fast previews: ultrafast, CRF 28, 96 kbit/s audio.
+	ProfileDraft QualityProfile = "draft"
+	// ProfileStandard is the default quality for publishing: medium, CRF 23,
+	// 128 kbit/s audio.
+	ProfileStandard QualityProfile = "standard"
+	// ProfileHighQuality is for final masters viewed at full size: slow,
+	// CRF 18, 192 kbit/s audio.
+	ProfileHighQuality QualityProfile = "high_quality"
+	// ProfileArchive keeps as much detail as a lossy encode can: veryslow,
+	// CRF 12, 320 kbit/s audio.
+	ProfileArchive QualityProfile = "archive"
+)
+
+// Parameters returns the profile's parameters, built on
+// DefaultVideoParameters. The CRF applies to libx264 and libx265; set a
+// codec with Merge to encode with another encoder at the profile's preset.
+// Set OutputPath and any other field with Merge:
+//
+//	parms, err := moviego.ProfileDraft.Parameters()
+//	parms = parms.Merge(moviego.VideoParameters{OutputPath: "preview.mp4"})
+func (q QualityProfile) Parameters() (VideoParameters, error) {
+	var preset Preset
+	var crf float64
+	var audioBitrate string
+	switch q {
+	case ProfileDraft:
+		preset, crf, audioBitrate = UltraFast, 28, "96k"
+	case ProfileStandard:
+		preset, crf, audioBitrate = Medium, 23, "128k"
+	case ProfileHighQuality:
+		preset, crf, audioBitrate = Slow, 18, "192k"
+	case ProfileArchive:
+		preset, crf, audioBitrate = VerySlow, 12, "320k"
+	default:
+		return VideoParameters{}, fmt.Errorf("QualityProfile: unknown profile %q (valid=draft, standard, high_quality, archive)", string(q))
+	}
+	return DefaultVideoParameters().Merge(VideoParameters{
+		Preset:         preset,
+		AudioBitrate:   audioBitrate,
+		EncoderOptions: EncoderOptions{X264: &X264Options{CRF: crf}},
+	}), nil
+}
+
+// Merge returns p with every field that is set in override replaced by the
+// override's value. Nested structs such as EncoderOptions, Keyframes and
+// HLS are merged field by field; pointers, slices, maps and functions are
+// replaced as a whole. Zero values in override never clear a field of p, so
+// a bool set in p cannot be turned off by merging.
+func (p VideoParameters) Merge(override VideoParameters) VideoParameters {
+	out := p
+	mergeFields(reflect.ValueOf(&out).Elem(), reflect.ValueOf(override))
+	return out
+}
+
+// mergeFields copies the non-zero fields of src into dst, recursing into
+// struct fields.
+func mergeFields(dst, src reflect.Value) {
+	for i := 0; i < src.NumField(); i++ {
+		field := src.Field(i)
+		if field.IsZero() {
+			continue
+		}
+		if field.Kind() == reflect.Struct {
+			mergeFields(dst.Field(i), field)
+			continue
+		}
+		dst.Field(i).Set(field)
+	}
+}
@@ -0,0 +1,106 @@
+package moviego
+
+// VideoValue is an immutable view of a Video for chained editing:
+//
+//	clip2, err := moviego.Edit(clip).
+//		WithCut(0, 5).
+//		WithFilter(func(v *moviego.Video) (*moviego.Video, error) { return v.Saturation(1.2) }).
+//		WithText(moviego.TextClip{Text: "Hello"}).
+//		Video()
+//
+// Every With method returns a new VideoValue and never modifies the receiver
+// or the Video it was created from, so one value can be branched into several
+// edits safely. The first error stops the chain and is returned by Video.
+// The pointer-returning Video API is unchanged.
+type VideoValue struct {
+	v   Video
+	err error
+}
+
+// Edit starts an immutable edit of v. v itself is copied and never modified.
+func Edit(v *Video) VideoValue {
+	return VideoValue{v: v.clone()}
+}
+
+// clone copies v including its filter chains, so changes to either copy
+// (such as the label rewriting done by WriteVideo) never reach the other.
+func (v *Video) clone() Video {
+	out := *v
+	out.filenames = append([]string(nil), v.filenames...)
+	out.filterComplex = append([]FilterComplex(nil), v.filterComplex...)
+	out.audio.filenames = append([]string(nil), v.audio.filenames...)
+	out.audio.filterComplex = append([]FilterComplex(nil), v.audio.filterComplex...)
+	out.overlays = append([]Overlay(nil), v.overlays...)
+	return out
+}
+
+// apply runs op on a private copy of the value.
+func (vv VideoValue) apply(op func(v *Video) (*Video, error)) VideoValue {
+	if vv.err != nil {
+		return vv
+	}
+	in := vv.v.clone()
+	out, err := op(&in)
+	if err != nil {
+		return VideoValue{err: err}
+	}
+	return VideoValue{v: out.clone()}
+}
+
+// set runs a setter on a private copy of the value.
+func (vv VideoValue) set(op func(v *Video)) VideoValue {
+	return vv.apply(func(v *Video) (*Video, error) {
+		op(v)
+		return v, nil
+	})
+}
+
+// WithFilter applies any Video operation, e.g. a method value such as
+// func(v *Video) (*Video, error) { return v.Blur(2) }.
+func (vv VideoValue) WithFilter(op func(v *Video) (*Video, error)) VideoValue {
+	return vv.apply(op)
+}
+
+// WithCut keeps the range [start, end] in seconds, see Video.Cut.
+func (vv VideoValue) WithCut(start, end float64) VideoValue {
+	return vv.apply(func(v *Video) (*Video, error) { return v.Cut(start, end) })
+}
+
+// WithSpeed changes the playback speed, see Video.Speed.
+func (vv VideoValue) WithSpeed(speed float64, pitch ...float64) VideoValue {
+	return vv.apply(func(v *Video) (*Video, error) { return v.Speed(speed, pitch...) })
+}
+
+// WithText draws text on the video, see Video.AddText.
+func (vv VideoValue) WithText(text TextClip) VideoValue {
+	return vv.apply(func(v *Video) (*Video, error) { return v.AddText(text) })
+}
+
+// WithPosition sets the overlay position used by CompositeClip.
+func (vv VideoValue) WithPosition(position Position) VideoValue {
+	return vv.set(func(v *Video) { v.SetPosition(position) })
+}
+
+// WithCompositeStart sets when the video starts inside a composite.
+func (vv VideoValue) WithCompositeStart(start float64) VideoValue {
+	return vv.set(func(v *Video) { v.SetCompositeStart(start) })
+}
+
+// WithAudio replaces the audio track.
+func (vv VideoValue) WithAudio(audio Audio) VideoValue {
+	return vv.set(func(v *Video) { v.SetAudio(audio) })
+}
+
+// Err returns the first error of the chain, if any.
+func (vv VideoValue) Err() error {
+	return vv.err
+}
+
+// Video returns a new Video holding the result of the chain.
+func (vv VideoValue) Video() (*Video, error) {
+	if vv.err != nil {
+		return nil, vv.err
+	}
+	out := vv.v.clone()
+	return &out, nil
+}
@@ -1,18 +1,12 @@
 package moviego
 
 import (
-	"bufio"
-	"bytes"
 	"fmt"
 	"log/slog"
 	"math"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"runtime"
-	"strconv"
 	"strings"
-	"time"
 )
 
 func writeFilterComplex(b *strings.Builder, raw string) {
@@ -26,10 +20,10 @@ func writeFilterComplex(b *strings.Builder, raw string) {
 	}
 }
 
-// formatCmd formats an exec.Cmd into a readable multi-line string.
-// The -filter_complex value is split by ";" so each filter gets its own line.
-func formatCmd(cmd *exec.Cmd) string {
-	args := cmd.Args
+// formatCmd formats a command line (program first) into a readable
+// multi-line string. The -filter_complex value is split by ";" so each
+// filter gets its own line.
+func formatCmd(args []string) string {
 	if len(args) == 0 {
 		return ""
 	}
@@ -69,14 +63,94 @@ func formatCmd(cmd *exec.Cmd) string {
 	return b.String()
 }
 
-// WriteVideo processes the video with applied filters and writes to output file
+// WriteVideo processes the video with applied filters and writes to output file.
+// Audio-only outputs (.mp3, .m4a, .aac, .wav, .flac, .opus, .ogg) get only the
+// audio, encoded with the Audio* parameters; video settings are rejected.
 func (v *Video) WriteVideo(parms VideoParameters) error {
 	if parms.OutputPath == "" {
 		return fmt.Errorf("WriteVideo: output path is empty, cannot write video")
 	}
 
+	if err := v.Validate(); err != nil {
+		return fmt.Errorf("WriteVideo: invalid video: %w", err)
+	}
+	if err := parms.Preset.Validate(); err != nil {
+		return fmt.Errorf("WriteVideo: %w (file=%s, label=%s)", err, safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	if _, err := resolveWorkingPixelFormat(parms.WorkingPixelFormat); err != nil {
+		return fmt.Errorf("WriteVideo: %w (file=%s)", err, safeFirstFilename(v.filenames))
+	}
+	if len(v.overlays) > 0 {
+		flat, err := v.FlattenOverlays()
+		if err != nil {
+			return fmt.Errorf("WriteVideo: %w", err)
+		}
+		return flat.WriteVideo(parms)
+	}
+	if parms.OnAudioReport != nil && v.audioRemoved && len(parms.AudioTracks) == 0 {
+		return fmt.Errorf("WriteVideo: OnAudioReport requires an output with audio (file=%s, label=%s)", safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	if isAudioOnlyOutput(parms.OutputPath) {
+		return v.writeAudioOnly(parms)
+	}
+	if d := parms.Delivery; d != nil {
+		if parms.StreamCopy {
+			return fmt.Errorf("WriteVideo: StreamCopy cannot be combined with a delivery profile (file=%s)", safeFirstFilename(v.filenames))
+		}
+		if err := d.validate(parms.OutputPath); err != nil {
+			return fmt.Errorf("WriteVideo: %w (file=%s)", err, safeFirstFilename(v.filenames))
+		}
+		prepared, err := d.prepare(v)
+		if err != nil {
+			return fmt.Errorf("WriteVideo: delivery profile %q: %w (file=%s)", d.Name, err, safeFirstFilename(v.filenames))
+		}
+		v, parms = prepared, d.apply(parms)
+		if parms.NormalizeLoudness == nil {
+			parms.NormalizeLoudness = d.loudness()
+		}
+	}
+	if target := parms.NormalizeLoudness; target != nil && !v.audioRemoved {
+		if parms.StreamCopy {
+			return fmt.Errorf("WriteVideo: StreamCopy cannot be combined with loudness normalization (file=%s)", safeFirstFilename(v.filenames))
+		}
+		normalized, err := v.normalizeLoudness("WriteVideo", *target, parms.Env)
+		if err != nil {
+			return err
+		}
+		v = normalized
+	}
+	if parms.OnLevels != nil && !v.audioRemoved {
+		if parms.StreamCopy {
+			return fmt.Errorf("WriteVideo: StreamCopy cannot be combined with OnLevels (file=%s)", safeFirstFilename(v.filenames))
+		}
+		metered := v.clone()
+		initRawVideo(&metered)
+		metered.audio = metered.audio.chain(meterFilter)
+		v = &metered
+	}
+
+	var frameLog string
+	if parms.FrameMetadata {
+		if parms.StreamCopy {
+			return fmt.Errorf("WriteVideo: StreamCopy cannot be combined with FrameMetadata (file=%s)", safeFirstFilename(v.filenames))
+		}
+		f, err := os.CreateTemp(parms.Env.tempDir(), "moviego-frames-*.log")
+		if err != nil {
+			return fmt.Errorf("WriteVideo: %w", err)
+		}
+		f.Close()
+		frameLog = f.Name()
+		defer os.Remove(frameLog)
+		// Printed last, so the timestamps are those of the output.
+		logged, err := v.videoFilter(frameMetadataFilter(frameLog))
+		if err != nil {
+			return fmt.Errorf("WriteVideo: %w", err)
+		}
+		v = logged
+	}
+
 	// Validate essential video properties before processing
-	if len(v.GetFilenames()) == 0 {
+	if len(v.GetFilenames()) == 0 && len(v.filterComplex) == 0 {
 		return fmt.Errorf("WriteVideo: video filename is empty, cannot process video (file=<none>)")
 	}
 	if v.GetWidth() <= 0 || v.GetHeight() <= 0 {
@@ -97,9 +171,223 @@ func (v *Video) WriteVideo(parms VideoParameters) error {
 
 
 
+	var ffmpegArgs []string
+	start, end, direct := v.directSource()
+	if direct && (parms.AdaptiveThreads || parms.WorkingPixelFormat != "") {
+		if parms.StreamCopy {
+			return fmt.Errorf("WriteVideo: StreamCopy cannot be combined with AdaptiveThreads or WorkingPixelFormat (file=%s)", safeFirstFilename(v.filenames))
+		}
+		// Both options act on the filter graph, so the video goes through one.
+		graphed := v.clone()
+		if len(graphed.filterComplex) == 0 {
+			initRawVideo(&graphed)
+			graphed.audioRemoved = !v.HasAudio()
+		}
+		v, direct = &graphed, false
+	}
+	if direct {
+		// A plain read or Cut of one file needs no filter graph: FFmpeg seeks
+		// the input and encodes (or copies) it in a single pass.
+		ffmpegArgs = directArgs(v.filenames[0], start, end)
+		if v.audioRemoved {
+			ffmpegArgs = ffmpegArgs[:len(ffmpegArgs)-2]
+		}
+	} else {
+		if parms.StreamCopy {
+			return fmt.Errorf("WriteVideo: StreamCopy requires a video without filters other than Cut (file=%s, label=%s)", safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+		}
+		if ffmpegArgs, err = v.graphArgs(parms); err != nil {
+			return err
+		}
+	}
+	graphArgs := ffmpegArgs[:len(ffmpegArgs):len(ffmpegArgs)]
+	encoder := resolveVideoEncoder(parms.Codec, v.GetCodec())
+	if parms.StreamCopy {
+		encoder = "copy"
+	}
+	ffmpegArgs = append(ffmpegArgs, "-c:v", encoder)
+	if err := parms.Captions.validate(encoder, parms.OutputPath); err != nil {
+		return fmt.Errorf("WriteVideo: %w (file=%s)", err, safeFirstFilename(v.filenames))
+	}
+
+	// Threads (compute effective value - applyParameters modifies a copy)
+	effectiveThreads := parms.Threads
+	if effectiveThreads == 0 {
+		effectiveThreads = defaultThreads()
+	}
+	effectiveThreads = parms.Env.threads(effectiveThreads)
+	threadsIndex := len(ffmpegArgs) + 1
+	ffmpegArgs = append(ffmpegArgs, "-threads", fmt.Sprintf("%d", effectiveThreads))
+	encoding := SidecarEncoding{Encoder: encoder, Threads: effectiveThreads}
+
+	if parms.StreamCopy {
+		if parms.AudioCodec != "" || parms.AudioBitrate != "" || parms.AudioSampleRate > 0 || parms.AudioChannels > 0 || len(parms.AudioTracks) > 0 {
+			return fmt.Errorf("WriteVideo: StreamCopy cannot be combined with audio encoding options (file=%s)", safeFirstFilename(v.filenames))
+		}
+		ffmpegArgs = append(ffmpegArgs, "-c:a", "copy")
+	} else {
+		// FPS (if set)
+		if fps := resolveFps(parms.Fps, v.GetFps()); fps > 0 {
+			ffmpegArgs = append(ffmpegArgs, "-r", fmt.Sprintf("%d", fps))
+			encoding.Fps = fps
+		}
+
+		// Bitrate (if set)
+		if br := resolveBitrate(parms.Bitrate, v.GetBitRate()); br != "" {
+			ffmpegArgs = append(ffmpegArgs, "-b:v", br)
+			encoding.Bitrate = br
+		}
+
+		// Preset (codec-specific, only for encoders that support it)
+		presetStr := resolvePreset(parms.Preset, v.GetPreset())
+		mappedPreset := mapPresetForCodec(encoder, presetStr)
+		if mappedPreset != "" {
+			ffmpegArgs = append(ffmpegArgs, "-preset", mappedPreset)
+			encoding.Preset = mappedPreset
+		}
+
+		tune, err := tuneArgs(parms.Tune, encoder, resolveFps(parms.Fps, v.GetFps()))
+		if err != nil {
+			return fmt.Errorf("WriteVideo: %w (file=%s)", err, safeFirstFilename(v.filenames))
+		}
+		ffmpegArgs = append(ffmpegArgs, tune...)
+		encoding.Tune = parms.Tune
+		encoderArgs, err := parms.Keyframes.sceneCutOptions(parms.EncoderOptions, encoder).args(encoder)
+		if err != nil {
+			return fmt.Errorf("WriteVideo: %w (file=%s)", err, safeFirstFilename(v.filenames))
+		}
+		ffmpegArgs = append(ffmpegArgs, encoderArgs...)
+		if parms.Delivery != nil {
+			ffmpegArgs = append(ffmpegArgs, parms.Delivery.VideoArgs...)
+			encoding.FieldOrder = parms.Delivery.FieldOrder
+		}
+		// Last, so explicit keyframe settings override the GOP of a tune or
+		// delivery profile.
+		keyframes, err := keyframeArgs(parms.Keyframes, encoder, resolveFps(parms.Fps, v.GetFps()))
+		if err != nil {
+			return fmt.Errorf("WriteVideo: %w (file=%s)", err, safeFirstFilename(v.filenames))
+		}
+		ffmpegArgs = append(ffmpegArgs, keyframes...)
+
+		// Pixel format (default to yuv420p to strip alpha from internal YUVA pipeline)
+		pf := resolvePixelFormat(parms.PixelFormat, v.GetPixelFormat())
+		if pf == "" {
+			pf = PixelFormatYUV420P
+		}
+		ffmpegArgs = append(ffmpegArgs, "-pix_fmt", string(pf))
+		encoding.PixelFormat = pf
+
+		audioArgs, audioEncoder, err := audioEncoderArgs(parms, parms.OutputPath)
+		if err != nil {
+			return fmt.Errorf("WriteVideo: %w (file=%s)", err, safeFirstFilename(v.filenames))
+		}
+		ffmpegArgs = append(ffmpegArgs, audioArgs...)
+		encoding.AudioEncoder = audioEncoder
+		encoding.AudioBitrate = parms.AudioBitrate
+	}
+
+	if parms.AdaptiveThreads && !direct && effectiveThreads > 1 {
+		profile, err := profileStages(parms.Env, ffmpegPath, graphArgs, ffmpegArgs)
+		if err != nil {
+			slog.Warn("Adaptive threads: profiling failed, keeping default split", "error", err)
+		} else {
+			filterThreads, encoderThreads := profile.split(effectiveThreads)
+			slog.Info("Adaptive threads", "graph", profile.graph, "full", profile.full, "filter_threads", filterThreads, "encoder_threads", encoderThreads)
+			ffmpegArgs[threadsIndex] = fmt.Sprintf("%d", encoderThreads)
+			encoding.Threads = uint16(encoderThreads)
+			ffmpegArgs = append([]string{"-filter_complex_threads", fmt.Sprintf("%d", filterThreads)}, ffmpegArgs...)
+		}
+	}
+
+	if v.startTimecode != "" {
+		ffmpegArgs = append(ffmpegArgs, "-timecode", v.startTimecode)
+		if strings.EqualFold(filepath.Ext(parms.OutputPath), ".mp4") {
+			// MP4 only gets a timecode track on request; MOV and MXF always do.
+			ffmpegArgs = append(ffmpegArgs, "-write_tmcd", "1")
+		}
+	}
+
+	ffmpegArgs, cleanupTracks, err := audioTrackArgs(ffmpegArgs, parms, !v.audioRemoved && (!direct || v.HasAudio()))
+	if err != nil {
+		return fmt.Errorf("WriteVideo: %w (file=%s)", err, safeFirstFilename(v.filenames))
+	}
+	defer cleanupTracks()
+
+	// Inserted last: the caption input shifts the positions of the arguments.
+	ffmpegArgs = parms.Captions.apply(ffmpegArgs, encoder)
+
+	outputArgs := []string{"-metadata:s:v:0", "rotate=0"}
+	outputArgs = append(outputArgs, bsfArgs("v", parms.BitstreamFilters)...)
+	outputArgs = append(outputArgs, bsfArgs("a", parms.AudioBitstreamFilters)...)
+	if parms.StripMetadata {
+		outputArgs = append(outputArgs, stripMetadataArgs...)
+	}
+	if strings.EqualFold(filepath.Ext(parms.OutputPath), ".m3u8") {
+		hlsArgs, cleanup, err := hlsOutputArgs(parms.OutputPath, parms.HLS, parms.Env)
+		if err != nil {
+			return fmt.Errorf("WriteVideo: %w (file=%s)", err, safeFirstFilename(v.filenames))
+		}
+		defer cleanup()
+		outputArgs = append(outputArgs, hlsArgs...)
+	} else if parms.HLS.Encryption != nil {
+		return fmt.Errorf("WriteVideo: HLS encryption requires an .m3u8 output (output=%s, file=%s)", parms.OutputPath, safeFirstFilename(v.filenames))
+	}
+	if parms.CENC != nil {
+		cenc, err := cencArgs(parms.OutputPath, parms.CENC)
+		if err != nil {
+			return fmt.Errorf("WriteVideo: %w (file=%s)", err, safeFirstFilename(v.filenames))
+		}
+		outputArgs = append(outputArgs, cenc...)
+	}
+	job := renderJob{
+		op:         "WriteVideo",
+		args:       ffmpegArgs,
+		outputArgs: outputArgs,
+		output:     parms.OutputPath,
+		duration:   v.GetDuration(),
+		env:        parms.Env,
+		onProgress: parms.OnProgress,
+		onLevels:   parms.OnLevels,
+		silent:     parms.SilentProgress,
+	}
+	err = job.run(ffmpegPath)
+	// Rendered clips (OpenClip, HTML, SVG, Lottie) are only kept for the
+	// render reading them.
+	releaseClipTempDirs(job.commandArgs())
+	if err != nil {
+		return err
+	}
+	if check := parms.VerifyDuration; check != nil {
+		withAudio := !v.audioRemoved && (!direct || v.HasAudio()) || len(parms.AudioTracks) > 0
+		if err := verifyDuration("WriteVideo", parms.OutputPath, v.GetDuration(), resolveFps(parms.Fps, v.GetFps()), withAudio, *check); err != nil {
+			return err
+		}
+	}
+	if frameLog != "" {
+		if err := writeFrameMetadata(frameLog, parms.OutputPath); err != nil {
+			return err
+		}
+	}
+	if parms.Sidecar {
+		if err := writeSidecar(&job, encoding); err != nil {
+			return err
+		}
+	}
+	return checkAudioReport("WriteVideo", parms)
+}
+
+// graphArgs returns the inputs, filter graph and output maps for rendering v
+// through a filter complex.
+func (v *Video) graphArgs(parms VideoParameters) ([]string, error) {
 	ffmpegArgs := []string{}
+	// Removed audio is left out of the graph entirely.
+	audioFilenames, audioChain := v.audio.filenames, v.audio.filterComplex
+	if v.audioRemoved {
+		audioFilenames, audioChain = nil, nil
+	}
 	videoFilenames := v.GetFilenames()
 	for _, filename := range videoFilenames {
+		ffmpegArgs = append(ffmpegArgs, inputArgs(filename, v.filterComplex, audioChain)...)
 		ffmpegArgs = append(ffmpegArgs, "-i", filename)
 	}
 
@@ -111,16 +399,22 @@ func (v *Video) WriteVideo(parms VideoParameters) error {
 		videoFilenameSet[fn] = struct{}{}
 	}
 	var audioOnlyFilenames []string
-	for _, fn := range v.audio.filenames {
+	for _, fn := range audioFilenames {
 		if _, exists := videoFilenameSet[fn]; !exists {
 			audioOnlyFilenames = append(audioOnlyFilenames, fn)
 			videoFilenameSet[fn] = struct{}{} // avoid duplicates
 		}
 	}
 	for _, filename := range audioOnlyFilenames {
+		ffmpegArgs = append(ffmpegArgs, inputArgs(filename, audioChain)...)
 		ffmpegArgs = append(ffmpegArgs, "-i", filename)
 	}
 
+	workingFormat, err := resolveWorkingPixelFormat(parms.WorkingPixelFormat)
+	if err != nil {
+		return nil, fmt.Errorf("WriteVideo: %w (file=%s)", err, safeFirstFilename(v.filenames))
+	}
+
 	var filterComplex strings.Builder
 
 	// split part – video+audio inputs
@@ -138,12 +432,12 @@ func (v *Video) WriteVideo(parms VideoParameters) error {
 			videoLabels = append(videoLabels, newLabel)
 		}
 		if len(videoLabels) > 0 {
-			filterComplex.WriteString(fmt.Sprintf("[%d:v]format=yuva420p,split=%d[%s];", i, len(videoLabels), strings.Join(videoLabels, "][")))
+			filterComplex.WriteString(fmt.Sprintf("[%d:v]%ssplit=%d[%s];", i, workingFormat, len(videoLabels), strings.Join(videoLabels, "][")))
 		}
 
 		audioLabels := []string{}
-		for idx := range v.audio.filterComplex {
-			f := &v.audio.filterComplex[idx]
+		for idx := range audioChain {
+			f := &audioChain[idx]
 			if f.FileCopy.Filename != filename {
 				continue
 			}
@@ -162,8 +456,8 @@ func (v *Video) WriteVideo(parms VideoParameters) error {
 	for j, filename := range audioOnlyFilenames {
 		inputIndex := len(videoFilenames) + j
 		audioLabels := []string{}
-		for idx := range v.audio.filterComplex {
-			f := &v.audio.filterComplex[idx]
+		for idx := range audioChain {
+			f := &audioChain[idx]
 			if f.FileCopy.Filename != filename {
 				continue
 			}
@@ -181,15 +475,15 @@ func (v *Video) WriteVideo(parms VideoParameters) error {
 	}
 
 	videoIndex, audioIndex := 0, 0
-	videoLen, audioLen := len(v.filterComplex), len(v.audio.filterComplex)
+	videoLen, audioLen := len(v.filterComplex), len(audioChain)
 
 	for videoIndex < videoLen || audioIndex < audioLen {
 		nextOrder := uint64(math.MaxUint64)
 		if videoIndex < videoLen {
 			nextOrder = v.filterComplex[videoIndex].Order
 		}
-		if audioIndex < audioLen && v.audio.filterComplex[audioIndex].Order < nextOrder {
-			nextOrder = v.audio.filterComplex[audioIndex].Order
+		if audioIndex < audioLen && audioChain[audioIndex].Order < nextOrder {
+			nextOrder = audioChain[audioIndex].Order
 		}
 
 		if videoIndex < videoLen && v.filterComplex[videoIndex].Order == nextOrder {
@@ -203,8 +497,8 @@ func (v *Video) WriteVideo(parms VideoParameters) error {
 			}
 			videoIndex++
 		}
-		if audioIndex < audioLen && v.audio.filterComplex[audioIndex].Order == nextOrder {
-			filter := v.audio.filterComplex[audioIndex]
+		if audioIndex < audioLen && audioChain[audioIndex].Order == nextOrder {
+			filter := audioChain[audioIndex]
 			if filter.FilterElement != "" {
 				filterComplex.WriteString(filter.FilterElement)
 				if !strings.HasSuffix(filter.FilterElement, "]") {
@@ -222,169 +516,20 @@ func (v *Video) WriteVideo(parms VideoParameters) error {
 
 	videoLabel := v.lastVideoLabel()
 	if videoLabel == "" {
-		return fmt.Errorf("WriteVideo: no video output label generated (file=%s)", safeFirstFilename(v.filenames))
-	}
-
-	audioLabel := v.audio.lastAudioLabel()
-	if audioLabel == "" {
-		return fmt.Errorf("WriteVideo: no audio output label generated (file=%s)", safeFirstFilename(v.filenames))
+		return nil, fmt.Errorf("WriteVideo: no video output label generated (file=%s)", safeFirstFilename(v.filenames))
 	}
 
 	mapVideo := fmt.Sprintf("[%s]", videoLabel)
-	encoder := resolveVideoEncoder(parms.Codec, v.GetCodec())
-
-	mapAudio := fmt.Sprintf("[%s]", audioLabel)
-	ffmpegArgs = append(ffmpegArgs, "-filter_complex", filterComplex.String(), "-map", mapVideo, "-map", mapAudio, "-c:v", encoder)
-
-	// Threads (compute effective value - applyParameters modifies a copy)
-	effectiveThreads := parms.Threads
-	if effectiveThreads == 0 {
-		totalCPUs := runtime.GOMAXPROCS(0)
-		if totalCPUs <= 2 {
-			effectiveThreads = uint16(totalCPUs)
-		} else {
-			effectiveThreads = uint16((totalCPUs * 6) / 10)
-			if effectiveThreads < 2 {
-				effectiveThreads = 2
-			}
-		}
-	}
-	ffmpegArgs = append(ffmpegArgs, "-threads", fmt.Sprintf("%d", effectiveThreads))
-
-	// FPS (if set)
-	if fps := resolveFps(parms.Fps, v.GetFps()); fps > 0 {
-		ffmpegArgs = append(ffmpegArgs, "-r", fmt.Sprintf("%d", fps))
-	}
-
-	// Bitrate (if set)
-	if br := resolveBitrate(parms.Bitrate, v.GetBitRate()); br != "" {
-		ffmpegArgs = append(ffmpegArgs, "-b:v", br)
-	}
-
-	// Preset (codec-specific, only for encoders that support it)
-	presetStr := resolvePreset(parms.Preset, v.GetPreset())
-	mappedPreset := mapPresetForCodec(encoder, presetStr)
-	if mappedPreset != "" {
-		ffmpegArgs = append(ffmpegArgs, "-preset", mappedPreset)
-	}
-
-	// Pixel format (default to yuv420p to strip alpha from internal YUVA pipeline)
-	pf := resolvePixelFormat(parms.PixelFormat, v.GetPixelFormat())
-	if pf == "" {
-		pf = PixelFormatYUV420P
+	ffmpegArgs = append(ffmpegArgs, "-filter_complex", filterComplex.String(), "-map", mapVideo)
+	if v.audioRemoved {
+		return ffmpegArgs, nil
 	}
-	ffmpegArgs = append(ffmpegArgs, "-pix_fmt", string(pf))
 
-	// Audio codec for MP4 output
-	outputExt := strings.ToLower(filepath.Ext(parms.OutputPath))
-	if outputExt == ".mp4" || outputExt == ".m4a" {
-		ffmpegArgs = append(ffmpegArgs, "-c:a", "aac")
-	}
-
-	progressEnabled := parms.OnProgress != nil || !parms.SilentProgress
-	if progressEnabled {
-		ffmpegArgs = append(ffmpegArgs, "-progress", "pipe:1", "-nostats")
-	}
-
-	ffmpegArgs = append(ffmpegArgs, "-metadata:s:v:0", "rotate=0", "-y", parms.OutputPath)
-
-	cmd := exec.Command(ffmpegPath, ffmpegArgs...)
-	var stderrBuf bytes.Buffer
-	cmd.Stderr = &stderrBuf
-
-	displayProgram := filepath.Base(ffmpegPath)
-	displayProgram = strings.TrimSuffix(displayProgram, filepath.Ext(displayProgram))
-	displayCmd := &exec.Cmd{Args: append([]string{displayProgram}, ffmpegArgs...)}
-
-	fmt.Printf("Writing to %s\n", parms.OutputPath)
-	fmt.Println(formatCmd(displayCmd))
-
-	if progressEnabled {
-		handler := parms.OnProgress
-		if handler == nil {
-			handler = defaultProgressHandler(parms.OutputPath)
-		}
-		if err := v.runWithProgress(cmd, &stderrBuf, handler); err != nil {
-			return err
-		}
-		slog.Info("Export completed", "path", parms.OutputPath)
-		return nil
-	}
-
-	if err := cmd.Run(); err != nil {
-		stderr := strings.TrimSpace(stderrBuf.String())
-		if stderr != "" {
-			return fmt.Errorf("WriteVideo: failed to execute ffmpeg: %w\nffmpeg stderr: %s", err, stderr)
-		}
-		return fmt.Errorf("WriteVideo: failed to execute ffmpeg: %w", err)
-	}
-
-	slog.Info("Export completed", "path", parms.OutputPath)
-	return nil
-}
-
-func (v *Video) runWithProgress(cmd *exec.Cmd, stderrBuf *bytes.Buffer, onProgress func(Progress)) error {
-	stdoutPipe, err := cmd.StdoutPipe()
-	if err != nil {
-		return fmt.Errorf("WriteVideo: failed to create stdout pipe: %w", err)
-	}
-
-	if err := cmd.Start(); err != nil {
-		return fmt.Errorf("WriteVideo: failed to start ffmpeg: %w", err)
-	}
-
-	startTime := time.Now()
-	totalDuration := v.GetDuration()
-	scanner := bufio.NewScanner(stdoutPipe)
-	cur := Progress{TotalDuration: totalDuration}
-
-	for scanner.Scan() {
-		line := scanner.Text()
-		key, value, ok := strings.Cut(line, "=")
-		if !ok {
-			continue
-		}
-
-		switch key {
-		case "frame":
-			cur.Frame, _ = strconv.ParseInt(value, 10, 64)
-		case "fps":
-			cur.FPS, _ = strconv.ParseFloat(value, 64)
-		case "bitrate":
-			cur.Bitrate = value
-		case "out_time_us":
-			us, _ := strconv.ParseInt(value, 10, 64)
-			cur.OutTime = float64(us) / 1_000_000
-			if totalDuration > 0 {
-				cur.Percentage = math.Min((cur.OutTime/totalDuration)*100, 100)
-			}
-		case "speed":
-			s := strings.TrimSuffix(strings.TrimSpace(value), "x")
-			cur.Speed, _ = strconv.ParseFloat(s, 64)
-		case "progress":
-			cur.Done = value == "end"
-			if cur.Done {
-				cur.Percentage = 100
-			}
-			cur.ElapsedSeconds = time.Since(startTime).Seconds()
-			if cur.Percentage > 0 && cur.Percentage < 100 {
-				cur.ExpectedTotalSeconds = cur.ElapsedSeconds / (cur.Percentage / 100)
-			} else if cur.Done {
-				cur.ExpectedTotalSeconds = cur.ElapsedSeconds
-			}
-			onProgress(cur)
-		}
-	}
-
-	if err := cmd.Wait(); err != nil {
-		stderr := strings.TrimSpace(stderrBuf.String())
-		if stderr != "" {
-			return fmt.Errorf("WriteVideo: failed to execute ffmpeg: %w\nffmpeg stderr: %s", err, stderr)
-		}
-		return fmt.Errorf("WriteVideo: failed to execute ffmpeg: %w", err)
+	audioLabel := v.audio.lastAudioLabel()
+	if audioLabel == "" {
+		return nil, fmt.Errorf("WriteVideo: no audio output label generated (file=%s)", safeFirstFilename(v.filenames))
 	}
-
-	return nil
+	return append(ffmpegArgs, "-map", fmt.Sprintf("[%s]", audioLabel)), nil
 }
 
 const (
@@ -0,0 +1,72 @@
+package moviego
+
+import (
+	"fmt"
+	"strings"
+)
+
+// VolumeKeyframe is a point of a volume envelope: the gain at Time seconds
+// (1.0 = unchanged, 0 = silent). Curve shapes the ramp from the previous
+// keyframe into this one.
+type VolumeKeyframe struct {
+	Time  float64
+	Level float64
+	Curve Curve
+}
+
+// volumeEnvelopeExpr compiles keyframes to a volume expression. The level
+// holds the first keyframe before it and the last keyframe after it; each
+// ramp adds its change once it starts, so the segments sum to the envelope.
+func volumeEnvelopeExpr(keys []VolumeKeyframe) (string, error) {
+	if len(keys) == 0 {
+		return "", fmt.Errorf("no keyframes provided")
+	}
+	for i, k := range keys {
+		if k.Time < 0 || k.Level < 0 {
+			return "", fmt.Errorf("keyframe %d: time and level must be >= 0 (got time=%.4f, level=%.4f)", i, k.Time, k.Level)
+		}
+		if i > 0 && k.Time <= keys[i-1].Time {
+			return "", fmt.Errorf("keyframe %d: times must be increasing (got=%.4f after %.4f)", i, k.Time, keys[i-1].Time)
+		}
+	}
+	terms := []string{fmt.Sprintf("%.4f", keys[0].Level)}
+	for i := 1; i < len(keys); i++ {
+		prev, k := keys[i-1], keys[i]
+		if k.Level == prev.Level {
+			continue
+		}
+		ramp := Animation{Start: 0, End: k.Level - prev.Level, StartTime: prev.Time, EndTime: k.Time, Curve: k.Curve}
+		terms = append(terms, "("+ramp.toExprDefault()+")")
+	}
+	return strings.Join(terms, "+"), nil
+}
+
+// SetVolumeEnvelope automates the volume along keyframes, e.g. to dip music
+// under an interview and bring it back up afterwards.
+func (a *Audio) SetVolumeEnvelope(keys []VolumeKeyframe) (*Audio, error) {
+	expr, err := volumeEnvelopeExpr(keys)
+	if err != nil {
+		return nil, fmt.Errorf("SetVolumeEnvelope: %w (file=%s, label=%s)", err, safeFirstFilename(a.filenames), a.safeLabel())
+	}
+	return a.audioFilter(fmt.Sprintf("volume='%s':eval=frame", expr))
+}
+
+// SetVolumeEnvelope automates the volume of the video's audio along
+// keyframes, on the video's own timeline.
+func (v *Video) SetVolumeEnvelope(keys []VolumeKeyframe) (*Video, error) {
+	expr, err := volumeEnvelopeExpr(keys)
+	if err != nil {
+		return nil, fmt.Errorf("SetVolumeEnvelope: %w (file=%s, label=%s)", err, safeFirstFilename(v.filenames), safeLastVideoLabel(v))
+	}
+	if len(v.overlays) > 0 {
+		flat, err := v.FlattenOverlays()
+		if err != nil {
+			return nil, fmt.Errorf("SetVolumeEnvelope: %w", err)
+		}
+		return flat.SetVolumeEnvelope(keys)
+	}
+	out := v.clone()
+	initRawVideo(&out)
+	out.audio = out.audio.chain(fmt.Sprintf("volume='%s':eval=frame", expr))
+	return &out, nil
+}
@@ -0,0 +1,255 @@
+package moviego
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WatchConfig configures a Watcher. Zero fields use the defaults listed below.
+type WatchConfig struct {
+	Input  string // directory watched for new media
+	Output string // directory receiving the rendered files and manifests
+
+	// Process applies the edit to each new file, e.g. a function adding it
+	// to a Timeline or filling a template. nil writes the file unchanged.
+	Process func(v *Video) (*Video, error)
+	// Parameters are used for every output; OutputPath is set per file.
+	Parameters VideoParameters
+
+	Extensions   []string      // accepted input extensions (default: .mp4 .mov .mkv .webm .avi)
+	OutputExt    string        // appended to the input name, clip.mov -> clip.mov.mp4 (default: ".mp4")
+	PollInterval time.Duration // time between scans (default: 2s)
+	StableFor    time.Duration // a file is processed once unmodified for this long (default: 5s)
+	Workers      int           // files processed in parallel (default: 1)
+}
+
+func (c WatchConfig) withDefaults() WatchConfig {
+	if len(c.Extensions) == 0 {
+		c.Extensions = []string{".mp4", ".mov", ".mkv", ".webm", ".avi"}
+	}
+	if c.OutputExt == "" {
+		c.OutputExt = ".mp4"
+	}
+	if c.PollInterval == 0 {
+		c.PollInterval = 2 * time.Second
+	}
+	if c.StableFor == 0 {
+		c.StableFor = 5 * time.Second
+	}
+	if c.Workers == 0 {
+		c.Workers = 1
+	}
+	return c
+}
+
+// WatchResult is the manifest written for every processed file, to
+// <Output>/manifests/<input name>.json.
+type WatchResult struct {
+	Input    string    `json:"input"`
+	Output   string    `json:"output,omitempty"`
+	Success  bool      `json:"success"`
+	Error    string    `json:"error,omitempty"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+}
+
+// Watcher turns a directory into a transcode drop folder: each media file
+// appearing in Input is processed and written to Output, with a success or
+// failure manifest. Files that already have a manifest are skipped, so a
+// restarted watcher continues where it stopped.
+type Watcher struct {
+	cfg WatchConfig
+
+	mu      sync.Mutex
+	pending map[string]bool // files being processed
+}
+
+// NewWatcher validates cfg and creates the output directories.
+func NewWatcher(cfg WatchConfig) (*Watcher, error) {
+	cfg = cfg.withDefaults()
+	if cfg.Input == "" || cfg.Output == "" {
+		return nil, fmt.Errorf("NewWatcher: Input and Output are required")
+	}
+	if filepath.Clean(cfg.Input) == filepath.Clean(cfg.Output) {
+		return nil, fmt.Errorf("NewWatcher: Input and Output must differ (got=%s)", cfg.Input)
+	}
+	if cfg.Workers < 0 || cfg.PollInterval < 0 || cfg.StableFor < 0 {
+		return nil, fmt.Errorf("NewWatcher: Workers, PollInterval and StableFor must be positive")
+	}
+	if info, err := os.Stat(cfg.Input); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("NewWatcher: input '%s' is not a directory", cfg.Input)
+	}
+	if err := os.MkdirAll(filepath.Join(cfg.Output, "manifests"), 0755); err != nil {
+		return nil, fmt.Errorf("NewWatcher: failed to create '%s': %w", cfg.Output, err)
+	}
+	return &Watcher{cfg: cfg, pending: make(map[string]bool)}, nil
+}
+
+// Run scans Input every PollInterval until stop is closed.
+func (w *Watcher) Run(stop <-chan struct{}) error {
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := w.Scan(); err != nil {
+			return err
+		}
+		select {
+		case <-stop:
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Scan processes the new files of Input that are stable and returns their
+// results. Failures of single files are reported in the results and their
+// manifests, not as an error.
+func (w *Watcher) Scan() ([]WatchResult, error) {
+	files, err := w.newFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]WatchResult, len(files))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for n := 0; n < min(w.cfg.Workers, len(files)); n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = w.process(files[i])
+			}
+		}()
+	}
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results, nil
+}
+
+// newFiles lists the stable input files without a manifest and marks them
+// pending.
+func (w *Watcher) newFiles() ([]string, error) {
+	entries, err := os.ReadDir(w.cfg.Input)
+	if err != nil {
+		return nil, fmt.Errorf("Watcher: failed to read '%s': %w", w.cfg.Input, err)
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var files []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || strings.HasPrefix(name, ".") || !w.accepts(name) || w.pending[name] {
+			continue
+		}
+		if _, err := os.Stat(w.manifestPath(name)); err == nil {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil || time.Since(info.ModTime()) < w.cfg.StableFor {
+			continue // still being copied
+		}
+		w.pending[name] = true
+		files = append(files, name)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func (w *Watcher) accepts(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	for _, e := range w.cfg.Extensions {
+		if strings.ToLower(e) == ext {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Watcher) manifestPath(name string) string {
+	return filepath.Join(w.cfg.Output, "manifests", name+".json")
+}
+
+// process renders one file and writes its manifest.
+func (w *Watcher) process(name string) WatchResult {
+	defer func() {
+		w.mu.Lock()
+		delete(w.pending, name)
+		w.mu.Unlock()
+	}()
+
+	input := filepath.Join(w.cfg.Input, name)
+	// The input extension is kept, so clip.mov and clip.mp4 do not both
+	// render to clip.mp4.
+	output := filepath.Join(w.cfg.Output, name+w.cfg.OutputExt)
+	result := WatchResult{Input: input, Started: time.Now()}
+	err := w.render(input, output)
+	result.Finished = time.Now()
+	if err != nil {
+		result.Error = err.Error()
+		slog.Error("Watcher: processing failed", "input", input, "error", err)
+	} else {
+		result.Success = true
+		result.Output = output
+		slog.Info("Watcher: processed", "input", input, "output", output)
+	}
+	if err := writeJSON(w.manifestPath(name), result); err != nil {
+		slog.Error("Watcher: failed to write manifest", "input", input, "error", err)
+	}
+	return result
+}
+
+func (w *Watcher) render(input, output string) (err error) {
+	// A panicking Process must not stop the watcher.
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	v, err := NewVideoFile(input)
+	if err != nil {
+		return err
+	}
+	if w.cfg.Process != nil {
+		if v, err = w.cfg.Process(v); err != nil {
+			return err
+		}
+	}
+	parms := w.cfg.Parameters
+	parms.OutputPath = output
+	parms.SilentProgress = true
+	return v.WriteVideo(parms)
+}
+
+// Manifests returns the manifests written so far, sorted by input.
+func (w *Watcher) Manifests() ([]WatchResult, error) {
+	paths, err := filepath.Glob(filepath.Join(w.cfg.Output, "manifests", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	results := make([]WatchResult, 0, len(paths))
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("Watcher: failed to read manifest '%s': %w", p, err)
+		}
+		var r WatchResult
+		if err := json.Unmarshal(data, &r); err != nil {
+			return nil, fmt.Errorf("Watcher: failed to parse manifest '%s': %w", p, err)
+		}
+		results = append(results, r)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Input < results[j].Input })
+	return results, nil
+}
@@ -0,0 +1,216 @@
+package moviego
+
+import "fmt"
+
+// WaveformMode is how WaveformClip draws the samples (FFmpeg showwaves modes).
+type WaveformMode string
+
+const (
+	WaveformCenteredLine WaveformMode = "cline" // vertical lines centered on the axis (default)
+	WaveformLine         WaveformMode = "line"  // vertical lines from the axis
+	WaveformPoint        WaveformMode = "point" // a point per sample
+	WaveformPointToPoint WaveformMode = "p2p"   // points joined by lines
+)
+
+// WaveformClip renders the waveform of an audio track, e.g. an audiogram
+// over a podcast still or a scope overlaid on the video the audio comes
+// from. The background is transparent, so it composites like an ImageClip.
+// The clip is silent unless WithAudio is set, so overlaying it on its own
+// video does not play the audio twice.
+type WaveformClip struct {
+	audio            Audio
+	width            uint64
+	height           uint64
+	duration         float64
+	fps              uint64
+	color            Color
+	mode             WaveformMode
+	withAudio        bool
+	position         Position
+	animatedPosition *AnimatedPosition
+	animatedOpacity  *Animation
+}
+
+// NewWaveformClip creates a width x height waveform of audio, lasting as
+// long as the audio. Filters of audio are applied before drawing.
+func NewWaveformClip(audio *Audio, width, height uint64) *WaveformClip {
+	wc := &WaveformClip{width: width, height: height}
+	if audio != nil {
+		wc.audio = *audio
+		wc.duration = audio.duration
+	}
+	return wc
+}
+
+// NewVideoWaveformClip creates a width x height waveform of the audio of v,
+// lasting as long as v.
+func NewVideoWaveformClip(v *Video, width, height uint64) *WaveformClip {
+	wc := NewWaveformClip(v.GetAudio(), width, height)
+	wc.duration = v.duration
+	return wc
+}
+
+// GetWidth returns the clip width.
+func (wc *WaveformClip) GetWidth() uint64 {
+	return wc.width
+}
+
+// GetHeight returns the clip height.
+func (wc *WaveformClip) GetHeight() uint64 {
+	return wc.height
+}
+
+// GetDuration returns the clip duration.
+func (wc *WaveformClip) GetDuration() float64 {
+	return wc.duration
+}
+
+// GetFps returns the clip frame rate (default: 30).
+func (wc *WaveformClip) GetFps() uint64 {
+	if wc.fps == 0 {
+		return defaultClipFps
+	}
+	return wc.fps
+}
+
+// GetPosition returns the overlay position.
+// Returns center position if none was explicitly set.
+func (wc *WaveformClip) GetPosition() Position {
+	if wc.position.X == "" && wc.position.Y == "" {
+		return CenterPosition()
+	}
+	return wc.position
+}
+
+// GetColor returns the waveform color (default: white).
+func (wc *WaveformClip) GetColor() Color {
+	if wc.color == "" {
+		return "white"
+	}
+	return wc.color
+}
+
+// GetMode returns the drawing mode (default: WaveformCenteredLine).
+func (wc *WaveformClip) GetMode() WaveformMode {
+	if wc.mode == "" {
+		return WaveformCenteredLine
+	}
+	return wc.mode
+}
+
+// Color sets the waveform color, e.g. "white", "#1DB954" or "white@0.6".
+func (wc *WaveformClip) Color(color Color) *WaveformClip {
+	wc.color = color
+	return wc
+}
+
+// Mode sets how the samples are drawn.
+func (wc *WaveformClip) Mode(mode WaveformMode) *WaveformClip {
+	wc.mode = mode
+	return wc
+}
+
+// Duration sets the clip duration. The audio is cut or padded with silence.
+func (wc *WaveformClip) Duration(d float64) *WaveformClip {
+	wc.duration = d
+	return wc
+}
+
+// Fps sets the clip frame rate.
+func (wc *WaveformClip) Fps(fps uint64) *WaveformClip {
+	wc.fps = fps
+	return wc
+}
+
+// WithAudio makes the clip carry the audio it draws, for standalone
+// audiograms.
+func (wc *WaveformClip) WithAudio() *WaveformClip {
+	wc.withAudio = true
+	return wc
+}
+
+// SetPosition sets the overlay position used by CompositeClips.
+func (wc *WaveformClip) SetPosition(position Position) *WaveformClip {
+	wc.position = position
+	return wc
+}
+
+// SetAnimatedPosition sets the overlay position animation for CompositeClips.
+func (wc *WaveformClip) SetAnimatedPosition(ap AnimatedPosition) *WaveformClip {
+	wc.animatedPosition = &ap
+	return wc
+}
+
+// SetAnimatedOpacity sets the overlay opacity animation for CompositeClips.
+func (wc *WaveformClip) SetAnimatedOpacity(a Animation) *WaveformClip {
+	wc.animatedOpacity = &a
+	return wc
+}
+
+// toVideo feeds a private copy of the audio chain to showwaves. The copy is
+// relabeled so the clip can be composited with the video the audio comes
+// from.
+func (wc *WaveformClip) toVideo() (*Video, error) {
+	if err := wc.Validate(); err != nil {
+		return nil, err
+	}
+	fps := wc.GetFps()
+	label := fmt.Sprintf("waveform_%d", incrementGlobalCounter())
+
+	audio := wc.audio
+	audio.filterComplex, _ = deepCopySlice(audio.filterComplex)
+	initRawAudio(&audio)
+	audio.filterComplex = relabelChain(audio.filterComplex, "_"+label)
+
+	// Fit the audio to the clip, then draw one copy and output the other.
+	waveLabel := label + "_wave"
+	element := fmt.Sprintf("[%s]apad,atrim=duration=%.4f", audio.lastAudioLabel(), wc.duration)
+	if wc.withAudio {
+		element += fmt.Sprintf(",asplit=2[%s_a][%s]", label, waveLabel)
+	} else {
+		element += fmt.Sprintf("[%s]", waveLabel)
+	}
+	audio.filterComplex = append(audio.filterComplex, FilterComplex{
+		Order:         incrementOrderCounter(),
+		FilterElement: element,
+		Label:         label + "_a",
+	})
+
+	v := generatedVideo(nil, fmt.Sprintf("[%s]showwaves=s=%dx%d:mode=%s:colors=%s:rate=%d,format=rgba,setsar=1",
+		waveLabel, wc.width, wc.height, wc.GetMode(), wc.GetColor().ffmpeg(), fps), FileCopy{}, label, wc.width, wc.height, fps, wc.duration)
+	if wc.withAudio {
+		audio.duration = wc.duration
+		v.audio = audio
+	} else {
+		// The silence comes after the chain drawn by showwaves, so it is
+		// the audio of the clip.
+		silence := silentAudio(incrementOrderCounter(), label+"_silence", wc.duration)
+		audio.filterComplex = append(audio.filterComplex, silence.filterComplex...)
+		silence.filenames, silence.filterComplex = audio.filenames, audio.filterComplex
+		v.audio = silence
+	}
+	v.position = wc.position
+	v.animatedPosition = wc.animatedPosition
+	v.animatedOpacity = wc.animatedOpacity
+	return v, nil
+}
+
+// relabelChain appends suffix to every label of chain, so a copy of a chain
+// can sit in the same graph as the original.
+func relabelChain(chain []FilterComplex, suffix string) []FilterComplex {
+	rename := func(label string) string {
+		if label == "" {
+			return ""
+		}
+		return label + suffix
+	}
+	for i := range chain {
+		fc := &chain[i]
+		fc.Label = rename(fc.Label)
+		fc.FileCopy.Label = rename(fc.FileCopy.Label)
+		fc.FilterElement = labelPattern.ReplaceAllStringFunc(fc.FilterElement, func(m string) string {
+			return "[" + rename(m[1:len(m)-1]) + "]"
+		})
+	}
+	return chain
+}